@@ -0,0 +1,212 @@
+package app
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 支持的请求体校验和标头及其哈希算法。
+var checksumHeaders = []struct {
+	header  string
+	newHash func() hash.Hash
+}{
+	{header: consts.HeaderContentMD5, newHash: md5.New},
+	{header: consts.HeaderChecksumSHA256, newHash: sha256.New},
+}
+
+type checksumOptions struct {
+	// 缺少校验和标头时是否拒绝请求
+	required bool
+}
+
+// ChecksumOption 是请求体校验和的自定义选项。
+type ChecksumOption func(o *checksumOptions)
+
+// WithChecksumRequired 设置缺少校验和标头时是否拒绝请求，默认跳过校验。
+func WithChecksumRequired(required bool) ChecksumOption {
+	return func(o *checksumOptions) {
+		o.required = required
+	}
+}
+
+// VerifyBodyChecksum 按请求头中的校验和校验请求体的完整性。
+//
+// 支持的标头：
+//
+//   - Content-MD5：MD5 摘要的 base64 编码（RFC 1864）
+//   - X-Checksum-SHA256：SHA256 摘要的十六进制或 base64 编码
+//
+// 若校验失败，或启用 WithChecksumRequired 而缺少校验和标头，
+// 则以 400 中止请求并返回错误。
+//
+// 若请求体为流式正文，则在读取时边读边算，读完时若不匹配，读取方将得到 errors.ErrChecksumMismatch。
+func (ctx *RequestContext) VerifyBodyChecksum(opts ...ChecksumOption) error {
+	o := &checksumOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var checksums []checksum
+	for _, h := range checksumHeaders {
+		v := ctx.Request.Header.Peek(h.header)
+		if len(v) == 0 {
+			continue
+		}
+		hasher := h.newHash()
+		expected, err := decodeChecksum(v, hasher.Size())
+		if err != nil {
+			err = fmt.Errorf("无效的 %s 标头：%w", h.header, errors.ErrChecksumMismatch)
+			ctx.AbortWithMsg(err.Error(), consts.StatusBadRequest)
+			return err
+		}
+		checksums = append(checksums, checksum{hash: hasher, expected: expected})
+	}
+
+	if len(checksums) == 0 {
+		if o.required {
+			ctx.AbortWithMsg(errors.ErrChecksumMissing.Error(), consts.StatusBadRequest)
+			return errors.ErrChecksumMissing
+		}
+		return nil
+	}
+
+	if ctx.Request.IsBodyStream() {
+		ctx.Request.ConstructBodyStream(ctx.Request.BodyBuffer(), &checksumReader{
+			r:         ctx.Request.BodyStream(),
+			checksums: checksums,
+		})
+		return nil
+	}
+
+	body, err := ctx.Request.BodyE()
+	if err != nil {
+		return err
+	}
+	for _, c := range checksums {
+		c.hash.Write(body)
+		if !c.match() {
+			ctx.AbortWithMsg(errors.ErrChecksumMismatch.Error(), consts.StatusBadRequest)
+			return errors.ErrChecksumMismatch
+		}
+	}
+	return nil
+}
+
+type checksum struct {
+	hash     hash.Hash
+	expected []byte
+}
+
+func (c checksum) match() bool {
+	return bytes.Equal(c.hash.Sum(nil), c.expected)
+}
+
+// checksumReader 在读取正文流的同时计算校验和，读完时校验。
+type checksumReader struct {
+	r         io.Reader
+	checksums []checksum
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	for _, c := range cr.checksums {
+		c.hash.Write(p[:n])
+	}
+	if err == io.EOF {
+		for _, c := range cr.checksums {
+			if !c.match() {
+				return n, errors.ErrChecksumMismatch
+			}
+		}
+	}
+	return n, err
+}
+
+func (cr *checksumReader) Close() error {
+	if closer, ok := cr.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// decodeChecksum 按摘要长度 size 解码十六进制或 base64 编码的摘要值。
+func decodeChecksum(v []byte, size int) ([]byte, error) {
+	if len(v) == hex.EncodedLen(size) {
+		return hex.DecodeString(string(v))
+	}
+	b, err := base64.StdEncoding.DecodeString(string(v))
+	if err == nil && len(b) != size {
+		err = fmt.Errorf("摘要长度应为 %d，实为 %d", size, len(b))
+	}
+	return b, err
+}
+
+// ChecksumAlgo 是响应体校验和的哈希算法。
+type ChecksumAlgo int
+
+const (
+	// ChecksumMD5 以 Content-MD5 挂车发送 MD5 摘要的 base64 编码。
+	ChecksumMD5 ChecksumAlgo = iota
+	// ChecksumSHA256 以 X-Checksum-SHA256 挂车发送 SHA256 摘要的十六进制编码。
+	ChecksumSHA256
+)
+
+// SetBodyStreamWithChecksum 设置响应的正文流，并在发送的同时计算校验和，正文结束后作为挂车发送。
+//
+// 响应将以 chunked 编码发送；bodySize >= 0 时仅发送正文流的前 bodySize 字节。
+// 客户端可读取挂车并与收到的正文比对以校验完整性。
+func (ctx *RequestContext) SetBodyStreamWithChecksum(bodyStream io.Reader, bodySize int, algo ChecksumAlgo) {
+	cr := &checksumTrailerReader{
+		r:       bodyStream,
+		src:     bodyStream,
+		trailer: ctx.Response.Header.Trailer(),
+	}
+	if bodySize >= 0 {
+		cr.r = io.LimitReader(bodyStream, int64(bodySize))
+	}
+	switch algo {
+	case ChecksumMD5:
+		cr.key, cr.hash, cr.encode = consts.HeaderContentMD5, md5.New(), base64.StdEncoding.EncodeToString
+	default:
+		cr.key, cr.hash, cr.encode = consts.HeaderChecksumSHA256, sha256.New(), hex.EncodeToString
+	}
+	// 先声明挂车，值在正文结束时填充
+	_ = cr.trailer.Set(cr.key, "")
+	ctx.Response.SetBodyStream(cr, -1)
+}
+
+// checksumTrailerReader 在读取正文流的同时计算校验和，读完时写入挂车。
+type checksumTrailerReader struct {
+	r       io.Reader
+	src     io.Reader // 原始正文流，用于关闭
+	hash    hash.Hash
+	key     string
+	encode  func([]byte) string
+	trailer *protocol.Trailer
+}
+
+func (cr *checksumTrailerReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.hash.Write(p[:n])
+	if err == io.EOF {
+		_ = cr.trailer.Set(cr.key, cr.encode(cr.hash.Sum(nil)))
+	}
+	return n, err
+}
+
+func (cr *checksumTrailerReader) Close() error {
+	if closer, ok := cr.src.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/network"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/protocol/http1/resp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBodyChecksum(t *testing.T) {
+	body := []byte("hello wind")
+	md5Sum := md5.Sum(body)
+	sha256Sum := sha256.Sum256(body)
+
+	// 无校验和标头
+	ctx := NewContext(0)
+	ctx.Request.SetBody(body)
+	assert.Nil(t, ctx.VerifyBodyChecksum())
+	assert.Equal(t, errors.ErrChecksumMissing, ctx.VerifyBodyChecksum(WithChecksumRequired(true)))
+	assert.Equal(t, consts.StatusBadRequest, ctx.Response.StatusCode())
+	assert.True(t, ctx.IsAborted())
+
+	// Content-MD5
+	ctx = NewContext(0)
+	ctx.Request.SetBody(body)
+	ctx.Request.Header.Set(consts.HeaderContentMD5, base64.StdEncoding.EncodeToString(md5Sum[:]))
+	assert.Nil(t, ctx.VerifyBodyChecksum(WithChecksumRequired(true)))
+
+	// X-Checksum-SHA256 十六进制
+	ctx = NewContext(0)
+	ctx.Request.SetBody(body)
+	ctx.Request.Header.Set(consts.HeaderChecksumSHA256, hex.EncodeToString(sha256Sum[:]))
+	assert.Nil(t, ctx.VerifyBodyChecksum())
+
+	// 不匹配
+	ctx = NewContext(0)
+	ctx.Request.SetBody([]byte("tampered"))
+	ctx.Request.Header.Set(consts.HeaderChecksumSHA256, base64.StdEncoding.EncodeToString(sha256Sum[:]))
+	assert.Equal(t, errors.ErrChecksumMismatch, ctx.VerifyBodyChecksum())
+	assert.Equal(t, consts.StatusBadRequest, ctx.Response.StatusCode())
+
+	// 无效标头
+	ctx = NewContext(0)
+	ctx.Request.SetBody(body)
+	ctx.Request.Header.Set(consts.HeaderContentMD5, "invalid")
+	assert.NotNil(t, ctx.VerifyBodyChecksum())
+	assert.Equal(t, consts.StatusBadRequest, ctx.Response.StatusCode())
+}
+
+func TestVerifyBodyChecksumStream(t *testing.T) {
+	body := []byte("hello wind")
+	md5Sum := md5.Sum(body)
+
+	ctx := NewContext(0)
+	ctx.Request.SetBodyStream(bytes.NewReader(body), len(body))
+	ctx.Request.Header.Set(consts.HeaderContentMD5, base64.StdEncoding.EncodeToString(md5Sum[:]))
+	assert.Nil(t, ctx.VerifyBodyChecksum())
+	b, err := io.ReadAll(ctx.RequestBodyStream())
+	assert.Nil(t, err)
+	assert.Equal(t, body, b)
+
+	ctx = NewContext(0)
+	ctx.Request.SetBodyStream(bytes.NewReader([]byte("tampered")), -1)
+	ctx.Request.Header.Set(consts.HeaderContentMD5, base64.StdEncoding.EncodeToString(md5Sum[:]))
+	assert.Nil(t, ctx.VerifyBodyChecksum())
+	_, err = io.ReadAll(ctx.RequestBodyStream())
+	assert.Equal(t, errors.ErrChecksumMismatch, err)
+}
+
+func TestSetBodyStreamWithChecksum(t *testing.T) {
+	body := "hello checksum"
+	sha := sha256.Sum256([]byte(body))
+	md := md5.Sum([]byte(body[:5]))
+
+	for _, tc := range []struct {
+		algo     ChecksumAlgo
+		size     int
+		key      string
+		body     string
+		expected string
+	}{
+		{ChecksumSHA256, -1, consts.HeaderChecksumSHA256, body, hex.EncodeToString(sha[:])},
+		{ChecksumMD5, 5, consts.HeaderContentMD5, body[:5], base64.StdEncoding.EncodeToString(md[:])},
+	} {
+		ctx := NewContext(0)
+		ctx.SetBodyStreamWithChecksum(bytes.NewReader([]byte(body)), tc.size, tc.algo)
+
+		buf := &bytes.Buffer{}
+		w := network.NewWriter(buf)
+		assert.Nil(t, resp.Write(&ctx.Response, w))
+		assert.Nil(t, w.Flush())
+		assert.Contains(t, buf.String(), "Transfer-Encoding: chunked")
+
+		var r protocol.Response
+		assert.Nil(t, resp.Read(&r, mock.NewZeroCopyReader(buf.String())))
+		assert.Equal(t, tc.body, string(r.Body()))
+		assert.Equal(t, tc.expected, r.Header.Trailer().Get(tc.key))
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/favbox/wind/protocol/http1"
 	"github.com/favbox/wind/protocol/http1/factory"
 	"github.com/favbox/wind/protocol/suite"
+	"golang.org/x/sync/singleflight"
 )
 
 var (
@@ -176,6 +177,8 @@ type Client struct {
 	ms             map[string]client.HostClient // https 主机对应的主机客户端
 	mws            Middleware
 	lastMiddleware Middleware
+
+	sf singleflight.Group // 合并并发的相同请求
 }
 
 // NewClient 创建给定选项的客户端。
@@ -453,8 +456,12 @@ func (c *Client) do(ctx context.Context, req *protocol.Request, resp *protocol.R
 			c.clientFactory = factory.NewClientFactory(newHttp1OptionFromClient(c))
 		}
 		hc, _ = c.clientFactory.NewHostClient()
+		addr := utils.AddMissingPort(h, isTLS)
+		if c.options.UnixSocket != "" {
+			addr = http1.UnixAddrPrefix + c.options.UnixSocket
+		}
 		hc.SetDynamicConfig(&client.DynamicConfig{
-			Addr:     utils.AddMissingPort(h, isTLS),
+			Addr:     addr,
 			ProxyURI: proxyURI,
 			IsTLS:    isTLS,
 		})
@@ -479,6 +486,18 @@ func (c *Client) do(ctx context.Context, req *protocol.Request, resp *protocol.R
 		go c.mCleaner()
 	}
 
+	if c.options.Singleflight {
+		if key, ok := singleflightKey(req, c.options.SingleflightExcludeHeaders); ok {
+			return c.doSingleflight(ctx, key, hc, req, resp)
+		}
+	}
+	return c.doHostClient(ctx, hc, req, resp)
+}
+
+func (c *Client) doHostClient(ctx context.Context, hc client.HostClient, req *protocol.Request, resp *protocol.Response) error {
+	if c.options.CookieJar != nil {
+		return doWithCookieJar(ctx, c.options.CookieJar, hc, req, resp)
+	}
 	return hc.Do(ctx, req, resp)
 }
 
@@ -528,9 +547,13 @@ func newHttp1OptionFromClient(c *Client) *http1.ClientOptions {
 		DisablePathNormalizing:        c.options.DisablePathNormalizing,
 		MaxConnWaitTimeout:            c.options.MaxConnWaitTimeout,
 		ResponseBodyStream:            c.options.ResponseBodyStream,
+		RequestCompressed:             c.options.RequestCompressed,
+		ChunkedBodyThreshold:          c.options.ChunkedBodyThreshold,
+		Trace:                         c.options.Trace,
 		RetryConfig:                   c.options.RetryConfig,
 		RetryIfFunc:                   c.RetryIfFunc,
 		StateObserve:                  c.options.HostClientStateObserve,
 		ObservationInterval:           c.options.ObservationInterval,
+		OnConnStateChange:             c.options.OnConnStateChange,
 	}
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/favbox/wind/app"
 	"github.com/favbox/wind/app/client/retry"
+	"github.com/favbox/wind/app/client/trace"
 	"github.com/favbox/wind/common/config"
 	errs "github.com/favbox/wind/common/errors"
 	"github.com/favbox/wind/internal/bytestr"
@@ -2365,3 +2366,54 @@ func TestClientRetryErr(t *testing.T) {
 		engine.Close()
 	})
 }
+
+func TestClientTrace(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	var events []string
+	var mu sync.Mutex
+	record := func(name string) {
+		mu.Lock()
+		events = append(events, name)
+		mu.Unlock()
+	}
+	tr := &trace.ClientTrace{
+		GetConn:           func(at time.Time, addr string) { record("GetConn") },
+		DNSStart:          func(at time.Time, host string) { record("DNSStart") },
+		DNSDone:           func(at time.Time, addrs []net.IPAddr, err error) { record("DNSDone") },
+		ConnectStart:      func(at time.Time, network, addr string) { record("ConnectStart") },
+		ConnectDone:       func(at time.Time, network, addr string, err error) { record("ConnectDone") },
+		TLSHandshakeStart: func(at time.Time) { record("TLSHandshakeStart") },
+		TLSHandshakeDone: func(at time.Time, state tls.ConnectionState, err error) {
+			assert.Nil(t, err)
+			assert.True(t, state.HandshakeComplete)
+			record("TLSHandshakeDone")
+		},
+		GotConn:              func(at time.Time, reused bool) { record(fmt.Sprintf("GotConn:%v", reused)) },
+		WroteRequest:         func(at time.Time, err error) { record("WroteRequest") },
+		GotFirstResponseByte: func(at time.Time) { record("GotFirstResponseByte") },
+	}
+	c, _ := NewClient(
+		WithDialer(standard.NewDialer()),
+		WithTLSConfig(&tls.Config{InsecureSkipVerify: true}),
+		WithTrace(tr),
+	)
+
+	url := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+	status, body, err := c.Get(context.Background(), nil, url)
+	assert.Nil(t, err)
+	assert.Equal(t, consts.StatusOK, status)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, []string{
+		"GetConn", "DNSStart", "DNSDone", "ConnectStart", "ConnectDone",
+		"TLSHandshakeStart", "TLSHandshakeDone", "GotConn:false", "WroteRequest", "GotFirstResponseByte",
+	}, events)
+
+	events = nil
+	_, _, err = c.Get(context.Background(), nil, url)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GetConn", "GotConn:true", "WroteRequest", "GotFirstResponseByte"}, events)
+}
@@ -3,13 +3,20 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
 	"time"
 
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/network/netpoll"
 	"github.com/favbox/wind/network/standard"
+	"github.com/stretchr/testify/assert"
 )
 
 func newMockDialerWithCustomFunc(network, address string, timeout time.Duration, customDialerFunc func(network, address string, timeout time.Duration, tlsConfig *tls.Config)) network.Dialer {
@@ -25,3 +32,36 @@ func newMockDialerWithCustomFunc(network, address string, timeout time.Duration,
 		timeout:          timeout,
 	}
 }
+
+func TestClientUnixSocket(t *testing.T) {
+	// unix 套接字路径有长度限制，不用 t.TempDir 的长路径
+	dir, err := os.MkdirTemp("", "wind")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "app.sock")
+
+	ln, err := net.Listen("unix", sock)
+	assert.Nil(t, err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + r.URL.Path))
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c, err := NewClient(WithUnixSocket(sock), WithDialer(standard.NewDialer()))
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		status, body, err := c.Get(context.Background(), nil, "http://app.local/ping")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "app.local/ping", string(body))
+	}
+
+	// 不同主机共用同一套接字，连接池仍按主机分开
+	_, body, err := c.Get(context.Background(), nil, "http://other.local/")
+	assert.Nil(t, err)
+	assert.Equal(t, "other.local/", string(body))
+	c.mLock.Lock()
+	assert.Equal(t, 2, len(c.m))
+	c.mLock.Unlock()
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/client"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// doWithCookieJar 为请求附加 jar 中匹配的 cookie，执行后将响应的 Set-Cookie 存入 jar。
+//
+// 由 jar 附加的 cookie 在请求完成后移除，以免重定向时泄露至其他域；用户显式设置的同名 cookie 优先。
+func doWithCookieJar(ctx context.Context, jar http.CookieJar, hc client.HostClient, req *protocol.Request, resp *protocol.Response) error {
+	u, err := url.Parse(req.URI().String())
+	if err != nil {
+		return hc.Do(ctx, req, resp)
+	}
+
+	var added []string
+	for _, cookie := range jar.Cookies(u) {
+		if len(req.Header.Cookie(cookie.Name)) > 0 {
+			continue
+		}
+		req.Header.SetCookie(cookie.Name, cookie.Value)
+		added = append(added, cookie.Name)
+	}
+
+	err = hc.Do(ctx, req, resp)
+	for _, name := range added {
+		req.Header.DelCookie(name)
+	}
+	if err != nil || resp == nil {
+		return err
+	}
+
+	var setCookies []string
+	resp.Header.VisitAllCookie(func(_, value []byte) {
+		setCookies = append(setCookies, string(value))
+	})
+	if len(setCookies) > 0 {
+		header := http.Header{consts.HeaderSetCookie: setCookies}
+		jar.SetCookies(u, (&http.Response{Header: header}).Cookies())
+	}
+	return nil
+}
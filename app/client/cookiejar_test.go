@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCookieJar(t *testing.T) {
+	opt := config.NewOptions([]config.Option{})
+	opt.Network = "unix"
+	opt.Addr = "unix-test-10022"
+	engine := route.NewEngine(opt)
+	engine.GET("/login", func(c context.Context, ctx *app.RequestContext) {
+		ctx.SetCookie("session", "abc", 3600, "/", "", protocol.CookieSameSiteDefaultMode, false, true)
+		ctx.Redirect(consts.StatusFound, []byte("/profile"))
+	})
+	engine.GET("/profile", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "session=%s", ctx.Cookie("session"))
+	})
+	engine.GET("/admin/secret", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "admin=%s", ctx.Cookie("admin"))
+	})
+	engine.GET("/admin/login", func(c context.Context, ctx *app.RequestContext) {
+		ctx.SetCookie("admin", "yes", 3600, "/admin", "", protocol.CookieSameSiteDefaultMode, false, false)
+	})
+
+	go engine.Run()
+	defer func() {
+		engine.Close()
+	}()
+	time.Sleep(time.Millisecond * 500)
+
+	c, _ := NewClient(
+		WithDialer(newMockDialerWithCustomFunc(opt.Network, opt.Addr, 1*time.Second, nil)),
+		WithCookieJar(nil),
+	)
+
+	// 重定向过程中的 Set-Cookie 用于后续跳转
+	status, body, err := c.Get(context.Background(), nil, "http://example.com/login")
+	assert.Nil(t, err)
+	assert.Equal(t, consts.StatusOK, status)
+	assert.Equal(t, "session=abc", string(body))
+
+	// 路径规则
+	_, _, err = c.Get(context.Background(), nil, "http://example.com/admin/login")
+	assert.Nil(t, err)
+	_, body, _ = c.Get(context.Background(), nil, "http://example.com/admin/secret")
+	assert.Equal(t, "admin=yes", string(body))
+	_, body, _ = c.Get(context.Background(), nil, "http://example.com/profile")
+	assert.Equal(t, "session=abc", string(body))
+
+	// 域规则
+	_, body, _ = c.Get(context.Background(), nil, "http://other.com/profile")
+	assert.Equal(t, "session=", string(body))
+
+	// 用户显式设置的 cookie 优先，且 jar 附加的 cookie 不残留在请求中
+	req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+	defer func() {
+		protocol.ReleaseRequest(req)
+		protocol.ReleaseResponse(resp)
+	}()
+	req.SetRequestURI("http://example.com/profile")
+	req.SetCookie("session", "mine")
+	assert.Nil(t, c.Do(context.Background(), req, resp))
+	assert.Equal(t, "session=mine", string(resp.Body()))
+
+	req.Reset()
+	req.SetRequestURI("http://example.com/profile")
+	assert.Nil(t, c.Do(context.Background(), req, resp))
+	assert.Equal(t, "session=abc", string(resp.Body()))
+	assert.Equal(t, 0, len(req.Header.Cookie("session")))
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// MultipartBuilder 是多部分表单请求体的构建器。
+//
+// 正文在发送时边读边写，文件内容不会全量缓冲到内存。
+type MultipartBuilder struct {
+	boundary string
+	parts    []*multipartPart
+	err      error
+}
+
+// 表单的一个部分
+type multipartPart struct {
+	name        string
+	filename    string
+	contentType string
+	value       string
+	reader      io.Reader
+	path        string
+	size        int64 // 内容大小，-1 表示未知
+}
+
+// NewMultipartRequest 返回一个多部分表单构建器，边界值自动生成。
+func NewMultipartRequest() *MultipartBuilder {
+	return &MultipartBuilder{boundary: multipart.NewWriter(nil).Boundary()}
+}
+
+// AddField 添加普通表单字段。
+func (b *MultipartBuilder) AddField(name, value string) *MultipartBuilder {
+	b.parts = append(b.parts, &multipartPart{name: name, value: value, size: int64(len(value))})
+	return b
+}
+
+// AddFile 添加从 r 读取内容的表单文件。
+//
+// 若 r 实现了 Len() int（如 *bytes.Reader），将用于计算 Content-Length；
+// 若 r 实现了 io.Closer，发送完成后将被关闭。
+func (b *MultipartBuilder) AddFile(name, filename string, r io.Reader) *MultipartBuilder {
+	size := int64(-1)
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = int64(l.Len())
+	}
+	b.parts = append(b.parts, &multipartPart{
+		name:        name,
+		filename:    filename,
+		contentType: fileContentType(filename),
+		reader:      r,
+		size:        size,
+	})
+	return b
+}
+
+// AddFileFromPath 添加本地文件 path 作为表单文件，文件在发送时才打开。
+func (b *MultipartBuilder) AddFileFromPath(name, path string) *MultipartBuilder {
+	fi, err := os.Stat(path)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return b
+	}
+	filename := filepath.Base(path)
+	b.parts = append(b.parts, &multipartPart{
+		name:        name,
+		filename:    filename,
+		contentType: fileContentType(filename),
+		path:        path,
+		size:        fi.Size(),
+	})
+	return b
+}
+
+// Boundary 返回表单边界值。
+func (b *MultipartBuilder) Boundary() string {
+	return b.boundary
+}
+
+// ContentType 返回带边界值的 Content-Type 标头值。
+func (b *MultipartBuilder) ContentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+// Build 将表单设为 req 的正文流并设置 Content-Type。
+//
+// 所有部分的大小均已知时设置 Content-Length，否则以分块编码发送。
+// 构建期间的错误（如 AddFileFromPath 的文件不存在）在此返回。
+func (b *MultipartBuilder) Build(req *protocol.Request) error {
+	if b.err != nil {
+		return b.err
+	}
+	req.SetBodyStream(&multipartBody{builder: b}, b.contentLength())
+	req.Header.Set(consts.HeaderContentType, b.ContentType())
+	return nil
+}
+
+// 计算正文总长度，任一部分大小未知时返回 -1。
+func (b *MultipartBuilder) contentLength() int {
+	var cw countingWriter
+	mw := b.newWriter(&cw)
+	var size int64
+	for _, p := range b.parts {
+		if p.size < 0 {
+			return -1
+		}
+		if _, err := mw.CreatePart(p.header()); err != nil {
+			return -1
+		}
+		size += p.size
+	}
+	if err := mw.Close(); err != nil {
+		return -1
+	}
+	return int(cw.n + size)
+}
+
+func (b *MultipartBuilder) newWriter(w io.Writer) *multipart.Writer {
+	mw := multipart.NewWriter(w)
+	_ = mw.SetBoundary(b.boundary)
+	return mw
+}
+
+func (b *MultipartBuilder) writeTo(w io.Writer) error {
+	mw := b.newWriter(w)
+	for _, p := range b.parts {
+		pw, err := mw.CreatePart(p.header())
+		if err != nil {
+			return err
+		}
+		if err = p.writeTo(pw); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func (p *multipartPart) header() textproto.MIMEHeader {
+	return protocol.CreateMultipartHeader(p.name, p.filename, p.contentType)
+}
+
+func (p *multipartPart) writeTo(w io.Writer) error {
+	switch {
+	case p.path != "":
+		f, err := os.Open(p.path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	case p.reader != nil:
+		if c, ok := p.reader.(io.Closer); ok {
+			defer c.Close()
+		}
+		_, err := io.Copy(w, p.reader)
+		return err
+	default:
+		_, err := io.WriteString(w, p.value)
+		return err
+	}
+}
+
+// multipartBody 是表单的正文流，首次读取时才开始写入。
+type multipartBody struct {
+	builder *MultipartBuilder
+	once    sync.Once
+	pr      *io.PipeReader
+}
+
+func (m *multipartBody) Read(p []byte) (int, error) {
+	m.once.Do(func() {
+		pr, pw := io.Pipe()
+		m.pr = pr
+		go func() {
+			pw.CloseWithError(m.builder.writeTo(pw))
+		}()
+	})
+	return m.pr.Read(p)
+}
+
+// Close 终止尚未完成的写入。
+func (m *multipartBody) Close() error {
+	m.once.Do(func() {})
+	if m.pr != nil {
+		return m.pr.Close()
+	}
+	return nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+func fileContentType(filename string) string {
+	if ct := mime.TypeByExtension(filepath.Ext(filename)); ct != "" {
+		return ct
+	}
+	return consts.MIMEApplicationOctetStream
+}
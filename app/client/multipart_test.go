@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipartBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	assert.Nil(t, os.WriteFile(path, []byte("from path"), 0o644))
+
+	b := NewMultipartRequest().
+		AddField("name", "wind").
+		AddFile("data", "b.bin", bytes.NewReader([]byte("from reader"))).
+		AddFileFromPath("doc", path)
+	req := protocol.AcquireRequest()
+	defer protocol.ReleaseRequest(req)
+	assert.Nil(t, b.Build(req))
+	assert.Equal(t, b.ContentType(), string(req.Header.ContentType()))
+
+	body, err := io.ReadAll(req.BodyStream())
+	assert.Nil(t, err)
+	assert.Equal(t, len(body), req.Header.ContentLength())
+
+	form, err := multipart.NewReader(bytes.NewReader(body), b.Boundary()).ReadForm(1 << 20)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"wind"}, form.Value["name"])
+	assert.Equal(t, "b.bin", form.File["data"][0].Filename)
+	assert.Equal(t, consts.MIMEApplicationOctetStream, form.File["data"][0].Header.Get(consts.HeaderContentType))
+	assert.Equal(t, "a.txt", form.File["doc"][0].Filename)
+	f, _ := form.File["doc"][0].Open()
+	content, _ := io.ReadAll(f)
+	assert.Equal(t, "from path", string(content))
+}
+
+func TestMultipartBuilderUnknownSize(t *testing.T) {
+	req := protocol.AcquireRequest()
+	defer protocol.ReleaseRequest(req)
+	b := NewMultipartRequest().AddFile("data", "c.txt", io.MultiReader(strings.NewReader("streaming")))
+	assert.Nil(t, b.Build(req))
+	assert.Equal(t, -1, req.Header.ContentLength())
+	body, err := io.ReadAll(req.BodyStream())
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), "streaming")
+
+	err = NewMultipartRequest().AddFileFromPath("doc", "not-exist").Build(req)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -2,9 +2,12 @@ package client
 
 import (
 	"crypto/tls"
+	"net/http"
+	"net/http/cookiejar"
 	"time"
 
 	"github.com/favbox/wind/app/client/retry"
+	"github.com/favbox/wind/app/client/trace"
 	"github.com/favbox/wind/common/config"
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/network/dialer"
@@ -37,6 +40,15 @@ func WithDialFunc(f network.DialFunc, dialers ...network.Dialer) config.ClientOp
 	}}
 }
 
+// WithUnixSocket 设置经由的 unix 域套接字路径，适用于通过套接字暴露 HTTP 的 sidecar 或本地服务。
+//
+// 设置后所有请求均拨号该套接字，Host 标头仍取自请求网址的主机部分。
+func WithUnixSocket(path string) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.UnixSocket = path
+	}}
+}
+
 // WithMaxConnsPerHost 设置每个主机可建立的最大连接数。默认值：512 个。
 func WithMaxConnsPerHost(mc int) config.ClientOption {
 	return config.ClientOption{F: func(o *config.ClientOptions) {
@@ -94,6 +106,16 @@ func WithResponseBodyStream(b bool) config.ClientOption {
 	}}
 }
 
+// WithRequestCompressed 设置是否自动请求压缩响应。默认值：false。
+//
+// 启用后，未设置 Accept-Encoding 的请求将自动添加 gzip, br 并透明解压 gzip 或 br 响应，
+// 用户自行设置 Accept-Encoding 时不覆盖也不解压。流式响应正文时不生效。
+func WithRequestCompressed(b bool) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.RequestCompressed = b
+	}}
+}
+
 // WithHostClientConfigHook 用于重配主机客户端的回调钩子。
 func WithHostClientConfigHook(h func(hc any) error) config.ClientOption {
 	return config.ClientOption{F: func(o *config.ClientOptions) {
@@ -181,3 +203,51 @@ func newCustomDialerWithDialFunc(dialer network.Dialer, dialFunc network.DialFun
 		dialFunc: dialFunc,
 	}
 }
+
+// WithConnStateChange 设置连接池状态变化的回调，按事件而非定时上报，适合对接 Prometheus 等指标系统。
+//
+// 回调在后台协程中按序调用，不会阻塞请求，但处理过慢时会丢弃积压的事件。
+func WithConnStateChange(f config.ConnStateChangeFunc) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.OnConnStateChange = f
+	}}
+}
+
+// WithTrace 设置请求各阶段的跟踪钩子，可用于分析拨号、握手、首字节等阶段的耗时。
+func WithTrace(t *trace.ClientTrace) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.Trace = t
+	}}
+}
+
+// WithChunkedBodyThreshold 设置长度未知的流式请求体的分块阈值。
+//
+// 不超过 threshold 字节的流缓冲后以 Content-Length 发送，超过则直接以 chunked 发送。
+func WithChunkedBodyThreshold(threshold int) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.ChunkedBodyThreshold = threshold
+	}}
+}
+
+// WithCookieJar 设置自动管理 cookie 的容器，jar 为空则使用内存容器。
+//
+// 容器遵循域、路径、过期及 Secure 规则，重定向过程中的 Set-Cookie 同样会被捕获。
+func WithCookieJar(jar http.CookieJar) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		if jar == nil {
+			jar, _ = cookiejar.New(nil)
+		}
+		o.CookieJar = jar
+	}}
+}
+
+// WithSingleflight 开启并发相同请求的合并，带有 excludeHeaders 中任一标头的请求不参与合并。
+//
+// 方法、网址、标头及请求体均相同的并发请求只发送一次，各调用者获得响应的独立副本；
+// 请求体为流的请求总是单独发送。
+func WithSingleflight(excludeHeaders ...string) config.ClientOption {
+	return config.ClientOption{F: func(o *config.ClientOptions) {
+		o.Singleflight = true
+		o.SingleflightExcludeHeaders = excludeHeaders
+	}}
+}
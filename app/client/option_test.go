@@ -29,6 +29,7 @@ func TestClientOptions(t *testing.T) {
 		),
 		WithWriteTimeout(time.Second),
 		WithConnStateObserve(nil, time.Second),
+		WithSingleflight("X-Request-Id"),
 	})
 	assert.Equal(t, 100*time.Millisecond, opt.DialTimeout)
 	assert.Equal(t, 128, opt.MaxConnsPerHost)
@@ -44,5 +45,7 @@ func TestClientOptions(t *testing.T) {
 	assert.Equal(t, 5*time.Second, opt.RetryConfig.MaxDelay)
 	assert.Equal(t, 1*time.Second, opt.RetryConfig.MaxJitter)
 	assert.Equal(t, 1*time.Second, opt.ObservationInterval)
+	assert.Equal(t, true, opt.Singleflight)
+	assert.Equal(t, []string{"X-Request-Id"}, opt.SingleflightExcludeHeaders)
 	assert.Equal(t, fmt.Sprint(retry.CombineDelay(retry.FixedDelayPolicy, retry.BackoffDelayPolicy, retry.RandomDelayPolicy)), fmt.Sprint(opt.RetryConfig.DelayPolicy))
 }
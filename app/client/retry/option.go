@@ -2,6 +2,20 @@ package retry
 
 import "time"
 
+// BackoffType 表示内置的退避类型。
+type BackoffType int
+
+const (
+	// BackoffNone 不使用内置退避，由 Config.DelayPolicy 决定延迟，为默认值。
+	BackoffNone BackoffType = iota
+	// BackoffFixed 固定退避，每次重试均延迟 Config.Delay。
+	BackoffFixed
+	// BackoffLinear 线性退避，第 n 次重试延迟 n 倍的 Config.Delay。
+	BackoffLinear
+	// BackoffExponential 指数退避，延迟同 BackoffDelayPolicy，随重试次数成倍增加。
+	BackoffExponential
+)
+
 // Option 用于设置重试选项的唯一结构体。
 type Option struct {
 	F func(o *Config)
@@ -24,6 +38,12 @@ type Config struct {
 	// 延迟策略，可组合使用多种策略。
 	// 例如 CombineDelay(BackOffDelayPolicy, RandomDelayPolicy) 或 BackOffDelayPolicy 等。
 	DelayPolicy DelayPolicyFunc
+
+	// 内置退避类型，非 BackoffNone 时优先于 DelayPolicy
+	Backoff BackoffType
+
+	// 抖动比例，取值 [0, 1]，延迟在 [d*(1-Jitter), d] 内随机，1 即 full jitter，默认不抖动
+	Jitter float64
 }
 
 func (o *Config) Apply(opts []Option) {
@@ -64,6 +84,22 @@ func WithMaxJitter(maxJitter time.Duration) Option {
 	}}
 }
 
+// WithBackoff 设置内置的退避类型，优先于 WithDelayPolicy 设置的延迟策略。
+func WithBackoff(backoff BackoffType) Option {
+	return Option{F: func(o *Config) {
+		o.Backoff = backoff
+	}}
+}
+
+// WithJitter 设置延迟的抖动比例，取值 [0, 1]，超出范围将被截断。
+//
+// 抖动在按 MaxDelay 裁剪之后施加，故最终延迟不会超过 MaxDelay。
+func WithJitter(jitter float64) Option {
+	return Option{F: func(o *Config) {
+		o.Jitter = jitter
+	}}
+}
+
 // WithDelayPolicy 设置重试的延迟策略。
 func WithDelayPolicy(delayPolicy DelayPolicyFunc) Option {
 	return Option{F: func(o *Config) {
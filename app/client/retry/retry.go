@@ -42,10 +42,26 @@ func BackoffDelayPolicy(attempts uint, _ error, retryConfig *Config) time.Durati
 	if attempts > max {
 		attempts = max
 	}
+	// 左移溢出时取最大值，以便再由 MaxDelay 裁剪
+	if int64(retryConfig.Delay) > math.MaxInt64>>attempts {
+		return time.Duration(math.MaxInt64)
+	}
 
 	return retryConfig.Delay << attempts
 }
 
+// LinearDelayPolicy 是一种线性延迟策略的 DelayPolicyFunc。
+// 第 n 次重试延迟 n 倍的 Config.Delay，若 Config.Delay <= 0，则进行零延迟。
+func LinearDelayPolicy(attempts uint, _ error, retryConfig *Config) time.Duration {
+	if retryConfig.Delay <= 0 {
+		return 0 * time.Millisecond
+	}
+	if attempts > uint(math.MaxInt64/int64(retryConfig.Delay)) {
+		return time.Duration(math.MaxInt64)
+	}
+	return retryConfig.Delay * time.Duration(attempts)
+}
+
 // CombineDelay 将多个重试策略函数组合为一个并返回。
 func CombineDelay(delays ...DelayPolicyFunc) DelayPolicyFunc {
 	const maxInt64 = uint64(math.MaxInt64)
@@ -63,15 +79,50 @@ func CombineDelay(delays ...DelayPolicyFunc) DelayPolicyFunc {
 	}
 }
 
-// Delay 生成指定重试配置的延迟时间。若 Config.DelayPolicy 为空，则零延迟。
+// Delay 生成指定重试配置的延迟时间。
+//
+// 优先按 Config.Backoff 计算，其次按 Config.DelayPolicy，都未设置则零延迟。
+// 结果先裁剪到 Config.MaxDelay，再按 Config.Jitter 施加抖动。
 func Delay(attempts uint, err error, retryConfig *Config) time.Duration {
-	if retryConfig.DelayPolicy == nil {
+	policy := backoffPolicy(retryConfig.Backoff)
+	if policy == nil {
+		policy = retryConfig.DelayPolicy
+	}
+	if policy == nil {
 		return 0 * time.Millisecond
 	}
 
-	delayTime := retryConfig.DelayPolicy(attempts, err, retryConfig)
+	delayTime := policy(attempts, err, retryConfig)
 	if retryConfig.MaxDelay > 0 && delayTime > retryConfig.MaxDelay {
 		delayTime = retryConfig.MaxDelay
 	}
-	return delayTime
+	return jitter(delayTime, retryConfig.Jitter)
+}
+
+// 返回退避类型对应的延迟策略，BackoffNone 或未知类型返回 nil。
+func backoffPolicy(backoff BackoffType) DelayPolicyFunc {
+	switch backoff {
+	case BackoffFixed:
+		return FixedDelayPolicy
+	case BackoffLinear:
+		return LinearDelayPolicy
+	case BackoffExponential:
+		return BackoffDelayPolicy
+	}
+	return nil
+}
+
+// 在 [d*(1-ratio), d] 内随机选取延迟，ratio 截断到 [0, 1]。
+func jitter(d time.Duration, ratio float64) time.Duration {
+	if d <= 0 || ratio <= 0 {
+		return d
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	span := int64(float64(d) * ratio)
+	if span <= 0 {
+		return d
+	}
+	return d - time.Duration(fastrand.Int63n(span+1))
 }
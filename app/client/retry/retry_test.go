@@ -73,3 +73,38 @@ func TestRetryPolicy(t *testing.T) {
 	dur = delayFunc(0, nil, &conf)
 	assert.Equal(t, time.Duration(math.MaxInt64), dur)
 }
+
+func TestDelayBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     Config
+		attempts uint
+		min, max time.Duration
+	}{
+		{"未设置", Config{Delay: time.Second}, 3, 0, 0},
+		{"固定", Config{Delay: time.Second, Backoff: BackoffFixed}, 3, time.Second, time.Second},
+		{"线性", Config{Delay: time.Second, Backoff: BackoffLinear}, 3, 3 * time.Second, 3 * time.Second},
+		{"指数", Config{Delay: time.Second, Backoff: BackoffExponential}, 3, 8 * time.Second, 8 * time.Second},
+		{"指数裁剪", Config{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: 5 * time.Second}, 10, 5 * time.Second, 5 * time.Second},
+		{"指数溢出裁剪", Config{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: time.Minute}, 100, time.Minute, time.Minute},
+		{"半抖动", Config{Delay: time.Second, Backoff: BackoffLinear, Jitter: 0.5}, 2, time.Second, 2 * time.Second},
+		{"全抖动", Config{Delay: time.Second, Backoff: BackoffExponential, MaxDelay: 4 * time.Second, Jitter: 1}, 5, 0, 4 * time.Second},
+		{"抖动截断", Config{Delay: time.Second, Backoff: BackoffFixed, Jitter: 2}, 1, 0, time.Second},
+		{"优先于延迟策略", Config{Delay: time.Second, Backoff: BackoffFixed, DelayPolicy: DefaultDelayPolicy}, 1, time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				d := Delay(tt.attempts, nil, &tt.conf)
+				assert.True(t, d >= tt.min && d <= tt.max, "延迟 %s 超出 [%s, %s]", d, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestLinearDelayPolicy(t *testing.T) {
+	conf := Config{Delay: 0}
+	assert.Equal(t, time.Duration(0), LinearDelayPolicy(3, nil, &conf))
+	conf.Delay = time.Duration(math.MaxInt64 / 2)
+	assert.Equal(t, time.Duration(math.MaxInt64), LinearDelayPolicy(3, nil, &conf))
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/client"
+)
+
+// singleflightKey 返回请求的合并键，请求不可合并时返回 false。
+//
+// 合并键由方法、完整网址、全部标头及请求体摘要组成；请求体为流或多部分表单的请求不可合并。
+func singleflightKey(req *protocol.Request, excludeHeaders []string) (string, bool) {
+	if req.IsBodyStream() || req.HasMultipartForm() ||
+		len(req.MultipartFiles()) > 0 || len(req.MultipartFields()) > 0 {
+		return "", false
+	}
+	for _, h := range excludeHeaders {
+		if len(req.Header.Peek(h)) > 0 {
+			return "", false
+		}
+	}
+
+	sum := sha256.Sum256(req.BodyBytes())
+	key := make([]byte, 0, 256)
+	key = append(key, req.Header.Method()...)
+	key = append(key, ' ')
+	key = append(key, req.URI().FullURI()...)
+	key = append(key, '\n')
+	req.Header.VisitAll(func(k, v []byte) {
+		key = append(key, k...)
+		key = append(key, ':')
+		key = append(key, v...)
+		key = append(key, '\n')
+	})
+	key = append(key, sum[:]...)
+	return string(key), true
+}
+
+// doSingleflight 合并 key 相同的并发请求，仅由首个调用者实际发送，响应复制给所有调用者。
+//
+// 共享的响应由首个调用者的 ctx 控制，其取消将使所有等待者得到同一错误。
+func (c *Client) doSingleflight(ctx context.Context, key string, hc client.HostClient, req *protocol.Request, resp *protocol.Response) error {
+	v, err, _ := c.sf.Do(key, func() (any, error) {
+		shared := &protocol.Response{}
+		if err := c.doHostClient(ctx, hc, req, shared); err != nil {
+			return nil, err
+		}
+		// 读完流式响应体并初始化 Trailer，使后续并发复制只读
+		if shared.IsBodyStream() {
+			if _, err := shared.BodyE(); err != nil {
+				return nil, err
+			}
+		}
+		shared.Header.Trailer()
+		return shared, nil
+	})
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		v.(*protocol.Response).CopyTo(resp)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientSingleflight(t *testing.T) {
+	opt := config.NewOptions([]config.Option{})
+	opt.Network = "unix"
+	opt.Addr = "unix-test-10023"
+	engine := route.NewEngine(opt)
+	var hits int32
+	engine.Any("/data", func(c context.Context, ctx *app.RequestContext) {
+		n := atomic.AddInt32(&hits, 1)
+		time.Sleep(100 * time.Millisecond)
+		ctx.String(consts.StatusOK, "hit-%d", n)
+	})
+	go engine.Run()
+	defer func() {
+		engine.Close()
+	}()
+	time.Sleep(time.Millisecond * 500)
+
+	c, _ := NewClient(
+		WithDialer(newMockDialerWithCustomFunc(opt.Network, opt.Addr, 1*time.Second, nil)),
+		WithSingleflight("X-Request-Id"),
+	)
+
+	doConcurrently := func(n int, build func(i int, req *protocol.Request)) []string {
+		var wg sync.WaitGroup
+		bodies := make([]string, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+				defer protocol.ReleaseRequest(req)
+				defer protocol.ReleaseResponse(resp)
+				req.SetRequestURI("http://example.com/data")
+				build(i, req)
+				assert.Nil(t, c.Do(context.Background(), req, resp))
+				bodies[i] = string(resp.Body())
+				// 各调用者拿到的是独立副本
+				resp.SetBodyString("changed")
+			}(i)
+		}
+		wg.Wait()
+		return bodies
+	}
+
+	// 相同请求只发送一次
+	bodies := doConcurrently(5, func(i int, req *protocol.Request) {})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	for _, body := range bodies {
+		assert.Equal(t, "hit-1", body)
+	}
+
+	// 请求体或标头不同的请求不合并
+	atomic.StoreInt32(&hits, 0)
+	doConcurrently(3, func(i int, req *protocol.Request) {
+		req.Header.SetMethod(consts.MethodPost)
+		req.SetBodyString(strconv.Itoa(i))
+	})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	atomic.StoreInt32(&hits, 0)
+	doConcurrently(3, func(i int, req *protocol.Request) {
+		req.Header.Set("Authorization", strconv.Itoa(i))
+	})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	// 带排除标头的请求总是单独发送
+	atomic.StoreInt32(&hits, 0)
+	doConcurrently(3, func(i int, req *protocol.Request) {
+		req.Header.Set("X-Request-Id", "same")
+	})
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
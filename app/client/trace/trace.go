@@ -0,0 +1,48 @@
+// Package trace 提供客户端请求各阶段的跟踪钩子，类似 net/http/httptrace。
+package trace
+
+import (
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// ClientTrace 是客户端请求各阶段的钩子集合。
+//
+// 所有钩子均可为空，at 为事件发生的时间。钩子在发起请求的协程中同步调用，应尽快返回。
+type ClientTrace struct {
+	// GetConn 在获取连接前调用，addr 为目标主机地址。
+	GetConn func(at time.Time, addr string)
+
+	// GotConn 在获取到连接后调用，reused 表示是否为连接池中复用的连接。
+	GotConn func(at time.Time, reused bool)
+
+	// DNSStart 在域名解析前调用。
+	//
+	// 设置 DNSStart 或 DNSDone 后，客户端将自行解析域名并依次拨号解析出的地址；
+	// 使用代理或地址已是 IP 时不解析。
+	DNSStart func(at time.Time, host string)
+
+	// DNSDone 在域名解析完成后调用。
+	DNSDone func(at time.Time, addrs []net.IPAddr, err error)
+
+	// ConnectStart 在开始拨号时调用，使用代理时 addr 为代理地址。
+	ConnectStart func(at time.Time, network, addr string)
+
+	// ConnectDone 在拨号完成后调用。
+	ConnectDone func(at time.Time, network, addr string, err error)
+
+	// TLSHandshakeStart 在 TLS 握手前调用。
+	//
+	// 设置 TLSHandshakeStart 或 TLSHandshakeDone 后，客户端会在拨号后立即握手，而非延迟到首次写入。
+	TLSHandshakeStart func(at time.Time)
+
+	// TLSHandshakeDone 在 TLS 握手完成后调用。
+	TLSHandshakeDone func(at time.Time, state tls.ConnectionState, err error)
+
+	// WroteRequest 在请求写入并刷新到连接后调用。
+	WroteRequest func(at time.Time, err error)
+
+	// GotFirstResponseByte 在读到响应的首个字节时调用。
+	GotFirstResponseByte func(at time.Time)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/favbox/wind/network"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// Protocol 返回请求所用的协议版本，如 HTTP/1.0、HTTP/1.1、HTTP/2 和 HTTP/3。
+func (ctx *RequestContext) Protocol() string {
+	switch p := ctx.Request.Header.GetProtocol(); p {
+	case consts.HTTP20:
+		return "HTTP/2"
+	case consts.HTTP30:
+		return "HTTP/3"
+	default:
+		return p
+	}
+}
+
+// TLSConnectionState 返回连接的 TLS 状态，非 TLS 连接则 ok 为 false。
+func (ctx *RequestContext) TLSConnectionState() (state tls.ConnectionState, ok bool) {
+	tlsConn, ok := ctx.conn.(network.ConnTLSer)
+	if !ok {
+		return state, false
+	}
+	return tlsConn.ConnectionState(), true
+}
+
+// TLSVersion 返回连接的 TLS 版本，如 TLS 1.3，非 TLS 连接返回空字符串。
+func (ctx *RequestContext) TLSVersion() string {
+	state, ok := ctx.TLSConnectionState()
+	if !ok || state.Version == 0 {
+		return ""
+	}
+	return tlsVersionName(state.Version)
+}
+
+// 返回 TLS 版本的名称，未知版本返回其十六进制表示，如 0x0305。
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// CipherSuite 返回连接协商的加密套件名称，如 TLS_AES_128_GCM_SHA256，非 TLS 连接返回空字符串。
+func (ctx *RequestContext) CipherSuite() string {
+	state, ok := ctx.TLSConnectionState()
+	if !ok || state.CipherSuite == 0 {
+		return ""
+	}
+	return tls.CipherSuiteName(state.CipherSuite)
+}
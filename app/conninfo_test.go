@@ -0,0 +1,50 @@
+package app
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTLSConn struct {
+	*mock.Conn
+	state tls.ConnectionState
+}
+
+func (c *mockTLSConn) Handshake() error { return nil }
+
+func (c *mockTLSConn) ConnectionState() tls.ConnectionState { return c.state }
+
+func TestProtocol(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.Request.Header.SetProtocol(consts.HTTP11)
+	assert.Equal(t, "HTTP/1.1", ctx.Protocol())
+	ctx.Request.Header.SetProtocol(consts.HTTP20)
+	assert.Equal(t, "HTTP/2", ctx.Protocol())
+	ctx.Request.Header.SetProtocol(consts.HTTP30)
+	assert.Equal(t, "HTTP/3", ctx.Protocol())
+}
+
+func TestTLSInfo(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.SetConn(mock.NewConn(""))
+	_, ok := ctx.TLSConnectionState()
+	assert.False(t, ok)
+	assert.Equal(t, "", ctx.TLSVersion())
+	assert.Equal(t, "", ctx.CipherSuite())
+
+	ctx.SetConn(&mockTLSConn{Conn: mock.NewConn(""), state: tls.ConnectionState{
+		Version:     tls.VersionTLS13,
+		CipherSuite: tls.TLS_AES_128_GCM_SHA256,
+	}})
+	_, ok = ctx.TLSConnectionState()
+	assert.True(t, ok)
+	assert.Equal(t, "TLS 1.3", ctx.TLSVersion())
+	assert.Equal(t, "TLS_AES_128_GCM_SHA256", ctx.CipherSuite())
+
+	assert.Equal(t, "TLS 1.2", tlsVersionName(tls.VersionTLS12))
+	assert.Equal(t, "0x0305", tlsVersionName(0x0305))
+}
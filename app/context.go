@@ -11,6 +11,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/favbox/wind/app/server/binding"
 	"github.com/favbox/wind/app/server/render"
@@ -64,17 +65,32 @@ type RequestContext struct {
 	// 通过自定义函数获取客户端 IP
 	clientIPFunc ClientIP
 
+	// SecureJSON 的数组前缀
+	secureJSONPrefix string
+
+	envelope  *Envelope // 统一响应格式
+	enveloped bool      // 响应是否已按统一格式包装
+
+	// 获取路径可用方法的函数
+	allowedMethodsFunc func(path string) []string
+
 	// 通过自定义函数获取表单值
 	formValueFunc FormValueFunc
 
 	binder    binding.Binder          // 请求参数绑定器
 	validator binding.StructValidator // 请求参数验证器
+
+	peekedBody       []byte             // PeekBody 物化的流式请求体
+	responseWrappers []ResponseWrapper  // 响应改写函数
+	cookies          []*protocol.Cookie // Cookies 解析出的请求 cookie，请求结束时释放
+
+	responseErrorHandlers []func(err error) // 响应写出失败的回调
 }
 
 // NewContext 创建一个指定最大路由参数个数的且不包含请求/响应信息的纯上下文。
 func NewContext(maxParams uint16) *RequestContext {
 	v := make(param.Params, 0, maxParams)
-	ctx := &RequestContext{Params: v, index: -1}
+	ctx := &RequestContext{Params: v, index: -1, secureJSONPrefix: render.DefaultSecureJSONPrefix}
 	return ctx
 }
 
@@ -171,7 +187,7 @@ func (ctx *RequestContext) FileFromFS(filepath string, fs *FS) {
 //
 // 当客户端下载该文件，将会以给定的 filename 重命名。
 func (ctx *RequestContext) FileAttachment(filepath, filename string) {
-	ctx.Response.Header.Set("content-disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	ctx.Response.Header.Set(consts.HeaderContentDisposition, contentDisposition("attachment", filename))
 	ServeFile(ctx, filepath)
 }
 
@@ -308,6 +324,26 @@ func (ctx *RequestContext) SetClientIPFunc(fn ClientIP) {
 	ctx.clientIPFunc = fn
 }
 
+// SetSecureJSONPrefix 设置 SecureJSON 的数组前缀。
+func (ctx *RequestContext) SetSecureJSONPrefix(prefix string) {
+	ctx.secureJSONPrefix = prefix
+}
+
+// SetAllowedMethodsFunc 设置获取路径可用方法的函数，通常由引擎设置。
+func (ctx *RequestContext) SetAllowedMethodsFunc(f func(path string) []string) {
+	ctx.allowedMethodsFunc = f
+}
+
+// GetAllowedMethods 返回路径 path 上已注册的所有方法，供 CORS 等中间件使用。
+//
+// 未关联引擎时返回 nil。
+func (ctx *RequestContext) GetAllowedMethods(path string) []string {
+	if ctx.allowedMethodsFunc == nil {
+		return nil
+	}
+	return ctx.allowedMethodsFunc(path)
+}
+
 // SetFormValueFunc 设置获取表单值的自定义函数。
 func (ctx *RequestContext) SetFormValueFunc(f FormValueFunc) {
 	ctx.formValueFunc = f
@@ -377,6 +413,46 @@ func (ctx *RequestContext) MultipartForm() (*multipart.Form, error) {
 	return ctx.Request.MultipartForm()
 }
 
+// StreamFormFile 将表单文件字段 name 的内容直接拷贝至 w，返回拷贝的字节数。
+//
+// 配合 StreamRequestBody 和 DisablePreParseMultipartForm 使用时边读边写，不缓冲整个文件，适用于大文件中转。
+// 流式读取会消费请求体，位于该文件之后的表单字段将无法再读取；
+// 表单已被解析时则从解析结果中拷贝。找不到文件时返回 errors.ErrMissingFile。
+func (ctx *RequestContext) StreamFormFile(name string, w io.Writer) (int64, error) {
+	if ctx.Request.HasMultipartForm() {
+		fh, err := ctx.FormFile(name)
+		if err != nil {
+			return 0, err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		return io.Copy(w, f)
+	}
+
+	mr, err := ctx.Request.MultipartReader()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return 0, errors.ErrMissingFile
+		}
+		if err != nil {
+			return 0, err
+		}
+		if part.FormName() == name && part.FileName() != "" {
+			n, err := io.Copy(w, part)
+			part.Close()
+			return n, err
+		}
+		part.Close()
+	}
+}
+
 // SaveUploadedFile 上传表单文件到指定位置。
 func (ctx *RequestContext) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
 	src, err := file.Open()
@@ -411,6 +487,11 @@ func (ctx *RequestContext) ResetWithoutConn() {
 	ctx.index = -1
 	ctx.fullPath = ""
 	ctx.Keys = nil
+	ctx.peekedBody = nil
+	ctx.responseWrappers = nil
+	ctx.responseErrorHandlers = nil
+	ctx.enveloped = false
+	ctx.releaseCookies()
 
 	if ctx.finished != nil {
 		close(ctx.finished)
@@ -556,6 +637,20 @@ func (ctx *RequestContext) FullPath() string {
 	return ctx.fullPath
 }
 
+// UnmatchedRoute 是未匹配到路由（如 404、405）时 MatchedRoute 返回的路径。
+const UnmatchedRoute = "unmatched"
+
+// MatchedRoute 返回用作指标标签的规范化路径。
+//
+// 匹配到路由时返回路由模板（同 FullPath），如 "/user/:id"；否则返回 UnmatchedRoute，
+// 以免实际请求路径导致标签基数爆炸。
+func (ctx *RequestContext) MatchedRoute() string {
+	if ctx.fullPath == "" {
+		return UnmatchedRoute
+	}
+	return ctx.fullPath
+}
+
 // Redirect 重定向网址。
 // 注意，Redirect 不会中断当前处理器的后续处理器，如需中断请显示中断。
 //
@@ -565,6 +660,22 @@ func (ctx *RequestContext) Redirect(statusCode int, uri []byte) {
 	ctx.redirect(uri, statusCode)
 }
 
+// RedirectAbsolute 基于当前请求的 scheme+host+path 将 uri 解析为绝对网址后重定向。
+//
+// 相对路径按 RFC 3986 解析，支持 "../"、"?query" 和 "#hash"；绝对网址则原样使用。
+// 适用于对相对 Location 处理不一致的代理或客户端。
+func (ctx *RequestContext) RedirectAbsolute(statusCode int, uri []byte) {
+	u := protocol.AcquireURI()
+	defer protocol.ReleaseURI(u)
+
+	ctx.URI().CopyTo(u)
+	if _, ok := ctx.conn.(network.ConnTLSer); ok {
+		u.SetSchemeBytes(bytestr.StrHTTPS)
+	}
+	u.UpdateBytes(uri)
+	ctx.redirect(u.FullURI(), statusCode)
+}
+
 func (ctx *RequestContext) redirect(uri []byte, statusCode int) {
 	ctx.Response.Header.SetCanonical(bytestr.StrLocation, uri)
 	statusCode = getRedirectStatusCode(statusCode)
@@ -593,6 +704,22 @@ func (ctx *RequestContext) Data(code int, contentType string, data []byte) {
 	})
 }
 
+// Inline 以 200 状态码写入数据，并设置 Content-Disposition 为 inline，提示浏览器内联显示。
+//
+// 非 ASCII 的文件名将按 RFC 6266 以 UTF-8 编码。
+func (ctx *RequestContext) Inline(contentType string, data []byte, filename string) {
+	ctx.Response.Header.Set(consts.HeaderContentDisposition, contentDisposition("inline", filename))
+	ctx.Data(consts.StatusOK, contentType, data)
+}
+
+// Download 以 200 状态码写入数据，并设置 Content-Disposition 为 attachment，提示浏览器下载保存。
+//
+// 非 ASCII 的文件名将按 RFC 6266 以 UTF-8 编码。
+func (ctx *RequestContext) Download(contentType string, data []byte, filename string) {
+	ctx.Response.Header.Set(consts.HeaderContentDisposition, contentDisposition("attachment", filename))
+	ctx.Data(consts.StatusOK, contentType, data)
+}
+
 // ProtoBuf 将给定的结构作为 protobuf 序列化到响应体中。
 func (ctx *RequestContext) ProtoBuf(code int, obj any) {
 	ctx.Render(code, render.ProtoBuf{Data: obj})
@@ -632,6 +759,50 @@ func (ctx *RequestContext) IndentedJSON(code int, obj any) {
 	ctx.Render(code, render.IndentedJSON{Data: obj})
 }
 
+// AsciiJSON 序列化给定的结构体以纯 ASCII 的 json 形式写入响应正文。
+//
+// 非 ASCII 字符会被转义为 \uXXXX，同时会更新状态码并将 Content-Type 自动设置为 "application/json"。
+func (ctx *RequestContext) AsciiJSON(code int, obj any) {
+	ctx.Render(code, render.AsciiJSON{Data: obj})
+}
+
+// SecureJSON 序列化给定的结构体以安全 json 形式写入响应正文。
+//
+// 顶层为数组时会在正文前写入前缀（默认 "while(1);"），防止响应被恶意页面当作脚本加载。
+// 前缀可通过 engine.SetSecureJSONPrefix 修改。
+func (ctx *RequestContext) SecureJSON(code int, obj any) {
+	ctx.Render(code, render.SecureJSON{Prefix: ctx.secureJSONPrefix, Data: obj})
+}
+
+// JSONP 序列化给定的结构体以 jsonp 形式写入响应正文。
+//
+// 回调名取自查询参数 callback，为空或含非法字符时退化为普通 JSON。
+// 同时会更新状态码并将 Content-Type 自动设置为 "application/javascript"。
+func (ctx *RequestContext) JSONP(code int, obj any) {
+	ctx.Render(code, render.JsonpJSON{Callback: ctx.Query("callback"), Data: obj})
+}
+
+// XML 序列化给定的结构体以 xml 形式写入响应正文。
+//
+// 同时会更新状态码并将 Content-Type 自动设置为 "application/xml"。
+func (ctx *RequestContext) XML(code int, obj any) {
+	ctx.Render(code, render.XML{Data: obj})
+}
+
+// YAML 序列化给定的结构体以 yaml 形式写入响应正文，需先经 render.ResetYAMLMarshal 设置编码函数。
+//
+// 同时会更新状态码并将 Content-Type 自动设置为 "application/yaml"。
+func (ctx *RequestContext) YAML(code int, obj any) {
+	ctx.Render(code, render.YAML{Data: obj})
+}
+
+// MsgPack 序列化给定的结构体以 MessagePack 形式写入响应正文。
+//
+// 同时会更新状态码并将 Content-Type 自动设置为 "application/msgpack"。
+func (ctx *RequestContext) MsgPack(code int, obj any) {
+	ctx.Render(code, render.MsgPack{Data: obj})
+}
+
 // Query 返回给定 key 的查询值，否则返回空白字符串 `""`。
 //
 // 示例：
@@ -666,6 +837,35 @@ func (ctx *RequestContext) GetQuery(key string) (string, bool) {
 	return ctx.QueryArgs().PeekExists(key)
 }
 
+// QueryArray 返回指定 key 的所有查询值，不存在时返回空切片。
+func (ctx *RequestContext) QueryArray(key string) []string {
+	values, _ := ctx.GetQueryArray(key)
+	return values
+}
+
+// GetQueryArray 返回指定 key 的所有查询值及其是否存在。
+//
+// 示例： GET /?ids=1&ids=2
+//   - ([]string{"1", "2"}, true) == c.GetQueryArray("ids")
+//   - ([]string{}, false) == c.GetQueryArray("id")
+func (ctx *RequestContext) GetQueryArray(key string) ([]string, bool) {
+	return argsArray(ctx.QueryArgs(), key)
+}
+
+// QueryMap 返回形如 key[subkey]=value 的查询参数映射，不存在时返回空映射。
+func (ctx *RequestContext) QueryMap(key string) map[string]string {
+	values, _ := ctx.GetQueryMap(key)
+	return values
+}
+
+// GetQueryMap 返回形如 key[subkey]=value 的查询参数映射及其是否存在。
+//
+// 示例： GET /?filter[a]=x&filter[b]=y
+//   - (map[string]string{"a": "x", "b": "y"}, true) == c.GetQueryMap("filter")
+func (ctx *RequestContext) GetQueryMap(key string) (map[string]string, bool) {
+	return argsMap(ctx.QueryArgs(), key)
+}
+
 // Param 返回指定 key 的 路由参数的值。
 // 它是 ctx.Params.ByName(key) 的快捷键。
 //
@@ -710,11 +910,55 @@ func (ctx *RequestContext) GetPostForm(key string) (string, bool) {
 	return ctx.multipartFormValue(key)
 }
 
+// PostFormArray 返回 POST 表单（urlencoded 或多部分表单）中指定 key 的所有值，不存在时返回空切片。
+func (ctx *RequestContext) PostFormArray(key string) []string {
+	values, _ := ctx.GetPostFormArray(key)
+	return values
+}
+
+// GetPostFormArray 返回 POST 表单中指定 key 的所有值及其是否存在。
+//
+// 优先取 urlencoded 表单，其次为多部分表单。
+func (ctx *RequestContext) GetPostFormArray(key string) ([]string, bool) {
+	if values, ok := argsArray(ctx.PostArgs(), key); ok {
+		return values, true
+	}
+	if values, ok := ctx.multipartFormValues(key); ok {
+		return append([]string(nil), values...), true
+	}
+	return []string{}, false
+}
+
+// PostFormMap 返回 POST 表单中形如 key[subkey]=value 的映射，不存在时返回空映射。
+func (ctx *RequestContext) PostFormMap(key string) map[string]string {
+	values, _ := ctx.GetPostFormMap(key)
+	return values
+}
+
+// GetPostFormMap 返回 POST 表单中形如 key[subkey]=value 的映射及其是否存在。
+//
+// 优先取 urlencoded 表单，其次为多部分表单。
+func (ctx *RequestContext) GetPostFormMap(key string) (map[string]string, bool) {
+	if values, ok := argsMap(ctx.PostArgs(), key); ok {
+		return values, true
+	}
+	return ctx.multipartFormMap(key)
+}
+
 // BindAndValidate 绑定上下文的请求数据到 obj 并按需验证。 注意：obj 应为一个指针。
 func (ctx *RequestContext) BindAndValidate(obj any) error {
 	return ctx.getBinder().BindAndValidate(&ctx.Request, obj, ctx.Params)
 }
 
+// BindAndValidateAll 绑定上下文的请求数据到 obj 并验证，绑定失败时以 binding.FieldErrors 返回所有字段错误。
+// 若绑定器未实现 binding.AllErrorsBinder，则等同于 BindAndValidate。注意：obj 应为一个指针。
+func (ctx *RequestContext) BindAndValidateAll(obj any) error {
+	if b, ok := ctx.getBinder().(binding.AllErrorsBinder); ok {
+		return b.BindAndValidateAll(&ctx.Request, obj, ctx.Params)
+	}
+	return ctx.BindAndValidate(obj)
+}
+
 // Bind 绑定上下文的请求数据到 obj。注意：obj 应为一个指针。
 func (ctx *RequestContext) Bind(obj any) error {
 	return ctx.getBinder().Bind(&ctx.Request, obj, ctx.Params)
@@ -759,13 +1003,40 @@ func (ctx *RequestContext) BindProtobuf(obj any) error {
 	return ctx.getBinder().BindProtobuf(&ctx.Request, obj)
 }
 
+// BindYAML 从上下文绑定 yaml 请求体到 obj，绑定器须实现 binding.YAMLBinder。
+// 注意：obj 应为一个指针。
+func (ctx *RequestContext) BindYAML(obj any) error {
+	b, ok := ctx.getBinder().(binding.YAMLBinder)
+	if !ok {
+		return fmt.Errorf("绑定器 %s 不支持 YAML", ctx.getBinder().Name())
+	}
+	return b.BindYAML(&ctx.Request, obj)
+}
+
+// BindMsgPack 从上下文绑定 MessagePack 请求体到 obj，绑定器须实现 binding.MsgPackBinder。
+// 注意：obj 应为一个指针。
+func (ctx *RequestContext) BindMsgPack(obj any) error {
+	b, ok := ctx.getBinder().(binding.MsgPackBinder)
+	if !ok {
+		return fmt.Errorf("绑定器 %s 不支持 MessagePack", ctx.getBinder().Name())
+	}
+	return b.BindMsgPack(&ctx.Request, obj)
+}
+
 // Validate  用 "vd" 标签验证 obj。
 // 注意：
 //
 //   - obj 应为一个指针。
 //   - 验证应在 Bind 之后再调用。
+//   - 验证器实现 binding.LocalizedValidator 时，按请求的 Accept-Language 渲染错误消息。
 func (ctx *RequestContext) Validate(obj any) error {
-	return ctx.getValidator().ValidateStruct(obj)
+	vd := ctx.getValidator()
+	if lv, ok := vd.(binding.LocalizedValidator); ok {
+		if langs := ctx.Request.Header.AcceptLanguages(); len(langs) > 0 {
+			return lv.ValidateStructLang(obj, langs...)
+		}
+	}
+	return vd.ValidateStruct(obj)
 }
 
 // RemoteAddr 返回当前请求的远程计算机的IP地址或域名。
@@ -1025,11 +1296,76 @@ func (ctx *RequestContext) ContentType() []byte {
 	return ctx.Request.Header.ContentType()
 }
 
+// IsJSON 报告请求是否为 JSON 内容类型，含 application/json 及 +json 后缀类型。
+func (ctx *RequestContext) IsJSON() bool {
+	mt := ctx.mediaType()
+	return strings.EqualFold(mt, consts.MIMEApplicationJSON) || hasSuffixFold(mt, "+json")
+}
+
+// IsXML 报告请求是否为 XML 内容类型，含 application/xml、text/xml 及 +xml 后缀类型。
+func (ctx *RequestContext) IsXML() bool {
+	mt := ctx.mediaType()
+	return strings.EqualFold(mt, consts.MIMEApplicationXML) || strings.EqualFold(mt, consts.MIMETextXML) || hasSuffixFold(mt, "+xml")
+}
+
+// IsForm 报告请求是否为 application/x-www-form-urlencoded 表单。
+func (ctx *RequestContext) IsForm() bool {
+	return strings.EqualFold(ctx.mediaType(), consts.MIMEApplicationHTMLForm)
+}
+
+// IsMultipart 报告请求是否为 multipart/form-data 表单。
+func (ctx *RequestContext) IsMultipart() bool {
+	return strings.EqualFold(ctx.mediaType(), consts.MIMEMultipartPOSTForm)
+}
+
+// 返回去除 charset 等参数后的请求媒体类型。
+func (ctx *RequestContext) mediaType() string {
+	ct := strings.TrimSpace(bytesconv.B2s(ctx.Request.Header.ContentType()))
+	return utils.FilterContentType(ct)
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
 // Cookie 返回请求头中给定 key 的 cookie 值。
 func (ctx *RequestContext) Cookie(key string) []byte {
 	return ctx.Request.Header.Cookie(key)
 }
 
+// Cookies 返回请求头中的所有 cookie。
+//
+// 返回的 cookie 取自对象池，在下次调用 Cookies 或请求结束前有效，切勿保留或自行释放。
+func (ctx *RequestContext) Cookies() []*protocol.Cookie {
+	ctx.releaseCookies()
+	ctx.Request.Header.VisitAllCookie(func(key, value []byte) {
+		c := protocol.AcquireCookie()
+		c.SetKeyBytes(key)
+		c.SetValueBytes(value)
+		ctx.cookies = append(ctx.cookies, c)
+	})
+	return ctx.cookies
+}
+
+func (ctx *RequestContext) releaseCookies() {
+	for i, c := range ctx.cookies {
+		protocol.ReleaseCookie(c)
+		ctx.cookies[i] = nil
+	}
+	ctx.cookies = ctx.cookies[:0]
+}
+
+// SetCookies 批量添加 Set-Cookie 响应头，忽略 nil。
+//
+// cookie 内容会被拷贝，调用后即可释放传入的 cookie。
+func (ctx *RequestContext) SetCookies(cookies ...*protocol.Cookie) {
+	for _, c := range cookies {
+		if c != nil {
+			ctx.Response.Header.SetCookie(c)
+		}
+	}
+}
+
 // SetCookie 添加一个 Set-Cookie 响应头。
 //
 //	参数包括：
@@ -1049,6 +1385,21 @@ func (ctx *RequestContext) Cookie(key string) []byte {
 //	4. ctx.SetCookie("user", "", 10, "/", "localhost", protocol.CookieSameSiteLaxMode, false, false)
 //	添加响应头 ---> Set-Cookie: user=; max-age=10; domain=localhost; path=/; SameSite=Lax;
 func (ctx *RequestContext) SetCookie(name, value string, maxAge int, path, domain string, sameSite protocol.CookieSameSite, secure, httpOnly bool) {
+	ctx.setCookie(name, value, maxAge, path, domain, sameSite, secure, httpOnly, false)
+}
+
+// SetCookiePartitioned 同 SetCookie，但添加带 Partitioned 属性的分区 Cookie，用于 Chrome 等浏览器的第三方 Cookie 分区（CHIPS）。
+//
+// 分区 Cookie 总是带有 Secure 属性。
+//
+//	例如：
+//	ctx.SetCookiePartitioned("user", "wind", 1, "/", "localhost", protocol.CookieSameSiteNoneMode, true)
+//	添加响应头 ---> Set-Cookie: user=wind; max-age=1; domain=localhost; path=/; HttpOnly; secure; SameSite=None; Partitioned
+func (ctx *RequestContext) SetCookiePartitioned(name, value string, maxAge int, path, domain string, sameSite protocol.CookieSameSite, httpOnly bool) {
+	ctx.setCookie(name, value, maxAge, path, domain, sameSite, true, httpOnly, true)
+}
+
+func (ctx *RequestContext) setCookie(name, value string, maxAge int, path, domain string, sameSite protocol.CookieSameSite, secure, httpOnly, partitioned bool) {
 	if path == "" {
 		path = "/"
 	}
@@ -1062,6 +1413,7 @@ func (ctx *RequestContext) SetCookie(name, value string, maxAge int, path, domai
 	cookie.SetSecure(secure)
 	cookie.SetHTTPOnly(httpOnly)
 	cookie.SetSameSite(sameSite)
+	cookie.SetPartitioned(partitioned)
 	ctx.Response.Header.SetCookie(cookie)
 }
 
@@ -1100,18 +1452,45 @@ func (ctx *RequestContext) Copy() *RequestContext {
 	cp.formValueFunc = ctx.formValueFunc
 	cp.binder = ctx.binder
 	cp.validator = ctx.validator
+	cp.secureJSONPrefix = ctx.secureJSONPrefix
+	cp.envelope = ctx.envelope
+	cp.enveloped = ctx.enveloped
+	cp.allowedMethodsFunc = ctx.allowedMethodsFunc
 	return cp
 }
 
 func (ctx *RequestContext) multipartFormValue(key string) (string, bool) {
+	if vv, ok := ctx.multipartFormValues(key); ok {
+		return vv[0], true
+	}
+	return "", false
+}
+
+// 返回多部分表单中指定 key 的所有值。
+func (ctx *RequestContext) multipartFormValues(key string) ([]string, bool) {
 	mf, err := ctx.MultipartForm()
 	if err == nil && mf.Value != nil {
 		vv := mf.Value[key]
 		if len(vv) > 0 {
-			return vv[0], true
+			return vv, true
 		}
 	}
-	return "", false
+	return nil, false
+}
+
+// 返回多部分表单中形如 key[subkey]=value 的映射。
+func (ctx *RequestContext) multipartFormMap(key string) (map[string]string, bool) {
+	values := make(map[string]string)
+	mf, err := ctx.MultipartForm()
+	if err != nil || mf.Value == nil {
+		return values, false
+	}
+	for k, vv := range mf.Value {
+		if subKey, ok := mapSubKey(k, key); ok && len(vv) > 0 {
+			values[subKey] = vv[0]
+		}
+	}
+	return values, len(values) > 0
 }
 
 // bodyAllowedForStatus 拷贝自 http.bodyAllowedForStatus，
@@ -1142,6 +1521,86 @@ func (ctx *RequestContext) getValidator() binding.StructValidator {
 	return binding.DefaultValidator()
 }
 
+// contentDisposition 生成给定类型和文件名的 Content-Disposition 标头值。
+//
+// ASCII 文件名使用 filename 参数的 RFC 6266 quoted-string，
+// 否则附加 ASCII 回退名并使用 filename* 参数的 RFC 5987 UTF-8 编码。
+func contentDisposition(dispositionType, filename string) string {
+	if filename == "" {
+		return dispositionType
+	}
+	if isASCII(filename) {
+		return dispositionType + "; filename=" + quotedString(filename)
+	}
+	return dispositionType + "; filename=" + quotedString(asciiFallback(filename)) +
+		"; filename*=UTF-8''" + encodeExtValue(filename)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf || isCTL(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCTL(c byte) bool {
+	return c < 0x20 || c == 0x7f
+}
+
+// asciiFallback 将文件名中的非 ASCII 字符及控制字符替换为下划线，用于不支持 filename* 的客户端。
+func asciiFallback(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf || r < 0x20 || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// quotedString 返回 s 的 HTTP quoted-string 形式，仅转义双引号和反斜杠。
+func quotedString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// encodeExtValue 按 RFC 5987 的 value-chars 编码 s，attr-char 以外的字节均百分号编码。
+func encodeExtValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(s) * 3)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+// isAttrChar 汇报 c 是否为 RFC 5987 的 attr-char。
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
+
 func getRedirectStatusCode(statusCode int) int {
 	if statusCode == consts.StatusMovedPermanently ||
 		statusCode == consts.StatusFound ||
@@ -1279,3 +1738,41 @@ func validateHeader(trustedCIDRs []*net.IPNet, header string) (clientIP string,
 	}
 	return "", false
 }
+
+// 返回参数中指定 key 的所有值。
+func argsArray(args *protocol.Args, key string) ([]string, bool) {
+	raw := args.PeekAll(key)
+	values := make([]string, len(raw))
+	for i, v := range raw {
+		values[i] = string(v)
+	}
+	return values, len(values) > 0
+}
+
+// 返回参数中形如 key[subkey]=value 的映射，同一 subkey 取首个值。
+func argsMap(args *protocol.Args, key string) (map[string]string, bool) {
+	values := make(map[string]string)
+	args.VisitAll(func(k, v []byte) {
+		subKey, ok := mapSubKey(bytesconv.B2s(k), key)
+		if !ok {
+			return
+		}
+		if _, ok = values[subKey]; !ok {
+			values[strings.Clone(subKey)] = string(v)
+		}
+	})
+	return values, len(values) > 0
+}
+
+// 解析形如 key[subkey] 的参数名，返回其中的 subkey。
+func mapSubKey(name, key string) (string, bool) {
+	if len(name) <= len(key)+2 || name[:len(key)] != key || name[len(key)] != '[' {
+		return "", false
+	}
+	rest := name[len(key)+1:]
+	j := strings.IndexByte(rest, ']')
+	if j < 1 {
+		return "", false
+	}
+	return rest[:j], true
+}
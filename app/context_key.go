@@ -0,0 +1,46 @@
+package app
+
+import "fmt"
+
+// ContextKey 是类型化的上下文键，在 ctx.Keys 中存取 T 类型的值，免去字符串键拼写和类型断言。
+//
+// 键以 name 存入 ctx.Keys，同名的字符串键或其他 ContextKey 会相互覆盖，应选用不易冲突的名称。
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey 返回名为 name 的类型化上下文键，通常定义为包级变量：
+//
+//	var UserKey = app.NewContextKey[User]("user")
+func NewContextKey[T any](name string) ContextKey[T] {
+	return ContextKey[T]{name: name}
+}
+
+// Name 返回键名。
+func (k ContextKey[T]) Name() string {
+	return k.name
+}
+
+// Set 将 v 存入上下文。
+func (k ContextKey[T]) Set(ctx *RequestContext, v T) {
+	ctx.Set(k.name, v)
+}
+
+// Get 返回上下文中的值，不存在或类型不符时 ok 为 false。
+func (k ContextKey[T]) Get(ctx *RequestContext) (v T, ok bool) {
+	val, exists := ctx.Get(k.name)
+	if !exists {
+		return v, false
+	}
+	v, ok = val.(T)
+	return v, ok
+}
+
+// MustGet 返回上下文中的值，不存在或类型不符时触发恐慌。
+func (k ContextKey[T]) MustGet(ctx *RequestContext) T {
+	v, ok := k.Get(ctx)
+	if !ok {
+		panic(fmt.Sprintf("上下文键 %q 不存在或类型不是 %T", k.name, v))
+	}
+	return v
+}
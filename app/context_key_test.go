@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextKey(t *testing.T) {
+	type User struct {
+		Name string
+	}
+	userKey := NewContextKey[User]("user")
+	tenantKey := NewContextKey[*string]("tenant")
+
+	ctx := NewContext(0)
+	_, ok := userKey.Get(ctx)
+	assert.False(t, ok)
+	assert.Panics(t, func() { userKey.MustGet(ctx) })
+
+	userKey.Set(ctx, User{Name: "wind"})
+	u, ok := userKey.Get(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "wind", u.Name)
+	assert.Equal(t, "wind", userKey.MustGet(ctx).Name)
+	assert.Equal(t, "user", userKey.Name())
+
+	tenant := "acme"
+	tenantKey.Set(ctx, &tenant)
+	assert.Equal(t, "acme", *tenantKey.MustGet(ctx))
+
+	// 同名键类型不符
+	ctx.Set("user", "not a user")
+	_, ok = userKey.Get(ctx)
+	assert.False(t, ok)
+}
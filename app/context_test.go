@@ -1,10 +1,13 @@
 package app
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net"
 	"reflect"
 	"strings"
@@ -225,6 +228,34 @@ func TestRequestContext_Redirect(t *testing.T) {
 	assert.Equal(t, consts.StatusMovedPermanently, ctx.Response.StatusCode())
 }
 
+func TestRequestContext_RedirectAbsolute(t *testing.T) {
+	cases := []struct {
+		uri, location string
+	}{
+		{"/hello?a=1", "http://example.com/hello?a=1"},
+		{"c?x=y", "http://example.com/a/b/c?x=y"},
+		{"../c", "http://example.com/a/c"},
+		{"?page=2", "http://example.com/a/b/index?page=2"},
+		{"//other.com/x", "http://other.com/x"},
+		{"https://other.com/x?y", "https://other.com/x?y"},
+	}
+	for _, c := range cases {
+		ctx := NewContext(0)
+		ctx.Request.SetRequestURI("/a/b/index?q=1")
+		ctx.Request.SetHost("example.com")
+		ctx.RedirectAbsolute(consts.StatusFound, []byte(c.uri))
+		assert.Equal(t, consts.StatusFound, ctx.Response.StatusCode())
+		assert.Equal(t, c.location, string(ctx.Response.Header.Peek(consts.HeaderLocation)), c.uri)
+	}
+
+	// 原请求 URI 不受影响
+	ctx := NewContext(0)
+	ctx.Request.SetRequestURI("/a/b")
+	ctx.Request.SetHost("example.com")
+	ctx.RedirectAbsolute(consts.StatusMovedPermanently, []byte("c"))
+	assert.Equal(t, "/a/b", string(ctx.URI().Path()))
+}
+
 func TestGetRedirectStatusCode(t *testing.T) {
 	val := getRedirectStatusCode(consts.StatusMovedPermanently)
 	assert.Equal(t, consts.StatusMovedPermanently, val)
@@ -331,6 +362,25 @@ func TestCopy(t *testing.T) {
 	}
 }
 
+func TestCopySettings(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.SetSecureJSONPrefix("for(;;);")
+	ctx.SetEnvelope(&Envelope{CodeKey: "status"})
+	ctx.SetAllowedMethodsFunc(func(path string) []string {
+		return []string{consts.MethodGet}
+	})
+
+	cp := ctx.Copy()
+	cp.SecureJSON(consts.StatusOK, []int{1})
+	assert.Equal(t, "for(;;);[1]", string(cp.Response.Body()))
+	assert.Equal(t, []string{consts.MethodGet}, cp.GetAllowedMethods("/"))
+
+	cp = ctx.Copy()
+	cp.OK(nil)
+	assert.Contains(t, string(cp.Response.Body()), `"status":0`)
+	assert.True(t, cp.Enveloped())
+}
+
 func TestQuery(t *testing.T) {
 	var r protocol.Request
 	ctx := NewContext(0)
@@ -412,6 +462,38 @@ hello=world`)
 	}
 }
 
+func TestPostFormArrayAndMap(t *testing.T) {
+	t.Parallel()
+
+	c := NewContext(0)
+	c.Request.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationHTMLForm))
+	c.Request.SetBodyString("ids=1&ids=2&user[name]=a&user[age]=3")
+	values, exists := c.GetPostFormArray("ids")
+	assert.Equal(t, []string{"1", "2"}, values)
+	assert.True(t, exists)
+	assert.Equal(t, map[string]string{"name": "a", "age": "3"}, c.PostFormMap("user"))
+	values, exists = c.GetPostFormArray("none")
+	assert.Equal(t, []string{}, values)
+	assert.False(t, exists)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	assert.Nil(t, w.WriteField("ids", "1"))
+	assert.Nil(t, w.WriteField("ids", "2"))
+	assert.Nil(t, w.WriteField("user[name]", "b"))
+	assert.Nil(t, w.Close())
+	c = NewContext(0)
+	c.Request.Header.SetContentTypeBytes([]byte(w.FormDataContentType()))
+	c.Request.SetBody(body.Bytes())
+	assert.Equal(t, []string{"1", "2"}, c.PostFormArray("ids"))
+	m, exists := c.GetPostFormMap("user")
+	assert.Equal(t, map[string]string{"name": "b"}, m)
+	assert.True(t, exists)
+	m, exists = c.GetPostFormMap("none")
+	assert.Equal(t, map[string]string{}, m)
+	assert.False(t, exists)
+}
+
 func TestDefaultPostForm(t *testing.T) {
 	ctx := makeCtxByReqString(t, `POST /upload HTTP/1.1
 Host: localhost:10000
@@ -442,6 +524,54 @@ Content-Type: application/octet-stream
 	assert.Equal(t, "no val", val)
 }
 
+func TestRequestContext_StreamFormFile(t *testing.T) {
+	t.Parallel()
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	mw.WriteField("f1", "value1")
+	fw, _ := mw.CreateFormFile("file", "a.txt")
+	fw.Write([]byte("file content"))
+	mw.Close()
+
+	newCtx := func(stream bool) *RequestContext {
+		ctx := NewContext(0)
+		ctx.Request.Header.SetMethod(consts.MethodPost)
+		ctx.Request.Header.SetContentTypeBytes([]byte(mw.FormDataContentType()))
+		if stream {
+			ctx.Request.SetBodyStream(bytes.NewReader(body.Bytes()), body.Len())
+		} else {
+			ctx.Request.SetBody(body.Bytes())
+		}
+		return ctx
+	}
+
+	for _, stream := range []bool{false, true} {
+		dst := &bytes.Buffer{}
+		n, err := newCtx(stream).StreamFormFile("file", dst)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(12), n)
+		assert.Equal(t, "file content", dst.String())
+	}
+
+	// 普通字段不视为文件
+	_, err := newCtx(true).StreamFormFile("f1", io.Discard)
+	assert.True(t, errors.Is(err, errs.ErrMissingFile))
+
+	// 已解析的表单
+	ctx := newCtx(false)
+	_, err = ctx.MultipartForm()
+	assert.Nil(t, err)
+	defer ctx.Request.RemoveMultipartFormFiles()
+	dst := &bytes.Buffer{}
+	_, err = ctx.StreamFormFile("file", dst)
+	assert.Nil(t, err)
+	assert.Equal(t, "file content", dst.String())
+
+	_, err = NewContext(0).StreamFormFile("file", dst)
+	assert.True(t, errors.Is(err, errs.ErrNoMultipartForm))
+}
+
 func TestRequestContext_FormFile(t *testing.T) {
 	t.Parallel()
 
@@ -583,6 +713,37 @@ func TestContextRenderAttachment(t *testing.T) {
 		string(ctx.Response.Header.Peek("Content-Disposition")))
 }
 
+func TestInlineAndDownload(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.Inline(consts.MIMEImagePNG, []byte("png"), "a.png")
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, consts.MIMEImagePNG, string(ctx.Response.Header.ContentType()))
+	assert.Equal(t, "png", string(ctx.Response.Body()))
+	assert.Equal(t, `inline; filename="a.png"`, string(ctx.Response.Header.Peek(consts.HeaderContentDisposition)))
+
+	ctx = NewContext(0)
+	ctx.Download(consts.MIMEApplicationPdf, []byte("pdf"), "报告 2024.pdf")
+	assert.Equal(t, "pdf", string(ctx.Response.Body()))
+	assert.Equal(t, `attachment; filename="__ 2024.pdf"; filename*=UTF-8''%E6%8A%A5%E5%91%8A%202024.pdf`,
+		string(ctx.Response.Header.Peek(consts.HeaderContentDisposition)))
+
+	ctx = NewContext(0)
+	ctx.Download(consts.MIMEApplicationOctetStream, nil, "")
+	assert.Equal(t, "attachment", string(ctx.Response.Header.Peek(consts.HeaderContentDisposition)))
+}
+
+func TestContentDisposition(t *testing.T) {
+	// quoted-string 仅转义双引号和反斜杠
+	assert.Equal(t, `attachment; filename="a\"b\\c'd.txt"`, contentDisposition("attachment", `a"b\c'd.txt`))
+	// 控制字符不进入 quoted-string，改由 filename* 编码
+	assert.Equal(t, `attachment; filename="a_b"; filename*=UTF-8''a%7Fb`, contentDisposition("attachment", "a\x7fb"))
+	// attr-char 以外的字节均百分号编码，包括 URL 路径中允许的 '、(、)、@ 等
+	assert.Equal(t, `inline; filename="_'(1)@.txt"; filename*=UTF-8''%C3%A9%27%281%29%40.txt`,
+		contentDisposition("inline", "é'(1)@.txt"))
+	assert.Equal(t, `inline; filename="_!#$&+-.^_`+"`"+`|~"; filename*=UTF-8''%E2%82%AC!#$&+-.^_`+"`"+`|~`,
+		contentDisposition("inline", "€!#$&+-.^_`|~"))
+}
+
 func TestRequestContext_Header(t *testing.T) {
 	c := NewContext(0)
 
@@ -717,6 +878,65 @@ func TestJSON(t *testing.T) {
 	assert.True(t, strings.Contains(string(c.Response.Body()), "test"))
 }
 
+func TestJSONP(t *testing.T) {
+	c := NewContext(0)
+	c.Request.SetRequestURI("/?callback=handle")
+	c.JSONP(consts.StatusOK, map[string]string{"k": "v"})
+	assert.Equal(t, "application/javascript; charset=utf-8", string(c.Response.Header.ContentType()))
+	assert.Equal(t, `handle({"k":"v"});`, string(c.Response.Body()))
+
+	c = NewContext(0)
+	c.Request.SetRequestURI("/?callback=%3Cscript%3E")
+	c.JSONP(consts.StatusOK, map[string]string{"k": "v"})
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(c.Response.Header.ContentType()))
+	assert.Equal(t, `{"k":"v"}`, string(c.Response.Body()))
+}
+
+func TestAsciiJSON(t *testing.T) {
+	c := NewContext(0)
+	c.AsciiJSON(consts.StatusOK, map[string]any{"lang": "GO语言", "tag": "<br>", "emoji": "😀"})
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(c.Response.Header.ContentType()))
+	assert.Equal(t, `{"emoji":"\ud83d\ude00","lang":"GO\u8bed\u8a00","tag":"\u003cbr\u003e"}`, string(c.Response.Body()))
+}
+
+func TestSecureJSON(t *testing.T) {
+	c := NewContext(0)
+	c.SecureJSON(consts.StatusOK, []string{"a", "b"})
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(c.Response.Header.ContentType()))
+	assert.Equal(t, `while(1);["a","b"]`, string(c.Response.Body()))
+
+	c = NewContext(0)
+	c.SecureJSON(consts.StatusOK, map[string]string{"k": "v"})
+	assert.Equal(t, `{"k":"v"}`, string(c.Response.Body()))
+
+	c = NewContext(0)
+	c.SetSecureJSONPrefix(")]}',\n")
+	c.SecureJSON(consts.StatusOK, []int{1})
+	assert.Equal(t, ")]}',\n[1]", string(c.Response.Body()))
+}
+
+func TestXML(t *testing.T) {
+	type item struct {
+		XMLName xml.Name `xml:"item"`
+		ID      int      `xml:"id,attr"`
+		Name    string   `xml:"name"`
+	}
+	c := NewContext(0)
+	c.XML(consts.StatusCreated, item{ID: 1, Name: "<wind>"})
+	assert.Equal(t, consts.StatusCreated, c.Response.StatusCode())
+	assert.Equal(t, consts.MIMEApplicationXMLUTF8, string(c.Response.Header.ContentType()))
+	assert.Equal(t, `<item id="1"><name>&lt;wind&gt;</name></item>`, string(c.Response.Body()))
+
+	var got item
+	assert.Nil(t, xml.Unmarshal(c.Response.Body(), &got))
+	assert.Equal(t, 1, got.ID)
+	assert.Equal(t, "<wind>", got.Name)
+
+	// 序列化失败时与 JSON 一致地 panic
+	c = NewContext(0)
+	assert.Panics(t, func() { c.XML(consts.StatusOK, make(chan int)) })
+}
+
 func TestContextReset(t *testing.T) {
 	c := NewContext(0)
 
@@ -750,6 +970,35 @@ func TestGetQuery(t *testing.T) {
 	assert.Equal(t, true, exists)
 }
 
+func TestQueryArray(t *testing.T) {
+	c := NewContext(0)
+	c.Request.SetRequestURI("http://aaa.com?ids=1&ids=2&name=a&empty=")
+	values, exists := c.GetQueryArray("ids")
+	assert.Equal(t, []string{"1", "2"}, values)
+	assert.True(t, exists)
+	assert.Equal(t, []string{"a"}, c.QueryArray("name"))
+	assert.Equal(t, []string{""}, c.QueryArray("empty"))
+
+	values, exists = c.GetQueryArray("none")
+	assert.NotNil(t, values)
+	assert.Empty(t, values)
+	assert.False(t, exists)
+}
+
+func TestQueryMap(t *testing.T) {
+	c := NewContext(0)
+	c.Request.SetRequestURI("http://aaa.com?filter[a]=x&filter[b]=y&filter[a]=z&filter[]=n&filterx[c]=w&filter=v&f[d][e]=1")
+	values, exists := c.GetQueryMap("filter")
+	assert.Equal(t, map[string]string{"a": "x", "b": "y"}, values)
+	assert.True(t, exists)
+	assert.Equal(t, map[string]string{"d": "1"}, c.QueryMap("f"))
+
+	values, exists = c.GetQueryMap("none")
+	assert.NotNil(t, values)
+	assert.Empty(t, values)
+	assert.False(t, exists)
+}
+
 func TestGetPostForm(t *testing.T) {
 	c := NewContext(0)
 	c.Request.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationHTMLForm))
@@ -1151,6 +1400,13 @@ func TestFullPath(t *testing.T) {
 	assert.Equal(t, str, val)
 }
 
+func TestMatchedRoute(t *testing.T) {
+	ctx := NewContext(0)
+	assert.Equal(t, UnmatchedRoute, ctx.MatchedRoute())
+	ctx.SetFullPath("/user/:id")
+	assert.Equal(t, "/user/:id", ctx.MatchedRoute())
+}
+
 func TestReset(t *testing.T) {
 	ctx := NewContext(0)
 	ctx.Reset()
@@ -1240,6 +1496,23 @@ func TestBindAndValidate(t *testing.T) {
 	}
 }
 
+func TestValidateLocalized(t *testing.T) {
+	type Test struct {
+		B int `query:"b" vd:"$>10; msg:'too_small'"`
+	}
+
+	vc := binding.NewValidateConfig()
+	vc.RegValidateMessage("zh", "B", "too_small", "{field} 必须大于 10")
+	c := &RequestContext{}
+	c.SetValidator(binding.NewValidator(vc))
+	c.Request.Header.Set(consts.HeaderAcceptLanguage, "zh-CN,zh;q=0.9")
+
+	assert.EqualError(t, c.Validate(&Test{B: 9}), "B 必须大于 10")
+
+	c.Request.Header.Set(consts.HeaderAcceptLanguage, "en")
+	assert.EqualError(t, c.Validate(&Test{B: 9}), "too_small")
+}
+
 func TestBindForm(t *testing.T) {
 	type Test struct {
 		A string
@@ -1330,6 +1603,83 @@ func TestRequestContext_SetCookie(t *testing.T) {
 	assert.Equal(t, "user=wind; max-age=1; domain=localhost; path=/; HttpOnly; secure; SameSite=Lax", c.Response.Header.Get("Set-Cookie"))
 }
 
+func TestRequestContext_IsContentType(t *testing.T) {
+	cases := []struct {
+		ct                                 string
+		isJSON, isXML, isForm, isMultipart bool
+	}{
+		{"application/json", true, false, false, false},
+		{"Application/JSON; charset=utf-8", true, false, false, false},
+		{"application/problem+json", true, false, false, false},
+		{"application/xml;charset=utf-8", false, true, false, false},
+		{"text/xml", false, true, false, false},
+		{"application/atom+xml", false, true, false, false},
+		{"application/x-www-form-urlencoded; charset=UTF-8", false, false, true, false},
+		{"multipart/form-data; boundary=foo", false, false, false, true},
+		{" application/json", true, false, false, false},
+		{"application/jsonp", false, false, false, false},
+		{"", false, false, false, false},
+	}
+	for _, c := range cases {
+		ctx := NewContext(0)
+		ctx.Request.Header.SetContentTypeBytes([]byte(c.ct))
+		assert.Equal(t, c.isJSON, ctx.IsJSON(), c.ct)
+		assert.Equal(t, c.isXML, ctx.IsXML(), c.ct)
+		assert.Equal(t, c.isForm, ctx.IsForm(), c.ct)
+		assert.Equal(t, c.isMultipart, ctx.IsMultipart(), c.ct)
+	}
+}
+
+func TestRequestContext_Cookies(t *testing.T) {
+	c := NewContext(0)
+	c.Request.Header.Set(consts.HeaderCookie, "a=1; b=2")
+	cookies := c.Cookies()
+	assert.Equal(t, 2, len(cookies))
+	assert.Equal(t, "a", string(cookies[0].Key()))
+	assert.Equal(t, "1", string(cookies[0].Value()))
+	assert.Equal(t, "b", string(cookies[1].Key()))
+	assert.Equal(t, "2", string(cookies[1].Value()))
+
+	// 重复调用复用切片
+	assert.Equal(t, 2, len(c.Cookies()))
+
+	c.Reset()
+	assert.Empty(t, c.cookies)
+	assert.Empty(t, c.Cookies())
+}
+
+func TestRequestContext_SetCookies(t *testing.T) {
+	c := NewContext(0)
+	a := protocol.AcquireCookie()
+	a.SetKey("a")
+	a.SetValue("1")
+	b := protocol.AcquireCookie()
+	b.SetKey("b")
+	b.SetValue("2")
+	b.SetHTTPOnly(true)
+	c.SetCookies(a, nil, b)
+	protocol.ReleaseCookie(a)
+	protocol.ReleaseCookie(b)
+
+	var got []string
+	c.Response.Header.VisitAllCookie(func(key, value []byte) {
+		got = append(got, string(value))
+	})
+	assert.Equal(t, []string{"a=1", "b=2; HttpOnly"}, got)
+}
+
+func TestRequestContext_SetCookiePartitioned(t *testing.T) {
+	c := NewContext(0)
+	c.SetCookiePartitioned("user", "wind", 1, "/", "localhost", protocol.CookieSameSiteNoneMode, true)
+	assert.Equal(t, "user=wind; max-age=1; domain=localhost; path=/; HttpOnly; secure; SameSite=None; Partitioned", c.Response.Header.Get("Set-Cookie"))
+
+	cookie := protocol.AcquireCookie()
+	defer protocol.ReleaseCookie(cookie)
+	cookie.SetKey("user")
+	assert.True(t, c.Response.Header.Cookie(cookie))
+	assert.True(t, cookie.Partitioned())
+}
+
 func TestRequestContext_SetCookiePathEmpty(t *testing.T) {
 	c := NewContext(0)
 	c.SetCookie("user", "wind", 1, "", "localhost", protocol.CookieSameSiteDisabled, true, true)
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// WriteEarlyHints 在最终响应前发送 103 Early Hints 中间响应，
+// 每个 link 写为一个 Link 标头，如 `</app.css>; rel=preload; as=style`。
+//
+// 发送后处理器继续正常生成最终响应，可多次调用。
+// 仅 HTTP/1.1 请求会发送中间响应，其他协议版本直接忽略；响应已开始写出时返回 errors.ErrResponseCommitted。
+// link 含 CR、LF 等控制字符时返回错误且不发送，以防标头注入与响应拆分。
+func (ctx *RequestContext) WriteEarlyHints(links []string) error {
+	if len(links) == 0 || !ctx.Request.Header.IsHTTP11() {
+		return nil
+	}
+	for _, link := range links {
+		if hasControlByte(link) {
+			return fmt.Errorf("无效的 Link 标头值 %q：不得包含控制字符", link)
+		}
+	}
+	if ctx.Hijacked() {
+		return errors.ErrHijacked
+	}
+	if ctx.Response.GetHijackWriter() != nil {
+		return errors.ErrResponseCommitted
+	}
+	w := ctx.GetWriter()
+	if w == nil {
+		return errors.ErrConnectionClosed
+	}
+
+	buf := append([]byte(nil), consts.StatusLine(consts.StatusEarlyHints)...)
+	for _, link := range links {
+		buf = append(buf, consts.HeaderLink...)
+		buf = append(buf, ": "...)
+		buf = append(buf, link...)
+		buf = append(buf, "\r\n"...)
+	}
+	buf = append(buf, "\r\n"...)
+	if _, err := w.WriteBinary(buf); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// 汇报 s 是否包含除水平制表符外的控制字符。
+func hasControlByte(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < 0x20 && c != '\t') || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteEarlyHints(t *testing.T) {
+	conn := mock.NewConn("")
+	ctx := NewContext(0)
+	ctx.SetConn(conn)
+	ctx.Request.Header.SetProtocol(consts.HTTP11)
+	assert.Nil(t, ctx.WriteEarlyHints([]string{"</a.css>; rel=preload; as=style", "<https://cdn.example.com>; rel=preconnect"}))
+
+	recorder := conn.WriterRecorder()
+	out, _ := recorder.Peek(recorder.WroteLen())
+	assert.Equal(t, "HTTP/1.1 103 Early Hints\r\n"+
+		"Link: </a.css>; rel=preload; as=style\r\n"+
+		"Link: <https://cdn.example.com>; rel=preconnect\r\n\r\n", string(out))
+
+	// HTTP/1.0 不发送
+	conn = mock.NewConn("")
+	ctx.SetConn(conn)
+	ctx.Request.Header.SetProtocol(consts.HTTP10)
+	assert.Nil(t, ctx.WriteEarlyHints([]string{"</a.css>; rel=preload"}))
+	assert.Equal(t, 0, conn.WriterRecorder().WroteLen())
+
+	// 含控制字符的 link 被拒绝且不写出任何内容
+	conn = mock.NewConn("")
+	ctx.SetConn(conn)
+	ctx.Request.Header.SetProtocol(consts.HTTP11)
+	for _, link := range []string{"</a.css>\r\nSet-Cookie: a=b", "</a.css>\n", "</a.css>\x00"} {
+		assert.NotNil(t, ctx.WriteEarlyHints([]string{"</b.css>; rel=preload", link}))
+	}
+	assert.Equal(t, 0, conn.WriterRecorder().WroteLen())
+
+	// 响应已开始写出
+	ctx.Request.Header.SetProtocol(consts.HTTP11)
+	ctx.Response.HijackWriter(&mockHijackWriter{})
+	assert.Equal(t, errors.ErrResponseCommitted, ctx.WriteEarlyHints([]string{"</a.css>; rel=preload"}))
+}
+
+type mockHijackWriter struct{}
+
+func (m *mockHijackWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func (m *mockHijackWriter) Flush() error { return nil }
+
+func (m *mockHijackWriter) Finalize() error { return nil }
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"github.com/favbox/wind/app/server/render"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+const (
+	defaultEnvelopeCodeKey = "code"
+	defaultEnvelopeDataKey = "data"
+	defaultEnvelopeMsgKey  = "msg"
+	defaultEnvelopeOKMsg   = "ok"
+)
+
+// Envelope 是 OK、Fail 等方法输出的统一响应格式配置，零值字段使用默认值。
+//
+// 默认格式为 {"code":0,"data":...,"msg":"ok"}。
+type Envelope struct {
+	CodeKey    string // 业务码字段名，默认 code
+	DataKey    string // 数据字段名，默认 data
+	MsgKey     string // 消息字段名，默认 msg
+	OKCode     int    // 成功时的业务码，默认 0
+	OKMsg      string // 成功时的消息，默认 ok
+	FailStatus int    // 失败时的 HTTP 状态码，默认 200
+
+	// Wrap 自定义包装结构，非 nil 时忽略上述字段名。
+	Wrap func(code int, msg string, data any) any
+}
+
+// 按配置包装响应。
+func (e *Envelope) wrap(code int, msg string, data any) any {
+	if e == nil {
+		e = &Envelope{}
+	}
+	if e.Wrap != nil {
+		return e.Wrap(code, msg, data)
+	}
+	return map[string]any{
+		stringOr(e.CodeKey, defaultEnvelopeCodeKey): code,
+		stringOr(e.DataKey, defaultEnvelopeDataKey): data,
+		stringOr(e.MsgKey, defaultEnvelopeMsgKey):   msg,
+	}
+}
+
+// SetEnvelope 设置统一响应格式，nil 表示使用默认格式。
+//
+// 通常由 engine.SetEnvelope 统一设置。
+func (ctx *RequestContext) SetEnvelope(e *Envelope) {
+	ctx.envelope = e
+}
+
+// OK 以统一响应格式输出成功结果，HTTP 状态码为 200。
+func (ctx *RequestContext) OK(data any) {
+	e := ctx.envelope
+	msg, code := defaultEnvelopeOKMsg, 0
+	if e != nil {
+		msg, code = stringOr(e.OKMsg, defaultEnvelopeOKMsg), e.OKCode
+	}
+	ctx.renderEnvelope(consts.StatusOK, code, msg, data)
+}
+
+// Fail 以统一响应格式输出失败结果，code 为业务码。
+func (ctx *RequestContext) Fail(code int, msg string) {
+	ctx.FailWithData(code, msg, nil)
+}
+
+// FailWithData 同 Fail，但附带数据。
+func (ctx *RequestContext) FailWithData(code int, msg string, data any) {
+	status := consts.StatusOK
+	if ctx.envelope != nil && ctx.envelope.FailStatus > 0 {
+		status = ctx.envelope.FailStatus
+	}
+	ctx.renderEnvelope(status, code, msg, data)
+}
+
+// Enveloped 报告响应是否已由 OK、Fail 等方法包装。
+//
+// 统一处理错误的中间件应据此跳过已包装的响应，避免双重包装。
+func (ctx *RequestContext) Enveloped() bool {
+	return ctx.enveloped
+}
+
+// 输出包装后的响应，重复调用时以最后一次为准。
+func (ctx *RequestContext) renderEnvelope(status, code int, msg string, data any) {
+	if ctx.enveloped {
+		ctx.Response.ResetBody()
+	}
+	ctx.enveloped = true
+	ctx.Render(status, render.JSONRender{Data: ctx.envelope.wrap(code, msg, data)})
+}
+
+func stringOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelope(t *testing.T) {
+	c := NewContext(0)
+	assert.False(t, c.Enveloped())
+	c.OK(map[string]int{"id": 1})
+	assert.True(t, c.Enveloped())
+	assert.Equal(t, consts.StatusOK, c.Response.StatusCode())
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(c.Response.Header.ContentType()))
+	assert.Equal(t, `{"code":0,"data":{"id":1},"msg":"ok"}`, string(c.Response.Body()))
+
+	// 重复包装时以最后一次为准
+	c.Fail(1001, "参数错误")
+	assert.Equal(t, `{"code":1001,"data":null,"msg":"参数错误"}`, string(c.Response.Body()))
+
+	c.Reset()
+	assert.False(t, c.Enveloped())
+
+	c = NewContext(0)
+	c.SetEnvelope(&Envelope{CodeKey: "errno", MsgKey: "message", DataKey: "result", OKMsg: "success", FailStatus: consts.StatusBadRequest})
+	c.OK("hi")
+	assert.Equal(t, `{"errno":0,"message":"success","result":"hi"}`, string(c.Response.Body()))
+
+	c = NewContext(0)
+	c.SetEnvelope(&Envelope{CodeKey: "errno", FailStatus: consts.StatusBadRequest})
+	c.FailWithData(2, "bad", []int{1})
+	assert.Equal(t, consts.StatusBadRequest, c.Response.StatusCode())
+	assert.Equal(t, `{"data":[1],"errno":2,"msg":"bad"}`, string(c.Response.Body()))
+
+	c = NewContext(0)
+	c.SetEnvelope(&Envelope{Wrap: func(code int, msg string, data any) any {
+		return map[string]any{"success": code == 0, "payload": data}
+	}})
+	c.OK(1)
+	assert.Equal(t, `{"payload":1,"success":true}`, string(c.Response.Body()))
+}
@@ -4,21 +4,29 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha1"
+	"encoding/base64"
 	"fmt"
 	"html"
+	"html/template"
 	"io"
+	iofs "io/fs"
 	"mime"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/favbox/wind/common/bytebufferpool"
 	"github.com/favbox/wind/common/compress"
 	"github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/common/stackless"
 	"github.com/favbox/wind/common/utils"
 	"github.com/favbox/wind/common/wlog"
 	"github.com/favbox/wind/internal/bytesconv"
@@ -27,6 +35,7 @@ import (
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/protocol/consts"
+	"github.com/klauspost/compress/zstd"
 )
 
 var (
@@ -52,8 +61,17 @@ type FS struct {
 	noCopy nocopy.NoCopy
 
 	// 静态文件服务的根目录。
+	//
+	// 设置 FS 时为 FS 内的子目录，默认为 FS 的根。
 	Root string
 
+	// 文件系统，如 embed.FS。
+	//
+	// 非 nil 时所有文件读取均改走该接口，文件内容整体读入内存提供服务：
+	// Compress 退化为即时的内存压缩，不写入压缩缓存文件；
+	// 且内容视为不变，文件缓存不再按 CacheDuration 过期。
+	FS iofs.FS
+
 	// 访问目录时尝试打开的索引文件名称切片。
 	//
 	// 例如：
@@ -77,11 +95,37 @@ type FS struct {
 	// 因此，开启前要授予根目录及所有子目录写权限。
 	Compress bool
 
+	// 是否优先发送预压缩文件？
+	//
+	// 开启后若客户端接受 br、zstd 或 gzip 编码，优先发送同名的 .br、.zst 或 .gz 预压缩文件，
+	// 找不到时再按 Compress 的设置即时压缩。修改时间早于原文件的预压缩文件视为陈旧而忽略。
+	ServePrecompressed bool
+
+	// 是否支持 Brotli 压缩？
+	//
+	// 仅在 Compress 开启时生效。客户端同时接受 br 和 gzip 时优先使用 br。
+	CompressBrotli bool
+
+	// 是否支持 Zstd 压缩？
+	//
+	// 仅在 Compress 开启时生效。优先级低于 br、高于 gzip。
+	CompressZstd bool
+
 	// 要添加到缓存压缩文件名称的后缀。
 	//
 	// 仅在 Compress 开启时生效，默认值为 FSCompressedFileSuffix。
 	CompressedFileSuffix string
 
+	// 要添加到 Brotli 缓存压缩文件名称的后缀。
+	//
+	// 仅在 CompressBrotli 开启时生效，默认值为 FSBrotliCompressedFileSuffix。
+	BrotliCompressedFileSuffix string
+
+	// 要添加到 Zstd 缓存压缩文件名称的后缀。
+	//
+	// 仅在 CompressZstd 开启时生效，默认值为 FSZstdCompressedFileSuffix。
+	ZstdCompressedFileSuffix string
+
 	// 文件处理器的缓存时长。
 	//
 	// 默认值为 FSHandlerCacheDuration。
@@ -102,6 +146,16 @@ type FS struct {
 	// 默认返回 “无法打开请求路径”
 	PathNotFound HandlerFunc
 
+	// 是否按文件内容的哈希生成 ETag？
+	//
+	// 默认按文件大小与修改时间生成弱 ETag。开启后需在首次打开文件时读取完整内容，大文件会较慢。
+	ETagContentHash bool
+
+	// 自定义目录索引页模板，执行时传入 *DirIndexData。
+	//
+	// 仅在 GenerateIndexPages 开启时生效，默认使用内置样式。
+	DirIndexTemplate *template.Template
+
 	once sync.Once
 	h    HandlerFunc
 }
@@ -141,6 +195,14 @@ func (fs *FS) initRequestHandler() {
 	if len(compressedFileSuffix) == 0 {
 		compressedFileSuffix = consts.FSCompressedFileSuffix
 	}
+	brotliFileSuffix := fs.BrotliCompressedFileSuffix
+	if len(brotliFileSuffix) == 0 {
+		brotliFileSuffix = consts.FSBrotliCompressedFileSuffix
+	}
+	zstdFileSuffix := fs.ZstdCompressedFileSuffix
+	if len(zstdFileSuffix) == 0 {
+		zstdFileSuffix = consts.FSZstdCompressedFileSuffix
+	}
 
 	h := &fsHandler{
 		root:                 root,
@@ -148,12 +210,31 @@ func (fs *FS) initRequestHandler() {
 		pathRewrite:          fs.PathRewrite,
 		pathNotFound:         fs.PathNotFound,
 		generateIndexPages:   fs.GenerateIndexPages,
+		dirIndexTemplate:     fs.DirIndexTemplate,
 		compress:             fs.Compress,
+		compressBrotli:       fs.CompressBrotli,
+		compressZstd:         fs.CompressZstd,
+		servePrecompressed:   fs.ServePrecompressed,
 		acceptByteRange:      fs.AcceptByteRange,
+		etagContentHash:      fs.ETagContentHash,
 		cacheDuration:        cacheDuration,
 		compressedFileSuffix: compressedFileSuffix,
+		brotliFileSuffix:     brotliFileSuffix,
+		zstdFileSuffix:       zstdFileSuffix,
 		cache:                make(map[string]*fsFile),
 		compressedCache:      make(map[string]*fsFile),
+		brotliCache:          make(map[string]*fsFile),
+		zstdCache:            make(map[string]*fsFile),
+		precompressedCache:   make(map[string]*fsFile),
+		precompressedMisses:  make(map[string]time.Time),
+		fs:                   fs.FS,
+		startTime:            time.Now(),
+	}
+
+	// FS 中的内容不变，缓存无需过期
+	if h.fs != nil {
+		fs.h = h.handleRequest
+		return
 	}
 
 	go func() {
@@ -173,14 +254,27 @@ type fsHandler struct {
 	pathRewrite          PathRewriteFunc
 	pathNotFound         HandlerFunc
 	generateIndexPages   bool
+	dirIndexTemplate     *template.Template
 	compress             bool
+	compressBrotli       bool
+	compressZstd         bool
+	servePrecompressed   bool
 	acceptByteRange      bool
+	etagContentHash      bool
 	cacheDuration        time.Duration
 	compressedFileSuffix string
-
-	cache           map[string]*fsFile
-	compressedCache map[string]*fsFile
-	cacheLock       sync.Mutex
+	brotliFileSuffix     string
+	zstdFileSuffix       string
+	fs                   iofs.FS
+	startTime            time.Time // 处理器创建时间，作为 FS 中无修改时间文件的 Last-Modified
+
+	cache               map[string]*fsFile
+	compressedCache     map[string]*fsFile
+	brotliCache         map[string]*fsFile
+	zstdCache           map[string]*fsFile
+	precompressedCache  map[string]*fsFile   // 预压缩文件，键为后缀加路径
+	precompressedMisses map[string]time.Time // 不存在预压缩文件的记录，键同上
+	cacheLock           sync.Mutex
 
 	smallFileReaderPool sync.Pool
 }
@@ -211,38 +305,55 @@ func (h *fsHandler) handleRequest(c context.Context, ctx *RequestContext) {
 		}
 	}
 
-	// 是否需要压缩？
-	mustCompress := false
+	// 是否需要压缩？优先 br，其次 zstd，最后 gzip
+	var encoding []byte
 	fileCache := h.cache
 	byteRange := ctx.Request.Header.PeekRange()
-	if len(byteRange) == 0 && h.compress && ctx.Request.Header.HasAcceptEncodingBytes(bytestr.StrGzip) {
-		mustCompress = true
-		fileCache = h.compressedCache
+	if len(byteRange) == 0 && h.compress {
+		if h.compressBrotli && ctx.Request.Header.HasAcceptEncodingBytes(bytestr.StrBr) {
+			encoding = bytestr.StrBr
+			fileCache = h.brotliCache
+		} else if h.compressZstd && ctx.Request.Header.HasAcceptEncodingBytes(bytestr.StrZstd) {
+			encoding = bytestr.StrZstd
+			fileCache = h.zstdCache
+		} else if ctx.Request.Header.HasAcceptEncodingBytes(bytestr.StrGzip) {
+			encoding = bytestr.StrGzip
+			fileCache = h.compressedCache
+		}
+	}
+
+	// 优先发送预压缩文件
+	var ff *fsFile
+	if len(byteRange) == 0 && h.servePrecompressed {
+		ff = h.precompressedFSFile(ctx, string(path))
 	}
 
 	// 从缓存读取请求的文件
-	h.cacheLock.Lock()
-	ff, ok := fileCache[string(path)]
-	if ok {
-		ff.readersCount++
+	ok := ff != nil
+	if !ok {
+		h.cacheLock.Lock()
+		ff, ok = fileCache[string(path)]
+		if ok {
+			ff.readersCount++
+		}
+		h.cacheLock.Unlock()
 	}
-	h.cacheLock.Unlock()
 
 	// 读取请求的 fsFile，并缓存
 	if !ok {
 		pathStr := string(path)
 		filePath := h.root + pathStr
 		var err error
-		ff, err = h.openFSFile(filePath, mustCompress)
+		ff, err = h.openFSFile(filePath, encoding)
 
-		if mustCompress && err == errNoCreatePermission {
+		if encoding != nil && err == errNoCreatePermission {
 			wlog.SystemLogger().Errorf("权限不足，无法保存压缩文件 %q。正在提供未压缩文件。"+
 				"授予该文件所在目录的写权限，可提高服务器性能。", filePath)
-			mustCompress = false
-			ff, err = h.openFSFile(filePath, mustCompress)
+			encoding = nil
+			ff, err = h.openFSFile(filePath, encoding)
 		}
 		if err == errDirIndexRequired {
-			ff, err = h.openIndexFile(ctx, filePath, mustCompress)
+			ff, err = h.openIndexFile(ctx, filePath, encoding)
 			if err != nil {
 				wlog.SystemLogger().Errorf("无法打开目录索引文件，路径=%q, 错误=%s", filePath, err)
 				ctx.AbortWithMsg("目录索引被禁止", consts.StatusForbidden)
@@ -276,10 +387,17 @@ func (h *fsHandler) handleRequest(c context.Context, ctx *RequestContext) {
 		}
 	}
 
-	// 内容未修改，直接返回
-	if !ctx.IfModifiedSince(ff.lastModified) {
+	// 内容未修改，直接返回。If-None-Match 优先于 If-Modified-Since
+	notModified := false
+	if inm := ctx.Request.Header.Peek(consts.HeaderIfNoneMatch); len(inm) > 0 {
+		notModified = etagMatch(string(inm), ff.etag)
+	} else {
+		notModified = !ctx.IfModifiedSince(ff.lastModified)
+	}
+	if notModified {
 		ff.decReadersCount()
 		ctx.NotModified()
+		ctx.Response.Header.Set(consts.HeaderETag, ff.etag)
 		return
 	}
 
@@ -291,10 +409,13 @@ func (h *fsHandler) handleRequest(c context.Context, ctx *RequestContext) {
 		return
 	}
 
-	// 按需设置内容编码为 gzip
+	// 按需设置内容编码
 	hdr := &ctx.Response.Header
-	if ff.compressed {
-		hdr.SetContentEncodingBytes(bytestr.StrGzip)
+	if len(ff.encoding) > 0 {
+		hdr.SetContentEncodingBytes(ff.encoding)
+	}
+	if h.compress || h.servePrecompressed {
+		hdr.Add(consts.HeaderVary, consts.HeaderAcceptEncoding)
 	}
 
 	// 按需设置按字节区间传输，以及相关状态码和内容长度
@@ -324,8 +445,9 @@ func (h *fsHandler) handleRequest(c context.Context, ctx *RequestContext) {
 		}
 	}
 
-	// 设置内容修改时间并发送正文流
+	// 设置内容修改时间、实体标签并发送正文流
 	hdr.SetCanonical(bytestr.StrLastModified, ff.lastModifiedStr)
+	hdr.Set(consts.HeaderETag, ff.etag)
 	if !ctx.IsHead() {
 		ctx.SetBodyStream(r, contentLength)
 	} else {
@@ -367,6 +489,14 @@ func (h *fsHandler) cleanCache(pendingFiles []*fsFile) []*fsFile {
 
 	pendingFiles, filesToRelease = cleanCacheNoLock(h.cache, pendingFiles, filesToRelease, h.cacheDuration)
 	pendingFiles, filesToRelease = cleanCacheNoLock(h.compressedCache, pendingFiles, filesToRelease, h.cacheDuration)
+	pendingFiles, filesToRelease = cleanCacheNoLock(h.brotliCache, pendingFiles, filesToRelease, h.cacheDuration)
+	pendingFiles, filesToRelease = cleanCacheNoLock(h.zstdCache, pendingFiles, filesToRelease, h.cacheDuration)
+	pendingFiles, filesToRelease = cleanCacheNoLock(h.precompressedCache, pendingFiles, filesToRelease, h.cacheDuration)
+	for k, t := range h.precompressedMisses {
+		if time.Since(t) > h.cacheDuration {
+			delete(h.precompressedMisses, k)
+		}
+	}
 
 	h.cacheLock.Unlock()
 
@@ -394,7 +524,7 @@ func cleanCacheNoLock(cache map[string]*fsFile, pendingFiles, filesToRelease []*
 	return pendingFiles, filesToRelease
 }
 
-func (h *fsHandler) compressAndOpenFSFile(filePath string) (*fsFile, error) {
+func (h *fsHandler) compressAndOpenFSFile(filePath string, encoding []byte) (*fsFile, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -412,13 +542,13 @@ func (h *fsHandler) compressAndOpenFSFile(filePath string) (*fsFile, error) {
 	}
 
 	// 无需压缩的文件，直接返回
-	if strings.HasSuffix(filePath, h.compressedFileSuffix) || // 已经压缩了
+	if h.isCompressedFile(filePath) || // 已经压缩了
 		fileInfo.Size() > consts.FsMaxCompressibleFileSize || // 大于 8MB
 		!isFileCompressible(f, consts.FSMinCompressRatio) { // 压缩率不高
-		return h.newFSFile(f, fileInfo, false)
+		return h.newFSFile(f, fileInfo, nil)
 	}
 
-	compressedFilePath := filePath + h.compressedFileSuffix
+	compressedFilePath := filePath + h.compressedSuffix(encoding)
 	absPath, err := filepath.Abs(compressedFilePath)
 	if err != nil {
 		f.Close()
@@ -427,18 +557,18 @@ func (h *fsHandler) compressAndOpenFSFile(filePath string) (*fsFile, error) {
 
 	flock := getFileLock(absPath)
 	flock.Lock()
-	ff, err := h.compressFileNolock(f, fileInfo, filePath, compressedFilePath)
+	ff, err := h.compressFileNolock(f, fileInfo, filePath, compressedFilePath, encoding)
 	flock.Unlock()
 
 	return ff, err
 }
 
-func (h *fsHandler) compressFileNolock(f *os.File, fileInfo os.FileInfo, filePath, compressedFilePath string) (*fsFile, error) {
+func (h *fsHandler) compressFileNolock(f *os.File, fileInfo os.FileInfo, filePath, compressedFilePath string, encoding []byte) (*fsFile, error) {
 	// 尝试打开由其他并发协程创建的压缩文件。
 	// 该做法是安全的，因为文件创建受文件互斥锁保护 —— 见 getFileLock 调用。
 	if _, err := os.Stat(compressedFilePath); err == nil {
 		f.Close()
-		return h.newCompressedFSFile(compressedFilePath)
+		return h.newCompressedFSFile(compressedFilePath, encoding)
 	}
 
 	// 创建临时文件，所以并发协程在创建之前不会使用它。
@@ -452,13 +582,7 @@ func (h *fsHandler) compressFileNolock(f *os.File, fileInfo os.FileInfo, filePat
 		return nil, errNoCreatePermission
 	}
 
-	zw := compress.AcquireStacklessGzipWriter(zf, compress.CompressDefaultCompression)
-	zrw := network.NewWriter(zw)
-	_, err = utils.CopyZeroAlloc(zrw, f)
-	if err1 := zw.Flush(); err == nil {
-		err = err1
-	}
-	compress.ReleaseStacklessGzipWriter(zw, compress.CompressDefaultCompression)
+	err = copyCompressed(zf, f, encoding)
 	zf.Close()
 	f.Close()
 	if err != nil {
@@ -470,7 +594,57 @@ func (h *fsHandler) compressFileNolock(f *os.File, fileInfo os.FileInfo, filePat
 	if err = os.Rename(tmpFilePath, compressedFilePath); err != nil {
 		return nil, fmt.Errorf("无法移动压缩文件 %q 到 %q: %s", tmpFilePath, compressedFilePath, err)
 	}
-	return h.newCompressedFSFile(compressedFilePath)
+	return h.newCompressedFSFile(compressedFilePath, encoding)
+}
+
+// 以 encoding 编码压缩 r 的全部内容并写入 w。
+func copyCompressed(w io.Writer, r io.Reader, encoding []byte) error {
+	var zw stackless.Writer
+	switch {
+	case bytes.Equal(encoding, bytestr.StrBr):
+		zw = compress.AcquireStacklessBrotliWriter(w, compress.CompressBrotliDefaultCompression)
+		defer compress.ReleaseStacklessBrotliWriter(zw, compress.CompressBrotliDefaultCompression)
+	case bytes.Equal(encoding, bytestr.StrZstd):
+		zw = compress.AcquireStacklessZstdWriter(w, compress.CompressZstdDefaultCompression)
+		defer compress.ReleaseStacklessZstdWriter(zw, compress.CompressZstdDefaultCompression)
+	default:
+		zw = compress.AcquireStacklessGzipWriter(w, compress.CompressDefaultCompression)
+		defer compress.ReleaseStacklessGzipWriter(zw, compress.CompressDefaultCompression)
+	}
+	_, err := utils.CopyZeroAlloc(network.NewWriter(zw), r)
+	if err1 := zw.Flush(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+// 以 encoding 编码压缩 src 并附加到 dst。
+func appendCompressed(dst, src, encoding []byte) []byte {
+	switch {
+	case bytes.Equal(encoding, bytestr.StrBr):
+		return compress.AppendBrotliBytesLevel(dst, src, compress.CompressBrotliDefaultCompression)
+	case bytes.Equal(encoding, bytestr.StrZstd):
+		return compress.AppendZstdBytesLevel(dst, src, compress.CompressZstdDefaultCompression)
+	}
+	return compress.AppendGzipBytesLevel(dst, src, compress.CompressDefaultCompression)
+}
+
+// 返回 encoding 编码的压缩缓存文件后缀。
+func (h *fsHandler) compressedSuffix(encoding []byte) string {
+	switch {
+	case bytes.Equal(encoding, bytestr.StrBr):
+		return h.brotliFileSuffix
+	case bytes.Equal(encoding, bytestr.StrZstd):
+		return h.zstdFileSuffix
+	}
+	return h.compressedFileSuffix
+}
+
+// 判断是否为压缩缓存文件。
+func (h *fsHandler) isCompressedFile(name string) bool {
+	return strings.HasSuffix(name, h.compressedFileSuffix) ||
+		(h.compressBrotli && strings.HasSuffix(name, h.brotliFileSuffix)) ||
+		(h.compressZstd && strings.HasSuffix(name, h.zstdFileSuffix))
 }
 
 // ParseByteRange 解析标头 'Range: bytes=...' 的值。
@@ -528,17 +702,21 @@ func ParseByteRange(byteRange []byte, contentLength int) (startPos, endPos int,
 	return startPos, endPos, nil
 }
 
-func (h *fsHandler) openFSFile(filePath string, mustCompress bool) (*fsFile, error) {
+func (h *fsHandler) openFSFile(filePath string, encoding []byte) (*fsFile, error) {
+	if h.fs != nil {
+		return h.openMemFSFile(filePath, encoding)
+	}
+
 	filePathOriginal := filePath
-	if mustCompress {
-		filePath += h.compressedFileSuffix
+	if encoding != nil {
+		filePath += h.compressedSuffix(encoding)
 	}
 
 	f, err := os.Open(filePath)
 	if err != nil {
 		// 压缩文件不存在
-		if mustCompress && os.IsNotExist(err) {
-			return h.compressAndOpenFSFile(filePathOriginal)
+		if encoding != nil && os.IsNotExist(err) {
+			return h.compressAndOpenFSFile(filePathOriginal, encoding)
 		}
 		return nil, err
 	}
@@ -551,13 +729,13 @@ func (h *fsHandler) openFSFile(filePath string, mustCompress bool) (*fsFile, err
 
 	if fileInfo.IsDir() {
 		f.Close()
-		if mustCompress {
-			return nil, fmt.Errorf("目录后缀异常：%q。后缀：%q", filePath, h.compressedFileSuffix)
+		if encoding != nil {
+			return nil, fmt.Errorf("目录后缀异常：%q。后缀：%q", filePath, h.compressedSuffix(encoding))
 		}
 		return nil, errDirIndexRequired
 	}
 
-	if mustCompress {
+	if encoding != nil {
 		fileInfoOriginal, err := os.Stat(filePathOriginal)
 		if err != nil {
 			f.Close()
@@ -568,11 +746,257 @@ func (h *fsHandler) openFSFile(filePath string, mustCompress bool) (*fsFile, err
 			// 压缩文件已过时。重新创建。
 			f.Close()
 			os.Remove(filePath)
-			return h.compressAndOpenFSFile(filePathOriginal)
+			return h.compressAndOpenFSFile(filePathOriginal, encoding)
 		}
 	}
 
-	return h.newFSFile(f, fileInfo, mustCompress)
+	return h.newFSFile(f, fileInfo, encoding)
+}
+
+// 从 FS.FS 读取文件到内存，需要压缩时在内存中即时压缩。
+func (h *fsHandler) openMemFSFile(filePath string, encoding []byte) (*fsFile, error) {
+	name := fsName(filePath)
+	fileInfo, err := iofs.Stat(h.fs, name)
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.IsDir() {
+		return nil, errDirIndexRequired
+	}
+
+	data, err := iofs.ReadFile(h.fs, name)
+	if err != nil {
+		return nil, err
+	}
+	contentLength := len(data)
+	if int64(contentLength) != fileInfo.Size() {
+		return nil, fmt.Errorf("文件 %q 读取不完整：%d/%d 字节", name, contentLength, fileInfo.Size())
+	}
+
+	contentType := mime.TypeByExtension(fileExtension(name, false, ""))
+	if len(contentType) == 0 {
+		contentType = http.DetectContentType(data)
+	}
+
+	var dataEncoding []byte
+	if encoding != nil && !h.isCompressedFile(name) && contentLength <= consts.FsMaxCompressibleFileSize {
+		zdata := appendCompressed(nil, data, encoding)
+		if float64(len(zdata)) < float64(contentLength)*consts.FSMinCompressRatio {
+			data, dataEncoding = zdata, encoding
+		}
+	}
+
+	lastModified := fileInfo.ModTime()
+	if lastModified.IsZero() {
+		// embed.FS 中的文件没有修改时间
+		lastModified = h.startTime
+	}
+	var sum []byte
+	if h.etagContentHash {
+		s := sha1.Sum(data)
+		sum = s[:]
+	}
+	ff := &fsFile{
+		h:               h,
+		data:            data,
+		contentType:     contentType,
+		contentLength:   len(data),
+		compressed:      dataEncoding != nil,
+		encoding:        dataEncoding,
+		etag:            fsETag(len(data), lastModified, sum, dataEncoding),
+		lastModified:    lastModified,
+		lastModifiedStr: bytesconv.AppendHTTPDate(make([]byte, 0, len(http.TimeFormat)), lastModified),
+		t:               time.Now(),
+	}
+	return ff, nil
+}
+
+// 预压缩文件的内容编码及后缀，按优先级排列。
+var precompressedEncodings = []struct {
+	encoding []byte
+	suffix   string
+}{
+	{bytestr.StrBr, ".br"},
+	{bytestr.StrZstd, ".zst"},
+	{bytestr.StrGzip, ".gz"},
+}
+
+// 按客户端可接受的编码查找 path 的预压缩文件，找不到时返回 nil。
+//
+// 返回的文件已增加读取计数。
+func (h *fsHandler) precompressedFSFile(ctx *RequestContext, path string) *fsFile {
+	for _, pe := range precompressedEncodings {
+		if !ctx.Request.Header.HasAcceptEncodingBytes(pe.encoding) {
+			continue
+		}
+		key := pe.suffix + path
+
+		h.cacheLock.Lock()
+		ff, ok := h.precompressedCache[key]
+		if ok {
+			ff.readersCount++
+		}
+		_, missing := h.precompressedMisses[key]
+		h.cacheLock.Unlock()
+		if ok {
+			return ff
+		}
+		if missing {
+			continue
+		}
+
+		ff, err := h.openPrecompressedFSFile(h.root+path, pe.encoding, pe.suffix)
+		h.cacheLock.Lock()
+		if err != nil {
+			h.precompressedMisses[key] = time.Now()
+			h.cacheLock.Unlock()
+			continue
+		}
+		ff1, ok := h.precompressedCache[key]
+		if !ok {
+			h.precompressedCache[key] = ff
+			ff.readersCount++
+		} else {
+			ff1.readersCount++
+		}
+		h.cacheLock.Unlock()
+
+		if ok {
+			// 已被其他协程打开
+			ff.Release()
+			ff = ff1
+		}
+		return ff
+	}
+	return nil
+}
+
+// 打开 filePath 加 suffix 的预压缩文件，修改时间早于原文件时视为陈旧。
+func (h *fsHandler) openPrecompressedFSFile(filePath string, encoding []byte, suffix string) (*fsFile, error) {
+	origInfo, err := h.stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if origInfo.IsDir() {
+		return nil, errDirIndexRequired
+	}
+	zPath := filePath + suffix
+	zInfo, err := h.stat(zPath)
+	if err != nil {
+		return nil, err
+	}
+	if zInfo.IsDir() || zInfo.ModTime().Before(origInfo.ModTime()) {
+		return nil, fmt.Errorf("预压缩文件 %q 无效或已陈旧", zPath)
+	}
+
+	contentType := mime.TypeByExtension(fileExtension(filePath, false, ""))
+	if len(contentType) == 0 {
+		head, err := h.readHead(filePath)
+		if err != nil {
+			return nil, err
+		}
+		contentType = http.DetectContentType(head)
+	}
+
+	lastModified := origInfo.ModTime()
+	if lastModified.IsZero() {
+		lastModified = h.startTime
+	}
+	ff := &fsFile{
+		h:               h,
+		contentType:     contentType,
+		compressed:      true,
+		encoding:        encoding,
+		lastModified:    lastModified,
+		lastModifiedStr: bytesconv.AppendHTTPDate(make([]byte, 0, len(http.TimeFormat)), lastModified),
+		t:               time.Now(),
+	}
+
+	var sum []byte
+	if h.fs != nil {
+		if ff.data, err = iofs.ReadFile(h.fs, fsName(zPath)); err != nil {
+			return nil, err
+		}
+		ff.contentLength = len(ff.data)
+		if h.etagContentHash {
+			s := sha1.Sum(ff.data)
+			sum = s[:]
+		}
+	} else {
+		if ff.f, err = os.Open(zPath); err != nil {
+			return nil, err
+		}
+		ff.contentLength = int(zInfo.Size())
+		if h.etagContentHash {
+			if sum, err = fileSHA1(ff.f); err != nil {
+				ff.f.Close()
+				return nil, err
+			}
+		}
+	}
+	ff.etag = fsETag(ff.contentLength, lastModified, sum, encoding)
+	return ff, nil
+}
+
+// 获取文件信息。
+func (h *fsHandler) stat(filePath string) (os.FileInfo, error) {
+	if h.fs != nil {
+		return iofs.Stat(h.fs, fsName(filePath))
+	}
+	return os.Stat(filePath)
+}
+
+// 读取文件的前 512 字节，用于识别内容类型。
+func (h *fsHandler) readHead(filePath string) ([]byte, error) {
+	var (
+		f   io.ReadCloser
+		err error
+	)
+	if h.fs != nil {
+		f, err = h.fs.Open(fsName(filePath))
+	} else {
+		f, err = os.Open(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, 512))
+}
+
+// 将文件路径转换为 io/fs 要求的无前导斜杠的名称，根目录为 "."。
+func fsName(filePath string) string {
+	name := path.Clean("/" + filePath)[1:]
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// 读取目录下的文件信息。
+func (h *fsHandler) readDir(dirPath string) ([]os.FileInfo, error) {
+	if h.fs == nil {
+		f, err := os.Open(dirPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return f.Readdir(0)
+	}
+
+	entries, err := iofs.ReadDir(h.fs, fsName(dirPath))
+	if err != nil {
+		return nil, err
+	}
+	fileInfos := make([]os.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		fileInfos = append(fileInfos, fi)
+	}
+	return fileInfos, nil
 }
 
 var (
@@ -591,91 +1015,129 @@ func getFileLock(absPath string) *sync.Mutex {
 	return flock
 }
 
-func (h *fsHandler) createDirIndex(base *protocol.URI, dirPath string, mustCompress bool) (*fsFile, error) {
+// DirIndexData 是目录索引页模板的数据。
+type DirIndexData struct {
+	Path   string         // 当前目录的请求路径
+	Parent string         // 父目录链接，根目录时为空
+	Files  []DirIndexFile // 按名称排序的目录项
+}
+
+// DirIndexFile 是目录索引页中的一个目录项。
+type DirIndexFile struct {
+	Name    string
+	Link    string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+func (h *fsHandler) createDirIndex(base *protocol.URI, dirPath string, encoding []byte) (*fsFile, error) {
+	data, err := h.dirIndexData(base, dirPath)
+	if err != nil {
+		return nil, err
+	}
+
 	w := &bytebufferpool.ByteBuffer{}
+	if h.dirIndexTemplate != nil {
+		// html/template 会对文件名及链接自动转义
+		if err = h.dirIndexTemplate.Execute(w, data); err != nil {
+			return nil, err
+		}
+	} else {
+		writeDefaultDirIndex(w, data)
+	}
 
-	basePathEscaped := html.EscapeString(string(base.Path()))
-	fmt.Fprintf(w, "<html><head><title>%s</title><style>.dir {font-weight: bold}</style></head><body>", basePathEscaped)
-	fmt.Fprintf(w, "<h1>%s</h1>", basePathEscaped)
-	fmt.Fprintf(w, "<ul>")
+	if encoding != nil {
+		var zBuf bytebufferpool.ByteBuffer
+		zBuf.B = appendCompressed(zBuf.B, w.B, encoding)
+		w = &zBuf
+	}
+
+	dirIndex := w.B
+	lastModified := time.Now()
+	ff := &fsFile{
+		h:               h,
+		data:            dirIndex,
+		contentType:     "text/html; charset=utf-8",
+		contentLength:   len(dirIndex),
+		compressed:      encoding != nil,
+		encoding:        encoding,
+		etag:            fsETag(len(dirIndex), lastModified, nil, encoding),
+		lastModified:    lastModified,
+		lastModifiedStr: bytesconv.AppendHTTPDate(make([]byte, 0, len(http.TimeFormat)), lastModified),
+		t:               lastModified,
+	}
+	return ff, nil
+}
+
+func (h *fsHandler) dirIndexData(base *protocol.URI, dirPath string) (*DirIndexData, error) {
+	data := &DirIndexData{Path: string(base.Path())}
 
-	// 写入父级路径锚链接
-	if len(basePathEscaped) > 1 {
+	// 父级路径锚链接
+	if len(data.Path) > 1 {
 		var parentURI protocol.URI
 		base.CopyTo(&parentURI)
-		parentURI.Update(string(base.Path()) + "/..")
-		parentPathEscaped := html.EscapeString(string(parentURI.Path()))
-		fmt.Fprintf(w, `<li><a href="%s" class="dir">..</a></li>`, parentPathEscaped)
+		parentURI.Update(data.Path + "/..")
+		data.Parent = string(parentURI.Path())
 	}
 
-	f, err := os.Open(dirPath)
+	fileInfos, err := h.readDir(dirPath)
 	if err != nil {
 		return nil, err
 	}
 
-	fileInfos, err := f.Readdir(0)
-	f.Close()
-	if err != nil {
-		return nil, err
-	}
+	var u protocol.URI
+	base.CopyTo(&u)
+	u.Update(string(u.Path()) + "/")
 
-	fm := make(map[string]os.FileInfo, len(fileInfos))
-	fileNames := make([]string, 0, len(fileInfos))
+	data.Files = make([]DirIndexFile, 0, len(fileInfos))
 	for _, fi := range fileInfos {
 		name := fi.Name()
-		if strings.HasSuffix(name, h.compressedFileSuffix) {
+		if h.isCompressedFile(name) {
 			// 不在索引页显示缓存压缩文件
 			continue
 		}
-		fm[name] = fi
-		fileNames = append(fileNames, name)
-	}
-
-	var u protocol.URI
-	base.CopyTo(&u)
-	u.Update(string(u.Path()) + "/")
-
-	sort.Strings(fileNames)
-	for _, name := range fileNames {
 		u.Update(name)
-		pathEscaped := html.EscapeString(string(u.Path()))
-		fi := fm[name]
+		data.Files = append(data.Files, DirIndexFile{
+			Name:    name,
+			Link:    string(u.Path()),
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+			IsDir:   fi.IsDir(),
+		})
+	}
+	sort.Slice(data.Files, func(i, j int) bool {
+		return data.Files[i].Name < data.Files[j].Name
+	})
+	return data, nil
+}
+
+// 以内置样式写入目录索引页。
+func writeDefaultDirIndex(w io.Writer, data *DirIndexData) {
+	basePathEscaped := html.EscapeString(data.Path)
+	fmt.Fprintf(w, "<html><head><title>%s</title><style>.dir {font-weight: bold}</style></head><body>", basePathEscaped)
+	fmt.Fprintf(w, "<h1>%s</h1>", basePathEscaped)
+	fmt.Fprintf(w, "<ul>")
+	if data.Parent != "" {
+		fmt.Fprintf(w, `<li><a href="%s" class="dir">..</a></li>`, html.EscapeString(data.Parent))
+	}
+	for _, fi := range data.Files {
 		auxStr := "目录"
 		className := "dir"
-		if !fi.IsDir() {
-			auxStr = fmt.Sprintf("文件，%d 字节", fi.Size())
+		if !fi.IsDir {
+			auxStr = fmt.Sprintf("文件，%d 字节", fi.Size)
 			className = "file"
 		}
 		fmt.Fprintf(w, `<li><a href="%s" class="%s">%s</a>，%s，最后修改时间 %s</li>`,
-			pathEscaped, className, html.EscapeString(name), auxStr, fsModTime(fi.ModTime()))
+			html.EscapeString(fi.Link), className, html.EscapeString(fi.Name), auxStr, fsModTime(fi.ModTime))
 	}
-
 	fmt.Fprintf(w, "</ul></body></html>")
-	if mustCompress {
-		var zBuf bytebufferpool.ByteBuffer
-		zBuf.B = compress.AppendGzipBytesLevel(zBuf.B, w.B, compress.CompressDefaultCompression)
-		w = &zBuf
-	}
-
-	dirIndex := w.B
-	lastModified := time.Now()
-	ff := &fsFile{
-		h:               h,
-		dirIndex:        dirIndex,
-		contentType:     "text/html; charset=utf-8",
-		contentLength:   len(dirIndex),
-		compressed:      mustCompress,
-		lastModified:    lastModified,
-		lastModifiedStr: bytesconv.AppendHTTPDate(make([]byte, 0, len(http.TimeFormat)), lastModified),
-		t:               lastModified,
-	}
-	return ff, nil
 }
 
-func (h *fsHandler) openIndexFile(ctx *RequestContext, dirPath string, mustCompress bool) (*fsFile, error) {
+func (h *fsHandler) openIndexFile(ctx *RequestContext, dirPath string, encoding []byte) (*fsFile, error) {
 	for _, indexName := range h.indexNames {
 		indexFilePath := dirPath + "/" + indexName
-		ff, err := h.openFSFile(indexFilePath, mustCompress)
+		ff, err := h.openFSFile(indexFilePath, encoding)
 		if err == nil {
 			return ff, nil
 		}
@@ -688,10 +1150,10 @@ func (h *fsHandler) openIndexFile(ctx *RequestContext, dirPath string, mustCompr
 		return nil, fmt.Errorf("无法访问没有索引页的目录。目录 %q", dirPath)
 	}
 
-	return h.createDirIndex(ctx.URI(), dirPath, mustCompress)
+	return h.createDirIndex(ctx.URI(), dirPath, encoding)
 }
 
-func (h *fsHandler) newFSFile(f *os.File, fileInfo os.FileInfo, compressed bool) (*fsFile, error) {
+func (h *fsHandler) newFSFile(f *os.File, fileInfo os.FileInfo, encoding []byte) (*fsFile, error) {
 	n := fileInfo.Size()
 	contentLength := int(n)
 	if n != int64(contentLength) {
@@ -700,23 +1162,34 @@ func (h *fsHandler) newFSFile(f *os.File, fileInfo os.FileInfo, compressed bool)
 	}
 
 	// 检查内容类型
-	ext := fileExtension(fileInfo.Name(), compressed, h.compressedFileSuffix)
+	ext := fileExtension(fileInfo.Name(), encoding != nil, h.compressedSuffix(encoding))
 	contentType := mime.TypeByExtension(ext)
 	if len(contentType) == 0 {
-		data, err := readFileHeader(f, compressed)
+		data, err := readFileHeader(f, encoding)
 		if err != nil {
 			return nil, fmt.Errorf("无法读取文件头 %q: %s", f.Name(), err)
 		}
 		contentType = http.DetectContentType(data)
 	}
 
+	var sum []byte
+	if h.etagContentHash {
+		var err error
+		if sum, err = fileSHA1(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
 	lastModified := fileInfo.ModTime()
 	ff := &fsFile{
 		h:               h,
 		f:               f,
 		contentType:     contentType,
 		contentLength:   contentLength,
-		compressed:      compressed,
+		compressed:      encoding != nil,
+		encoding:        encoding,
+		etag:            fsETag(contentLength, lastModified, sum, encoding),
 		lastModified:    lastModified,
 		lastModifiedStr: bytesconv.AppendHTTPDate(make([]byte, 0, len(http.TimeFormat)), lastModified),
 		t:               time.Now(),
@@ -724,7 +1197,7 @@ func (h *fsHandler) newFSFile(f *os.File, fileInfo os.FileInfo, compressed bool)
 	return ff, nil
 }
 
-func (h *fsHandler) newCompressedFSFile(filePath string) (*fsFile, error) {
+func (h *fsHandler) newCompressedFSFile(filePath string, encoding []byte) (*fsFile, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("无法打开压缩文件 %q: %s", filePath, err)
@@ -734,17 +1207,64 @@ func (h *fsHandler) newCompressedFSFile(filePath string) (*fsFile, error) {
 		f.Close()
 		return nil, fmt.Errorf("无法获取压缩文件的信息 %q: %s", filePath, err)
 	}
-	return h.newFSFile(f, fileInfo, true)
+	return h.newFSFile(f, fileInfo, encoding)
+}
+
+// 生成文件的弱实体标签，sum 为空时按大小与修改时间生成。
+// 压缩版本追加 -gz 或 -br 后缀，以免与未压缩版本的缓存混用。
+func fsETag(size int, modTime time.Time, sum []byte, encoding []byte) string {
+	b := make([]byte, 0, 48)
+	b = append(b, `W/"`...)
+	b = strconv.AppendInt(b, int64(size), 16)
+	b = append(b, '-')
+	if len(sum) > 0 {
+		b = append(b, base64.RawURLEncoding.EncodeToString(sum)...)
+	} else {
+		b = strconv.AppendInt(b, modTime.UnixNano(), 16)
+	}
+	switch {
+	case bytes.Equal(encoding, bytestr.StrGzip):
+		b = append(b, "-gz"...)
+	case len(encoding) > 0:
+		b = append(b, '-')
+		b = append(b, encoding...)
+	}
+	return string(append(b, '"'))
+}
+
+// 计算文件内容的 SHA-1，完成后将读取位置复原。
+func fileSHA1(f *os.File) ([]byte, error) {
+	hash := sha1.New()
+	_, err := io.Copy(hash, f)
+	if _, err1 := f.Seek(0, 0); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return nil, fmt.Errorf("无法计算文件哈希 %q: %s", f.Name(), err)
+	}
+	return hash.Sum(nil), nil
 }
 
 func fsModTime(t time.Time) any {
 	return t.In(time.UTC).Truncate(time.Second)
 }
 
-func readFileHeader(f *os.File, compressed bool) ([]byte, error) {
+func readFileHeader(f *os.File, encoding []byte) ([]byte, error) {
 	r := io.Reader(f)
 	var zr *gzip.Reader
-	if compressed {
+	var br *brotli.Reader
+	var zsr *zstd.Decoder
+	switch {
+	case bytes.Equal(encoding, bytestr.StrBr):
+		br = compress.AcquireBrotliReader(f)
+		r = br
+	case bytes.Equal(encoding, bytestr.StrZstd):
+		var err error
+		if zsr, err = compress.AcquireZstdReader(f); err != nil {
+			return nil, err
+		}
+		r = zsr
+	case encoding != nil:
 		var err error
 		if zr, err = compress.AcquireGzipReader(f); err != nil {
 			return nil, err
@@ -764,6 +1284,12 @@ func readFileHeader(f *os.File, compressed bool) ([]byte, error) {
 	if zr != nil {
 		compress.ReleaseGzipReader(zr)
 	}
+	if br != nil {
+		compress.ReleaseBrotliReader(br)
+	}
+	if zsr != nil {
+		compress.ReleaseZstdReader(zsr)
+	}
 
 	return data, err
 }
@@ -805,10 +1331,12 @@ func isFileCompressible(f *os.File, minCompressRatio float64) bool {
 type fsFile struct {
 	h             *fsHandler
 	f             *os.File
-	dirIndex      []byte
+	data          []byte // 内存中的内容：目录索引页或 FS.FS 中的文件
 	contentType   string
 	contentLength int
 	compressed    bool
+	encoding      []byte // 内容编码，未压缩时为空
+	etag          string
 
 	lastModified    time.Time
 	lastModifiedStr []byte
@@ -846,7 +1374,7 @@ func (ff *fsFile) Release() {
 }
 
 func (ff *fsFile) isBig() bool {
-	return ff.contentLength > consts.MaxSmallFileSize && len(ff.dirIndex) == 0
+	return ff.contentLength > consts.MaxSmallFileSize && len(ff.data) == 0
 }
 
 func (ff *fsFile) bigFileReader() (io.Reader, error) {
@@ -985,7 +1513,7 @@ func (r *fsSmallFileReader) Read(p []byte) (int, error) {
 		return n, err
 	}
 
-	n := copy(p, ff.dirIndex[r.startPos:])
+	n := copy(p, ff.data[r.startPos:])
 	r.startPos += n
 	return n, nil
 }
@@ -996,7 +1524,7 @@ func (r *fsSmallFileReader) WriteTo(w io.Writer) (int64, error) {
 	var n int
 	var err error
 	if ff.f == nil {
-		n, err = w.Write(ff.dirIndex[r.startPos:r.endPos])
+		n, err = w.Write(ff.data[r.startPos:r.endPos])
 		return int64(n), err
 	}
 
@@ -3,10 +3,16 @@ package app
 import (
 	"bytes"
 	"context"
+	"html/template"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/favbox/wind/common/compress"
 	"github.com/favbox/wind/common/mock"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/protocol/consts"
@@ -129,6 +135,250 @@ func TestServeFileHead(t *testing.T) {
 	}
 }
 
+func TestDirIndexTemplate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(root, "sub"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "<x>.txt"), []byte("abc"), 0o644))
+
+	tpl := template.Must(template.New("dir").Parse(
+		`{{.Path}}|{{.Parent}}{{range .Files}}|{{.Name}},{{.Link}},{{if .IsDir}}dir{{else}}{{.Size}}{{end}}{{end}}`))
+	fs := &FS{Root: root, GenerateIndexPages: true, DirIndexTemplate: tpl}
+	h := fs.NewRequestHandler()
+
+	var ctx RequestContext
+	ctx.Request.SetRequestURI("http://foobar.com/")
+	h(context.Background(), &ctx)
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "/||&lt;x&gt;.txt,/&lt;x&gt;.txt,3|sub,/sub,dir", string(ctx.Response.Body()))
+
+	// 默认模板
+	fs = &FS{Root: root, GenerateIndexPages: true}
+	h = fs.NewRequestHandler()
+	ctx.Reset()
+	ctx.Request.SetRequestURI("http://foobar.com/sub")
+	h(context.Background(), &ctx)
+	body := string(ctx.Response.Body())
+	assert.Contains(t, body, `<h1>/sub</h1>`)
+	assert.Contains(t, body, `<li><a href="/" class="dir">..</a></li>`)
+}
+
+func TestFSWithIOFS(t *testing.T) {
+	t.Parallel()
+
+	js := bytes.Repeat([]byte("console.log('wind');\n"), 100)
+	mfs := fstest.MapFS{
+		"dist/index.html": {Data: []byte("<h1>首页</h1>")},
+		"dist/js/app.js":  {Data: js},
+	}
+	fs := &FS{Root: "dist", FS: mfs, IndexNames: []string{"index.html"}, Compress: true}
+	h := fs.NewRequestHandler()
+
+	var ctx RequestContext
+	ctx.Request.SetRequestURI("http://foobar.com/")
+	h(context.Background(), &ctx)
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "<h1>首页</h1>", string(ctx.Response.Body()))
+	assert.Equal(t, "text/html; charset=utf-8", string(ctx.Response.Header.ContentType()))
+	// 无修改时间的文件以处理器创建时间作为 Last-Modified
+	lastModified := string(ctx.Response.Header.Peek(consts.HeaderLastModified))
+	assert.NotEmpty(t, lastModified)
+
+	// 内存中即时压缩
+	ctx.Reset()
+	ctx.Request.SetRequestURI("http://foobar.com/js/app.js")
+	ctx.Request.Header.Set(consts.HeaderAcceptEncoding, "gzip")
+	h(context.Background(), &ctx)
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+	body, err := compress.AppendGunzipBytes(nil, ctx.Response.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, js, body)
+
+	ctx.Reset()
+	ctx.Request.SetRequestURI("http://foobar.com/js/app.js")
+	ctx.Request.Header.Set(consts.HeaderIfModifiedSince, lastModified)
+	h(context.Background(), &ctx)
+	assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode())
+
+	ctx.Reset()
+	ctx.Request.SetRequestURI("http://foobar.com/none.js")
+	h(context.Background(), &ctx)
+	assert.Equal(t, consts.StatusNotFound, ctx.Response.StatusCode())
+}
+
+func TestFSETag(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("wind etag "), 200)
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "a.txt"), content, 0o644))
+	fs := &FS{Root: root, Compress: true}
+	h := fs.NewRequestHandler()
+
+	serve := func(headers ...string) *RequestContext {
+		var ctx RequestContext
+		ctx.Request.SetRequestURI("http://foobar.com/a.txt")
+		for i := 0; i+1 < len(headers); i += 2 {
+			ctx.Request.Header.Set(headers[i], headers[i+1])
+		}
+		h(context.Background(), &ctx)
+		return &ctx
+	}
+
+	ctx := serve()
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	etag := string(ctx.Response.Header.Peek(consts.HeaderETag))
+	assert.Regexp(t, `^W/"[0-9a-f]+-[0-9a-f]+"$`, etag)
+
+	ctx = serve(consts.HeaderIfNoneMatch, `"x", `+etag)
+	assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode())
+	assert.Equal(t, etag, string(ctx.Response.Header.Peek(consts.HeaderETag)))
+
+	ctx = serve(consts.HeaderIfNoneMatch, "*")
+	assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode())
+
+	// If-None-Match 优先于 If-Modified-Since
+	ctx = serve(consts.HeaderIfNoneMatch, `W/"other"`, consts.HeaderIfModifiedSince, "Fri, 01 Jan 2100 00:00:00 GMT")
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+
+	// 压缩版本使用不同的 ETag
+	ctx = serve(consts.HeaderAcceptEncoding, "gzip")
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+	gzETag := string(ctx.Response.Header.Peek(consts.HeaderETag))
+	assert.True(t, strings.HasSuffix(gzETag, `-gz"`))
+	assert.NotEqual(t, etag, gzETag)
+	ctx = serve(consts.HeaderAcceptEncoding, "gzip", consts.HeaderIfNoneMatch, etag)
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+
+	// 按内容哈希生成
+	fs = &FS{Root: root, ETagContentHash: true}
+	h = fs.NewRequestHandler()
+	ctx = serve()
+	assert.Regexp(t, `^W/"[0-9a-f]+-[A-Za-z0-9_-]{27}"$`, string(ctx.Response.Header.Peek(consts.HeaderETag)))
+}
+
+func TestFSServePrecompressed(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	js := bytes.Repeat([]byte("console.log('wind');\n"), 100)
+	gz := compress.AppendGzipBytes(nil, js)
+	now := time.Now()
+	for name, data := range map[string][]byte{"app.js": js, "app.js.br": []byte("fake-br"), "app.js.gz": gz, "old.css": js, "old.css.gz": gz} {
+		assert.Nil(t, os.WriteFile(filepath.Join(root, name), data, 0o644))
+		assert.Nil(t, os.Chtimes(filepath.Join(root, name), now, now))
+	}
+	// 预压缩文件早于原文件视为陈旧
+	assert.Nil(t, os.Chtimes(filepath.Join(root, "old.css.gz"), now, now.Add(-time.Hour)))
+
+	fs := &FS{Root: root, ServePrecompressed: true, Compress: true, CompressedFileSuffix: ".wind.gz"}
+	h := fs.NewRequestHandler()
+	serve := func(uri, acceptEncoding string) *RequestContext {
+		var ctx RequestContext
+		ctx.Request.SetRequestURI(uri)
+		ctx.Request.Header.Set(consts.HeaderAcceptEncoding, acceptEncoding)
+		h(context.Background(), &ctx)
+		return &ctx
+	}
+
+	ctx := serve("http://foobar.com/app.js", "gzip, br")
+	assert.Equal(t, "br", string(ctx.Response.Header.ContentEncoding()))
+	assert.Equal(t, "fake-br", string(ctx.Response.Body()))
+	assert.Equal(t, consts.HeaderAcceptEncoding, ctx.Response.Header.Get(consts.HeaderVary))
+	assert.Contains(t, string(ctx.Response.Header.ContentType()), "javascript")
+	assert.True(t, strings.HasSuffix(string(ctx.Response.Header.Peek(consts.HeaderETag)), `-br"`))
+
+	ctx = serve("http://foobar.com/app.js", "gzip")
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+	assert.Equal(t, gz, ctx.Response.Body())
+
+	ctx = serve("http://foobar.com/app.js", "")
+	assert.Equal(t, "", string(ctx.Response.Header.ContentEncoding()))
+	assert.Equal(t, js, ctx.Response.Body())
+	assert.Equal(t, consts.HeaderAcceptEncoding, ctx.Response.Header.Get(consts.HeaderVary))
+
+	// 陈旧的预压缩文件被忽略，回退到即时压缩
+	ctx = serve("http://foobar.com/old.css", "gzip")
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+	body, err := compress.AppendGunzipBytes(nil, ctx.Response.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, js, body)
+	_, err = os.Stat(filepath.Join(root, "old.css.wind.gz"))
+	assert.Nil(t, err)
+}
+
+func TestFSCompressBrotli(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	js := bytes.Repeat([]byte("console.log('wind');\n"), 100)
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "app.js"), js, 0o644))
+
+	fs := &FS{Root: root, Compress: true, CompressBrotli: true}
+	h := fs.NewRequestHandler()
+	serve := func(acceptEncoding string) *RequestContext {
+		var ctx RequestContext
+		ctx.Request.SetRequestURI("http://foobar.com/app.js")
+		ctx.Request.Header.Set(consts.HeaderAcceptEncoding, acceptEncoding)
+		h(context.Background(), &ctx)
+		return &ctx
+	}
+
+	ctx := serve("gzip, br")
+	assert.Equal(t, "br", string(ctx.Response.Header.ContentEncoding()))
+	assert.Contains(t, string(ctx.Response.Header.ContentType()), "javascript")
+	body, err := compress.AppendUnbrotliBytes(nil, ctx.Response.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, js, body)
+	_, err = os.Stat(filepath.Join(root, "app.js"+consts.FSBrotliCompressedFileSuffix))
+	assert.Nil(t, err)
+
+	ctx = serve("gzip")
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+	body, err = compress.AppendGunzipBytes(nil, ctx.Response.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, js, body)
+
+	// 未开启 CompressBrotli 时仅使用 gzip
+	fs = &FS{Root: root, Compress: true}
+	h = fs.NewRequestHandler()
+	ctx = serve("br, gzip")
+	assert.Equal(t, "gzip", string(ctx.Response.Header.ContentEncoding()))
+}
+
+func TestFSCompressZstd(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	js := bytes.Repeat([]byte("console.log('wind');\n"), 100)
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "app.js"), js, 0o644))
+
+	fs := &FS{Root: root, Compress: true, CompressBrotli: true, CompressZstd: true}
+	h := fs.NewRequestHandler()
+	serve := func(acceptEncoding string) *RequestContext {
+		var ctx RequestContext
+		ctx.Request.SetRequestURI("http://foobar.com/app.js")
+		ctx.Request.Header.Set(consts.HeaderAcceptEncoding, acceptEncoding)
+		h(context.Background(), &ctx)
+		return &ctx
+	}
+
+	ctx := serve("gzip, zstd")
+	assert.Equal(t, "zstd", string(ctx.Response.Header.ContentEncoding()))
+	assert.Contains(t, string(ctx.Response.Header.ContentType()), "javascript")
+	body, err := compress.AppendUnzstdBytes(nil, ctx.Response.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, js, body)
+	_, err = os.Stat(filepath.Join(root, "app.js"+consts.FSZstdCompressedFileSuffix))
+	assert.Nil(t, err)
+
+	// br 优先于 zstd
+	ctx = serve("gzip, zstd, br")
+	assert.Equal(t, "br", string(ctx.Response.Header.ContentEncoding()))
+}
+
 func getFileContents(path string) ([]byte, error) {
 	path = "." + path
 	f, err := os.Open(path)
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 长轮询期间检测客户端连接是否存活的间隔。
+const longPollCheckInterval = 100 * time.Millisecond
+
+// LongPollWaitFunc 是长轮询的数据等待函数。
+//
+// 应阻塞至有数据可用，或在 c 结束（超时或客户端断开）时尽快返回 ok=false。
+type LongPollWaitFunc func(c context.Context) (data any, ok bool)
+
+// LongPoll 在 timeout 内等待 wait 返回数据。
+//
+//   - 有数据则以 JSON 输出，状态码为 200
+//   - 超时或无数据则返回 204 No Content
+//   - 客户端提前断开则停止等待，不再写入响应
+//
+// 示例：
+//
+//	ctx.LongPoll(30*time.Second, func(c context.Context) (any, bool) {
+//		select {
+//		case msg := <-messages:
+//			return msg, true
+//		case <-c.Done():
+//			return nil, false
+//		}
+//	})
+func (ctx *RequestContext) LongPoll(timeout time.Duration, wait LongPollWaitFunc) {
+	c, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type result struct {
+		data any
+		ok   bool
+	}
+	ch := make(chan result, 1)
+	go func() {
+		data, ok := wait(c)
+		ch <- result{data: data, ok: ok}
+	}()
+
+	closed := ctx.connClosed(c)
+	select {
+	case r := <-ch:
+		if r.ok {
+			ctx.JSON(consts.StatusOK, r.data)
+			return
+		}
+		ctx.SetStatusCode(consts.StatusNoContent)
+	case <-c.Done():
+		ctx.SetStatusCode(consts.StatusNoContent)
+	case <-closed:
+		ctx.Abort()
+	}
+}
+
+// connClosed 返回一个在客户端断开或请求结束时关闭的信道，c 结束后停止检测。
+func (ctx *RequestContext) connClosed(c context.Context) <-chan struct{} {
+	closed := make(chan struct{})
+	finished := ctx.Finished()
+	checker, _ := ctx.conn.(interface{ IsActive() bool })
+	go func() {
+		ticker := time.NewTicker(longPollCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.Done():
+				return
+			case <-finished:
+				close(closed)
+				return
+			case <-ticker.C:
+				if checker != nil && !checker.IsActive() {
+					close(closed)
+					return
+				}
+			}
+		}
+	}()
+	return closed
+}
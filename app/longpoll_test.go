@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockActiveConn struct {
+	*mock.Conn
+	active bool
+}
+
+func (c *mockActiveConn) IsActive() bool {
+	return c.active
+}
+
+func TestLongPoll(t *testing.T) {
+	// 有数据
+	ctx := NewContext(0)
+	ctx.LongPoll(time.Second, func(c context.Context) (any, bool) {
+		return map[string]int{"id": 1}, true
+	})
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, `{"id":1}`, string(ctx.Response.Body()))
+
+	// 超时
+	ctx = NewContext(0)
+	ctx.LongPoll(10*time.Millisecond, func(c context.Context) (any, bool) {
+		<-c.Done()
+		return nil, false
+	})
+	assert.Equal(t, consts.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, 0, len(ctx.Response.Body()))
+
+	// 客户端断开
+	ctx = NewContext(0)
+	ctx.SetConn(&mockActiveConn{Conn: mock.NewConn(""), active: false})
+	start := time.Now()
+	ctx.LongPoll(5*time.Second, func(c context.Context) (any, bool) {
+		<-c.Done()
+		return nil, false
+	})
+	assert.True(t, time.Since(start) < time.Second)
+	assert.True(t, ctx.IsAborted())
+}
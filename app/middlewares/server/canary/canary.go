@@ -0,0 +1,58 @@
+package canary
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/favbox/wind/app"
+)
+
+// ContextKey 是记录本次请求是否命中灰度的上下文键，值为 bool。
+const ContextKey = "canary"
+
+// New 返回按权重在新旧处理器间分流的处理器。
+//
+// 分流键经哈希后取模与权重比较，同一键稳定命中同一版本，权重调大时已命中新版本的键不会回退；
+// 分流键为空时按请求随机分流。强制灰度判定优先于权重。
+func New(canary, stable app.HandlerFunc, opts ...Option) app.HandlerFunc {
+	o := newOptions(opts...)
+	return func(c context.Context, ctx *app.RequestContext) {
+		hit := o.hit(c, ctx)
+		ctx.Set(ContextKey, hit)
+		if hit {
+			canary(c, ctx)
+			return
+		}
+		stable(c, ctx)
+	}
+}
+
+func (o *options) hit(c context.Context, ctx *app.RequestContext) bool {
+	if o.match != nil && o.match(c, ctx) {
+		return true
+	}
+	weight := o.weight()
+	if weight <= 0 {
+		return false
+	}
+	if weight >= 100 {
+		return true
+	}
+	return bucket(o.key(ctx)) < weight
+}
+
+// 将分流键映射到 [0, 100) 的桶。
+func bucket(key string) int {
+	if key == "" {
+		return rand.Intn(100)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
+
+// IsCanary 报告本次请求是否命中灰度。
+func IsCanary(ctx *app.RequestContext) bool {
+	return ctx.GetBool(ContextKey)
+}
@@ -0,0 +1,93 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine(opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.GET("/", New(
+		func(c context.Context, ctx *app.RequestContext) {
+			if IsCanary(ctx) {
+				ctx.String(consts.StatusOK, "new")
+			}
+		},
+		func(c context.Context, ctx *app.RequestContext) {
+			ctx.String(consts.StatusOK, "old")
+		},
+		opts...,
+	))
+	return engine
+}
+
+func perform(engine *route.Engine, uid string) string {
+	w := ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: "X-User-ID", Value: uid})
+	return string(w.Result().Body())
+}
+
+func TestCanaryWeight(t *testing.T) {
+	engine := newEngine(WithWeight(0), WithKeyFunc(KeyFromHeader("X-User-ID")))
+	assert.Equal(t, "old", perform(engine, "1"))
+
+	engine = newEngine(WithWeight(100), WithKeyFunc(KeyFromHeader("X-User-ID")))
+	assert.Equal(t, "new", perform(engine, "1"))
+
+	engine = newEngine(WithWeight(30), WithKeyFunc(KeyFromHeader("X-User-ID")))
+	hits := 0
+	for i := 0; i < 1000; i++ {
+		uid := fmt.Sprint(i)
+		got := perform(engine, uid)
+		// 同一用户稳定命中同一版本
+		assert.Equal(t, got, perform(engine, uid))
+		if got == "new" {
+			hits++
+		}
+	}
+	assert.InDelta(t, 300, hits, 60)
+}
+
+func TestCanaryWeightFunc(t *testing.T) {
+	var weight int32 = 10
+	engine := newEngine(WithKeyFunc(KeyFromHeader("X-User-ID")), WithWeightFunc(func() int {
+		return int(atomic.LoadInt32(&weight))
+	}))
+	var before []string
+	for i := 0; i < 100; i++ {
+		if perform(engine, fmt.Sprint(i)) == "new" {
+			before = append(before, fmt.Sprint(i))
+		}
+	}
+
+	// 调大权重后已命中新版本的用户不会回退
+	atomic.StoreInt32(&weight, 50)
+	for _, uid := range before {
+		assert.Equal(t, "new", perform(engine, uid))
+	}
+}
+
+func TestCanaryMatcher(t *testing.T) {
+	engine := newEngine(WithMatcher(func(c context.Context, ctx *app.RequestContext) bool {
+		return string(ctx.Request.Header.Peek("X-Canary")) == "1"
+	}))
+	w := ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: "X-Canary", Value: "1"})
+	assert.Equal(t, "new", string(w.Result().Body()))
+	w = ut.PerformRequest(engine, consts.MethodGet, "/", nil)
+	assert.Equal(t, "old", string(w.Result().Body()))
+}
+
+func TestKeyFromCookie(t *testing.T) {
+	ctx := app.NewContext(0)
+	ctx.Request.Header.SetCookie("uid", "42")
+	assert.Equal(t, "42", KeyFromCookie("uid")(ctx))
+	assert.Equal(t, "", KeyFromCookie("none")(ctx))
+}
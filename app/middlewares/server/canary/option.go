@@ -0,0 +1,75 @@
+package canary
+
+import (
+	"context"
+
+	"github.com/favbox/wind/app"
+)
+
+// 表示一个灰度路由的自定义选项结构体。
+type options struct {
+	// 返回灰度流量的百分比权重，取值 [0, 100]。
+	weight func() int
+	// 提取分流键，同一键稳定命中同一版本；返回空则按请求随机分流。
+	key func(ctx *app.RequestContext) string
+	// 强制灰度判定，命中即走新版本，不受权重影响。
+	match func(c context.Context, ctx *app.RequestContext) bool
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		weight: func() int { return 0 },
+		key: func(ctx *app.RequestContext) string {
+			return ctx.ClientIP()
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithWeight 设置灰度流量的百分比权重，取值 [0, 100]。默认 0，即全部走旧版本。
+func WithWeight(weight int) Option {
+	return func(o *options) {
+		o.weight = func() int { return weight }
+	}
+}
+
+// WithWeightFunc 设置动态权重，每个请求调用一次，须并发安全且低开销，可用于运行时调整灰度比例。
+func WithWeightFunc(f func() int) Option {
+	return func(o *options) {
+		o.weight = f
+	}
+}
+
+// WithKeyFunc 设置分流键的提取方法，如用户 ID。默认使用客户端 IP。
+func WithKeyFunc(f func(ctx *app.RequestContext) string) Option {
+	return func(o *options) {
+		o.key = f
+	}
+}
+
+// WithMatcher 设置强制灰度判定，如测试人员携带特定标头时总是走新版本。
+func WithMatcher(f func(c context.Context, ctx *app.RequestContext) bool) Option {
+	return func(o *options) {
+		o.match = f
+	}
+}
+
+// KeyFromHeader 返回以给定请求标头值为分流键的提取方法。
+func KeyFromHeader(name string) func(ctx *app.RequestContext) string {
+	return func(ctx *app.RequestContext) string {
+		return string(ctx.Request.Header.Peek(name))
+	}
+}
+
+// KeyFromCookie 返回以给定 cookie 值为分流键的提取方法。
+func KeyFromCookie(name string) func(ctx *app.RequestContext) string {
+	return func(ctx *app.RequestContext) string {
+		return string(ctx.Cookie(name))
+	}
+}
@@ -0,0 +1,135 @@
+package capture
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/common/wlog"
+)
+
+// MaskValue 是脱敏后的标头值。
+const MaskValue = "***"
+
+// Record 表示一条录制的请求及其响应，序列化为一行 JSON。
+type Record struct {
+	Time     time.Time      `json:"time"`
+	Request  RecordRequest  `json:"request"`
+	Response RecordResponse `json:"response"`
+}
+
+// RecordRequest 表示录制的请求。
+type RecordRequest struct {
+	Method    string      `json:"method"`
+	Scheme    string      `json:"scheme"`
+	Host      string      `json:"host"`
+	URI       string      `json:"uri"` // 含查询参数的请求路径
+	Header    http.Header `json:"header,omitempty"`
+	Body      []byte      `json:"body,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"` // 正文是否被截断
+}
+
+// RecordResponse 表示录制的响应。
+type RecordResponse struct {
+	StatusCode int           `json:"status_code"`
+	Header     http.Header   `json:"header,omitempty"`
+	Body       []byte        `json:"body,omitempty"`
+	Truncated  bool          `json:"truncated,omitempty"` // 正文是否被截断
+	Latency    time.Duration `json:"latency"`
+}
+
+// New 返回流量录制中间件，将请求与响应按每行一条 JSON 写入 w。
+//
+// 流式正文不录制；超过 WithMaxBodySize 的正文截断并标记 Truncated，
+// 截断的请求在回放时会被跳过。写入并发安全。
+func New(w io.Writer, opts ...Option) app.HandlerFunc {
+	cfg := newOptions(opts...)
+	var mu sync.Mutex
+	return func(c context.Context, ctx *app.RequestContext) {
+		if cfg.sampleRate < 1 && rand.Float64() >= cfg.sampleRate {
+			ctx.Next(c)
+			return
+		}
+
+		start := time.Now()
+		ctx.Next(c)
+
+		rec := &Record{Time: start}
+		req := &ctx.Request
+		rec.Request = RecordRequest{
+			Method: string(req.Method()),
+			Scheme: string(req.Scheme()),
+			Host:   string(req.Host()),
+			URI:    string(req.RequestURI()),
+			Header: make(http.Header),
+		}
+		req.Header.VisitAll(func(k, v []byte) {
+			rec.Request.Header.Add(string(k), string(v))
+		})
+		if !req.IsBodyStream() {
+			rec.Request.Body, rec.Request.Truncated = cfg.truncate(req.Body())
+		}
+
+		resp := &ctx.Response
+		rec.Response = RecordResponse{
+			StatusCode: resp.StatusCode(),
+			Header:     make(http.Header),
+			Latency:    time.Since(start),
+		}
+		resp.Header.VisitAll(func(k, v []byte) {
+			rec.Response.Header.Add(string(k), string(v))
+		})
+		if !resp.IsBodyStream() {
+			rec.Response.Body, rec.Response.Truncated = cfg.truncate(resp.Body())
+		}
+
+		cfg.mask(rec)
+
+		line, err := json.Marshal(rec)
+		if err != nil {
+			wlog.SystemLogger().CtxErrorf(c, "[流量录制] 序列化失败：%v", err)
+			return
+		}
+		line = append(line, '\n')
+		mu.Lock()
+		_, err = w.Write(line)
+		mu.Unlock()
+		if err != nil {
+			wlog.SystemLogger().CtxErrorf(c, "[流量录制] 写入失败：%v", err)
+		}
+	}
+}
+
+// 复制并按需截断正文
+func (o *options) truncate(body []byte) ([]byte, bool) {
+	truncated := o.maxBodySize > 0 && len(body) > o.maxBodySize
+	if truncated {
+		body = body[:o.maxBodySize]
+	}
+	if len(body) == 0 {
+		return nil, truncated
+	}
+	return append([]byte(nil), body...), truncated
+}
+
+func (o *options) mask(rec *Record) {
+	for _, h := range o.maskHeaders {
+		maskHeader(rec.Request.Header, h)
+		maskHeader(rec.Response.Header, h)
+	}
+	if o.masker != nil {
+		o.masker(rec)
+	}
+}
+
+func maskHeader(header http.Header, key string) {
+	key = http.CanonicalHeaderKey(key)
+	for i := range header[key] {
+		header[key][i] = MaskValue
+	}
+}
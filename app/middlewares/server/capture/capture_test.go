@@ -0,0 +1,100 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDoer struct {
+	uris    []string
+	methods []string
+	bodies  []string
+	headers []string
+}
+
+func (d *mockDoer) Do(_ context.Context, req *protocol.Request, resp *protocol.Response) error {
+	d.uris = append(d.uris, req.URI().String())
+	d.methods = append(d.methods, string(req.Method()))
+	d.bodies = append(d.bodies, string(req.Body()))
+	d.headers = append(d.headers, req.Header.Get("X-Trace"))
+	resp.SetStatusCode(consts.StatusOK)
+	return nil
+}
+
+func newEngine(buf *bytes.Buffer, opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(New(buf, opts...))
+	engine.POST("/echo", func(c context.Context, ctx *app.RequestContext) {
+		ctx.Data(consts.StatusCreated, consts.MIMETextPlain, ctx.Request.Body())
+	})
+	return engine
+}
+
+func TestCaptureAndReplay(t *testing.T) {
+	buf := &bytes.Buffer{}
+	engine := newEngine(buf, WithMasker(func(rec *Record) {
+		rec.Request.Body = bytes.ReplaceAll(rec.Request.Body, []byte("secret"), []byte(MaskValue))
+	}))
+
+	ut.PerformRequest(engine, consts.MethodPost, "http://example.com/echo?a=1", &ut.Body{Body: strings.NewReader("hi secret"), Len: 9},
+		ut.Header{Key: "X-Trace", Value: "t1"},
+		ut.Header{Key: consts.HeaderAuthorization, Value: "Bearer token"})
+
+	var rec Record
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &rec))
+	assert.Equal(t, consts.MethodPost, rec.Request.Method)
+	assert.Equal(t, "/echo?a=1", rec.Request.URI)
+	assert.Equal(t, "example.com", rec.Request.Host)
+	assert.Equal(t, "hi ***", string(rec.Request.Body))
+	assert.Equal(t, MaskValue, rec.Request.Header.Get(consts.HeaderAuthorization))
+	assert.Equal(t, "t1", rec.Request.Header.Get("X-Trace"))
+	assert.Equal(t, consts.StatusCreated, rec.Response.StatusCode)
+	assert.Equal(t, "hi secret", string(rec.Response.Body))
+
+	doer := &mockDoer{}
+	var codes []int
+	err := Replay(bytes.NewReader(buf.Bytes()), doer, WithTargetHost("localhost:8888"),
+		WithReplayCallback(func(rec *Record, resp *protocol.Response, err error) {
+			assert.Nil(t, err)
+			codes = append(codes, resp.StatusCode())
+		}))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"http://localhost:8888/echo?a=1"}, doer.uris)
+	assert.Equal(t, []string{consts.MethodPost}, doer.methods)
+	assert.Equal(t, []string{"hi ***"}, doer.bodies)
+	assert.Equal(t, []string{"t1"}, doer.headers)
+	assert.Equal(t, []int{consts.StatusOK}, codes)
+}
+
+func TestCaptureTruncateAndSample(t *testing.T) {
+	buf := &bytes.Buffer{}
+	engine := newEngine(buf, WithMaxBodySize(2))
+	ut.PerformRequest(engine, consts.MethodPost, "/echo", &ut.Body{Body: strings.NewReader("hello"), Len: 5})
+
+	var rec Record
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &rec))
+	assert.Equal(t, "he", string(rec.Request.Body))
+	assert.True(t, rec.Request.Truncated)
+	assert.True(t, rec.Response.Truncated)
+
+	// 截断的请求不回放
+	doer := &mockDoer{}
+	assert.Nil(t, Replay(bytes.NewReader(buf.Bytes()), doer))
+	assert.Empty(t, doer.uris)
+
+	buf.Reset()
+	engine = newEngine(buf, WithSampleRate(0))
+	ut.PerformRequest(engine, consts.MethodPost, "/echo", &ut.Body{Body: strings.NewReader("hello"), Len: 5})
+	assert.Equal(t, 0, buf.Len())
+}
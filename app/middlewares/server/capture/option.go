@@ -0,0 +1,94 @@
+package capture
+
+import (
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 默认脱敏的标头。
+var defaultMaskHeaders = []string{
+	consts.HeaderAuthorization,
+	consts.HeaderProxyAuthorization,
+	consts.HeaderCookie,
+	consts.HeaderSetCookie,
+}
+
+// 表示一个流量录制的自定义选项结构体。
+type options struct {
+	// 请求体和响应体的最大录制字节数，超出部分截断，0 表示不限制。
+	maxBodySize int
+	// 采样率，取值 [0, 1]。
+	sampleRate float64
+	// 需脱敏的标头，值替换为 MaskValue。
+	maskHeaders []string
+	// 写入前对记录的自定义处理，可用于正文字段脱敏等。
+	masker func(rec *Record)
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		maxBodySize: 64 * 1024,
+		sampleRate:  1,
+		maskHeaders: defaultMaskHeaders,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithMaxBodySize 设置请求体和响应体的最大录制字节数，默认 64KB，0 表示不限制。
+func WithMaxBodySize(size int) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithSampleRate 设置采样率，取值 [0, 1]，默认全部录制。
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithMaskHeaders 设置需脱敏的标头，将覆盖默认的 Authorization、Proxy-Authorization、Cookie 和 Set-Cookie。
+func WithMaskHeaders(headers ...string) Option {
+	return func(o *options) {
+		o.maskHeaders = headers
+	}
+}
+
+// WithMasker 设置写入前对记录的自定义处理，可用于正文字段脱敏等。
+func WithMasker(masker func(rec *Record)) Option {
+	return func(o *options) {
+		o.masker = masker
+	}
+}
+
+// 表示一个流量回放的自定义选项结构体。
+type replayOptions struct {
+	// 回放的目标主机，为空则使用录制时的主机。
+	host string
+	// 每条记录回放后的回调。
+	callback func(rec *Record, resp *protocol.Response, err error)
+}
+
+// ReplayOption 回放自定义选项的应用函数。
+type ReplayOption func(o *replayOptions)
+
+// WithTargetHost 设置回放的目标主机（如 localhost:8888），默认使用录制时的主机。
+func WithTargetHost(host string) ReplayOption {
+	return func(o *replayOptions) {
+		o.host = host
+	}
+}
+
+// WithReplayCallback 设置每条记录回放后的回调，可用于比对录制与回放的响应。
+func WithReplayCallback(fn func(rec *Record, resp *protocol.Response, err error)) ReplayOption {
+	return func(o *replayOptions) {
+		o.callback = fn
+	}
+}
@@ -0,0 +1,76 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/client"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 录制单行的最大长度。
+const maxRecordLineSize = 16 * 1024 * 1024
+
+// Replay 从 r 逐行读取录制的记录并通过 cli 依次重放。
+//
+// cli 通常为 *client.Client。正文被截断的记录将被跳过；
+// 单条请求的失败交由 WithReplayCallback 处理，不中断回放，仅读取或解析记录出错时返回错误。
+func Replay(r io.Reader, cli client.Doer, opts ...ReplayOption) error {
+	cfg := &replayOptions{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		rec := &Record{}
+		if err := json.Unmarshal(line, rec); err != nil {
+			return err
+		}
+		if rec.Request.Truncated {
+			continue
+		}
+
+		req := protocol.AcquireRequest()
+		resp := protocol.AcquireResponse()
+		buildRequest(req, rec, cfg.host)
+		err := cli.Do(context.Background(), req, resp)
+		if cfg.callback != nil {
+			cfg.callback(rec, resp, err)
+		}
+		protocol.ReleaseRequest(req)
+		protocol.ReleaseResponse(resp)
+	}
+	return scanner.Err()
+}
+
+func buildRequest(req *protocol.Request, rec *Record, host string) {
+	if host == "" {
+		host = rec.Request.Host
+	}
+	scheme := rec.Request.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	req.SetRequestURI(scheme + "://" + host + rec.Request.URI)
+	req.SetMethod(rec.Request.Method)
+	for k, vs := range rec.Request.Header {
+		switch http.CanonicalHeaderKey(k) {
+		case consts.HeaderHost, consts.HeaderContentLength:
+			continue
+		}
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.SetBody(rec.Request.Body)
+}
@@ -0,0 +1,226 @@
+// Package compress 提供响应压缩中间件。
+package compress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/compress"
+	"github.com/favbox/wind/common/stackless"
+	"github.com/favbox/wind/internal/bytestr"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 流式压缩时每次从原始正文读取的字节数。
+const streamChunkSize = 4096
+
+// 表示一种内容编码的压缩实现。
+type encoder struct {
+	encoding    []byte
+	appendBytes func(dst, src []byte) []byte
+	acquire     func(w io.Writer) stackless.Writer
+	release     func(zw stackless.Writer)
+}
+
+// Gzip 返回以 gzip 压缩响应体的中间件，level 为 compress 包支持的压缩级别。
+//
+// 处理器执行后，若请求接受 gzip、响应的 Content-Type 在可压缩白名单内且正文长度不小于阈值，
+// 则压缩响应体并设置 Content-Encoding 与 Vary。流式正文边读边压缩，不会缓冲整个正文。
+// 响应已设置 Content-Encoding（如处理器自行压缩或代理透传的已压缩内容）时跳过，避免二次压缩损坏内容。
+func Gzip(level int, opts ...Option) app.HandlerFunc {
+	return newHandler(&encoder{
+		encoding: bytestr.StrGzip,
+		appendBytes: func(dst, src []byte) []byte {
+			return compress.AppendGzipBytesLevel(dst, src, level)
+		},
+		acquire: func(w io.Writer) stackless.Writer {
+			return compress.AcquireStacklessGzipWriter(w, level)
+		},
+		release: func(zw stackless.Writer) {
+			compress.ReleaseStacklessGzipWriter(zw, level)
+		},
+	}, newOptions(opts...))
+}
+
+// Zstd 返回以 zstd 压缩响应体的中间件，level 为 compress 包支持的 Zstd 压缩级别。
+//
+// 行为同 Gzip，仅在请求接受 zstd 时生效。可与 Gzip 一同注册，客户端同时接受两者时，后注册者先完成压缩而优先生效。
+func Zstd(level int, opts ...Option) app.HandlerFunc {
+	return newHandler(&encoder{
+		encoding: bytestr.StrZstd,
+		appendBytes: func(dst, src []byte) []byte {
+			return compress.AppendZstdBytesLevel(dst, src, level)
+		},
+		acquire: func(w io.Writer) stackless.Writer {
+			return compress.AcquireStacklessZstdWriter(w, level)
+		},
+		release: func(zw stackless.Writer) {
+			compress.ReleaseStacklessZstdWriter(zw, level)
+		},
+	}, newOptions(opts...))
+}
+
+// 返回以 enc 压缩响应体的中间件。
+func newHandler(enc *encoder, o *options) app.HandlerFunc {
+	return func(c context.Context, ctx *app.RequestContext) {
+		excluded := o.excluded(ctx)
+		ctx.Next(c)
+
+		if excluded || !ctx.Request.Header.HasAcceptEncodingBytes(enc.encoding) {
+			return
+		}
+		if !o.shouldCompress(ctx) {
+			return
+		}
+
+		resp := &ctx.Response
+		if resp.IsBodyStream() {
+			resp.SetBodyStreamNoReset(newCompressReader(resp.BodyStream(), enc), -1)
+		} else {
+			resp.SetBody(enc.appendBytes(nil, resp.Body()))
+		}
+		resp.Header.SetContentEncodingBytes(enc.encoding)
+		addVary(ctx)
+	}
+}
+
+// 汇报请求路径是否被排除在压缩之外。
+func (o *options) excluded(ctx *app.RequestContext) bool {
+	p := string(ctx.Path())
+	if len(o.excludedExtensions) > 0 {
+		if _, ok := o.excludedExtensions[strings.ToLower(path.Ext(p))]; ok {
+			return true
+		}
+	}
+	for _, prefix := range o.excludedPaths {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 汇报响应是否需要压缩。
+func (o *options) shouldCompress(ctx *app.RequestContext) bool {
+	resp := &ctx.Response
+	// 已编码的响应不再压缩
+	if len(resp.Header.ContentEncoding()) > 0 {
+		return false
+	}
+	if ctx.Request.Header.IsHead() || resp.Header.MustSkipContentLength() {
+		return false
+	}
+	if !o.compressible(resp.Header.ContentType()) {
+		return false
+	}
+	if resp.IsBodyStream() {
+		// 长度未知（< 0）的流式正文总是压缩
+		n := resp.Header.ContentLength()
+		return n < 0 || (n > 0 && n >= o.minLength)
+	}
+	n := len(resp.Body())
+	return n > 0 && n >= o.minLength
+}
+
+// 汇报内容类型是否在可压缩白名单内。
+func (o *options) compressible(contentType []byte) bool {
+	if i := bytes.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(string(contentType)))
+	if mediaType == "" {
+		return false
+	}
+	for _, t := range o.contentTypes {
+		t = strings.ToLower(t)
+		if strings.HasSuffix(t, "/*") {
+			if strings.HasPrefix(mediaType, t[:len(t)-1]) {
+				return true
+			}
+			continue
+		}
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// 在 Vary 中追加 Accept-Encoding。
+func addVary(ctx *app.RequestContext) {
+	vary := ctx.Response.Header.Peek(consts.HeaderVary)
+	if len(vary) == 0 {
+		ctx.Response.Header.Set(consts.HeaderVary, consts.HeaderAcceptEncoding)
+		return
+	}
+	for _, v := range bytes.Split(vary, []byte(",")) {
+		if v = bytes.TrimSpace(v); bytes.EqualFold(v, []byte(consts.HeaderAcceptEncoding)) || bytes.Equal(v, []byte("*")) {
+			return
+		}
+	}
+	ctx.Response.Header.Set(consts.HeaderVary, string(vary)+", "+consts.HeaderAcceptEncoding)
+}
+
+// 边读取原始正文边压缩的读取器。
+//
+// 每读到一块原始数据即压缩并 Flush，使流式响应（如逐步推送的数据）能及时送达客户端。
+type compressReader struct {
+	src   io.Reader
+	enc   *encoder
+	zw    stackless.Writer
+	buf   bytes.Buffer // 已压缩待读取的数据
+	chunk []byte
+	err   error
+}
+
+func newCompressReader(src io.Reader, enc *encoder) *compressReader {
+	r := &compressReader{
+		src:   src,
+		enc:   enc,
+		chunk: make([]byte, streamChunkSize),
+	}
+	r.zw = enc.acquire(&r.buf)
+	return r
+}
+
+func (r *compressReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 && r.err == nil {
+		n, err := r.src.Read(r.chunk)
+		if n > 0 {
+			if _, werr := r.zw.Write(r.chunk[:n]); werr != nil {
+				err = werr
+			} else if werr = r.zw.Flush(); werr != nil {
+				err = werr
+			}
+		}
+		if err != nil {
+			r.err = err
+			// 正常结束时写出压缩尾部
+			r.releaseWriter()
+		}
+	}
+	if r.buf.Len() > 0 {
+		return r.buf.Read(p)
+	}
+	return 0, r.err
+}
+
+// Close 释放压缩写入器并关闭原始正文流。
+func (r *compressReader) Close() error {
+	r.releaseWriter()
+	if c, ok := r.src.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (r *compressReader) releaseWriter() {
+	if r.zw != nil {
+		r.enc.release(r.zw)
+		r.zw = nil
+	}
+}
@@ -0,0 +1,134 @@
+package compress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/compress"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+var text = strings.Repeat("wind ", 100)
+
+func newEngine() *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(Gzip(compress.CompressDefaultCompression))
+	engine.GET("/", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, text)
+	})
+	engine.GET("/gzipped", func(c context.Context, ctx *app.RequestContext) {
+		ctx.Response.Header.Set(consts.HeaderContentEncoding, "gzip")
+		ctx.Data(consts.StatusOK, consts.MIMETextPlainUTF8, compress.AppendGzipBytes(nil, []byte(text)))
+	})
+	return engine
+}
+
+func TestGzip(t *testing.T) {
+	engine := newEngine()
+
+	w := ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip, deflate"})
+	resp := w.Result()
+	assert.Equal(t, "gzip", string(resp.Header.ContentEncoding()))
+	assert.Equal(t, consts.HeaderAcceptEncoding, string(resp.Header.Peek(consts.HeaderVary)))
+	body, err := compress.AppendGunzipBytes(nil, resp.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, text, string(body))
+
+	// 不接受 gzip 时不压缩
+	w = ut.PerformRequest(engine, consts.MethodGet, "/", nil)
+	resp = w.Result()
+	assert.Empty(t, resp.Header.ContentEncoding())
+	assert.Equal(t, text, string(resp.Body()))
+}
+
+func TestGzipSkipEncoded(t *testing.T) {
+	engine := newEngine()
+
+	w := ut.PerformRequest(engine, consts.MethodGet, "/gzipped", nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip"})
+	resp := w.Result()
+	assert.Equal(t, "gzip", string(resp.Header.ContentEncoding()))
+	// 只解压一次即得原文，说明未被二次压缩
+	body, err := compress.AppendGunzipBytes(nil, resp.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, text, string(body))
+}
+
+func TestZstd(t *testing.T) {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(Gzip(compress.CompressDefaultCompression), Zstd(compress.CompressZstdDefaultCompression))
+	engine.GET("/", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, text)
+	})
+
+	w := ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip, zstd"})
+	resp := w.Result()
+	assert.Equal(t, "zstd", string(resp.Header.ContentEncoding()))
+	body, err := compress.AppendUnzstdBytes(nil, resp.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, text, string(body))
+
+	// 不接受 zstd 时回退到 gzip
+	w = ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip"})
+	resp = w.Result()
+	assert.Equal(t, "gzip", string(resp.Header.ContentEncoding()))
+}
+
+func TestGzipOptions(t *testing.T) {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(Gzip(compress.CompressDefaultCompression,
+		WithMinLength(100),
+		WithExcludedExtensions(".PNG"),
+		WithExcludedPaths("/download/")))
+	engine.GET("/*path", func(c context.Context, ctx *app.RequestContext) {
+		if ctx.Query("short") != "" {
+			ctx.String(consts.StatusOK, "short")
+			return
+		}
+		if ctx.Query("bin") != "" {
+			ctx.Data(consts.StatusOK, "application/octet-stream", []byte(text))
+			return
+		}
+		ctx.Data(consts.StatusOK, "application/json; charset=utf-8", []byte(text))
+	})
+	perform := func(url string) string {
+		w := ut.PerformRequest(engine, consts.MethodGet, url, nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip"})
+		return string(w.Result().Header.ContentEncoding())
+	}
+
+	assert.Equal(t, "gzip", perform("/data"))
+	assert.Empty(t, perform("/data?short=1"))
+	assert.Empty(t, perform("/data?bin=1"))
+	assert.Empty(t, perform("/logo.png"))
+	assert.Empty(t, perform("/download/data"))
+}
+
+func TestGzipContentTypes(t *testing.T) {
+	o := newOptions(WithContentTypes("text/*", "application/vnd.api+json"))
+	assert.True(t, o.compressible([]byte("text/css")))
+	assert.True(t, o.compressible([]byte("Application/VND.api+json; charset=utf-8")))
+	assert.False(t, o.compressible([]byte("application/json")))
+	assert.False(t, o.compressible(nil))
+}
+
+func TestGzipStream(t *testing.T) {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(Gzip(compress.CompressDefaultCompression))
+	engine.GET("/stream", func(c context.Context, ctx *app.RequestContext) {
+		ctx.SetContentType(consts.MIMETextPlainUTF8)
+		ctx.SetBodyStream(bytes.NewReader([]byte(text)), -1)
+	})
+
+	w := ut.PerformRequest(engine, consts.MethodGet, "/stream", nil, ut.Header{Key: consts.HeaderAcceptEncoding, Value: "gzip"})
+	resp := w.Result()
+	assert.Equal(t, "gzip", string(resp.Header.ContentEncoding()))
+	body, err := compress.AppendGunzipBytes(nil, resp.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, text, string(body))
+}
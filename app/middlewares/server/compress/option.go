@@ -0,0 +1,75 @@
+package compress
+
+import "strings"
+
+// 默认可压缩的内容类型，以 /* 结尾的表示该大类下的所有子类型。
+var defaultContentTypes = []string{
+	"text/*",
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// 表示一个响应压缩的自定义选项结构体。
+type options struct {
+	// 触发压缩的最小响应体字节数。
+	minLength int
+	// 可压缩的内容类型白名单。
+	contentTypes []string
+	// 不压缩的请求路径扩展名，如 .png。
+	excludedExtensions map[string]struct{}
+	// 不压缩的请求路径前缀。
+	excludedPaths []string
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		contentTypes:       defaultContentTypes,
+		excludedExtensions: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithMinLength 设置触发压缩的最小响应体字节数，小于该值的响应不压缩。
+// 默认为 0，即压缩所有非空响应。长度未知的流式响应总会压缩。
+func WithMinLength(n int) Option {
+	return func(o *options) {
+		o.minLength = n
+	}
+}
+
+// WithContentTypes 设置可压缩的内容类型白名单，覆盖默认值。
+// 类型忽略大小写与参数，以 /* 结尾的如 text/* 匹配该大类下的所有子类型。
+func WithContentTypes(types ...string) Option {
+	return func(o *options) {
+		o.contentTypes = types
+	}
+}
+
+// WithExcludedExtensions 设置不压缩的请求路径扩展名，如 .png、.zip 等已压缩格式。
+func WithExcludedExtensions(exts ...string) Option {
+	return func(o *options) {
+		for _, ext := range exts {
+			o.excludedExtensions[strings.ToLower(ext)] = struct{}{}
+		}
+	}
+}
+
+// WithExcludedPaths 设置不压缩的请求路径前缀，如 /api/download。
+func WithExcludedPaths(paths ...string) Option {
+	return func(o *options) {
+		o.excludedPaths = append(o.excludedPaths, paths...)
+	}
+}
@@ -0,0 +1,129 @@
+package decompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/bytebufferpool"
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 解压输出达到该大小后才检查压缩比，避免小请求因高压缩比被误判。
+const minRatioCheckSize = 64 * 1024
+
+var errRatioExceeded = errors.New("请求体压缩比超过限制")
+
+// New 返回请求体解压中间件。
+//
+// 按 Content-Encoding 流式解压请求体，边解压边检查输出大小与压缩比，超限立即中止：
+// 编码不在白名单返回 415，解压后超过大小限制返回 413，压缩比超限或数据损坏返回 400。
+// 解压成功后替换请求体并移除 Content-Encoding 标头。
+func New(opts ...Option) app.HandlerFunc {
+	o := newOptions(opts...)
+	return func(c context.Context, ctx *app.RequestContext) {
+		ce := strings.TrimSpace(string(ctx.Request.Header.Peek(consts.HeaderContentEncoding)))
+		if ce == "" || strings.EqualFold(ce, "identity") {
+			ctx.Next(c)
+			return
+		}
+		if !o.allowed(ce) {
+			ctx.AbortWithMsg("不支持的内容编码", consts.StatusUnsupportedMediaType)
+			return
+		}
+
+		var src io.Reader
+		if ctx.Request.IsBodyStream() {
+			src = ctx.Request.BodyStream()
+		} else {
+			src = bytes.NewReader(ctx.Request.Body())
+		}
+		buf := bytebufferpool.Get()
+		defer bytebufferpool.Put(buf)
+		if err := o.decompress(buf, src, ce); err != nil {
+			switch {
+			case errors.Is(err, errs.ErrBodyTooLarge):
+				ctx.AbortWithMsg("解压后的请求体过大", consts.StatusRequestEntityTooLarge)
+			case errors.Is(err, errRatioExceeded):
+				ctx.AbortWithMsg("请求体压缩比过高", consts.StatusBadRequest)
+			default:
+				ctx.AbortWithMsg("无法解压请求体", consts.StatusBadRequest)
+			}
+			return
+		}
+
+		ctx.Request.SetBody(buf.B)
+		ctx.Request.Header.Del(consts.HeaderContentEncoding)
+		ctx.Request.Header.SetContentLength(len(buf.B))
+		ctx.Next(c)
+	}
+}
+
+func (o *options) allowed(encoding string) bool {
+	for _, e := range o.allowedEncodings {
+		if strings.EqualFold(e, encoding) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *options) decompress(dst io.Writer, src io.Reader, encoding string) error {
+	in := &countingReader{r: src}
+	var (
+		zr  io.ReadCloser
+		err error
+	)
+	switch strings.ToLower(encoding) {
+	case "gzip":
+		zr, err = gzip.NewReader(in)
+	case "deflate":
+		zr, err = zlib.NewReader(in)
+	default:
+		return errors.New("不支持的内容编码：" + encoding)
+	}
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	_, err = io.Copy(&limitedWriter{w: dst, in: in, options: o}, zr)
+	return err
+}
+
+// 统计已读取的压缩数据字节数。
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// 每次写入解压数据时检查输出大小与压缩比。
+type limitedWriter struct {
+	*options
+	w  io.Writer
+	in *countingReader
+	n  int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	if w.maxDecompressedSize > 0 && w.n > w.maxDecompressedSize {
+		return 0, errs.ErrBodyTooLarge
+	}
+	if w.maxRatio > 0 && w.n > minRatioCheckSize && float64(w.n) > float64(w.in.n)*w.maxRatio {
+		return 0, errRatioExceeded
+	}
+	return w.w.Write(p)
+}
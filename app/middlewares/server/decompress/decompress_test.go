@@ -0,0 +1,87 @@
+package decompress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine(opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(New(opts...))
+	engine.POST("/", func(c context.Context, ctx *app.RequestContext) {
+		ce := string(ctx.Request.Header.Peek(consts.HeaderContentEncoding))
+		ctx.String(consts.StatusOK, ce+string(ctx.Request.Body()))
+	})
+	return engine
+}
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(b)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func deflateBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(b)
+	zw.Close()
+	return buf.Bytes()
+}
+
+func perform(engine *route.Engine, encoding string, body []byte) *ut.ResponseRecorder {
+	return ut.PerformRequest(engine, consts.MethodPost, "/", &ut.Body{Body: bytes.NewReader(body), Len: len(body)},
+		ut.Header{Key: consts.HeaderContentEncoding, Value: encoding})
+}
+
+func TestDecompress(t *testing.T) {
+	engine := newEngine()
+
+	w := perform(engine, "gzip", gzipBytes([]byte("hello")))
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+
+	w = perform(engine, "deflate", deflateBytes([]byte("world")))
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "world", w.Body.String())
+
+	w = perform(engine, "", []byte("plain"))
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "plain", w.Body.String())
+}
+
+func TestDecompressReject(t *testing.T) {
+	// 不在白名单
+	w := perform(newEngine(WithAllowedEncodings("gzip")), "deflate", deflateBytes([]byte("x")))
+	assert.Equal(t, consts.StatusUnsupportedMediaType, w.Code)
+	w = perform(newEngine(), "br", []byte("x"))
+	assert.Equal(t, consts.StatusUnsupportedMediaType, w.Code)
+
+	// 解压后超过大小限制
+	body := gzipBytes([]byte(strings.Repeat("a", 2048)))
+	w = perform(newEngine(WithMaxDecompressedSize(1024), WithMaxRatio(0)), "gzip", body)
+	assert.Equal(t, consts.StatusRequestEntityTooLarge, w.Code)
+
+	// 压缩炸弹
+	bomb := gzipBytes(make([]byte, 10*1024*1024))
+	w = perform(newEngine(WithMaxDecompressedSize(0)), "gzip", bomb)
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	assert.Equal(t, "请求体压缩比过高", w.Body.String())
+
+	// 数据损坏
+	w = perform(newEngine(), "gzip", []byte("not gzip"))
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+}
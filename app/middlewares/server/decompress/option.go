@@ -0,0 +1,48 @@
+package decompress
+
+// 表示一个请求体解压的自定义选项结构体。
+type options struct {
+	// 解压后的最大字节数，0 表示不限制。
+	maxDecompressedSize int64
+	// 允许的内容编码白名单。
+	allowedEncodings []string
+	// 最大压缩比（解压后/解压前），0 表示不限制。
+	maxRatio float64
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		maxDecompressedSize: 16 * 1024 * 1024,
+		allowedEncodings:    []string{"gzip", "deflate"},
+		maxRatio:            100,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithMaxDecompressedSize 设置解压后请求体的最大字节数，超限返回 413。默认 16MB，0 表示不限制。
+func WithMaxDecompressedSize(size int64) Option {
+	return func(o *options) {
+		o.maxDecompressedSize = size
+	}
+}
+
+// WithAllowedEncodings 设置允许的内容编码白名单，其余编码返回 415。
+// 默认 gzip 和 deflate，仅支持这两种编码。
+func WithAllowedEncodings(encodings ...string) Option {
+	return func(o *options) {
+		o.allowedEncodings = encodings
+	}
+}
+
+// WithMaxRatio 设置最大压缩比（解压后/解压前），超限视为压缩炸弹返回 400。默认 100，0 表示不限制。
+func WithMaxRatio(ratio float64) Option {
+	return func(o *options) {
+		o.maxRatio = ratio
+	}
+}
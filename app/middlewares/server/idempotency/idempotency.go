@@ -0,0 +1,115 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/wlog"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 等待处理中请求时的轮询间隔。
+const waitInterval = 10 * time.Millisecond
+
+// New 返回幂等键中间件。
+//
+// 对携带幂等键的写请求，首次处理后将响应存入 store，相同键的重复请求直接返回缓存响应
+// 并添加 Idempotent-Replayed 标头，不再执行处理器。幂等键按请求方法和路径隔离。
+// 5xx 响应、流式响应及处理器恐慌时不缓存，客户端可用相同键重试。
+func New(store Store, opts ...Option) app.HandlerFunc {
+	cfg := newOptions(opts...)
+	return func(c context.Context, ctx *app.RequestContext) {
+		idemKey := ctx.Request.Header.Peek(cfg.header)
+		if len(idemKey) == 0 {
+			ctx.Next(c)
+			return
+		}
+		if _, ok := cfg.methods[string(ctx.Method())]; !ok {
+			ctx.Next(c)
+			return
+		}
+		key := string(ctx.Method()) + " " + string(ctx.Path()) + " " + string(idemKey)
+
+		resp, locked, err := cfg.acquire(c, store, key)
+		if err != nil {
+			wlog.SystemLogger().CtxErrorf(c, "[幂等键] 存储访问失败：%v", err)
+			ctx.AbortWithStatus(consts.StatusInternalServerError)
+			return
+		}
+		if resp != nil {
+			replay(ctx, resp)
+			return
+		}
+		if !locked {
+			ctx.AbortWithMsg("相同幂等键的请求正在处理中", consts.StatusConflict)
+			return
+		}
+
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := store.Unlock(c, key); err != nil {
+				wlog.SystemLogger().CtxErrorf(c, "[幂等键] 释放锁定失败：%v", err)
+			}
+		}()
+
+		ctx.Next(c)
+
+		if ctx.Response.StatusCode() >= consts.StatusInternalServerError || ctx.Response.IsBodyStream() {
+			return
+		}
+		if err = store.Set(c, key, newResponse(ctx), cfg.ttl); err != nil {
+			wlog.SystemLogger().CtxErrorf(c, "[幂等键] 保存响应失败：%v", err)
+			return
+		}
+		stored = true
+	}
+}
+
+// 返回已缓存的响应，或锁定键；相同键处理中时按需等待其缓存响应
+func (o *options) acquire(c context.Context, store Store, key string) (resp *Response, locked bool, err error) {
+	deadline := time.Now().Add(o.waitTimeout)
+	for {
+		if resp, err = store.Get(c, key); err != nil || resp != nil {
+			return
+		}
+		if locked, err = store.Lock(c, key, o.lockTTL); err != nil || locked {
+			return
+		}
+		if !time.Now().Before(deadline) {
+			return
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+func newResponse(ctx *app.RequestContext) *Response {
+	resp := &Response{
+		StatusCode: ctx.Response.StatusCode(),
+		Header:     make(http.Header),
+		Body:       append([]byte(nil), ctx.Response.Body()...),
+	}
+	ctx.Response.Header.VisitAll(func(k, v []byte) {
+		switch string(k) {
+		case consts.HeaderContentLength, consts.HeaderDate, consts.HeaderServer:
+			return
+		}
+		resp.Header.Add(string(k), string(v))
+	})
+	return resp
+}
+
+func replay(ctx *app.RequestContext, resp *Response) {
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			ctx.Response.Header.Add(k, v)
+		}
+	}
+	ctx.Response.Header.Set(consts.HeaderIdempotentReplayed, "true")
+	ctx.Data(resp.StatusCode, string(ctx.Response.Header.ContentType()), resp.Body)
+	ctx.Abort()
+}
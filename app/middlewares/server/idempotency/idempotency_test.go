@@ -0,0 +1,125 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine(calls *int32, delay time.Duration, opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(New(NewMemoryStore(), opts...))
+	engine.POST("/pay", func(c context.Context, ctx *app.RequestContext) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		ctx.Header("X-Order", "o1")
+		ctx.String(consts.StatusCreated, "paid %d", n)
+	})
+	engine.POST("/fail", func(c context.Context, ctx *app.RequestContext) {
+		atomic.AddInt32(calls, 1)
+		ctx.AbortWithStatus(consts.StatusServiceUnavailable)
+	})
+	return engine
+}
+
+func TestIdempotency(t *testing.T) {
+	var calls int32
+	engine := newEngine(&calls, 0)
+	key := ut.Header{Key: consts.HeaderIdempotencyKey, Value: "k1"}
+
+	w := ut.PerformRequest(engine, consts.MethodPost, "/pay", nil, key)
+	assert.Equal(t, consts.StatusCreated, w.Code)
+	assert.Equal(t, "paid 1", w.Body.String())
+	assert.Empty(t, w.Header().Get(consts.HeaderIdempotentReplayed))
+
+	w = ut.PerformRequest(engine, consts.MethodPost, "/pay", nil, key)
+	assert.Equal(t, consts.StatusCreated, w.Code)
+	assert.Equal(t, "paid 1", w.Body.String())
+	assert.Equal(t, "o1", w.Header().Get("X-Order"))
+	assert.Equal(t, "true", w.Header().Get(consts.HeaderIdempotentReplayed))
+	assert.Equal(t, int32(1), calls)
+
+	// 无幂等键或不同键正常处理
+	w = ut.PerformRequest(engine, consts.MethodPost, "/pay", nil)
+	assert.Equal(t, "paid 2", w.Body.String())
+	w = ut.PerformRequest(engine, consts.MethodPost, "/pay", nil, ut.Header{Key: consts.HeaderIdempotencyKey, Value: "k2"})
+	assert.Equal(t, "paid 3", w.Body.String())
+
+	// 5xx 不缓存，可重试
+	calls = 0
+	ut.PerformRequest(engine, consts.MethodPost, "/fail", nil, key)
+	ut.PerformRequest(engine, consts.MethodPost, "/fail", nil, key)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestIdempotencyConcurrent(t *testing.T) {
+	key := ut.Header{Key: consts.HeaderIdempotencyKey, Value: "k1"}
+	concurrent := func(engine *route.Engine) []int {
+		var wg sync.WaitGroup
+		codes := make([]int, 2)
+		for i := range codes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				time.Sleep(time.Duration(i) * 20 * time.Millisecond)
+				codes[i] = ut.PerformRequest(engine, consts.MethodPost, "/pay", nil, key).Code
+			}(i)
+		}
+		wg.Wait()
+		return codes
+	}
+
+	var calls int32
+	assert.Equal(t, []int{consts.StatusCreated, consts.StatusConflict}, concurrent(newEngine(&calls, 100*time.Millisecond)))
+
+	calls = 0
+	codes := concurrent(newEngine(&calls, 100*time.Millisecond, WithWaitTimeout(time.Second)))
+	assert.Equal(t, []int{consts.StatusCreated, consts.StatusCreated}, codes)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestMemoryStoreTTL(t *testing.T) {
+	s := NewMemoryStore()
+	ok, _ := s.Lock(context.Background(), "k", 10*time.Millisecond)
+	assert.True(t, ok)
+	ok, _ = s.Lock(context.Background(), "k", 10*time.Millisecond)
+	assert.False(t, ok)
+	time.Sleep(20 * time.Millisecond)
+	ok, _ = s.Lock(context.Background(), "k", 10*time.Millisecond)
+	assert.True(t, ok)
+
+	assert.Nil(t, s.Set(context.Background(), "k", &Response{StatusCode: 200}, 10*time.Millisecond))
+	resp, _ := s.Get(context.Background(), "k")
+	assert.Equal(t, 200, resp.StatusCode)
+	time.Sleep(20 * time.Millisecond)
+	resp, _ = s.Get(context.Background(), "k")
+	assert.Nil(t, resp)
+	assert.Nil(t, s.Close())
+	assert.Nil(t, s.Close())
+}
+
+func TestMemoryStoreCleanup(t *testing.T) {
+	s := NewMemoryStore()
+	defer s.Close()
+	ctx := context.Background()
+	_, _ = s.Lock(ctx, "locked", 10*time.Millisecond)
+	_ = s.Set(ctx, "expired", &Response{StatusCode: 200}, 10*time.Millisecond)
+	_ = s.Set(ctx, "alive", &Response{StatusCode: 200}, time.Hour)
+	time.Sleep(20 * time.Millisecond)
+
+	// 从未再次访问的过期键同样被清理
+	s.deleteExpired()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Len(t, s.entries, 1)
+	assert.NotNil(t, s.entries["alive"])
+}
@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 表示一个幂等键的自定义选项结构体。
+type options struct {
+	// 携带幂等键的标头键名。
+	header string
+	// 生效的请求方法。
+	methods map[string]struct{}
+	// 缓存响应的有效期。
+	ttl time.Duration
+	// 处理中的锁定有效期，防止进程异常退出后键被永久锁定。
+	lockTTL time.Duration
+	// 相同键的请求处理中时，后续请求等待缓存响应的最长时间，0 表示立即返回 409。
+	waitTimeout time.Duration
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		header:  consts.HeaderIdempotencyKey,
+		ttl:     24 * time.Hour,
+		lockTTL: time.Minute,
+	}
+	WithMethods(consts.MethodPost, consts.MethodPut, consts.MethodPatch, consts.MethodDelete)(cfg)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithHeader 自定义携带幂等键的标头键名，默认为 Idempotency-Key。
+func WithHeader(header string) Option {
+	return func(o *options) {
+		o.header = header
+	}
+}
+
+// WithMethods 设置生效的请求方法，默认为 POST、PUT、PATCH 和 DELETE。
+func WithMethods(methods ...string) Option {
+	return func(o *options) {
+		o.methods = make(map[string]struct{}, len(methods))
+		for _, m := range methods {
+			o.methods[m] = struct{}{}
+		}
+	}
+}
+
+// WithTTL 设置缓存响应的有效期，默认 24 小时。
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithLockTTL 设置处理中的锁定有效期，默认 1 分钟，应大于处理器的最长耗时。
+func WithLockTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.lockTTL = ttl
+	}
+}
+
+// WithWaitTimeout 设置相同键的请求处理中时，后续请求等待缓存响应的最长时间。
+//
+// 默认为 0，即立即返回 409 Conflict；等待超时同样返回 409。
+func WithWaitTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.waitTimeout = timeout
+	}
+}
@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response 表示一个缓存的响应。
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// Store 是幂等键的存储，可基于 Redis 等实现以支持多实例部署。
+type Store interface {
+	// Get 返回键对应的已缓存响应，不存在时返回 nil。
+	Get(c context.Context, key string) (*Response, error)
+	// Lock 尝试锁定键以开始处理，键已被锁定或已有缓存响应时返回 false。
+	Lock(c context.Context, key string, ttl time.Duration) (bool, error)
+	// Unlock 释放键的锁定，以便请求可被重试。
+	Unlock(c context.Context, key string) error
+	// Set 保存键对应的响应并释放锁定。
+	Set(c context.Context, key string, resp *Response, ttl time.Duration) error
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// 内存存储定期清理过期键的间隔。
+const cleanupInterval = time.Minute
+
+// MemoryStore 是基于内存的 Store 实现，仅适用于单实例部署。
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type memoryEntry struct {
+	resp     *Response // 为空表示处理中
+	expireAt time.Time
+}
+
+// NewMemoryStore 创建一个基于内存的 Store。
+//
+// 过期的键在访问时惰性清理，并由后台协程每分钟清理一次，不再使用时应调用 Close 停止后台协程。
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		stop:    make(chan struct{}),
+	}
+	go s.cleanup(cleanupInterval)
+	return s
+}
+
+// Close 停止后台清理协程，可重复调用。
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+	return nil
+}
+
+// 每隔 interval 清理一次过期的键，直至 Close。
+func (s *MemoryStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.deleteExpired()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// 删除所有过期的键。
+func (s *MemoryStore) deleteExpired() {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, e := range s.entries {
+		if now.After(e.expireAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.entry(key); e != nil {
+		return e.resp, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entry(key) != nil {
+		return false, nil
+	}
+	s.entries[key] = &memoryEntry{expireAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) Unlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e := s.entries[key]; e != nil && e.resp == nil {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, resp *Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &memoryEntry{resp: resp, expireAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// 返回未过期的条目，调用方须持有锁
+func (s *MemoryStore) entry(key string) *memoryEntry {
+	e := s.entries[key]
+	if e == nil {
+		return nil
+	}
+	if time.Now().After(e.expireAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,67 @@
+package loadshed
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// New 返回负载降级中间件。
+//
+// 并发请求数、协程数或自定义指标任一超过阈值即视为过载，过载时按比例拒绝非优先请求，
+// 返回 503 及 Retry-After 标头。协程数按采样间隔缓存，不会每个请求都统计。
+func New(opts ...Option) app.HandlerFunc {
+	s := &shedder{options: newOptions(opts...)}
+	retryAfter := strconv.Itoa(int((s.retryAfter + time.Second - 1) / time.Second))
+	return func(c context.Context, ctx *app.RequestContext) {
+		n := atomic.AddInt64(&s.inFlight, 1)
+		defer atomic.AddInt64(&s.inFlight, -1)
+
+		if s.overload(n) && s.shouldShed(ctx) {
+			ctx.AbortWithMsg("服务过载，请稍后重试", consts.StatusServiceUnavailable)
+			ctx.Header(consts.HeaderRetryAfter, retryAfter)
+			return
+		}
+		ctx.Next(c)
+	}
+}
+
+type shedder struct {
+	*options
+	inFlight   int64
+	goroutines int64
+	sampledAt  int64 // 上次采样协程数的时间（纳秒）
+}
+
+func (s *shedder) overload(inFlight int64) bool {
+	if s.maxInFlight > 0 && inFlight > s.maxInFlight {
+		return true
+	}
+	if s.maxGoroutines > 0 && s.numGoroutine() > s.maxGoroutines {
+		return true
+	}
+	return s.overloaded != nil && s.overloaded()
+}
+
+func (s *shedder) shouldShed(ctx *app.RequestContext) bool {
+	if s.priority != nil && s.priority(ctx) {
+		return false
+	}
+	return s.shedRatio >= 1 || rand.Float64() < s.shedRatio
+}
+
+// 返回采样的协程数，超过采样间隔时重新统计
+func (s *shedder) numGoroutine() int {
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&s.sampledAt)
+	if now-last >= int64(s.sampleInterval) && atomic.CompareAndSwapInt64(&s.sampledAt, last, now) {
+		atomic.StoreInt64(&s.goroutines, int64(runtime.NumGoroutine()))
+	}
+	return int(atomic.LoadInt64(&s.goroutines))
+}
@@ -0,0 +1,69 @@
+package loadshed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine(opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(New(opts...))
+	engine.GET("/", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "ok")
+	})
+	return engine
+}
+
+func TestLoadShedInFlight(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	engine := newEngine(WithMaxInFlight(1), WithRetryAfter(1500*time.Millisecond),
+		WithPriority(func(ctx *app.RequestContext) bool {
+			return string(ctx.Request.Header.Peek("X-Priority")) == "high"
+		}))
+	engine.GET("/slow", func(c context.Context, ctx *app.RequestContext) {
+		close(entered)
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ut.PerformRequest(engine, consts.MethodGet, "/slow", nil)
+		close(done)
+	}()
+	<-entered
+
+	w := ut.PerformRequest(engine, consts.MethodGet, "/", nil)
+	assert.Equal(t, consts.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get(consts.HeaderRetryAfter))
+
+	w = ut.PerformRequest(engine, consts.MethodGet, "/", nil, ut.Header{Key: "X-Priority", Value: "high"})
+	assert.Equal(t, consts.StatusOK, w.Code)
+
+	close(release)
+	<-done
+	w = ut.PerformRequest(engine, consts.MethodGet, "/", nil)
+	assert.Equal(t, consts.StatusOK, w.Code)
+}
+
+func TestLoadShedOverload(t *testing.T) {
+	overloaded := true
+	engine := newEngine(WithOverloadFunc(func() bool { return overloaded }))
+	assert.Equal(t, consts.StatusServiceUnavailable, ut.PerformRequest(engine, consts.MethodGet, "/", nil).Code)
+	overloaded = false
+	assert.Equal(t, consts.StatusOK, ut.PerformRequest(engine, consts.MethodGet, "/", nil).Code)
+
+	engine = newEngine(WithMaxGoroutines(1))
+	assert.Equal(t, consts.StatusServiceUnavailable, ut.PerformRequest(engine, consts.MethodGet, "/", nil).Code)
+
+	// 拒绝比例为 0 时不拒绝
+	engine = newEngine(WithMaxGoroutines(1), WithShedRatio(0))
+	assert.Equal(t, consts.StatusOK, ut.PerformRequest(engine, consts.MethodGet, "/", nil).Code)
+}
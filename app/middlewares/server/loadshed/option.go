@@ -0,0 +1,89 @@
+package loadshed
+
+import (
+	"time"
+
+	"github.com/favbox/wind/app"
+)
+
+// 表示一个负载降级的自定义选项结构体。
+type options struct {
+	// 最大并发请求数，0 表示不限制。
+	maxInFlight int64
+	// 最大协程数，0 表示不限制。
+	maxGoroutines int
+	// 协程数的采样间隔。
+	sampleInterval time.Duration
+	// 自定义过载判定，可基于队列延迟等指标。
+	overloaded func() bool
+	// 过载时非优先请求的拒绝比例，取值 [0, 1]。
+	shedRatio float64
+	// 判定优先请求，优先请求不会被拒绝。
+	priority func(ctx *app.RequestContext) bool
+	// 拒绝时 Retry-After 标头的建议重试间隔。
+	retryAfter time.Duration
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		sampleInterval: 100 * time.Millisecond,
+		shedRatio:      1,
+		retryAfter:     time.Second,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithMaxInFlight 设置最大并发请求数，超过即视为过载。默认不限制。
+func WithMaxInFlight(n int) Option {
+	return func(o *options) {
+		o.maxInFlight = int64(n)
+	}
+}
+
+// WithMaxGoroutines 设置最大协程数，超过即视为过载。默认不限制。
+func WithMaxGoroutines(n int) Option {
+	return func(o *options) {
+		o.maxGoroutines = n
+	}
+}
+
+// WithSampleInterval 设置协程数的采样间隔，默认 100 毫秒。
+func WithSampleInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.sampleInterval = interval
+	}
+}
+
+// WithOverloadFunc 设置自定义过载判定，可基于队列延迟、CPU 使用率等指标，每个请求调用一次，须低开销。
+func WithOverloadFunc(f func() bool) Option {
+	return func(o *options) {
+		o.overloaded = f
+	}
+}
+
+// WithShedRatio 设置过载时非优先请求的拒绝比例，取值 [0, 1]，默认全部拒绝。
+func WithShedRatio(ratio float64) Option {
+	return func(o *options) {
+		o.shedRatio = ratio
+	}
+}
+
+// WithPriority 设置优先请求的判定，优先请求在过载时仍会被处理。
+func WithPriority(f func(ctx *app.RequestContext) bool) Option {
+	return func(o *options) {
+		o.priority = f
+	}
+}
+
+// WithRetryAfter 设置拒绝时 Retry-After 标头的建议重试间隔，默认 1 秒，按秒向上取整。
+func WithRetryAfter(d time.Duration) Option {
+	return func(o *options) {
+		o.retryAfter = d
+	}
+}
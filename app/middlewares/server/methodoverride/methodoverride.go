@@ -0,0 +1,82 @@
+package methodoverride
+
+import (
+	"context"
+	"strings"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/internal/bytesconv"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+)
+
+// 允许覆盖成的目标方法。
+var allowedMethods = map[string]struct{}{
+	consts.MethodPut:    {},
+	consts.MethodPatch:  {},
+	consts.MethodDelete: {},
+}
+
+// 表示一个方法覆盖的自定义选项结构体。
+type options struct {
+	// 携带真实方法的标头键名。
+	header string
+	// 携带真实方法的表单字段名。
+	formField string
+}
+
+// Option 自定义选项的应用函数。
+type Option func(o *options)
+
+func newOptions(opts ...Option) *options {
+	cfg := &options{
+		header:    consts.HeaderXHTTPMethodOverride,
+		formField: "_method",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithHeader 自定义携带真实方法的标头键名，默认为 X-HTTP-Method-Override，为空则不读取标头。
+func WithHeader(header string) Option {
+	return func(o *options) {
+		o.header = header
+	}
+}
+
+// WithFormField 自定义携带真实方法的表单字段名，默认为 _method，为空则不读取表单。
+func WithFormField(field string) Option {
+	return func(o *options) {
+		o.formField = field
+	}
+}
+
+// New 返回 HTTP 方法覆盖的路由预处理钩子。
+//
+// 仅 POST 请求可被覆盖，且只能覆盖为 PUT、PATCH 或 DELETE，标头优先于表单字段。
+// 需在路由匹配前生效，用法：
+//
+//	h.PreRouting(methodoverride.New())
+func New(opts ...Option) route.PreRoutingFunc {
+	cfg := newOptions(opts...)
+	return func(c context.Context, ctx *app.RequestContext) bool {
+		if bytesconv.B2s(ctx.Request.Header.Method()) != consts.MethodPost {
+			return true
+		}
+
+		var method string
+		if cfg.header != "" {
+			method = ctx.Request.Header.Get(cfg.header)
+		}
+		if method == "" && cfg.formField != "" {
+			method = ctx.PostForm(cfg.formField)
+		}
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if _, ok := allowedMethods[method]; ok {
+			ctx.Request.Header.SetMethod(method)
+		}
+		return true
+	}
+}
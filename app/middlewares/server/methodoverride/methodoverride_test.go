@@ -0,0 +1,64 @@
+package methodoverride
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/common/ut"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEngine(opts ...Option) *route.Engine {
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.PreRouting(New(opts...))
+	handler := func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, string(ctx.Method()))
+	}
+	engine.POST("/user", handler)
+	engine.PUT("/user", handler)
+	engine.DELETE("/user", handler)
+	engine.GET("/user", handler)
+	return engine
+}
+
+func TestMethodOverrideHeader(t *testing.T) {
+	engine := newEngine()
+
+	w := ut.PerformRequest(engine, consts.MethodPost, "/user", nil, ut.Header{Key: consts.HeaderXHTTPMethodOverride, Value: "put"})
+	assert.Equal(t, "PUT", w.Body.String())
+
+	// 仅允许覆盖为 PUT/PATCH/DELETE
+	w = ut.PerformRequest(engine, consts.MethodPost, "/user", nil, ut.Header{Key: consts.HeaderXHTTPMethodOverride, Value: "GET"})
+	assert.Equal(t, "POST", w.Body.String())
+
+	// 仅 POST 可被覆盖
+	w = ut.PerformRequest(engine, consts.MethodGet, "/user", nil, ut.Header{Key: consts.HeaderXHTTPMethodOverride, Value: "DELETE"})
+	assert.Equal(t, "GET", w.Body.String())
+}
+
+func TestMethodOverrideForm(t *testing.T) {
+	engine := newEngine()
+	body := &ut.Body{Body: strings.NewReader("_method=DELETE"), Len: len("_method=DELETE")}
+	w := ut.PerformRequest(engine, consts.MethodPost, "/user", body, ut.Header{Key: consts.HeaderContentType, Value: consts.MIMEApplicationHTMLForm})
+	assert.Equal(t, "DELETE", w.Body.String())
+
+	engine = newEngine(WithFormField(""))
+	body = &ut.Body{Body: strings.NewReader("_method=DELETE"), Len: len("_method=DELETE")}
+	w = ut.PerformRequest(engine, consts.MethodPost, "/user", body, ut.Header{Key: consts.HeaderContentType, Value: consts.MIMEApplicationHTMLForm})
+	assert.Equal(t, "POST", w.Body.String())
+}
+
+func TestOption(t *testing.T) {
+	opts := newOptions()
+	assert.Equal(t, consts.HeaderXHTTPMethodOverride, opts.header)
+	assert.Equal(t, "_method", opts.formField)
+
+	opts = newOptions(WithHeader("X-Method"), WithFormField("method"))
+	assert.Equal(t, "X-Method", opts.header)
+	assert.Equal(t, "method", opts.formField)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"io"
+	"mime/multipart"
+	"net/textproto"
+
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// MultipartWriter 是复合（multipart）响应的写入器。
+//
+// 各部分依次写入响应正文：若响应写入器已被劫持（如分块写入器），
+// 则随写随发；否则缓存在响应正文中，由框架预计算 Content-Length。
+type MultipartWriter struct {
+	mw *multipart.Writer
+}
+
+// Boundary 返回复合响应的边界值。
+func (w *MultipartWriter) Boundary() string {
+	return w.mw.Boundary()
+}
+
+// CreatePart 以给定的头信息创建一个新的部分，并返回该部分正文的写入器。
+//
+// 写入新部分前，上一部分的正文须已写完。
+func (w *MultipartWriter) CreatePart(header textproto.MIMEHeader) (io.Writer, error) {
+	return w.mw.CreatePart(header)
+}
+
+// WritePart 是 CreatePart 的快捷方式，写入给定内容类型和正文的部分。
+func (w *MultipartWriter) WritePart(contentType string, body []byte) error {
+	header := make(textproto.MIMEHeader)
+	if contentType != "" {
+		header.Set(consts.HeaderContentType, contentType)
+	}
+	pw, err := w.mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = pw.Write(body)
+	return err
+}
+
+// Close 写入结尾边界，结束复合响应。
+func (w *MultipartWriter) Close() error {
+	return w.mw.Close()
+}
+
+// MultipartResponse 创建一个复合响应写入器，并设置带有边界值的响应内容类型。
+//
+// contentType 为复合类型（如 "multipart/mixed"），为空则默认使用 "multipart/mixed"。
+//
+//	mw := ctx.MultipartResponse("multipart/mixed")
+//	_ = mw.WritePart("application/json", []byte(`{"id":1}`))
+//	_ = mw.WritePart("text/plain", []byte("hello"))
+//	_ = mw.Close()
+func (ctx *RequestContext) MultipartResponse(contentType string) *MultipartWriter {
+	if contentType == "" {
+		contentType = "multipart/mixed"
+	}
+	mw := multipart.NewWriter(ctx.Response.BodyWriter())
+	ctx.SetContentType(contentType + "; boundary=" + mw.Boundary())
+	return &MultipartWriter{mw: mw}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipartResponse(t *testing.T) {
+	ctx := NewContext(0)
+	mw := ctx.MultipartResponse("")
+
+	mediaType, params, err := mime.ParseMediaType(string(ctx.Response.Header.ContentType()))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+	assert.Equal(t, mw.Boundary(), params["boundary"])
+
+	assert.Nil(t, mw.WritePart("application/json", []byte(`{"id":1}`)))
+	header := make(textproto.MIMEHeader)
+	header.Set("X-Part", "2")
+	pw, err := mw.CreatePart(header)
+	assert.Nil(t, err)
+	_, _ = pw.Write([]byte("hello"))
+	assert.Nil(t, mw.Close())
+
+	mr := multipart.NewReader(strings.NewReader(string(ctx.Response.Body())), params["boundary"])
+	p, err := mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "application/json", p.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(p)
+	assert.Equal(t, `{"id":1}`, string(b))
+
+	p, err = mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "2", p.Header.Get("X-Part"))
+	b, _ = io.ReadAll(p)
+	assert.Equal(t, "hello", string(b))
+
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
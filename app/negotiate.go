@@ -0,0 +1,129 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// Negotiate 是内容协商渲染的配置。
+//
+// 各格式专属数据为空时使用 Data。
+type Negotiate struct {
+	Offered  []string // 可提供的 MIME 类型，按优先级排列
+	HTMLName string   // HTML 模板名称
+	HTMLData any
+	JSONData any
+	XMLData  any
+	Data     any
+}
+
+// Negotiate 按请求的 Accept 标头在 config.Offered 中选出最佳的 MIME 类型并渲染响应。
+//
+// 支持 JSON、XML、HTML 及 text/plain，无可接受的类型时以 406 中止请求。
+func (ctx *RequestContext) Negotiate(code int, config Negotiate) {
+	format, _, _ := strings.Cut(ctx.NegotiateFormat(config.Offered...), ";")
+	switch strings.TrimSpace(format) {
+	case consts.MIMEApplicationJSON:
+		ctx.JSON(code, chooseData(config.JSONData, config.Data))
+	case consts.MIMEApplicationXML, consts.MIMETextXML:
+		ctx.XML(code, chooseData(config.XMLData, config.Data))
+	case consts.MIMETextHtml:
+		ctx.HTML(code, config.HTMLName, chooseData(config.HTMLData, config.Data))
+	case consts.MIMETextPlain:
+		ctx.String(code, "%v", config.Data)
+	default:
+		_ = ctx.AbortWithError(consts.StatusNotAcceptable, errs.ErrNotAcceptable)
+	}
+}
+
+// NegotiateFormat 按请求的 Accept 标头在 offered 中选出最佳的 MIME 类型。
+//
+// 每个类型的质量取最具体的匹配媒体范围的 q 值，支持 type/* 与 */* 通配，q=0 表示不可接受；
+// 选择质量最高者，同质量时按 offered 的顺序。
+// Accept 为空或无合法媒体范围时返回 offered[0]，无可接受的类型时返回空字符串。
+func (ctx *RequestContext) NegotiateFormat(offered ...string) string {
+	if len(offered) == 0 {
+		return ""
+	}
+	accept := ctx.Request.Header.Get(consts.HeaderAccept)
+	if accept == "" {
+		return offered[0]
+	}
+
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		// 无合法媒体范围时视同未指定
+		return offered[0]
+	}
+	best, bestQ := "", 0.0
+	for _, o := range offered {
+		if q := quality(ranges, o); q > bestQ {
+			best, bestQ = o, q
+		}
+	}
+	return best
+}
+
+func chooseData(custom, wildcard any) any {
+	if custom != nil {
+		return custom
+	}
+	return wildcard
+}
+
+// 媒体范围，如 text/html;q=0.8。
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+	specificity  int // 0: */*，1: type/*，2: type/subtype
+}
+
+func (r acceptRange) match(mime string) bool {
+	mime, _, _ = strings.Cut(mime, ";")
+	typ, subtype, _ := strings.Cut(strings.TrimSpace(mime), "/")
+	return (r.typ == "*" || strings.EqualFold(r.typ, typ)) &&
+		(r.subtype == "*" || strings.EqualFold(r.subtype, subtype))
+}
+
+// 返回 mime 的质量，即最具体的匹配媒体范围的 q 值，无匹配时为 0。
+func quality(ranges []acceptRange, mime string) float64 {
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		if r.specificity > specificity && r.match(mime) {
+			q, specificity = r.q, r.specificity
+		}
+	}
+	return q
+}
+
+// 解析 Accept 标头中的媒体范围。
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.TrimSpace(mediaRange), "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+		r := acceptRange{typ: typ, subtype: subtype, q: 1, specificity: 2}
+		if subtype == "*" {
+			r.specificity = 1
+			if typ == "*" {
+				r.specificity = 0
+			}
+		}
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && q >= 0 && q <= 1 {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
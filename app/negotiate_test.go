@@ -0,0 +1,73 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_NegotiateFormat(t *testing.T) {
+	offered := []string{consts.MIMEApplicationJSON, consts.MIMEApplicationXML, consts.MIMETextHtml}
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", consts.MIMEApplicationJSON},
+		{"application/xml", consts.MIMEApplicationXML},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", consts.MIMETextHtml},
+		{"application/json;q=0.5, application/xml", consts.MIMEApplicationXML},
+		{"*/*", consts.MIMEApplicationJSON},
+		{"text/*", consts.MIMETextHtml},
+		{"application/*;q=0.2, text/html;q=0.1", consts.MIMEApplicationJSON},
+		// 最具体的匹配决定质量
+		{"*/*;q=0.8, application/json;q=0.1", consts.MIMEApplicationXML},
+		{"*/*, application/json;q=0, application/xml;q=0", consts.MIMETextHtml},
+		{"image/png", ""},
+		{"application/json;q=0", ""},
+		{"invalid, */json", consts.MIMEApplicationJSON},
+	}
+	for _, c := range cases {
+		ctx := NewContext(0)
+		if c.accept != "" {
+			ctx.Request.Header.Set(consts.HeaderAccept, c.accept)
+		}
+		assert.Equal(t, c.want, ctx.NegotiateFormat(offered...), c.accept)
+	}
+
+	ctx := NewContext(0)
+	assert.Equal(t, "", ctx.NegotiateFormat())
+	ctx.Request.Header.Set(consts.HeaderAccept, "application/json")
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, ctx.NegotiateFormat(consts.MIMEApplicationJSONUTF8))
+}
+
+func TestRequestContext_Negotiate(t *testing.T) {
+	type data struct {
+		Name string `json:"name" xml:"name"`
+	}
+	offered := []string{consts.MIMEApplicationJSON, consts.MIMEApplicationXML, consts.MIMETextPlain}
+
+	ctx := NewContext(0)
+	ctx.Request.Header.Set(consts.HeaderAccept, "application/json")
+	ctx.Negotiate(consts.StatusOK, Negotiate{Offered: offered, Data: data{"wind"}})
+	assert.Equal(t, `{"name":"wind"}`, string(ctx.Response.Body()))
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(ctx.Response.Header.ContentType()))
+
+	ctx = NewContext(0)
+	ctx.Request.Header.Set(consts.HeaderAccept, "application/xml, application/json;q=0.9")
+	ctx.Negotiate(consts.StatusCreated, Negotiate{Offered: offered, Data: data{"wind"}, XMLData: data{"xml"}})
+	assert.Equal(t, consts.StatusCreated, ctx.Response.StatusCode())
+	assert.Equal(t, `<data><name>xml</name></data>`, string(ctx.Response.Body()))
+
+	ctx = NewContext(0)
+	ctx.Request.Header.Set(consts.HeaderAccept, "text/plain")
+	ctx.Negotiate(consts.StatusOK, Negotiate{Offered: offered, Data: "hello"})
+	assert.Equal(t, "hello", string(ctx.Response.Body()))
+
+	ctx = NewContext(0)
+	ctx.Request.Header.Set(consts.HeaderAccept, "image/png")
+	ctx.Negotiate(consts.StatusOK, Negotiate{Offered: offered, Data: "hello"})
+	assert.Equal(t, consts.StatusNotAcceptable, ctx.Response.StatusCode())
+	assert.True(t, ctx.IsAborted())
+	assert.Equal(t, 1, len(ctx.Errors))
+}
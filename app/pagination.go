@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	errs "github.com/favbox/wind/common/errors"
+)
+
+const (
+	defaultPageKey     = "page"
+	defaultPageSizeKey = "pageSize"
+	defaultSortKey     = "sort"
+	defaultPageSize    = 20
+	defaultMaxPageSize = 100
+)
+
+// PaginationOptions 是分页参数的解析配置，零值字段使用默认值。
+type PaginationOptions struct {
+	PageKey         string   // 页码参数名，默认 page
+	PageSizeKey     string   // 每页条数参数名，默认 pageSize
+	SortKey         string   // 排序参数名，默认 sort
+	DefaultPageSize int      // 默认每页条数，默认 20
+	MaxPageSize     int      // 每页条数上限，默认 100
+	DefaultSort     string   // 默认排序
+	SortFields      []string // 允许排序的字段，非空时排序字段（去掉 +/- 前缀）须在其中
+}
+
+// Pagination 是解析后的分页参数。
+type Pagination struct {
+	Page     int    // 页码，从 1 开始
+	PageSize int    // 每页条数
+	Offset   int    // 偏移量，即 (Page-1)*PageSize
+	Limit    int    // 条数上限，同 PageSize
+	Sort     string // 排序，如 -created_at
+}
+
+// Pagination 解析查询参数中的分页参数，opts 为 nil 时使用默认配置。
+//
+// 非法值回退为默认值，超过上限的每页条数截断为上限。
+func (ctx *RequestContext) Pagination(opts *PaginationOptions) Pagination {
+	p, _ := ctx.PaginationE(opts)
+	return p
+}
+
+// PaginationE 同 Pagination，但参数非法时一并返回 errors.ErrInvalidPagination 包装的错误。
+//
+// 即便返回错误，返回的分页参数也已回退为默认值，可直接使用。
+func (ctx *RequestContext) PaginationE(opts *PaginationOptions) (Pagination, error) {
+	o := PaginationOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.PageKey == "" {
+		o.PageKey = defaultPageKey
+	}
+	if o.PageSizeKey == "" {
+		o.PageSizeKey = defaultPageSizeKey
+	}
+	if o.SortKey == "" {
+		o.SortKey = defaultSortKey
+	}
+	if o.MaxPageSize <= 0 {
+		o.MaxPageSize = defaultMaxPageSize
+	}
+	if o.DefaultPageSize <= 0 {
+		o.DefaultPageSize = defaultPageSize
+	}
+	if o.DefaultPageSize > o.MaxPageSize {
+		o.DefaultPageSize = o.MaxPageSize
+	}
+
+	var err error
+	p := Pagination{Page: 1, PageSize: o.DefaultPageSize, Sort: o.DefaultSort}
+
+	if v, ok := ctx.GetQuery(o.PageKey); ok && v != "" {
+		if n, e := strconv.Atoi(v); e == nil && n > 0 {
+			p.Page = n
+		} else {
+			err = fmt.Errorf("%w: %s=%q", errs.ErrInvalidPagination, o.PageKey, v)
+		}
+	}
+	if v, ok := ctx.GetQuery(o.PageSizeKey); ok && v != "" {
+		if n, e := strconv.Atoi(v); e == nil && n > 0 {
+			p.PageSize = n
+			if n > o.MaxPageSize {
+				p.PageSize = o.MaxPageSize
+				if err == nil {
+					err = fmt.Errorf("%w: %s=%d 超过上限 %d", errs.ErrInvalidPagination, o.PageSizeKey, n, o.MaxPageSize)
+				}
+			}
+		} else if err == nil {
+			err = fmt.Errorf("%w: %s=%q", errs.ErrInvalidPagination, o.PageSizeKey, v)
+		}
+	}
+	if v, ok := ctx.GetQuery(o.SortKey); ok && v != "" {
+		if sortAllowed(v, o.SortFields) {
+			p.Sort = v
+		} else if err == nil {
+			err = fmt.Errorf("%w: %s=%q", errs.ErrInvalidPagination, o.SortKey, v)
+		}
+	}
+
+	// 防止偏移量溢出
+	if p.Page-1 > math.MaxInt/p.PageSize {
+		p.Page = 1
+		if err == nil {
+			err = fmt.Errorf("%w: %s 过大", errs.ErrInvalidPagination, o.PageKey)
+		}
+	}
+	p.Offset = (p.Page - 1) * p.PageSize
+	p.Limit = p.PageSize
+	return p, err
+}
+
+// 判断排序字段是否在白名单中，白名单为空时均允许。
+func sortAllowed(sort string, fields []string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	field := strings.TrimLeft(sort, "+-")
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
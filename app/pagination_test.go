@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_Pagination(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.Request.SetRequestURI("/list")
+	assert.Equal(t, Pagination{Page: 1, PageSize: 20, Offset: 0, Limit: 20}, ctx.Pagination(nil))
+
+	ctx.Request.SetRequestURI("/list?page=3&pageSize=10&sort=-id")
+	p, err := ctx.PaginationE(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Page: 3, PageSize: 10, Offset: 20, Limit: 10, Sort: "-id"}, p)
+
+	// 自定义参数名、默认值与上限
+	opts := &PaginationOptions{
+		PageKey:         "p",
+		PageSizeKey:     "size",
+		SortKey:         "order",
+		DefaultPageSize: 5,
+		MaxPageSize:     50,
+		DefaultSort:     "id",
+		SortFields:      []string{"id", "name"},
+	}
+	ctx.Request.SetRequestURI("/list?p=2&order=%2Bname")
+	assert.Equal(t, Pagination{Page: 2, PageSize: 5, Offset: 5, Limit: 5, Sort: "+name"}, ctx.Pagination(opts))
+
+	// 超过上限截断
+	ctx.Request.SetRequestURI("/list?size=1000")
+	p, err = ctx.PaginationE(opts)
+	assert.True(t, errors.Is(err, errs.ErrInvalidPagination))
+	assert.Equal(t, 50, p.Limit)
+
+	// 非法值回退默认
+	ctx.Request.SetRequestURI("/list?p=-1&size=abc&order=password")
+	p, err = ctx.PaginationE(opts)
+	assert.True(t, errors.Is(err, errs.ErrInvalidPagination))
+	assert.Equal(t, Pagination{Page: 1, PageSize: 5, Offset: 0, Limit: 5, Sort: "id"}, p)
+
+	// 偏移量溢出
+	ctx.Request.SetRequestURI("/list?page=9223372036854775807&pageSize=100")
+	p, err = ctx.PaginationE(nil)
+	assert.True(t, errors.Is(err, errs.ErrInvalidPagination))
+	assert.Equal(t, 0, p.Offset)
+}
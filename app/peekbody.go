@@ -0,0 +1,68 @@
+package app
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/favbox/wind/common/errors"
+)
+
+// DefaultPeekBodyMaxSize 是 PeekBody 物化流式请求体的默认最大字节数。
+const DefaultPeekBodyMaxSize = 4 * 1024 * 1024
+
+// PeekBody 返回请求体的只读视图，可被多个中间件和处理器重复读取而不消费请求体。
+//
+// 等同于 PeekBodyLimit(DefaultPeekBodyMaxSize)。返回的切片不可修改。
+func (ctx *RequestContext) PeekBody() ([]byte, error) {
+	return ctx.PeekBodyLimit(DefaultPeekBodyMaxSize)
+}
+
+// PeekBodyLimit 返回请求体的只读视图，流式请求体至多物化 maxSize 字节。
+//
+// 非流式请求体直接返回正文缓冲区。
+// 流式请求体首次调用时读入内存并缓存，原始流随即关闭，并以缓存内容重建正文流，
+// 后续的 Body()、RequestBodyStream() 及绑定仍可获得完整请求体；缓存在请求结束时释放。
+// 若超过 maxSize 则返回 errors.ErrBodyTooLarge，已读部分会放回正文流，不影响后续读取。
+func (ctx *RequestContext) PeekBodyLimit(maxSize int) ([]byte, error) {
+	if ctx.peekedBody != nil {
+		return ctx.peekedBody, nil
+	}
+	if !ctx.Request.IsBodyStream() {
+		return ctx.Request.BodyE()
+	}
+
+	stream := ctx.Request.BodyStream()
+	body, err := io.ReadAll(io.LimitReader(stream, int64(maxSize)+1))
+	if err == nil && len(body) > maxSize {
+		err = errors.ErrBodyTooLarge
+	}
+	if err != nil {
+		// 已读部分放回正文流
+		ctx.Request.ConstructBodyStream(ctx.Request.BodyBuffer(), &peekedStream{
+			Reader: io.MultiReader(bytes.NewReader(body), stream),
+			stream: stream,
+		})
+		return nil, err
+	}
+
+	_ = ctx.Request.CloseBodyStream()
+	if body == nil {
+		body = []byte{}
+	}
+	ctx.peekedBody = body
+	ctx.Request.ConstructBodyStream(ctx.Request.BodyBuffer(), bytes.NewReader(body))
+	return body, nil
+}
+
+// peekedStream 是放回已读部分的正文流，关闭时关闭原始流。
+type peekedStream struct {
+	io.Reader
+	stream io.Reader
+}
+
+func (s *peekedStream) Close() error {
+	if closer, ok := s.stream.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
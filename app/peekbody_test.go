@@ -0,0 +1,43 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/favbox/wind/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeekBody(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.Request.SetBody([]byte("hello"))
+	body, err := ctx.PeekBody()
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(body))
+	assert.Equal(t, "hello", string(ctx.Request.Body()))
+
+	ctx = NewContext(0)
+	ctx.Request.SetBodyStream(bytes.NewReader([]byte("streaming")), -1)
+	for i := 0; i < 2; i++ {
+		body, err = ctx.PeekBody()
+		assert.Nil(t, err)
+		assert.Equal(t, "streaming", string(body))
+	}
+	data, err := io.ReadAll(ctx.RequestBodyStream())
+	assert.Nil(t, err)
+	assert.Equal(t, "streaming", string(data))
+
+	ctx.ResetWithoutConn()
+	assert.Nil(t, ctx.peekedBody)
+}
+
+func TestPeekBodyLimit(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.Request.SetBodyStream(bytes.NewReader([]byte("0123456789")), -1)
+	_, err := ctx.PeekBodyLimit(4)
+	assert.Equal(t, errors.ErrBodyTooLarge, err)
+
+	// 超限后正文流保持完整
+	assert.Equal(t, "0123456789", string(ctx.Request.Body()))
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/favbox/wind/internal/bytesconv"
+	"github.com/favbox/wind/internal/bytestr"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// ResourceOpts 是 ServeResource 的资源选项。
+type ResourceOpts struct {
+	// 实体标签，如 "v1" 或 W/"v1"，未加引号时自动补全。
+	// 为空且 Body 非空时按 Body 内容自动生成强标签。
+	ETag string
+
+	// 最后修改时间，零值表示不设置。
+	LastModified time.Time
+
+	// 缓存有效期，大于 0 时设置 Cache-Control: max-age，等于 0 时设置 no-cache，小于 0 时不设置。
+	MaxAge time.Duration
+
+	// 完整的 Cache-Control 值，非空时优先于 MaxAge。
+	CacheControl string
+
+	// 附加到 Vary 标头的请求标头名称。
+	Vary []string
+
+	// 内容类型，为空时沿用响应已有的内容类型。
+	ContentType string
+
+	// 资源正文。
+	Body []byte
+
+	// 资源正文流，Body 为空时使用；BodySize 为 -1 表示长度未知。
+	BodyStream io.Reader
+	BodySize   int
+}
+
+// ServeResource 按资源的 ETag 与最后修改时间处理条件请求，并设置缓存相关的响应标头。
+//
+// GET 和 HEAD 请求满足 If-None-Match 或 If-Modified-Since 时返回 304 且不发送正文，
+// 其他方法满足 If-None-Match 时返回 412；否则以 200 返回资源正文。
+// HEAD 请求与 GET 请求的处理一致，正文由服务器跳过发送。
+func (ctx *RequestContext) ServeResource(opts ResourceOpts) {
+	etag := opts.ETag
+	if etag == "" && len(opts.Body) > 0 {
+		etag = generateETag(opts.Body)
+	} else if etag != "" && !strings.HasSuffix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+
+	status := consts.StatusOK
+	isGetOrHead := ctx.IsGet() || ctx.IsHead()
+	if inm := ctx.Request.Header.Peek(consts.HeaderIfNoneMatch); len(inm) > 0 {
+		if etag != "" && etagMatch(string(inm), etag) {
+			status = consts.StatusPreconditionFailed
+			if isGetOrHead {
+				status = consts.StatusNotModified
+			}
+		}
+	} else if isGetOrHead && !opts.LastModified.IsZero() && !ctx.IfModifiedSince(opts.LastModified) {
+		status = consts.StatusNotModified
+	}
+
+	switch status {
+	case consts.StatusNotModified:
+		ctx.NotModified()
+	case consts.StatusPreconditionFailed:
+		ctx.AbortWithStatus(status)
+		return
+	}
+
+	if etag != "" {
+		ctx.Response.Header.Set(consts.HeaderETag, etag)
+	}
+	if !opts.LastModified.IsZero() {
+		ctx.Response.Header.SetCanonical(bytestr.StrLastModified, bytesconv.AppendHTTPDate(nil, opts.LastModified))
+	}
+	switch {
+	case opts.CacheControl != "":
+		ctx.Response.Header.Set(consts.HeaderCacheControl, opts.CacheControl)
+	case opts.MaxAge > 0:
+		ctx.Response.Header.Set(consts.HeaderCacheControl, "max-age="+strconv.FormatInt(int64(opts.MaxAge/time.Second), 10))
+	case opts.MaxAge == 0:
+		ctx.Response.Header.Set(consts.HeaderCacheControl, "no-cache")
+	}
+	for _, v := range opts.Vary {
+		ctx.Response.Header.Add(consts.HeaderVary, v)
+	}
+
+	if status == consts.StatusNotModified {
+		if c, ok := opts.BodyStream.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return
+	}
+
+	ctx.SetStatusCode(status)
+	if opts.ContentType != "" {
+		ctx.SetContentType(opts.ContentType)
+	}
+	if len(opts.Body) > 0 || opts.BodyStream == nil {
+		ctx.Response.SetBody(opts.Body)
+		return
+	}
+	ctx.SetBodyStream(opts.BodyStream, opts.BodySize)
+}
+
+// 按正文内容生成强实体标签。
+func generateETag(body []byte) string {
+	sum := sha1.Sum(body)
+	return `"` + strconv.FormatInt(int64(len(body)), 16) + "-" + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`
+}
+
+// 判断 If-None-Match 列表是否与 etag 弱匹配。
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/internal/bytesconv"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_ServeResource(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newCtx := func(method string, headers ...string) *RequestContext {
+		ctx := NewContext(0)
+		ctx.Request.Header.SetMethod(method)
+		for i := 0; i+1 < len(headers); i += 2 {
+			ctx.Request.Header.Set(headers[i], headers[i+1])
+		}
+		return ctx
+	}
+
+	// 自动生成 ETag 并设置缓存头
+	ctx := newCtx(consts.MethodGet)
+	ctx.ServeResource(ResourceOpts{Body: []byte("hello"), MaxAge: time.Hour, Vary: []string{"Accept"}, ContentType: consts.MIMETextPlainUTF8})
+	etag := string(ctx.Response.Header.Peek(consts.HeaderETag))
+	assert.True(t, strings.HasPrefix(etag, `"5-`))
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "hello", string(ctx.Response.Body()))
+	assert.Equal(t, "max-age=3600", string(ctx.Response.Header.Peek(consts.HeaderCacheControl)))
+	assert.Equal(t, "Accept", string(ctx.Response.Header.Peek(consts.HeaderVary)))
+	assert.Equal(t, consts.MIMETextPlainUTF8, string(ctx.Response.Header.ContentType()))
+
+	// If-None-Match 命中返回 304，保留缓存头
+	for _, inm := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		ctx = newCtx(consts.MethodGet, consts.HeaderIfNoneMatch, inm)
+		ctx.ServeResource(ResourceOpts{Body: []byte("hello"), MaxAge: time.Hour})
+		assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode(), inm)
+		assert.Equal(t, 0, len(ctx.Response.Body()))
+		assert.Equal(t, etag, string(ctx.Response.Header.Peek(consts.HeaderETag)))
+		assert.Equal(t, "max-age=3600", string(ctx.Response.Header.Peek(consts.HeaderCacheControl)))
+	}
+
+	// HEAD 请求同样按条件处理
+	ctx = newCtx(consts.MethodHead, consts.HeaderIfNoneMatch, `"v1"`)
+	ctx.ServeResource(ResourceOpts{ETag: "v1", MaxAge: -1})
+	assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode())
+	assert.Equal(t, 0, len(ctx.Response.Header.Peek(consts.HeaderCacheControl)))
+
+	// 非 GET/HEAD 请求命中返回 412
+	ctx = newCtx(consts.MethodPut, consts.HeaderIfNoneMatch, "*")
+	ctx.ServeResource(ResourceOpts{ETag: `W/"v1"`})
+	assert.Equal(t, consts.StatusPreconditionFailed, ctx.Response.StatusCode())
+	assert.True(t, ctx.IsAborted())
+
+	// If-Modified-Since
+	ims := string(bytesconv.AppendHTTPDate(nil, lastModified))
+	ctx = newCtx(consts.MethodGet, consts.HeaderIfModifiedSince, ims)
+	ctx.ServeResource(ResourceOpts{LastModified: lastModified, CacheControl: "public, max-age=60"})
+	assert.Equal(t, consts.StatusNotModified, ctx.Response.StatusCode())
+	assert.Equal(t, ims, string(ctx.Response.Header.Peek(consts.HeaderLastModified)))
+	assert.Equal(t, "public, max-age=60", string(ctx.Response.Header.Peek(consts.HeaderCacheControl)))
+
+	// If-None-Match 存在时忽略 If-Modified-Since
+	ctx = newCtx(consts.MethodGet, consts.HeaderIfModifiedSince, ims, consts.HeaderIfNoneMatch, `"v0"`)
+	ctx.ServeResource(ResourceOpts{ETag: "v1", LastModified: lastModified, BodyStream: strings.NewReader("stream"), BodySize: -1})
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, `"v1"`, string(ctx.Response.Header.Peek(consts.HeaderETag)))
+	assert.Equal(t, "no-cache", string(ctx.Response.Header.Peek(consts.HeaderCacheControl)))
+	body, err := io.ReadAll(ctx.Response.BodyStream())
+	assert.Nil(t, err)
+	assert.Equal(t, "stream", string(body))
+}
@@ -0,0 +1,19 @@
+package app
+
+// OnResponseError 注册响应写出失败时的回调，如客户端中途断开导致响应未送达。
+//
+// 回调在处理器返回、响应写出或刷新失败后由服务器调用，可用于回滚副作用（如标记消息未送达）。
+// 客户端断开时 err 为 errors.ErrConnectionClosed，可用 errors.Is 区分其他写出错误。
+// 如需对所有请求生效，可在全局中间件中注册。
+func (ctx *RequestContext) OnResponseError(f func(err error)) {
+	ctx.responseErrorHandlers = append(ctx.responseErrorHandlers, f)
+}
+
+// NotifyResponseError 依次调用已注册的响应写出失败回调，由服务器调用，每个请求仅生效一次。
+func (ctx *RequestContext) NotifyResponseError(err error) {
+	handlers := ctx.responseErrorHandlers
+	ctx.responseErrorHandlers = nil
+	for _, f := range handlers {
+		f(err)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext_OnResponseError(t *testing.T) {
+	ctx := NewContext(0)
+	var got []error
+	ctx.OnResponseError(func(err error) { got = append(got, err) })
+	ctx.OnResponseError(func(err error) { got = append(got, err) })
+
+	ctx.NotifyResponseError(errs.ErrConnectionClosed)
+	assert.Equal(t, 2, len(got))
+	assert.True(t, errors.Is(got[0], errs.ErrConnectionClosed))
+
+	// 仅生效一次
+	ctx.NotifyResponseError(errs.ErrConnectionClosed)
+	assert.Equal(t, 2, len(got))
+
+	// 重置后清空
+	ctx.OnResponseError(func(err error) { got = append(got, err) })
+	ctx.ResetWithoutConn()
+	ctx.NotifyResponseError(errs.ErrConnectionClosed)
+	assert.Equal(t, 2, len(got))
+}
@@ -0,0 +1,34 @@
+package app
+
+// ResponseWrapper 是响应改写函数，接收当前的状态码、内容类型和正文，返回改写后的值。
+type ResponseWrapper func(status int, contentType string, body []byte) (int, string, []byte)
+
+// WrapResponse 注册响应改写函数，在处理链结束后、响应发送前执行。
+//
+// 后注册的先执行，与中间件的洋葱模型一致：外层中间件注册的改写函数作用于内层改写后的结果。
+// 流式响应、已劫持的连接或响应写入器不可改写，此时跳过所有改写函数。
+func (ctx *RequestContext) WrapResponse(w ResponseWrapper) {
+	ctx.responseWrappers = append(ctx.responseWrappers, w)
+}
+
+// ApplyResponseWrappers 依次执行已注册的响应改写函数，由引擎在处理链结束后调用，每个请求仅生效一次。
+func (ctx *RequestContext) ApplyResponseWrappers() {
+	wrappers := ctx.responseWrappers
+	if len(wrappers) == 0 {
+		return
+	}
+	ctx.responseWrappers = nil
+
+	resp := &ctx.Response
+	if resp.IsBodyStream() || resp.GetHijackWriter() != nil || ctx.Hijacked() {
+		return
+	}
+
+	status, contentType, body := resp.StatusCode(), string(resp.Header.ContentType()), resp.Body()
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		status, contentType, body = wrappers[i](status, contentType, body)
+	}
+	resp.SetStatusCode(status)
+	resp.Header.SetContentType(contentType)
+	resp.SetBody(body)
+}
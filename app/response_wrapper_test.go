@@ -0,0 +1,41 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapResponse(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.WrapResponse(func(status int, contentType string, body []byte) (int, string, []byte) {
+		return consts.StatusOK, contentType, append([]byte(`{"data":`), append(body, '}')...)
+	})
+	ctx.WrapResponse(func(status int, contentType string, body []byte) (int, string, []byte) {
+		assert.Equal(t, consts.StatusCreated, status)
+		return status, consts.MIMEApplicationJSONUTF8, bytes.ToUpper(body)
+	})
+	ctx.Data(consts.StatusCreated, consts.MIMETextPlain, []byte(`"ok"`))
+	ctx.ApplyResponseWrappers()
+	assert.Equal(t, consts.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, consts.MIMEApplicationJSONUTF8, string(ctx.Response.Header.ContentType()))
+	assert.Equal(t, `{"data":"OK"}`, string(ctx.Response.Body()))
+
+	// 仅生效一次
+	ctx.ApplyResponseWrappers()
+	assert.Equal(t, `{"data":"OK"}`, string(ctx.Response.Body()))
+}
+
+func TestWrapResponseSkipStream(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.WrapResponse(func(status int, contentType string, body []byte) (int, string, []byte) {
+		t.Fatal("流式响应不应被改写")
+		return status, contentType, body
+	})
+	ctx.SetBodyStream(strings.NewReader("stream"), -1)
+	ctx.ApplyResponseWrappers()
+	assert.True(t, ctx.Response.IsBodyStream())
+}
@@ -1,6 +1,7 @@
 package binding
 
 import (
+	inDecoder "github.com/favbox/wind/app/server/binding/internal/decoder"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/route/param"
 )
@@ -17,3 +18,39 @@ type Binder interface {
 	BindJSON(*protocol.Request, any) error
 	BindProtobuf(*protocol.Request, any) error
 }
+
+// AfterBinder 表示绑定完成后需要执行回调的目标。
+//
+// Bind 和 BindAndValidate 在字段填充（及验证）完成后自动调用 AfterBind，
+// 可用于派生计算、规范化或二次校验，返回错误则绑定失败。
+type AfterBinder interface {
+	AfterBind(req *protocol.Request) error
+}
+
+// YAMLBinder 表示可绑定 YAML 请求体的绑定器。
+type YAMLBinder interface {
+	BindYAML(*protocol.Request, any) error
+}
+
+// MsgPackBinder 表示可绑定 MessagePack 请求体的绑定器。
+type MsgPackBinder interface {
+	BindMsgPack(*protocol.Request, any) error
+}
+
+// AllErrorsBinder 表示可一次收集所有字段绑定错误的绑定器。
+type AllErrorsBinder interface {
+	BindAndValidateAll(*protocol.Request, any, param.Params) error
+}
+
+// FieldError 表示单个字段的绑定错误，含字段路径、参数来源、原始值和期望类型。
+type FieldError = inDecoder.FieldError
+
+// FieldErrors 表示多个字段的绑定错误。
+type FieldErrors = inDecoder.FieldErrors
+
+// Precompiler 表示支持预编译的绑定器或验证器。
+//
+// 在注册路由时预先完成反射和表达式解析并缓存，以降低首次请求的延迟。
+type Precompiler interface {
+	Precompile(v any) error
+}
@@ -1,21 +1,29 @@
 package binding
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	inDecoder "github.com/favbox/wind/app/server/binding/internal/decoder"
 	"github.com/favbox/wind/app/server/binding/testdata"
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/common/msgpack"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/protocol/consts"
 	req2 "github.com/favbox/wind/protocol/http1/req"
 	"github.com/favbox/wind/route/param"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
 )
 
 type mockRequest struct {
@@ -1349,6 +1357,439 @@ func TestBind_Issue1015(t *testing.T) {
 	assert.Equal(t, "asd", result.A)
 }
 
+type afterBindReq struct {
+	FirstName string `query:"first"`
+	LastName  string `query:"last"`
+	Age       int    `query:"age" vd:"$>0"`
+	FullName  string
+}
+
+func (r *afterBindReq) AfterBind(req *protocol.Request) error {
+	if r.Age > 150 {
+		return fmt.Errorf("年龄无效：%d", r.Age)
+	}
+	r.FullName = r.FirstName + " " + r.LastName
+	return nil
+}
+
+func TestBind_AfterBind(t *testing.T) {
+	req := newMockRequest().SetRequestURI("http://foobar.com?first=Tom&last=Lee&age=18")
+	var result afterBindReq
+	err := DefaultBinder().BindAndValidate(req.Req, &result, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Tom Lee", result.FullName)
+
+	// 验证失败时不调用
+	req = newMockRequest().SetRequestURI("http://foobar.com?first=Tom&last=Lee&age=0")
+	result = afterBindReq{}
+	err = DefaultBinder().BindAndValidate(req.Req, &result, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "", result.FullName)
+
+	// 回调返回错误则绑定失败
+	req = newMockRequest().SetRequestURI("http://foobar.com?first=Tom&last=Lee&age=200")
+	result = afterBindReq{}
+	err = DefaultBinder().Bind(req.Req, &result, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "", result.FullName)
+}
+
+func TestBind_SniffContentType(t *testing.T) {
+	type Req struct {
+		Name string `json:"name" form:"name"`
+		Age  int    `json:"age" form:"age"`
+	}
+	config := NewBindConfig()
+	config.SniffContentType = true
+	binder := NewBinder(config)
+
+	// 无 Content-Type 的 JSON
+	req := newMockRequest().SetRequestURI("http://foobar.com").SetBody([]byte(` {"name":"wind","age":3}`))
+	var result Req
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	assert.Equal(t, Req{Name: "wind", Age: 3}, result)
+
+	// text/plain 的表单
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody([]byte("name=wind&age=3"))
+	req.Req.Header.SetContentTypeBytes([]byte(consts.MIMETextPlain))
+	result = Req{}
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	assert.Equal(t, Req{Name: "wind", Age: 3}, result)
+
+	// 二进制及无法识别的请求体被忽略
+	for _, body := range [][]byte{{0x00, 'n', '=', 0xff}, []byte("{not json"), []byte("hello world"), []byte("=x")} {
+		req = newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+		result = Req{}
+		assert.Nil(t, binder.Bind(req.Req, &result, nil))
+		assert.Equal(t, Req{}, result)
+	}
+
+	// 未开启时不嗅探
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody([]byte(`{"name":"wind"}`))
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, "", result.Name)
+}
+
+func TestBind_YAML(t *testing.T) {
+	type Address struct {
+		City string `yaml:"city"`
+		Zip  string `yaml:"zip_code"`
+	}
+	type Req struct {
+		Name    string   `yaml:"name" vd:"len($)>0"`
+		Tags    []string `yaml:"tags"`
+		Address Address  `yaml:"address"`
+		Page    int      `query:"page"`
+	}
+	body := []byte("name: wind\ntags: [a, b]\naddress:\n  city: shanghai\n  zip_code: \"200000\"\n")
+	want := Req{Name: "wind", Tags: []string{"a", "b"}, Address: Address{City: "shanghai", Zip: "200000"}, Page: 2}
+
+	config := NewBindConfig()
+	config.YAMLUnmarshaler = yaml.Unmarshal
+	binder := NewBinder(config)
+
+	for _, ct := range []string{consts.MIMEApplicationYAML, consts.MIMEApplicationXYAML, consts.MIMETextYAML + "; charset=utf-8"} {
+		req := newMockRequest().SetRequestURI("http://foobar.com?page=2").SetBody(body)
+		req.Req.Header.SetContentTypeBytes([]byte(ct))
+		var result Req
+		assert.Nil(t, binder.BindAndValidate(req.Req, &result, nil), ct)
+		assert.Equal(t, want, result, ct)
+	}
+
+	// 显式绑定
+	req := newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+	var result Req
+	assert.Nil(t, binder.(YAMLBinder).BindYAML(req.Req, &result))
+	assert.Equal(t, "shanghai", result.Address.City)
+
+	// 验证失败
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody([]byte("tags: [a]\n"))
+	req.Req.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationYAML))
+	result = Req{}
+	assert.NotNil(t, binder.BindAndValidate(req.Req, &result, nil))
+
+	// 未设置解码函数时忽略请求体，显式绑定报错
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+	req.Req.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationYAML))
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, Req{}, result)
+	assert.NotNil(t, BindYAML(req.Req, &result))
+}
+
+func TestBind_MsgPack(t *testing.T) {
+	type Req struct {
+		Name string `json:"name" vd:"len($)>0"`
+		Age  int    `json:"age"`
+		Page int    `query:"page"`
+	}
+	body, err := msgpack.Marshal(map[string]any{"name": "wind", "age": 3})
+	assert.Nil(t, err)
+
+	for _, ct := range []string{consts.MIMEApplicationMsgPack, consts.MIMEApplicationXMsgPack} {
+		req := newMockRequest().SetRequestURI("http://foobar.com?page=2").SetBody(body)
+		req.Req.Header.SetContentTypeBytes([]byte(ct))
+		var result Req
+		assert.Nil(t, DefaultBinder().BindAndValidate(req.Req, &result, nil), ct)
+		assert.Equal(t, Req{Name: "wind", Age: 3, Page: 2}, result, ct)
+	}
+
+	// 显式绑定
+	req := newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+	var result Req
+	assert.Nil(t, BindMsgPack(req.Req, &result))
+	assert.Equal(t, Req{Name: "wind", Age: 3}, result)
+
+	// 请求体为空时保持零值
+	req = newMockRequest().SetRequestURI("http://foobar.com?page=2")
+	req.Req.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationMsgPack))
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, Req{Page: 2}, result)
+	result = Req{}
+	assert.Nil(t, BindMsgPack(req.Req, &result))
+	assert.Equal(t, Req{}, result)
+
+	// 非法请求体
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody([]byte{0xc1})
+	req.Req.Header.SetContentTypeBytes([]byte(consts.MIMEApplicationMsgPack))
+	assert.NotNil(t, DefaultBinder().Bind(req.Req, &result, nil))
+
+	// 替换解码函数
+	errMock := errors.New("mock")
+	config := NewBindConfig()
+	config.MsgPackUnmarshaler = func(data []byte, v any) error { return errMock }
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+	assert.Equal(t, errMock, NewBinder(config).(MsgPackBinder).BindMsgPack(req.Req, &result))
+}
+
+func TestBind_Cookie(t *testing.T) {
+	type Req struct {
+		Session string   `cookie:"session"`
+		UID     int      `cookie:"uid"`
+		Theme   string   `cookie:"theme" default:"light"`
+		Langs   []string `cookie:"lang"`
+		Nick    *string  `cookie:"nick"`
+		// 标签按 path、form、query、cookie、header 的顺序取值
+		Source string `query:"source" cookie:"source" header:"source"`
+	}
+	req := newMockRequest().SetRequestURI("http://foobar.com").SetHeader("source", "header")
+	req.Req.Header.SetCookie("session", "abc")
+	req.Req.Header.SetCookie("uid", "42")
+	req.Req.Header.SetCookie("lang", "zh")
+	// 与 RequestContext.SetCookie 的转义对称
+	req.Req.Header.SetCookie("nick", url.QueryEscape("风 & 雨"))
+	req.Req.Header.SetCookie("source", "cookie")
+	var result Req
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, "abc", result.Session)
+	assert.Equal(t, 42, result.UID)
+	assert.Equal(t, "light", result.Theme)
+	assert.Equal(t, []string{"zh"}, result.Langs)
+	assert.Equal(t, "风 & 雨", *result.Nick)
+	assert.Equal(t, "cookie", result.Source)
+
+	// query 优先于 cookie
+	req.SetRequestURI("http://foobar.com?source=query")
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, "query", result.Source)
+
+	// 无法还原的值原样保留
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	req.Req.Header.SetCookie("session", "%zz")
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, "%zz", result.Session)
+
+	// 类型转换失败
+	req.Req.Header.SetCookie("uid", "abc")
+	assert.NotNil(t, DefaultBinder().Bind(req.Req, &result, nil))
+
+	// 必填缺失
+	type RequiredReq struct {
+		Token string `cookie:"token,required"`
+	}
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	var requiredResult RequiredReq
+	err := DefaultBinder().Bind(req.Req, &requiredResult, nil)
+	assert.NotNil(t, err)
+	var fe *FieldError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, "cookie", fe.Source)
+	req.Req.Header.SetCookie("token", "t")
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &requiredResult, nil))
+	assert.Equal(t, "t", requiredResult.Token)
+}
+
+func TestBind_RawBody(t *testing.T) {
+	type Req struct {
+		Raw  []byte `raw_body:""`
+		Text string `raw_body:""`
+		Name string `json:"name"`
+	}
+	body := []byte(`{"name":"wind"}`)
+
+	req := newMockRequest().SetRequestURI("http://foobar.com").SetJSONContentType().SetBody(body)
+	var result Req
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, body, result.Raw)
+	assert.Equal(t, string(body), result.Text)
+	assert.Equal(t, "wind", result.Name)
+
+	// 分块传输，无 Content-Length
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetBody(body)
+	req.Req.Header.SetContentLength(-1)
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, string(body), result.Text)
+
+	// 空请求体
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	result = Req{}
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, Req{}, result)
+
+	type RequiredReq struct {
+		Raw string `raw_body:",required"`
+	}
+	var requiredResult RequiredReq
+	assert.NotNil(t, DefaultBinder().Bind(req.Req, &requiredResult, nil))
+
+	// 流式请求体
+	config := NewBindConfig()
+	config.MaxRawBodySize = len(body)
+	binder := NewBinder(config)
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	req.Req.SetBodyStream(bytes.NewReader(body), -1)
+	result = Req{}
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	assert.Equal(t, body, result.Raw)
+	assert.Equal(t, string(body), result.Text)
+
+	// 流式请求体超限
+	config = NewBindConfig()
+	config.MaxRawBodySize = len(body) - 1
+	binder = NewBinder(config)
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	req.Req.SetBodyStream(bytes.NewReader(body), -1)
+	result = Req{}
+	err := binder.Bind(req.Req, &result, nil)
+	assert.True(t, errors.Is(err, errs.ErrBodyTooLarge))
+	var fe *FieldError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, "raw_body", fe.Source)
+	assert.Nil(t, result.Raw)
+
+	req = newMockRequest().SetRequestURI("http://foobar.com")
+	req.Req.SetBodyStream(bytes.NewReader(body), -1)
+	err = binder.(AllErrorsBinder).BindAndValidateAll(req.Req, &result, nil)
+	var fes FieldErrors
+	assert.True(t, errors.As(err, &fes))
+	assert.Len(t, fes, 1)
+	assert.True(t, errors.Is(fes[0], errs.ErrBodyTooLarge))
+
+	// 流式的 JSON 请求体在解码前即受限，不会被无限制地读入内存
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetJSONContentType()
+	req.Req.SetBodyStream(bytes.NewReader(body), len(body))
+	result = Req{}
+	err = binder.Bind(req.Req, &result, nil)
+	assert.True(t, errors.Is(err, errs.ErrBodyTooLarge))
+	assert.Equal(t, Req{}, result)
+
+	config = NewBindConfig()
+	config.MaxRawBodySize = len(body)
+	binder = NewBinder(config)
+	req = newMockRequest().SetRequestURI("http://foobar.com").SetJSONContentType()
+	req.Req.SetBodyStream(bytes.NewReader(body), len(body))
+	result = Req{}
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	assert.Equal(t, "wind", result.Name)
+	assert.Equal(t, string(body), result.Text)
+}
+
+func TestBind_TimeFormat(t *testing.T) {
+	type Req struct {
+		CreatedAt time.Time  `query:"created_at"`
+		UpdatedAt *time.Time `query:"updated_at"`
+		Day       time.Time  `query:"day" time_format:"2006-01-02"`
+	}
+	config := NewBindConfig()
+	config.TimeFormats = []string{"2006-01-02 15:04:05", time.RFC3339}
+	binder := NewBinder(config)
+
+	req := newMockRequest().SetRequestURI("http://foobar.com?created_at=2024-05-01+08:30:00&updated_at=2024-05-01T08:30:00Z&day=2024-05-01")
+	var result Req
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	want := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	assert.True(t, want.Equal(result.CreatedAt))
+	assert.True(t, want.Equal(*result.UpdatedAt))
+	assert.True(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).Equal(result.Day))
+
+	// 非松散模式不接受时间戳
+	req = newMockRequest().SetRequestURI("http://foobar.com?created_at=1714552200")
+	result = Req{}
+	err := binder.Bind(req.Req, &result, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "2006-01-02 15:04:05")
+
+	// 松散模式下按位数区分秒与毫秒
+	config = NewBindConfig()
+	config.LooseTimeMode = true
+	binder = NewBinder(config)
+	req = newMockRequest().SetRequestURI("http://foobar.com?created_at=1714552200&updated_at=1714552200123&day=1714552200")
+	result = Req{}
+	assert.Nil(t, binder.Bind(req.Req, &result, nil))
+	assert.True(t, want.Equal(result.CreatedAt))
+	assert.True(t, want.Add(123*time.Millisecond).Equal(*result.UpdatedAt))
+	assert.True(t, want.Equal(result.Day))
+
+	// 解析失败
+	req = newMockRequest().SetRequestURI("http://foobar.com?day=2024.05.01")
+	result = Req{}
+	err = binder.Bind(req.Req, &result, nil)
+	var fe *FieldError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, "Day", fe.Path)
+	assert.Contains(t, err.Error(), `无法解析时间 "2024.05.01"`)
+}
+
+func TestBind_Precompile(t *testing.T) {
+	type Req struct {
+		ID   int    `query:"id" vd:"$>0"`
+		Name string `query:"name"`
+	}
+
+	binder := NewBinder(NewBindConfig()).(*defaultBinder)
+	assert.Nil(t, binder.Precompile(Req{}))
+	for _, tag := range []string{"", pathTag, queryTag, headerTag, formTag} {
+		_, ok := binder.tagCache(tag).Load(typeIDOf(&Req{}))
+		assert.True(t, ok)
+	}
+	assert.Nil(t, DefaultValidator().(Precompiler).Precompile(&Req{}))
+	assert.NotNil(t, binder.Precompile(1))
+	assert.NotNil(t, DefaultValidator().(Precompiler).Precompile(nil))
+
+	req := newMockRequest().SetRequestURI("http://foobar.com?id=1&name=wind")
+	var result Req
+	assert.Nil(t, binder.BindAndValidate(req.Req, &result, nil))
+	assert.Equal(t, 1, result.ID)
+	assert.Equal(t, "wind", result.Name)
+}
+
+func TestBind_FieldErrors(t *testing.T) {
+	type Address struct {
+		Zip  int    `query:"zip"`
+		City string `query:"city,required"`
+	}
+	type User struct {
+		Name    string  `query:"name,required"`
+		Age     int     `query:"age"`
+		Address Address `query:"-"`
+	}
+	type Req struct {
+		User User   `query:"-"`
+		IDs  []int  `query:"ids"`
+		Keep string `query:"keep"`
+	}
+
+	req := newMockRequest().SetRequestURI("http://foobar.com?age=x&zip=abc&ids=1&ids=b&keep=ok")
+	var result Req
+	err := DefaultBinder().Bind(req.Req, &result, nil)
+	var fe *FieldError
+	assert.True(t, errors.As(err, &fe))
+	assert.Equal(t, "User.Name", fe.Path)
+	assert.Equal(t, "query", fe.Source)
+	assert.Equal(t, "'User.Name' 字段必填，但请求无此参数", err.Error())
+
+	result = Req{}
+	err = BindAndValidateAll(req.Req, &result, nil)
+	var fes FieldErrors
+	assert.True(t, errors.As(err, &fes))
+	assert.Len(t, fes, 5)
+	assert.Equal(t, "User.Name", fes[0].Path)
+
+	assert.Equal(t, "User.Age", fes[1].Path)
+	assert.Equal(t, "query", fes[1].Source)
+	assert.Equal(t, "x", fes[1].Value)
+	assert.Equal(t, "int", fes[1].Type)
+
+	assert.Equal(t, "User.Address.Zip", fes[2].Path)
+	assert.Equal(t, "abc", fes[2].Value)
+	assert.Equal(t, "User.Address.City", fes[3].Path)
+	assert.Equal(t, "IDs", fes[4].Path)
+	assert.Equal(t, "[]int", fes[4].Type)
+
+	// 其余字段照常绑定
+	assert.Equal(t, "ok", result.Keep)
+}
+
+func typeIDOf(v any) uintptr {
+	_, typeID := valueAndTypeID(v)
+	return typeID
+}
+
 func Benchmark_Binding(b *testing.B) {
 	type Req struct {
 		Version string `path:"v"`
@@ -1390,3 +1831,264 @@ func Benchmark_Binding(b *testing.B) {
 		}
 	}
 }
+
+type benchColdReq struct {
+	ID    int    `query:"id" vd:"$>0"`
+	Name  string `query:"name" vd:"len($)>0"`
+	Token string `header:"token"`
+	Form  string `form:"f"`
+}
+
+func benchmarkBindingCold(b *testing.B, precompile bool) {
+	req := newMockRequest().
+		SetRequestURI("http://foobar.com?id=12&name=wind").
+		SetHeaders("Token", "t").
+		SetPostArg("f", "form").
+		SetUrlEncodedContentType()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		config := NewBindConfig()
+		config.Validator = NewValidator(NewValidateConfig())
+		binder := NewBinder(config)
+		if precompile {
+			_ = binder.(Precompiler).Precompile(benchColdReq{})
+			_ = config.Validator.(Precompiler).Precompile(benchColdReq{})
+		}
+		b.StartTimer()
+
+		var result benchColdReq
+		if err := binder.BindAndValidate(req.Req, &result, nil); err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+// 首次请求（冷启动）的绑定与验证耗时
+func Benchmark_BindingCold(b *testing.B) {
+	benchmarkBindingCold(b, false)
+}
+
+// 预编译后首次请求的绑定与验证耗时
+func Benchmark_BindingPrecompiled(b *testing.B) {
+	benchmarkBindingCold(b, true)
+}
+
+func TestBind_Bodyless(t *testing.T) {
+	type Req struct {
+		ID   int    `query:"id"`
+		Name string `json:"name"`
+		Form string `form:"f" default:"def"`
+	}
+
+	// Content-Length 为 0 时跳过请求体解码
+	req := newMockRequest().
+		SetRequestURI("http://foobar.com?id=12").
+		SetJSONContentType()
+	req.Req.Header.SetContentLength(0)
+	assert.True(t, inDecoder.IsBodyless(req.Req))
+	var result Req
+	assert.Nil(t, DefaultBinder().BindAndValidate(req.Req, &result, nil))
+	assert.Equal(t, 12, result.ID)
+	assert.Equal(t, "", result.Name)
+	assert.Equal(t, "def", result.Form)
+
+	// 分块请求不视为无体
+	req.Req.Header.SetContentLength(-1)
+	assert.False(t, inDecoder.IsBodyless(req.Req))
+
+	// 有请求体时照常解码
+	req = newMockRequest().
+		SetRequestURI("http://foobar.com?id=12").
+		SetUrlEncodedContentType().
+		SetBody([]byte("f=form"))
+	assert.False(t, inDecoder.IsBodyless(req.Req))
+	result = Req{}
+	assert.Nil(t, DefaultBinder().BindAndValidate(req.Req, &result, nil))
+	assert.Equal(t, "form", result.Form)
+}
+
+// 无体 GET 请求的绑定耗时：Bodyless 为明确无体的请求，UnknownLength 为长度未知、需走请求体解码的请求
+func Benchmark_BindingBodyless(b *testing.B) {
+	type Req struct {
+		ID    int    `query:"id"`
+		Name  string `query:"name"`
+		Token string `header:"token"`
+		Page  int    `form:"page"`
+		Size  int    `json:"size"`
+	}
+
+	for _, bc := range []struct {
+		name          string
+		contentLength int
+	}{
+		{"Bodyless", 0},
+		{"UnknownLength", -2},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			req := newMockRequest().
+				SetRequestURI("http://foobar.com?id=12&name=wind").
+				SetHeaders("Token", "t")
+			req.Req.Header.SetMethod(consts.MethodGet)
+			req.Req.Header.SetContentLength(bc.contentLength)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var result Req
+				if err := DefaultBinder().BindAndValidate(req.Req, &result, nil); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestBind_JSONRawMessage(t *testing.T) {
+	type Item struct {
+		ID   int             `json:"id"`
+		Data json.RawMessage `json:"data"`
+	}
+	type Req struct {
+		Meta  json.RawMessage   `json:"meta"`
+		Item  Item              `json:"item"`
+		Items []Item            `json:"items"`
+		Raws  []json.RawMessage `json:"raws"`
+		Ptr   *json.RawMessage  `json:"ptr"`
+	}
+
+	body := `{"meta":{"a": [1, 2]},"item":{"id":1,"data":"x"},"items":[{"id":2,"data":{"b":null}}],"raws":[1,"s",{"c":true}],"ptr":[ 3 ]}`
+	req := newMockRequest().
+		SetRequestURI("http://foobar.com").
+		SetJSONContentType().
+		SetBody([]byte(body))
+	var result Req
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, `{"a": [1, 2]}`, string(result.Meta))
+	assert.Equal(t, `"x"`, string(result.Item.Data))
+	assert.Equal(t, `{"b":null}`, string(result.Items[0].Data))
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`1`), json.RawMessage(`"s"`), json.RawMessage(`{"c":true}`)}, result.Raws)
+	assert.Equal(t, `[ 3 ]`, string(*result.Ptr))
+
+	// query/form 来源保留原始字符串
+	type QueryReq struct {
+		Q    json.RawMessage   `query:"q"`
+		Qs   []json.RawMessage `query:"qs"`
+		F    json.RawMessage   `form:"f"`
+		None json.RawMessage   `query:"none"`
+	}
+	req = newMockRequest().
+		SetRequestURI("http://foobar.com?q=123&qs=a&qs=%7B%22k%22%3A1%7D").
+		SetUrlEncodedContentType().
+		SetPostArg("f", "hello")
+	var qr QueryReq
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &qr, nil))
+	assert.Equal(t, "123", string(qr.Q))
+	assert.Equal(t, []json.RawMessage{json.RawMessage("a"), json.RawMessage(`{"k":1}`)}, qr.Qs)
+	assert.Equal(t, "hello", string(qr.F))
+	assert.Nil(t, qr.None)
+}
+
+func TestBind_MaxJSONDepth(t *testing.T) {
+	type Req struct {
+		A any `json:"a"`
+	}
+	bindConfig := NewBindConfig()
+	bindConfig.MaxJSONDepth = 3
+	binder := NewBinder(bindConfig)
+
+	newReq := func(body string) *mockRequest {
+		return newMockRequest().
+			SetRequestURI("http://foobar.com").
+			SetJSONContentType().
+			SetBody([]byte(body))
+	}
+
+	// 字符串内的括号和转义引号不计入层数
+	var result Req
+	assert.Nil(t, binder.Bind(newReq(`{"a":[{"b":"[[{{\"]]"}]}`).Req, &result, nil))
+	assert.Nil(t, binder.BindJSON(newReq(`{"a":[{"b":1}]}`).Req, &result))
+
+	// 深层嵌套负载被拒绝且不崩溃
+	bomb := `{"a":` + strings.Repeat("[", 100000) + strings.Repeat("]", 100000) + `}`
+	assert.ErrorIs(t, binder.Bind(newReq(bomb).Req, &result, nil), errs.ErrJSONTooDeep)
+	assert.ErrorIs(t, binder.BindJSON(newReq(bomb).Req, &result), errs.ErrJSONTooDeep)
+	var m map[string]any
+	assert.ErrorIs(t, binder.Bind(newReq(bomb).Req, &m, nil), errs.ErrJSONTooDeep)
+
+	// 默认不限制
+	assert.Nil(t, DefaultBinder().BindJSON(newReq(`{"a":[[[[1]]]]}`).Req, &result))
+}
+
+type upperString string
+
+func (s *upperString) Scan(src any) error {
+	if src == nil {
+		*s = ""
+		return nil
+	}
+	*s = upperString(strings.ToUpper(src.(string)))
+	return nil
+}
+
+func TestBind_SQLNull(t *testing.T) {
+	type Req struct {
+		Name    sql.NullString    `query:"name"`
+		Age     sql.NullInt64     `query:"age"`
+		Score   *sql.NullFloat64  `query:"score"`
+		OK      sql.NullBool      `query:"ok"`
+		Empty   sql.NullInt32     `query:"empty"`
+		Missing sql.NullString    `query:"missing"`
+		IDs     []sql.NullInt64   `query:"id"`
+		PIDs    []*sql.NullString `query:"pid"`
+		Upper   upperString       `query:"upper"`
+	}
+	req := newMockRequest().
+		SetRequestURI("http://foobar.com?name=wind&age=18&score=9.5&ok=true&empty=&id=1&id=&id=3&pid=a&upper=abc")
+	var result Req
+	assert.Nil(t, DefaultBinder().Bind(req.Req, &result, nil))
+	assert.Equal(t, sql.NullString{String: "wind", Valid: true}, result.Name)
+	assert.Equal(t, sql.NullInt64{Int64: 18, Valid: true}, result.Age)
+	assert.Equal(t, &sql.NullFloat64{Float64: 9.5, Valid: true}, result.Score)
+	assert.Equal(t, sql.NullBool{Bool: true, Valid: true}, result.OK)
+	assert.False(t, result.Empty.Valid)
+	assert.False(t, result.Missing.Valid)
+	assert.Equal(t, []sql.NullInt64{{Int64: 1, Valid: true}, {}, {Int64: 3, Valid: true}}, result.IDs)
+	assert.Equal(t, []*sql.NullString{{String: "a", Valid: true}}, result.PIDs)
+	assert.Equal(t, upperString("ABC"), result.Upper)
+
+	// 无法转换时报错
+	req = newMockRequest().SetRequestURI("http://foobar.com?age=abc")
+	assert.NotNil(t, DefaultBinder().Bind(req.Req, &Req{}, nil))
+}
+
+func TestCacheStats(t *testing.T) {
+	type Req struct {
+		ID int `query:"id"`
+	}
+	ClearCache()
+	assert.Equal(t, DecoderCacheStats{}, CacheStats())
+
+	req := newMockRequest().SetRequestURI("http://foobar.com?id=12")
+	for i := 0; i < 3; i++ {
+		var result Req
+		assert.Nil(t, Bind(req.Req, &result, nil))
+	}
+	assert.Equal(t, DecoderCacheStats{Types: 1, Hits: 2, Misses: 1}, CacheStats())
+
+	// 每次新建绑定器会导致未命中
+	for i := 0; i < 2; i++ {
+		var result Req
+		assert.Nil(t, NewBinder(nil).Bind(req.Req, &result, nil))
+	}
+	assert.Equal(t, DecoderCacheStats{Types: 1, Hits: 2, Misses: 3}, CacheStats())
+
+	ClearCache()
+	assert.Equal(t, DecoderCacheStats{}, CacheStats())
+	var result Req
+	assert.Nil(t, Bind(req.Req, &result, nil))
+	assert.Equal(t, 12, result.ID)
+	assert.Equal(t, DecoderCacheStats{Types: 1, Misses: 1}, CacheStats())
+}
@@ -0,0 +1,42 @@
+package binding
+
+import "sync/atomic"
+
+// 所有绑定器共享的解码器缓存命中与未命中计数。
+var cacheHits, cacheMisses uint64
+
+// DecoderCacheStats 表示绑定器字段解码器缓存的统计信息。
+type DecoderCacheStats struct {
+	Types  int    // 默认绑定器已缓存的解码器数，同一类型在不同绑定标签下分别计数
+	Hits   uint64 // 缓存命中次数
+	Misses uint64 // 缓存未命中（即构建解码器）次数
+}
+
+// CacheStats 返回字段解码器缓存的统计信息。
+//
+// Hits 和 Misses 为进程内所有绑定器的累计值，Misses 持续增长通常意味着反复新建绑定器导致缓存失效。
+func CacheStats() DecoderCacheStats {
+	stats := DecoderCacheStats{
+		Hits:   atomic.LoadUint64(&cacheHits),
+		Misses: atomic.LoadUint64(&cacheMisses),
+	}
+	if b, ok := defaultBind.(*defaultBinder); ok {
+		stats.Types = int(atomic.LoadInt64(&b.cachedTypes))
+	}
+	return stats
+}
+
+// ClearCache 清空默认绑定器的字段解码器缓存及枚举字段缓存，并重置命中计数。
+//
+// 用于测试或热更新结构体定义后重建解码器。
+func ClearCache() {
+	if b, ok := defaultBind.(*defaultBinder); ok {
+		b.clearCache()
+	}
+	enumFieldsCache.Range(func(key, _ any) bool {
+		enumFieldsCache.Delete(key)
+		return true
+	})
+	atomic.StoreUint64(&cacheHits, 0)
+	atomic.StoreUint64(&cacheMisses, 0)
+}
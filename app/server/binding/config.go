@@ -4,6 +4,7 @@ import (
 	stdJson "encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	exprValidator "github.com/bytedance/go-tagexpr/v2/validator"
@@ -54,6 +55,65 @@ type BindConfig struct {
 	// 默认值：false，即不禁用未知字段。
 	EnableDecoderDisallowUnknownFields bool
 
+	// 是否在 Content-Type 缺失或不明确时嗅探请求体的格式。
+	//
+	// 意为：开启后，若 Content-Type 为空、text/plain 或 application/octet-stream，
+	// 则请求体为合法 JSON 对象或数组时按 JSON 绑定，为 key=val&... 形式的文本时按表单绑定，否则忽略请求体。
+	//
+	// 默认值：false，不嗅探。
+	SniffContentType bool
+
+	// JSON 请求体允许的最大嵌套层数，对象和数组各计一层。
+	//
+	// 意为：解码前预扫描请求体，超限则返回 errors.ErrJSONTooDeep，
+	// 对标准库和第三方 JSON 库均生效，用于防御深层嵌套的 JSON 炸弹。
+	//
+	// 默认值：0，不限制。
+	MaxJSONDepth int
+
+	// time.Time 字段依次尝试的时间格式，如 "2006-01-02 15:04:05"。
+	//
+	// 意为：绑定 query/form/header/path/cookie 等文本参数到 time.Time 字段时，按顺序尝试这些格式，
+	// 字段可用 time_format 标签覆盖，如 `time_format:"2006-01-02"`。
+	// JSON 请求体中的时间仍由 JSON 解码器解析。
+	//
+	// 默认值：nil，仅支持 RFC 3339。
+	TimeFormats []string
+
+	// 是否为松散的时间模式。
+	//
+	// 意为：若设为 true，则 time.Time 字段的纯数字参数视为 Unix 时间戳，不超过 10 位按秒，否则按毫秒。
+	//
+	// 默认值：false，不接受时间戳。
+	LooseTimeMode bool
+
+	// 绑定时读取流式请求体的最大字节数。
+	//
+	// 意为：开启 StreamRequestBody 时，超出服务端 MaxRequestBodySize 的请求体以流的形式交给处理器，
+	// 绑定 raw_body 字段或解码 JSON、protobuf、YAML、MessagePack 请求体时最多读取该字节数，
+	// 超限则返回 errors.ErrBodyTooLarge，以防内存耗尽。
+	//
+	// 默认值：0，由服务端设置为 MaxRequestBodySize，单独使用绑定器时为 4MB。
+	MaxRawBodySize int
+
+	// YAML 请求体的解码函数，如 gopkg.in/yaml.v3 的 yaml.Unmarshal。
+	//
+	// 意为：设置后，Content-Type 为 application/yaml、application/x-yaml 或 text/yaml 的请求体
+	// 在 Bind/BindAndValidate 时按 YAML 解码，也可用 BindYAML 显式绑定。
+	// 框架本身不依赖 YAML 库，由使用方按需注入。
+	//
+	// 默认值：nil，忽略 YAML 请求体，BindYAML 返回错误。
+	YAMLUnmarshaler func(data []byte, v any) error
+
+	// MessagePack 请求体的解码函数。
+	//
+	// 意为：Content-Type 为 application/msgpack 或 application/x-msgpack 的请求体
+	// 在 Bind/BindAndValidate 时按 MessagePack 解码，也可用 BindMsgPack 显式绑定。
+	// 可替换为第三方实现，如 github.com/vmihailenco/msgpack/v5 的 msgpack.Unmarshal。
+	//
+	// 默认值：nil，使用 common/msgpack 的轻量实现，字段名沿用 json 标签。
+	MsgPackUnmarshaler func(data []byte, v any) error
+
 	// 注册自定义类型的解码器。
 	TypeUnmarshalFuncs map[reflect.Type]decoder.CustomizedDecodeFunc
 	// 用于 BindAndValidate() 的验证。
@@ -87,17 +147,15 @@ func (c *BindConfig) MustRegTypeUnmarshal(t reflect.Type, fn decoder.CustomizedD
 	}
 }
 
-// 初始化默认的类型解码器(如 time.Time)。
+// 初始化默认的类型解码器(如 time.Time、json.RawMessage)。
 func (c *BindConfig) initTypeUnmarshal() {
-	c.MustRegTypeUnmarshal(reflect.TypeOf(time.Time{}), func(req *protocol.Request, params param.Params, text string) (reflect.Value, error) {
+	c.MustRegTypeUnmarshal(reflect.TypeOf(time.Time{}), decoder.TimeDecodeFunc(c.TimeFormats, c.LooseTimeMode))
+	// json.RawMessage 保留原始文本，JSON 请求体中的字段由 JSON 解码器原样保留
+	c.MustRegTypeUnmarshal(reflect.TypeOf(stdJson.RawMessage{}), func(req *protocol.Request, params param.Params, text string) (reflect.Value, error) {
 		if text == "" {
-			return reflect.ValueOf(time.Time{}), nil
-		}
-		t, err := time.Parse(time.RFC3339, text)
-		if err != nil {
-			return reflect.Value{}, err
+			return reflect.ValueOf(stdJson.RawMessage(nil)), nil
 		}
-		return reflect.ValueOf(t), nil
+		return reflect.ValueOf(stdJson.RawMessage(text)), nil
 	})
 }
 
@@ -138,6 +196,13 @@ type ValidateErrFactory func(fieldSelector, msg string) error
 type ValidateConfig struct {
 	ValidateTag string             // 验证标签，支持自定义
 	ErrFactory  ValidateErrFactory // 自定义的错误处理函数
+
+	messages map[string]map[validateMsgKey]string // 多语言错误消息模板：语言 -> 字段及规则 -> 模板
+}
+
+// 错误消息模板的键。
+type validateMsgKey struct {
+	field, rule string
 }
 
 // MustRegValidateFunc 注册验证函数表达式。
@@ -154,6 +219,25 @@ func (c *ValidateConfig) SetValidatorErrorFactory(errFactory ValidateErrFactory)
 	c.ErrFactory = errFactory
 }
 
+// RegValidateMessage 注册 lang 语言下字段 field 验证失败时的错误消息模板。
+//
+// field 为字段路径，如 User.Name；rule 为验证表达式中 msg 的取值，
+// 如 `vd:"len($)>0; msg:'required'"` 的 required，枚举校验失败时为 enums，跨字段比较失败时为对应标签名如 gtfield，为空表示匹配该字段的任意失败。
+// 模板中的 {field} 和 {msg} 分别替换为字段路径和原始错误消息。
+//
+// 验证失败时按请求 Accept-Language 的顺序选择语言，如 zh-cn 未注册时回退到 zh。
+// 需在创建验证器之前注册。
+func (c *ValidateConfig) RegValidateMessage(lang, field, rule, tmpl string) {
+	lang = strings.ToLower(lang)
+	if c.messages == nil {
+		c.messages = make(map[string]map[validateMsgKey]string)
+	}
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[validateMsgKey]string)
+	}
+	c.messages[lang][validateMsgKey{field, rule}] = tmpl
+}
+
 // SetValidatorTag 自定义验证器的标签。
 func (c *ValidateConfig) SetValidatorTag(tag string) {
 	c.ValidateTag = tag
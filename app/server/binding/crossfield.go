@@ -0,0 +1,310 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 跨字段比较标签，值为同一结构体中另一字段的名称，如 `gtfield:"StartAt"`。
+const (
+	gtFieldTag  = "gtfield"  // 大于
+	gteFieldTag = "gtefield" // 大于等于
+	ltFieldTag  = "ltfield"  // 小于
+	lteFieldTag = "ltefield" // 小于等于
+)
+
+// 跨字段比较标签及其文字描述
+var crossFieldTags = []struct {
+	tag, text string
+}{
+	{gtFieldTag, "大于"},
+	{gteFieldTag, "大于等于"},
+	{ltFieldTag, "小于"},
+	{lteFieldTag, "小于等于"},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// 字段的比较方式
+type compareKind int
+
+const (
+	compareNone   compareKind = iota
+	compareNumber             // 数值
+	compareTime               // time.Time
+	compareLen                // 字符串按字符数，切片、数组与映射按元素个数
+)
+
+// 带跨字段比较标签的字段
+type crossField struct {
+	index     int
+	name      string
+	other     int    // 被比较字段的索引
+	otherName string // 被比较字段的名称
+	rule      string // 比较标签，如 gtfield
+	text      string
+	kind      compareKind
+}
+
+// 结构体类型 -> 其跨字段比较的字段及需递归校验的嵌套字段
+type crossFields struct {
+	fields []crossField
+	nested []crossField
+	err    error // 标签引用了不存在或类型不可比较的字段
+}
+
+var crossFieldsCache sync.Map
+
+// validateCrossFields 校验 obj 中带 gtfield、gtefield、ltfield、ltefield 标签的字段与同一结构体中被引用字段的大小关系。
+//
+// 数值按数值比较，time.Time 按时间先后比较，字符串按字符数、切片与映射按元素个数比较；任一侧为空指针时跳过。
+func validateCrossFields(obj any) error {
+	rv, ok := obj.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(obj)
+	}
+	return validateCrossFieldsValue(rv, "")
+}
+
+func validateCrossFieldsValue(rv reflect.Value, prefix string) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	info := getCrossFields(rv.Type())
+	if info.err != nil {
+		return info.err
+	}
+	for _, f := range info.fields {
+		a, b := indirect(rv.Field(f.index)), indirect(rv.Field(f.other))
+		if !a.IsValid() || !b.IsValid() {
+			continue
+		}
+		if crossFieldSatisfied(f.rule, compareValues(f.kind, a, b)) {
+			continue
+		}
+		path, otherPath := prefix+f.name, prefix+f.otherName
+		msg := fmt.Sprintf("%s 须%s %s", path, f.text, otherPath)
+		if f.kind == compareLen {
+			msg = fmt.Sprintf("%s 的长度须%s %s 的长度", path, f.text, otherPath)
+		}
+		return &validateError{FailPath: path, Msg: msg, ruleName: f.rule}
+	}
+	for _, f := range info.nested {
+		if err := validateCrossFieldsValue(rv.Field(f.index), prefix+f.name+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 汇报 rt 或其嵌套结构体中是否有跨字段比较标签。
+func hasCrossFields(rt reflect.Type) bool {
+	return hasCrossFieldsSeen(rt, make(map[reflect.Type]bool))
+}
+
+func hasCrossFieldsSeen(rt reflect.Type, seen map[reflect.Type]bool) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || seen[rt] {
+		return false
+	}
+	seen[rt] = true
+	info := getCrossFields(rt)
+	if len(info.fields) > 0 || info.err != nil {
+		return true
+	}
+	for _, f := range info.nested {
+		if hasCrossFieldsSeen(rt.Field(f.index).Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func getCrossFields(rt reflect.Type) *crossFields {
+	if v, ok := crossFieldsCache.Load(rt); ok {
+		return v.(*crossFields)
+	}
+	info := &crossFields{}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tagged := false
+		for _, t := range crossFieldTags {
+			otherName, ok := sf.Tag.Lookup(t.tag)
+			if !ok {
+				continue
+			}
+			tagged = true
+			other, ok := rt.FieldByName(otherName)
+			if !ok || len(other.Index) != 1 || !other.IsExported() {
+				info.err = fmt.Errorf("字段 %s 的 %s 标签引用了不存在的字段 %q", sf.Name, t.tag, otherName)
+				break
+			}
+			kind := compareKindOf(sf.Type)
+			if kind == compareNone || kind != compareKindOf(other.Type) {
+				info.err = fmt.Errorf("字段 %s 与 %s 的类型 %s 和 %s 不可比较", sf.Name, otherName, sf.Type, other.Type)
+				break
+			}
+			info.fields = append(info.fields, crossField{
+				index:     i,
+				name:      sf.Name,
+				other:     other.Index[0],
+				otherName: otherName,
+				rule:      t.tag,
+				text:      t.text,
+				kind:      kind,
+			})
+		}
+		if info.err != nil {
+			break
+		}
+		if tagged {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			info.nested = append(info.nested, crossField{index: i, name: sf.Name})
+		}
+	}
+	crossFieldsCache.Store(rt, info)
+	return info
+}
+
+func compareKindOf(t reflect.Type) compareKind {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return compareTime
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return compareNumber
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return compareLen
+	}
+	return compareNone
+}
+
+// 解引用指针，空指针返回无效值。
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// 比较 a 与 b，a 小于、等于、大于 b 时分别返回 -1、0、1。
+func compareValues(kind compareKind, a, b reflect.Value) int {
+	switch kind {
+	case compareTime:
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		}
+		return 0
+	case compareLen:
+		return compareOrdered(length(a), length(b))
+	}
+	return compareNumbers(a, b)
+}
+
+func compareNumbers(a, b reflect.Value) int {
+	if isFloat(a) || isFloat(b) {
+		return compareOrdered(toFloat(a), toFloat(b))
+	}
+	// 有符号与无符号混合时，负数总是更小，其余按无符号比较
+	switch {
+	case isSigned(a) && isSigned(b):
+		return compareOrdered(a.Int(), b.Int())
+	case isSigned(a) && a.Int() < 0:
+		return -1
+	case isSigned(b) && b.Int() < 0:
+		return 1
+	}
+	return compareOrdered(toUint(a), toUint(b))
+}
+
+func compareOrdered[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func length(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+func isFloat(v reflect.Value) bool {
+	return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func isSigned(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isFloat(v):
+		return v.Float()
+	case isSigned(v):
+		return float64(v.Int())
+	}
+	return float64(v.Uint())
+}
+
+func toUint(v reflect.Value) uint64 {
+	if isSigned(v) {
+		return uint64(v.Int())
+	}
+	return v.Uint()
+}
+
+// 汇报比较结果是否满足标签要求的大小关系。
+func crossFieldSatisfied(rule string, cmp int) bool {
+	switch rule {
+	case gtFieldTag:
+		return cmp > 0
+	case gteFieldTag:
+		return cmp >= 0
+	case ltFieldTag:
+		return cmp < 0
+	}
+	return cmp <= 0
+}
@@ -3,15 +3,20 @@ package binding
 import (
 	"bytes"
 	stdJson "encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	exprValidator "github.com/bytedance/go-tagexpr/v2/validator"
 	inDecoder "github.com/favbox/wind/app/server/binding/internal/decoder"
+	errs "github.com/favbox/wind/common/errors"
 	wjson "github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/common/msgpack"
 	"github.com/favbox/wind/common/utils"
 	"github.com/favbox/wind/internal/bytesconv"
 	"github.com/favbox/wind/protocol"
@@ -28,6 +33,8 @@ const (
 	defaultValidateTag = "vd"
 )
 
+var errYAMLUnmarshalerNotSet = errors.New("未设置 YAML 解码函数，请配置 BindConfig.YAMLUnmarshaler")
+
 type decoderInfo struct {
 	decoder      inDecoder.Decoder
 	needValidate bool
@@ -67,6 +74,14 @@ func BindAndValidate(req *protocol.Request, obj any, pathParams param.Params) er
 	return DefaultBinder().BindAndValidate(req, obj, pathParams)
 }
 
+// BindAndValidateAll 将 *protocol.Request 的数据绑定到 obj 并验证，绑定失败时以 FieldErrors 返回所有字段错误。
+// 注意：
+//
+//	obj 应为指针类型。
+func BindAndValidateAll(req *protocol.Request, obj any, pathParams param.Params) error {
+	return DefaultBinder().(AllErrorsBinder).BindAndValidateAll(req, obj, pathParams)
+}
+
 // Bind 将 *protocol.Request 的数据绑定到 obj。
 // 注意：
 //
@@ -75,6 +90,22 @@ func Bind(req *protocol.Request, obj any, pathParam param.Params) error {
 	return DefaultBinder().Bind(req, obj, pathParam)
 }
 
+// BindYAML 将 *protocol.Request 的 YAML 请求体绑定到 obj，需在 BindConfig 中设置 YAMLUnmarshaler。
+// 注意：
+//
+//	obj 应为指针类型。
+func BindYAML(req *protocol.Request, obj any) error {
+	return DefaultBinder().(YAMLBinder).BindYAML(req, obj)
+}
+
+// BindMsgPack 将 *protocol.Request 的 MessagePack 请求体绑定到 obj。
+// 注意：
+//
+//	obj 应为指针类型。
+func BindMsgPack(req *protocol.Request, obj any) error {
+	return DefaultBinder().(MsgPackBinder).BindMsgPack(req, obj)
+}
+
 // Validate 使用 "vd" 标签来验证 obj。
 // 注意：
 //
@@ -91,6 +122,7 @@ type defaultBinder struct {
 	queryDecoderCache  sync.Map
 	headerDecoderCache sync.Map
 	formDecoderCache   sync.Map
+	cachedTypes        int64 // 各标签缓存中的解码器总数
 }
 
 func (b *defaultBinder) Name() string {
@@ -98,11 +130,17 @@ func (b *defaultBinder) Name() string {
 }
 
 func (b *defaultBinder) BindAndValidate(req *protocol.Request, v any, params param.Params) error {
-	return b.bindTagAndValidate(req, v, params, "")
+	if err := b.bindTagAndValidate(req, v, params, "", false); err != nil {
+		return err
+	}
+	return afterBind(req, v)
 }
 
 func (b *defaultBinder) Bind(req *protocol.Request, v any, params param.Params) error {
-	return b.bindTag(req, v, params, "")
+	if err := b.bindTag(req, v, params, ""); err != nil {
+		return err
+	}
+	return afterBind(req, v)
 }
 
 func (b *defaultBinder) BindPath(req *protocol.Request, v any, params param.Params) error {
@@ -122,9 +160,27 @@ func (b *defaultBinder) BindForm(req *protocol.Request, v any) error {
 }
 
 func (b *defaultBinder) BindJSON(req *protocol.Request, v any) error {
+	if err := b.checkJSONDepth(req.Body()); err != nil {
+		return err
+	}
 	return b.decodeJSON(bytes.NewReader(req.Body()), v)
 }
 
+func (b *defaultBinder) BindYAML(req *protocol.Request, v any) error {
+	if b.config.YAMLUnmarshaler == nil {
+		return errYAMLUnmarshalerNotSet
+	}
+	return b.config.YAMLUnmarshaler(req.Body(), v)
+}
+
+// BindMsgPack 解码 MessagePack 请求体到 v，请求体为空时保持 v 不变，与 Bind 的请求体预绑定一致。
+func (b *defaultBinder) BindMsgPack(req *protocol.Request, v any) error {
+	if len(req.Body()) == 0 {
+		return nil
+	}
+	return b.msgPackUnmarshaler()(req.Body(), v)
+}
+
 func (b *defaultBinder) BindProtobuf(req *protocol.Request, v any) error {
 	msg, ok := v.(proto.Message)
 	if !ok {
@@ -143,42 +199,30 @@ func (b *defaultBinder) bindTag(req *protocol.Request, v any, params param.Param
 		return b.bindNonStruct(req, v)
 	}
 
-	err := b.preBindBody(req, v)
-	if err != nil {
-		return fmt.Errorf("绑定请求体失败，错误=%v", err)
-	}
-
-	cache := b.tagCache(tag)
-	cached, ok := cache.Load(typeID)
-	if ok {
-		// 快速路径：已缓存的字段解码器
-		decoder := cached.(decoderInfo)
-		return decoder.decoder(req, params, rv.Elem())
+	// 明确无体的请求（如 GET）跳过请求体解码，只做 query/path/header 等绑定
+	if !inDecoder.IsBodyless(req) {
+		if err := b.preBindBody(req, v); err != nil {
+			return fmt.Errorf("绑定请求体失败，错误=%w", err)
+		}
 	}
 
-	validateTag := defaultValidateTag
-	if len(b.config.Validator.ValidateTag()) != 0 {
-		validateTag = b.config.Validator.ValidateTag()
-	}
-	decodeConfig := &inDecoder.DecodeConfig{
-		LooseZeroMode:                      b.config.LooseZeroMode,
-		DisableDefaultTag:                  b.config.DisableDefaultTag,
-		DisableStructFieldResolve:          b.config.DisableStructFieldResolve,
-		EnableDecoderUseNumber:             b.config.EnableDecoderUseNumber,
-		EnableDecoderDisallowUnknownFields: b.config.EnableDecoderDisallowUnknownFields,
-		ValidateTag:                        validateTag,
-		TypeUnmarshalFuncs:                 b.config.TypeUnmarshalFuncs,
-	}
-	decoder, needValidate, err := inDecoder.GetReqDecoder(rv.Type(), tag, decodeConfig)
+	decoder, err := b.getDecoder(rv.Type(), typeID, tag)
 	if err != nil {
 		return err
 	}
+	return decoder.decoder.Decode(req, params, rv.Elem())
+}
 
-	cache.Store(typeID, decoderInfo{decoder: decoder, needValidate: needValidate})
-	return decoder(req, params, rv.Elem())
+// BindAndValidateAll 类似 BindAndValidate，但绑定时不在首个字段错误处中止，
+// 而是以 FieldErrors 返回所有字段的绑定错误；绑定无误时再进行验证。
+func (b *defaultBinder) BindAndValidateAll(req *protocol.Request, v any, params param.Params) error {
+	if err := b.bindTagAndValidate(req, v, params, "", true); err != nil {
+		return err
+	}
+	return afterBind(req, v)
 }
 
-func (b *defaultBinder) bindTagAndValidate(req *protocol.Request, v any, params param.Params, tag string) error {
+func (b *defaultBinder) bindTagAndValidate(req *protocol.Request, v any, params param.Params, tag string, collectAll bool) error {
 	rv, typeID := valueAndTypeID(v)
 
 	// 确保接收器为非空指针
@@ -192,25 +236,40 @@ func (b *defaultBinder) bindTagAndValidate(req *protocol.Request, v any, params
 		return b.bindNonStruct(req, v)
 	}
 
-	err := b.preBindBody(req, v)
+	// 明确无体的请求（如 GET）跳过请求体解码，只做 query/path/header 等绑定
+	if !inDecoder.IsBodyless(req) {
+		if err := b.preBindBody(req, v); err != nil {
+			return fmt.Errorf("绑定请求体失败，错误=%w", err)
+		}
+	}
+
+	decoder, err := b.getDecoder(rv.Type(), typeID, tag)
 	if err != nil {
-		return fmt.Errorf("绑定请求体失败，错误=%v", err)
+		return err
+	}
+	if collectAll {
+		err = decoder.decoder.DecodeAll(req, params, rv.Elem())
+	} else {
+		err = decoder.decoder.Decode(req, params, rv.Elem())
+	}
+	if err != nil {
+		return err
 	}
+	if decoder.needValidate {
+		err = validateRequest(b.config.Validator, req, rv.Elem())
+	}
+	return err
+}
 
+// 获取类型 rt 在标签 tag 下的字段解码器，未缓存则构建并缓存。
+func (b *defaultBinder) getDecoder(rt reflect.Type, typeID uintptr, tag string) (decoderInfo, error) {
 	cache := b.tagCache(tag)
-	cached, ok := cache.Load(typeID)
-	if ok {
+	if cached, ok := cache.Load(typeID); ok {
 		// 快速路径：已缓存的字段解码器
-		decoder := cached.(decoderInfo)
-		err = decoder.decoder(req, params, rv.Elem())
-		if err != nil {
-			return err
-		}
-		if decoder.needValidate {
-			err = b.config.Validator.ValidateStruct(rv.Elem())
-		}
-		return err
+		atomic.AddUint64(&cacheHits, 1)
+		return cached.(decoderInfo), nil
 	}
+	atomic.AddUint64(&cacheMisses, 1)
 
 	validateTag := defaultValidateTag
 	if len(b.config.Validator.ValidateTag()) != 0 {
@@ -224,27 +283,50 @@ func (b *defaultBinder) bindTagAndValidate(req *protocol.Request, v any, params
 		EnableDecoderDisallowUnknownFields: b.config.EnableDecoderDisallowUnknownFields,
 		ValidateTag:                        validateTag,
 		TypeUnmarshalFuncs:                 b.config.TypeUnmarshalFuncs,
+		MaxRawBodySize:                     b.config.MaxRawBodySize,
+		LooseTimeMode:                      b.config.LooseTimeMode,
 	}
-	decoder, needValidate, err := inDecoder.GetReqDecoder(rv.Type(), tag, decodeConfig)
+	decoder, needValidate, err := inDecoder.GetReqDecoder(rt, tag, decodeConfig)
 	if err != nil {
-		return err
+		return decoderInfo{}, err
 	}
 
-	cache.Store(typeID, decoderInfo{decoder: decoder, needValidate: needValidate})
-	err = decoder(req, params, rv.Elem())
-	if err != nil {
-		return err
+	// 仅带跨字段比较或枚举标签的结构体同样需要验证
+	needValidate = needValidate || hasCrossFields(rt) || hasEnumFields(rt)
+	info := decoderInfo{decoder: decoder, needValidate: needValidate}
+	if _, loaded := cache.LoadOrStore(typeID, info); !loaded {
+		atomic.AddInt64(&b.cachedTypes, 1)
 	}
-	if needValidate {
-		err = b.config.Validator.ValidateStruct(rv.Elem())
+	return info, nil
+}
+
+// Precompile 预先构建 v 类型在各绑定标签下的字段解码器并缓存，v 可为结构体或其指针。
+func (b *defaultBinder) Precompile(v any) error {
+	rv, typeID := valueAndTypeID(newStructPointer(v))
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("预编译的目标须为结构体或其指针，实为 %T", v)
 	}
-	return err
+	for _, tag := range []string{"", pathTag, queryTag, headerTag, formTag} {
+		if _, err := b.getDecoder(rv.Type(), typeID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (b *defaultBinder) bindNonStruct(req *protocol.Request, v any) (err error) {
-	ct := bytesconv.B2s(req.Header.ContentType())
-	switch utils.FilterContentType(ct) {
+	ct := utils.FilterContentType(bytesconv.B2s(req.Header.ContentType()))
+	if err = b.limitBodyStream(req, ct); err != nil {
+		return err
+	}
+	if b.config.SniffContentType && isAmbiguousContentType(ct) {
+		ct = sniffContentType(req)
+	}
+	switch ct {
 	case consts.MIMEApplicationJSON:
+		if err = b.checkJSONDepth(req.Body()); err != nil {
+			return err
+		}
 		err = wjson.Unmarshal(req.Body(), v)
 	case consts.MIMEPROTOBUF:
 		msg, ok := v.(proto.Message)
@@ -252,6 +334,13 @@ func (b *defaultBinder) bindNonStruct(req *protocol.Request, v any) (err error)
 			return fmt.Errorf("%s 未实现 'proto.Message'", v)
 		}
 		err = proto.Unmarshal(req.Body(), msg)
+	case consts.MIMEApplicationYAML, consts.MIMEApplicationXYAML, consts.MIMETextYAML:
+		if b.config.YAMLUnmarshaler == nil {
+			return errYAMLUnmarshalerNotSet
+		}
+		err = b.config.YAMLUnmarshaler(req.Body(), v)
+	case consts.MIMEApplicationMsgPack, consts.MIMEApplicationXMsgPack:
+		err = b.BindMsgPack(req, v)
 	case consts.MIMEMultipartPOSTForm:
 		form := make(url.Values)
 		mf, err1 := req.MultipartForm()
@@ -283,13 +372,30 @@ func (b *defaultBinder) bindNonStruct(req *protocol.Request, v any) (err error)
 	return
 }
 
+// afterBind 若 v 实现了 AfterBinder，则调用其绑定后的回调。
+func afterBind(req *protocol.Request, v any) error {
+	if ab, ok := v.(AfterBinder); ok {
+		return ab.AfterBind(req)
+	}
+	return nil
+}
+
 func (b *defaultBinder) preBindBody(req *protocol.Request, v any) error {
 	if req.Header.ContentLength() <= 0 {
 		return nil
 	}
-	ct := bytesconv.B2s(req.Header.ContentType())
-	switch utils.FilterContentType(ct) {
+	ct := utils.FilterContentType(bytesconv.B2s(req.Header.ContentType()))
+	if err := b.limitBodyStream(req, ct); err != nil {
+		return err
+	}
+	if b.config.SniffContentType && isAmbiguousContentType(ct) {
+		ct = sniffContentType(req)
+	}
+	switch ct {
 	case consts.MIMEApplicationJSON, consts.MIMEApplicationJSONUTF8:
+		if err := b.checkJSONDepth(req.Body()); err != nil {
+			return err
+		}
 		return wjson.Unmarshal(req.Body(), v)
 	case consts.MIMEPROTOBUF:
 		msg, ok := v.(proto.Message)
@@ -297,11 +403,58 @@ func (b *defaultBinder) preBindBody(req *protocol.Request, v any) error {
 			return fmt.Errorf("%s 未实现 'proto.Message'", v)
 		}
 		return proto.Unmarshal(req.Body(), msg)
+	case consts.MIMEApplicationYAML, consts.MIMEApplicationXYAML, consts.MIMETextYAML:
+		if b.config.YAMLUnmarshaler == nil {
+			return nil
+		}
+		return b.config.YAMLUnmarshaler(req.Body(), v)
+	case consts.MIMEApplicationMsgPack, consts.MIMEApplicationXMsgPack:
+		return b.msgPackUnmarshaler()(req.Body(), v)
 	default:
 		return nil
 	}
 }
 
+// 以 MaxRawBodySize 为上限读入需整体解码的流式请求体，包括 JSON、protobuf、YAML、MessagePack 及待嗅探的类型，
+// 以防随后的 req.Body() 无限制地读入内存。表单等其他类型的流式请求体保持不变。
+func (b *defaultBinder) limitBodyStream(req *protocol.Request, ct string) error {
+	if !req.IsBodyStream() {
+		return nil
+	}
+	switch ct {
+	case consts.MIMEApplicationJSON, consts.MIMEApplicationJSONUTF8, consts.MIMEPROTOBUF,
+		consts.MIMEApplicationYAML, consts.MIMEApplicationXYAML, consts.MIMETextYAML,
+		consts.MIMEApplicationMsgPack, consts.MIMEApplicationXMsgPack:
+	default:
+		if !b.config.SniffContentType || !isAmbiguousContentType(ct) {
+			return nil
+		}
+	}
+	return inDecoder.ReadBodyStream(req, b.config.MaxRawBodySize)
+}
+
+// 返回配置的 MessagePack 解码函数，未配置则使用默认实现。
+func (b *defaultBinder) msgPackUnmarshaler() func(data []byte, v any) error {
+	if b.config.MsgPackUnmarshaler != nil {
+		return b.config.MsgPackUnmarshaler
+	}
+	return msgpack.Unmarshal
+}
+
+// 清空各标签下的解码器缓存。
+func (b *defaultBinder) clearCache() {
+	for _, cache := range []*sync.Map{
+		&b.decoderCache, &b.pathDecoderCache, &b.queryDecoderCache, &b.headerDecoderCache, &b.formDecoderCache,
+	} {
+		cache.Range(func(key, _ any) bool {
+			if _, loaded := cache.LoadAndDelete(key); loaded {
+				atomic.AddInt64(&b.cachedTypes, -1)
+			}
+			return true
+		})
+	}
+}
+
 func (b *defaultBinder) tagCache(tag string) *sync.Map {
 	switch tag {
 	case pathTag:
@@ -329,22 +482,75 @@ func (b *defaultBinder) decodeJSON(r io.Reader, obj any) error {
 	return decoder.Decode(obj)
 }
 
+// 按配置检查 JSON 的嵌套层数。
+func (b *defaultBinder) checkJSONDepth(data []byte) error {
+	if b.config.MaxJSONDepth <= 0 {
+		return nil
+	}
+	return checkJSONDepth(data, b.config.MaxJSONDepth)
+}
+
+// 预扫描 data 的嵌套层数，超过 maxDepth 则返回 errors.ErrJSONTooDeep。
+//
+// 仅跟踪字符串边界和括号，不校验语法，语法错误留给解码器报告。
+func checkJSONDepth(data []byte, maxDepth int) error {
+	depth := 0
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return errs.ErrJSONTooDeep
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
 var defaultValidate = NewValidator(NewValidateConfig())
 
+// 验证 obj，验证器支持多语言时按请求的 Accept-Language 渲染错误消息。
+func validateRequest(vd StructValidator, req *protocol.Request, obj any) error {
+	if lv, ok := vd.(LocalizedValidator); ok {
+		if langs := req.Header.AcceptLanguages(); len(langs) > 0 {
+			return lv.ValidateStructLang(obj, langs...)
+		}
+	}
+	return vd.ValidateStruct(obj)
+}
+
 // NewValidator 创建给定配置的验证器。
 func NewValidator(config *ValidateConfig) StructValidator {
 	validateTag := defaultValidateTag
 	if config != nil && len(config.ValidateTag) != 0 {
 		validateTag = config.ValidateTag
 	}
+	// 底层统一产生 *validateError，本地化后再交由自定义错误工厂
 	vd := exprValidator.New(validateTag).SetErrorFactory(defaultValidateErrorFactory)
-	if config != nil && config.ErrFactory != nil {
-		vd.SetErrorFactory(config.ErrFactory)
-	}
-	return &validator{
+	v := &validator{
 		validateTag: validateTag,
 		validate:    vd,
 	}
+	if config != nil {
+		v.errFactory = config.ErrFactory
+		v.messages = config.messages
+	}
+	return v
 }
 
 // DefaultValidator 返回默认验证器。
@@ -357,14 +563,69 @@ var _ StructValidator = (*validator)(nil)
 type validator struct {
 	validateTag string
 	validate    *exprValidator.Validator
+	errFactory  ValidateErrFactory
+	messages    map[string]map[validateMsgKey]string
 }
 
 // ValidateStruct 可接收任何类型，但只处理结构体或结构体指针。
+//
+// 除验证标签外，还会校验 enums 标签声明的枚举白名单，以及 gtfield、ltfield 等标签声明的跨字段大小关系。
 func (v *validator) ValidateStruct(obj any) error {
+	return v.ValidateStructLang(obj)
+}
+
+// ValidateStructLang 同 ValidateStruct，验证失败时按 langs 的顺序选择已注册的多语言消息模板。
+func (v *validator) ValidateStructLang(obj any, langs ...string) error {
 	if obj == nil {
 		return nil
 	}
-	return v.validate.Validate(obj)
+	err := v.validate.Validate(obj)
+	if err == nil {
+		err = validateEnums(obj, enumErrorFactory)
+	}
+	if err == nil {
+		err = validateCrossFields(obj)
+	}
+	ve, ok := err.(*validateError)
+	if !ok {
+		return err
+	}
+
+	msg := ve.Msg
+	if tmpl, ok := v.lookupMessage(langs, ve.FailPath, ve.rule()); ok {
+		msg = strings.NewReplacer("{field}", ve.FailPath, "{msg}", ve.Msg).Replace(tmpl)
+	}
+	if v.errFactory != nil {
+		return v.errFactory(ve.FailPath, msg)
+	}
+	ve.Msg = msg
+	return ve
+}
+
+// 按语言优先级查找字段及规则对应的消息模板，语言未注册时回退到其主语言，如 zh-cn 回退到 zh。
+func (v *validator) lookupMessage(langs []string, field, rule string) (string, bool) {
+	if len(v.messages) == 0 {
+		return "", false
+	}
+	for _, lang := range langs {
+		lang = strings.ToLower(lang)
+		for {
+			if m := v.messages[lang]; m != nil {
+				if tmpl, ok := m[validateMsgKey{field, rule}]; ok {
+					return tmpl, true
+				}
+				if tmpl, ok := m[validateMsgKey{field, ""}]; ok {
+					return tmpl, true
+				}
+			}
+			i := strings.LastIndexByte(lang, '-')
+			if i < 0 {
+				break
+			}
+			lang = lang[:i]
+		}
+	}
+	return "", false
 }
 
 // Engine 返回底层验证器。
@@ -377,9 +638,31 @@ func (v *validator) ValidateTag() string {
 	return v.validateTag
 }
 
+// Precompile 预先解析 obj 类型的验证表达式并缓存，obj 可为结构体或其指针。
+func (v *validator) Precompile(obj any) error {
+	rv := reflect.ValueOf(newStructPointer(obj))
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("预编译的目标须为结构体或其指针，实为 %T", obj)
+	}
+	if _, err := v.validate.VM().Run(rv); err != nil {
+		return err
+	}
+	getEnumFields(rv.Elem().Type())
+	return getCrossFields(rv.Elem().Type()).err
+}
+
 // 验证错误
 type validateError struct {
 	FailPath, Msg string
+	ruleName      string // 内置规则名，如 enums、gtfield，为空时以 Msg 作为规则名
+}
+
+// 返回错误对应的规则名，用于查找多语言消息模板。
+func (e *validateError) rule() string {
+	if e.ruleName != "" {
+		return e.ruleName
+	}
+	return e.Msg
 }
 
 // 实现错误接口
@@ -396,3 +679,11 @@ func defaultValidateErrorFactory(failPath, msg string) error {
 		Msg:      msg,
 	}
 }
+
+func enumErrorFactory(failPath, msg string) error {
+	return &validateError{
+		FailPath: failPath,
+		Msg:      msg,
+		ruleName: enumsTag,
+	}
+}
@@ -0,0 +1,205 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 枚举白名单标签，如 `enums:"TWEET,RETWEET"` 或 `enums:"0,1,2"`。
+const enumsTag = "enums"
+
+// 可声明 required 选项的绑定标签，如 `query:"type,required"`。
+var requiredTags = []string{pathTag, formTag, queryTag, headerTag, "cookie", "json", "file_name"}
+
+// 带枚举白名单的字段
+type enumField struct {
+	index    int
+	name     string
+	allowed  []string
+	raw      string
+	required bool // 零值同样需要校验
+}
+
+// 结构体类型 -> 其枚举字段及需递归校验的嵌套字段
+type enumFields struct {
+	fields []enumField
+	nested []enumField
+}
+
+var enumFieldsCache sync.Map
+
+// validateEnums 校验 obj 中带 enums 标签的字段取值是否在白名单内。
+//
+// 实现 fmt.Stringer 的值按 String() 或底层字面值匹配，其他值按字面值匹配；切片逐个元素校验。
+// 空指针与未设置的零值跳过，除非字段的绑定标签同时声明了 required。
+func validateEnums(obj any, errFactory ValidateErrFactory) error {
+	rv, ok := obj.(reflect.Value)
+	if !ok {
+		rv = reflect.ValueOf(obj)
+	}
+	return validateEnumsValue(rv, "", errFactory)
+}
+
+func validateEnumsValue(rv reflect.Value, prefix string, errFactory ValidateErrFactory) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	info := getEnumFields(rv.Type())
+	for _, f := range info.fields {
+		fv := rv.Field(f.index)
+		if !f.required && fv.IsZero() {
+			continue
+		}
+		if err := checkEnumValue(fv, prefix+f.name, f, errFactory); err != nil {
+			return err
+		}
+	}
+	for _, f := range info.nested {
+		if err := validateEnumsValue(rv.Field(f.index), prefix+f.name+".", errFactory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkEnumValue(fv reflect.Value, path string, f enumField, errFactory ValidateErrFactory) error {
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+		for i := 0; i < fv.Len(); i++ {
+			if err := checkEnumValue(fv.Index(i), path+"["+strconv.Itoa(i)+"]", f, errFactory); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	literal := enumLiteral(fv)
+	var text string
+	if fv.CanInterface() {
+		if s, ok := fv.Interface().(fmt.Stringer); ok {
+			text = s.String()
+		}
+	}
+	for _, a := range f.allowed {
+		if a == literal || (text != "" && a == text) {
+			return nil
+		}
+	}
+
+	msg := fmt.Sprintf("%s 的取值 %s 无效，允许的取值：%s", path, literal, f.raw)
+	if errFactory != nil {
+		return errFactory(path, msg)
+	}
+	return defaultValidateErrorFactory(path, msg)
+}
+
+// 返回值的字面文本
+func enumLiteral(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// 汇报 rt 或其嵌套结构体中是否有枚举标签。
+func hasEnumFields(rt reflect.Type) bool {
+	return hasEnumFieldsSeen(rt, make(map[reflect.Type]bool))
+}
+
+func hasEnumFieldsSeen(rt reflect.Type, seen map[reflect.Type]bool) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct || seen[rt] {
+		return false
+	}
+	seen[rt] = true
+	info := getEnumFields(rt)
+	if len(info.fields) > 0 {
+		return true
+	}
+	for _, f := range info.nested {
+		if hasEnumFieldsSeen(rt.Field(f.index).Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// 汇报字段的绑定标签是否声明了 required 选项。
+func isRequiredField(sf reflect.StructField) bool {
+	for _, key := range requiredTags {
+		tag, ok := sf.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		_, opts, _ := strings.Cut(tag, ",")
+		for _, opt := range strings.Split(opts, ",") {
+			if strings.TrimSpace(opt) == "required" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getEnumFields(rt reflect.Type) *enumFields {
+	if v, ok := enumFieldsCache.Load(rt); ok {
+		return v.(*enumFields)
+	}
+	info := &enumFields{}
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if tag, ok := sf.Tag.Lookup(enumsTag); ok {
+			allowed := strings.Split(tag, ",")
+			for j := range allowed {
+				allowed[j] = strings.TrimSpace(allowed[j])
+			}
+			info.fields = append(info.fields, enumField{
+				index:    i,
+				name:     sf.Name,
+				allowed:  allowed,
+				raw:      tag,
+				required: isRequiredField(sf),
+			})
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			info.nested = append(info.nested, enumField{index: i, name: sf.Name})
+		}
+	}
+	enumFieldsCache.Store(rt, info)
+	return info
+}
@@ -13,6 +13,7 @@ type fieldInfo struct {
 	index       int           // 字段索引
 	parentIndex []int         // 父级索引切片
 	fieldName   string        // 字段名称
+	fieldPath   string        // 字段路径，如 User.Address.Zip
 	tagInfos    []TagInfo     // 标签切片
 	fieldType   reflect.Type  // 字段的反射类型
 	config      *DecodeConfig // 解码配置
@@ -28,6 +29,7 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 	var text string
 	var exists bool
 	var defaultValue string
+	var source string
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			defaultValue = tagInfo.Default
@@ -36,7 +38,7 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' 字段必填，但请求体无此参数 '%s'", d.fieldName, tagInfo.JSONName)
+					err = d.fieldError(jsonTag, "", fmt.Errorf("字段必填，但请求体无此参数 '%s'", tagInfo.JSONName))
 				}
 			}
 			continue
@@ -45,10 +47,11 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 		defaultValue = tagInfo.Default
 		if exists {
 			err = nil
+			source = tagInfo.Key
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+			err = d.fieldError(tagInfo.Key, "", errRequired)
 		}
 	}
 	if err != nil {
@@ -56,6 +59,7 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 	}
 	if len(text) == 0 && len(defaultValue) != 0 {
 		text = defaultValue
+		source = defaultTag
 	}
 	if !exists && len(text) == 0 {
 		return nil
@@ -74,7 +78,7 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 		var vv reflect.Value
 		vv, err := stringToValue(t, text, req, params, d.config)
 		if err != nil {
-			return err
+			return d.fieldError(source, text, fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err))
 		}
 		field.Set(ReferenceValue(vv, ptrDepth))
 		return nil
@@ -83,7 +87,7 @@ func (d *baseTypeFieldTextDecoder) Decode(req *protocol.Request, params param.Pa
 	// 非指针元素
 	err = d.decoder.UnmarshalString(text, field, d.config.LooseZeroMode)
 	if err != nil {
-		return fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err)
+		return d.fieldError(source, text, fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err))
 	}
 
 	return nil
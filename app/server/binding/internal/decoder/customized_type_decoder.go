@@ -20,6 +20,7 @@ func (d *customizedFieldTextDecoder) Decode(req *protocol.Request, params param.
 	var text string
 	var exists bool
 	var defaultValue string
+	var source string
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			defaultValue = tagInfo.Default
@@ -28,6 +29,7 @@ func (d *customizedFieldTextDecoder) Decode(req *protocol.Request, params param.
 		text, exists = tagInfo.Getter(req, params, tagInfo.Value)
 		defaultValue = tagInfo.Default
 		if exists {
+			source = tagInfo.Key
 			break
 		}
 	}
@@ -40,7 +42,7 @@ func (d *customizedFieldTextDecoder) Decode(req *protocol.Request, params param.
 
 	v, err := d.decodeFunc(req, params, text)
 	if err != nil {
-		return err
+		return d.fieldError(source, text, err)
 	}
 	if !v.IsValid() {
 		return nil
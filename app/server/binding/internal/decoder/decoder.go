@@ -3,9 +3,11 @@ package decoder
 
 import (
 	"fmt"
+	"io"
 	"mime/multipart"
 	"reflect"
 
+	werrors "github.com/favbox/wind/common/errors"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/route/param"
 )
@@ -13,10 +15,80 @@ import (
 // 定义了字段解码器需要实现的解码函数签名。
 type fieldDecoder interface {
 	Decode(req *protocol.Request, params param.Params, refValue reflect.Value) error
+	info() *fieldInfo
 }
 
 // Decoder 是请求的解码器。
-type Decoder func(req *protocol.Request, params param.Params, rv reflect.Value) error
+type Decoder struct {
+	decoders       []fieldDecoder
+	rawBodyField   fieldDecoder // 首个带 raw_body 标签的字段，为空表示无需读取原始请求体
+	maxRawBodySize int
+}
+
+// Decode 将请求解码到 rv，遇到首个字段错误即返回。
+func (d Decoder) Decode(req *protocol.Request, params param.Params, rv reflect.Value) error {
+	if err := d.readRawBody(req); err != nil {
+		return err
+	}
+	for _, decoder := range d.decoders {
+		if err := decoder.Decode(req, params, rv); err != nil {
+			return decoder.info().fieldError("", "", err)
+		}
+	}
+	return nil
+}
+
+// DecodeAll 将请求解码到 rv，解码所有字段并以 FieldErrors 返回全部字段错误。
+func (d Decoder) DecodeAll(req *protocol.Request, params param.Params, rv reflect.Value) error {
+	if err := d.readRawBody(req); err != nil {
+		return FieldErrors{err}
+	}
+	var errs FieldErrors
+	for _, decoder := range d.decoders {
+		if err := decoder.Decode(req, params, rv); err != nil {
+			errs = append(errs, decoder.info().fieldError("", "", err))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// 流式请求体未限制大小时，raw_body 字段读取请求体的默认上限。
+const defaultMaxRawBodySize = 4 * 1024 * 1024
+
+// 有 raw_body 字段且请求体为流时，以大小限制读入完整请求体，供各字段直接取用。
+func (d Decoder) readRawBody(req *protocol.Request) *FieldError {
+	if d.rawBodyField == nil {
+		return nil
+	}
+	if err := ReadBodyStream(req, d.maxRawBodySize); err != nil {
+		return d.rawBodyField.info().fieldError(rawBodyTag, "", err)
+	}
+	return nil
+}
+
+// ReadBodyStream 在请求体为流时最多读入 maxSize 字节并设为 req 的请求体，超限返回 errors.ErrBodyTooLarge。
+//
+// maxSize <= 0 时上限为 4MB，请求体非流时直接返回。
+func ReadBodyStream(req *protocol.Request, maxSize int) error {
+	if !req.IsBodyStream() {
+		return nil
+	}
+	if maxSize <= 0 {
+		maxSize = defaultMaxRawBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(maxSize)+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxSize {
+		return werrors.ErrBodyTooLarge
+	}
+	req.SetBody(body)
+	return nil
+}
 
 // DecodeConfig 是请求的解码配置项。
 type DecodeConfig struct {
@@ -27,6 +99,8 @@ type DecodeConfig struct {
 	EnableDecoderDisallowUnknownFields bool                                  // 有未知不匹配字段则报错
 	ValidateTag                        string                                // 验证标签
 	TypeUnmarshalFuncs                 map[reflect.Type]CustomizedDecodeFunc // 自定义类型解码函数
+	MaxRawBodySize                     int                                   // 流式请求体下 raw_body 字段读取的最大字节数
+	LooseTimeMode                      bool                                  // 时间字段接受 Unix 秒/毫秒时间戳
 }
 
 // GetReqDecoder 获取请求的解码器。
@@ -36,7 +110,7 @@ func GetReqDecoder(rt reflect.Type, byTag string, config *DecodeConfig) (Decoder
 
 	el := rt.Elem()
 	if el.Kind() != reflect.Struct {
-		return nil, false, fmt.Errorf("不支持 %s 类型绑定", rt.String())
+		return Decoder{}, false, fmt.Errorf("不支持 %s 类型绑定", rt.String())
 	}
 
 	for i := 0; i < el.NumField(); i++ {
@@ -46,10 +120,10 @@ func GetReqDecoder(rt reflect.Type, byTag string, config *DecodeConfig) (Decoder
 		}
 
 		// dec, needValidate2, err := getFieldDecoder(el.Field(i), i, []int{}, "", byTag, config)
-		dec, needValidate2, err := getFieldDecoder(parentInfos{[]reflect.Type{el}, []int{}, ""}, el.Field(i), i, byTag, config)
+		dec, needValidate2, err := getFieldDecoder(parentInfos{[]reflect.Type{el}, []int{}, "", ""}, el.Field(i), i, byTag, config)
 
 		if err != nil {
-			return nil, false, err
+			return Decoder{}, false, err
 		}
 		needValidate = needValidate || needValidate2
 
@@ -58,22 +132,26 @@ func GetReqDecoder(rt reflect.Type, byTag string, config *DecodeConfig) (Decoder
 		}
 	}
 
-	return func(req *protocol.Request, params param.Params, rv reflect.Value) error {
-		for _, decoder := range decoders {
-			err := decoder.Decode(req, params, rv)
-			if err != nil {
-				return err
+	d := Decoder{decoders: decoders, maxRawBodySize: config.MaxRawBodySize}
+	for _, dec := range decoders {
+		for _, tagInfo := range dec.info().tagInfos {
+			if tagInfo.Key == rawBodyTag && !tagInfo.Skip {
+				d.rawBodyField = dec
+				break
 			}
 		}
-
-		return nil
-	}, needValidate, nil
+		if d.rawBodyField != nil {
+			break
+		}
+	}
+	return d, needValidate, nil
 }
 
 type parentInfos struct {
 	Types    []reflect.Type
 	Indexes  []int
 	JSONName string
+	Path     string // 父字段路径，如 User.Address
 }
 
 // func getFieldDecoder(field reflect.StructField, index int, parentIdx []int, parentJSONName, byTag string, config *DecodeConfig) ([]fieldDecoder, bool, error) {
@@ -89,6 +167,12 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 		return nil, false, nil
 	}
 
+	// 匿名嵌入的结构体字段被提升，不计入路径
+	fieldPath := pInfo.Path
+	if !field.Anonymous {
+		fieldPath = joinFieldPath(pInfo.Path, field.Name)
+	}
+
 	// 形如 'a.b.c' 的 JSONName 用于必填验证。
 	fieldTagInfos, newParentJSONName, needValidate := lookupFieldTags(field, pInfo.JSONName, config)
 	if len(fieldTagInfos) == 0 && !config.DisableDefaultTag {
@@ -98,22 +182,34 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 		fieldTagInfos = getFieldTagInfoByTag(field, byTag)
 	}
 
+	// 字段级的时间格式覆盖全局配置
+	if layout := field.Tag.Get(timeFormatTag); layout != "" && field.Type == timeType {
+		dec, err := getCustomizedFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, TimeDecodeFunc([]string{layout}, config.LooseTimeMode), config)
+		return setFieldPath(dec, fieldPath), needValidate, err
+	}
+
 	// 自定义类型解码器拥有最高优先级
 	if customizedFunc, exists := config.TypeUnmarshalFuncs[field.Type]; exists {
 		dec, err := getCustomizedFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, customizedFunc, config)
-		return dec, needValidate, err
+		return setFieldPath(dec, fieldPath), needValidate, err
+	}
+
+	// 实现了 sql.Scanner 的类型，如 sql.Null*
+	if scanFunc := scannerDecodeFunc(field.Type); scanFunc != nil {
+		dec, err := getCustomizedFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, scanFunc, config)
+		return setFieldPath(dec, fieldPath), needValidate, err
 	}
 
 	// 切片、数组字段解码器
 	if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array {
 		dec, err := getSliceFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, config)
-		return dec, needValidate, err
+		return setFieldPath(dec, fieldPath), needValidate, err
 	}
 
 	// 映射字段解码器
 	if field.Type.Kind() == reflect.Map {
 		dec, err := getMapFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, config)
-		return dec, needValidate, err
+		return setFieldPath(dec, fieldPath), needValidate, err
 	}
 
 	// 结构体字段将被递归解析
@@ -124,7 +220,7 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 		switch el {
 		case reflect.TypeOf(multipart.FileHeader{}):
 			dec, err := getMultipartFileDecoder(field, index, fieldTagInfos, pInfo.Indexes, config)
-			return dec, needValidate, err
+			return setFieldPath(dec, fieldPath), needValidate, err
 		}
 		if !config.DisableStructFieldResolve { // 单独解码结构体类型
 			structFieldDecoder, err := getStructTypeFieldDecoder(field, index, fieldTagInfos, pInfo.Indexes, config)
@@ -132,7 +228,7 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 				return nil, needValidate, err
 			}
 			if structFieldDecoder != nil {
-				decoders = append(decoders, structFieldDecoder...)
+				decoders = append(decoders, setFieldPath(structFieldDecoder, fieldPath)...)
 			}
 		}
 
@@ -155,6 +251,7 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 			pInfo.Indexes = indices
 			pInfo.Types = append(pInfo.Types, el)
 			pInfo.JSONName = newParentJSONName
+			pInfo.Path = fieldPath
 			dec, needValidate2, err := getFieldDecoder(pInfo, el.Field(i), i, byTag, config)
 			needValidate = needValidate || needValidate2
 			if err != nil {
@@ -169,7 +266,15 @@ func getFieldDecoder(pInfo parentInfos, field reflect.StructField, index int, by
 
 	// 基本类型解码器
 	dec, err := getBaseTypeTextDecoder(field, index, fieldTagInfos, pInfo.Indexes, config)
-	return dec, needValidate, err
+	return setFieldPath(dec, fieldPath), needValidate, err
+}
+
+// 设置字段解码器的字段路径
+func setFieldPath(decoders []fieldDecoder, path string) []fieldDecoder {
+	for _, d := range decoders {
+		d.info().fieldPath = path
+	}
+	return decoders
 }
 
 // hasSameType 确定父子关系中是否存在相同类型
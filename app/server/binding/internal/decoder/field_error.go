@@ -0,0 +1,65 @@
+package decoder
+
+import (
+	"errors"
+	"strings"
+)
+
+var errRequired = errors.New("字段必填，但请求无此参数")
+
+// FieldError 表示单个字段的绑定错误。
+type FieldError struct {
+	Path   string // 字段路径，如 User.Address.Zip
+	Source string // 参数来源，如 query、form、json
+	Value  string // 请求中的原始值
+	Type   string // 字段期望的类型
+	Err    error  // 底层错误
+}
+
+func (e *FieldError) Error() string {
+	return "'" + e.Path + "' " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors 表示多个字段的绑定错误。
+type FieldErrors []*FieldError
+
+func (es FieldErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// 返回带字段路径等信息的绑定错误，err 已是 *FieldError 时原样返回。
+func (f *fieldInfo) fieldError(source, value string, err error) *FieldError {
+	if fe, ok := err.(*FieldError); ok {
+		return fe
+	}
+	return &FieldError{
+		Path:   f.fieldPath,
+		Source: source,
+		Value:  value,
+		Type:   f.fieldType.String(),
+		Err:    err,
+	}
+}
+
+func (f *fieldInfo) info() *fieldInfo {
+	return f
+}
+
+// 拼接字段路径
+func joinFieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	if name == "" {
+		return parent
+	}
+	return parent + "." + name
+}
@@ -1,10 +1,19 @@
 package decoder
 
 import (
+	"net/url"
+
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/route/param"
 )
 
+// IsBodyless 判断请求是否明确无体：Content-Length 为 0（非分块）且无请求体数据。
+//
+// 无体请求无需进行请求体及多部分表单解码。
+func IsBodyless(req *protocol.Request) bool {
+	return req.Header.ContentLength() == 0 && !req.IsBodyStream() && len(req.BodyBytes()) == 0
+}
+
 type getter func(req *protocol.Request, params param.Params, key string, defaultValue ...string) (ret string, exists bool)
 
 func path(_ *protocol.Request, params param.Params, key string, defaultValue ...string) (ret string, exists bool) {
@@ -45,7 +54,7 @@ func header(req *protocol.Request, _ param.Params, key string, defaultValue ...s
 
 func cookie(req *protocol.Request, _ param.Params, key string, defaultValue ...string) (ret string, exists bool) {
 	if val := req.Header.Cookie(key); val != nil {
-		ret = string(val)
+		ret = unescapeCookie(string(val))
 		return ret, true
 	}
 
@@ -56,11 +65,27 @@ func cookie(req *protocol.Request, _ param.Params, key string, defaultValue ...s
 	return ret, false
 }
 
+// 还原 RequestContext.SetCookie 以 url.QueryEscape 转义的值，无法还原时原样返回。
+func unescapeCookie(value string) string {
+	if v, err := url.QueryUnescape(value); err == nil {
+		return v
+	}
+	return value
+}
+
 func postForm(req *protocol.Request, _ param.Params, key string, defaultValue ...string) (ret string, exists bool) {
 	if ret, exists = req.PostArgs().PeekExists(key); exists {
 		return
 	}
 
+	// 无体请求不必再解析多部分表单
+	if IsBodyless(req) {
+		if len(defaultValue) != 0 {
+			ret = defaultValue[0]
+		}
+		return ret, false
+	}
+
 	mf, err := req.MultipartForm()
 	if err == nil && mf.Value != nil {
 		for k, v := range mf.Value {
@@ -82,8 +107,9 @@ func postForm(req *protocol.Request, _ param.Params, key string, defaultValue ..
 }
 
 func rawBody(req *protocol.Request, _ param.Params, key string, _ ...string) (ret string, exists bool) {
-	if req.Header.ContentLength() > 0 {
-		ret = string(req.Body())
+	// 分块传输的请求体无 Content-Length，以实际内容判断
+	if body := req.Body(); len(body) > 0 {
+		ret = string(body)
 		exists = true
 	}
 
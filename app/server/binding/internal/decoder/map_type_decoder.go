@@ -19,6 +19,7 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 	var text string
 	var exists bool
 	var defaultValue string
+	var source string
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			defaultValue = tagInfo.Default
@@ -27,7 +28,7 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+					err = d.fieldError(jsonTag, "", errRequired)
 				}
 			}
 			continue
@@ -36,10 +37,11 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 		defaultValue = tagInfo.Default
 		if exists {
 			err = nil
+			source = tagInfo.Key
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+			err = d.fieldError(tagInfo.Key, "", errRequired)
 		}
 	}
 	if err != nil {
@@ -47,6 +49,7 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 	}
 	if len(text) == 0 && len(defaultValue) != 0 {
 		text = defaultValue
+		source = defaultTag
 	}
 	if !exists && len(text) == 0 {
 		return nil
@@ -64,7 +67,7 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 		var vv reflect.Value
 		vv, err := stringToValue(t, text, req, params, d.config)
 		if err != nil {
-			return fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err)
+			return d.fieldError(source, text, fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err))
 		}
 		field.Set(ReferenceValue(vv, ptrDepth))
 		return nil
@@ -72,7 +75,7 @@ func (d *mapTypeFieldDecoder) Decode(req *protocol.Request, params param.Params,
 
 	err = wjson.Unmarshal(bytesconv.S2b(text), field.Addr().Interface())
 	if err != nil {
-		return fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err)
+		return d.fieldError(source, text, fmt.Errorf("无法解码 '%s' 为 %s: %w", text, d.fieldType.Name(), err))
 	}
 
 	return nil
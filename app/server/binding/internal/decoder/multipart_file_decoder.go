@@ -37,7 +37,7 @@ func (d *fileTypeDecoder) Decode(req *protocol.Request, params param.Params, ref
 	}
 	file, err := req.FormFile(fileName)
 	if err != nil {
-		return fmt.Errorf("无法获取文件 '%s'，错误：%v", fileName, err)
+		return d.fieldError(formTag, "", fmt.Errorf("无法获取文件 '%s'，错误：%v", fileName, err))
 	}
 	if field.Kind() == reflect.Ptr {
 		t := field.Type()
@@ -90,16 +90,16 @@ func (d *fileTypeDecoder) fileSliceDecode(req *protocol.Request, _ param.Params,
 	}
 	multipartForm, err := req.MultipartForm()
 	if err != nil {
-		return fmt.Errorf("无法获取多部分表单信息，错误：%v", err)
+		return d.fieldError(formTag, "", fmt.Errorf("无法获取多部分表单信息，错误：%v", err))
 	}
 	files, exists := multipartForm.File[fileName]
 	if !exists {
-		return fmt.Errorf("文件 '%s' 不存在", fileName)
+		return d.fieldError(formTag, "", fmt.Errorf("文件 '%s' 不存在", fileName))
 	}
 
 	if field.Kind() == reflect.Array {
 		if len(files) != field.Len() {
-			return fmt.Errorf("文件 '%s' 的个数(%d) 与 %s 的长度(%d)不匹配", fileName, len(files), field.Type().String(), field.Len())
+			return d.fieldError(formTag, "", fmt.Errorf("文件 '%s' 的个数(%d) 与 %s 的长度(%d)不匹配", fileName, len(files), field.Type().String(), field.Len()))
 		}
 	} else {
 		// 切片需要足够的容量
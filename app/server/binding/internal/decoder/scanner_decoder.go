@@ -0,0 +1,31 @@
+package decoder
+
+import (
+	"database/sql"
+	"reflect"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/route/param"
+)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// 若 *t 实现了 sql.Scanner（如 sql.NullString、sql.NullInt64），则返回按其 Scan 解码的函数，否则返回 nil。
+//
+// 空文本按 Scan(nil) 处理，即 sql.Null* 的 Valid 为 false。
+func scannerDecodeFunc(t reflect.Type) CustomizedDecodeFunc {
+	if t.Kind() == reflect.Ptr || !reflect.PtrTo(t).Implements(scannerType) {
+		return nil
+	}
+	return func(req *protocol.Request, params param.Params, text string) (reflect.Value, error) {
+		v := reflect.New(t)
+		var src any
+		if text != "" {
+			src = text
+		}
+		if err := v.Interface().(sql.Scanner).Scan(src); err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Elem(), nil
+	}
+}
@@ -55,7 +55,7 @@ func headerSlice(req *protocol.Request, _ param.Params, key string, defaultValue
 func cookieSlice(req *protocol.Request, _ param.Params, key string, defaultValue ...string) (ret []string) {
 	req.Header.VisitAllCookie(func(cookieKey, value []byte) {
 		if key == bytesconv.B2s(cookieKey) {
-			ret = append(ret, string(value))
+			ret = append(ret, unescapeCookie(string(value)))
 		}
 	})
 
@@ -76,6 +76,11 @@ func postFormSlice(req *protocol.Request, _ param.Params, key string, defaultVal
 		return
 	}
 
+	// 无体请求不必再解析多部分表单
+	if IsBodyless(req) {
+		return append(ret, defaultValue...)
+	}
+
 	mf, err := req.MultipartForm()
 	if err == nil && mf.Value != nil {
 		for k, v := range mf.Value {
@@ -96,8 +101,9 @@ func postFormSlice(req *protocol.Request, _ param.Params, key string, defaultVal
 }
 
 func rawBodySlice(req *protocol.Request, _ param.Params, key string, _ ...string) (ret []string) {
-	if req.Header.ContentLength() > 0 {
-		ret = append(ret, string(req.Body()))
+	// 分块传输的请求体无 Content-Length，以实际内容判断
+	if body := req.Body(); len(body) > 0 {
+		ret = append(ret, string(body))
 	}
 
 	return
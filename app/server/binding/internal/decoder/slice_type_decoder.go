@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mime/multipart"
 	"reflect"
+	"strings"
 
 	wjson "github.com/favbox/wind/common/json"
 	"github.com/favbox/wind/internal/bytesconv"
@@ -21,6 +22,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 	var texts []string
 	var defaultValue string
 	var bindRawBody bool
+	var source string
 	for _, tagInfo := range d.tagInfos {
 		if tagInfo.Skip || tagInfo.Key == jsonTag || tagInfo.Key == fileNameTag {
 			defaultValue = tagInfo.Default
@@ -29,7 +31,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+					err = d.fieldError(jsonTag, "", errRequired)
 				}
 			}
 			continue
@@ -41,10 +43,11 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 		defaultValue = tagInfo.Default
 		if len(texts) != 0 {
 			err = nil
+			source = tagInfo.Key
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+			err = d.fieldError(tagInfo.Key, "", errRequired)
 		}
 	}
 	if err != nil {
@@ -52,6 +55,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 	}
 	if len(texts) == 0 && len(defaultValue) != 0 {
 		texts = append(texts, defaultValue)
+		source = defaultTag
 	}
 	if len(texts) == 0 {
 		return nil
@@ -74,7 +78,7 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 
 	if d.isArray {
 		if len(texts) != field.Len() {
-			return fmt.Errorf("%q 对于 %s 不是有效的值。", texts, field.Type().String())
+			return d.fieldError(source, strings.Join(texts, ","), fmt.Errorf("%q 对于 %s 不是有效的值。", texts, field.Type().String()))
 		}
 	} else {
 		// 切片需要足够的容量
@@ -106,12 +110,12 @@ func (d *sliceTypeFieldTextDecoder) Decode(req *protocol.Request, params param.P
 	}
 	if err != nil {
 		if !refValue.Field(d.index).CanAddr() {
-			return err
+			return d.fieldError(source, strings.Join(texts, ","), err)
 		}
 		// texts[0] 是可用于 []Type 的完整json内容。
 		err = wjson.Unmarshal(bytesconv.S2b(texts[0]), refValue.Field(d.index).Addr().Interface())
 		if err != nil {
-			return fmt.Errorf("使用 '%s' 解码字段 '%s: %s' 失败，%v", texts[0], d.fieldName, d.fieldType.String(), err)
+			return d.fieldError(source, texts[0], fmt.Errorf("使用 '%s' 解码为 %s 失败，%v", texts[0], d.fieldType.String(), err))
 		}
 	} else {
 		refValue.Field(d.index).Set(ReferenceValue(field, parentPtrDepth))
@@ -130,6 +134,9 @@ func stringToValue(elemType reflect.Type, text string, req *protocol.Request, pa
 			return val, nil
 		}
 	}
+	if scanFunc := scannerDecodeFunc(elemType); scanFunc != nil {
+		return scanFunc(req, params, text)
+	}
 
 	switch elemType.Kind() {
 	case reflect.Struct:
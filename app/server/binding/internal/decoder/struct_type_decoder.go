@@ -1,7 +1,6 @@
 package decoder
 
 import (
-	"fmt"
 	"reflect"
 
 	wjson "github.com/favbox/wind/common/json"
@@ -28,7 +27,7 @@ func (d *structTypeFieldTextDecoder) Decode(req *protocol.Request, params param.
 				if found {
 					err = nil
 				} else {
-					err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+					err = d.fieldError(jsonTag, "", errRequired)
 				}
 			}
 			continue
@@ -40,7 +39,7 @@ func (d *structTypeFieldTextDecoder) Decode(req *protocol.Request, params param.
 			break
 		}
 		if tagInfo.Required {
-			err = fmt.Errorf("'%s' 字段必填，但请求无此参数", d.fieldName)
+			err = d.fieldError(tagInfo.Key, "", errRequired)
 		}
 	}
 	if err != nil {
@@ -16,6 +16,10 @@ const (
 	fileNameTag = "file_name"
 )
 
+const (
+	timeFormatTag = "time_format" // 时间格式标签，覆盖全局的时间格式
+)
+
 const (
 	defaultTag = "default" // 默认值标签
 )
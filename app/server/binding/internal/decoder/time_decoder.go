@@ -0,0 +1,69 @@
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/route/param"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// 不超过该位数的纯数字时间戳按秒解析，否则按毫秒解析。
+const unixSecondsMaxDigits = 10
+
+// TimeDecodeFunc 返回按 layouts 依次尝试解析 time.Time 的解码函数，layouts 为空时使用 RFC 3339。
+//
+// loose 为 true 时，纯数字的文本视为 Unix 时间戳：不超过 10 位按秒，否则按毫秒。空文本解码为零值。
+func TimeDecodeFunc(layouts []string, loose bool) CustomizedDecodeFunc {
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+	return func(req *protocol.Request, params param.Params, text string) (reflect.Value, error) {
+		t, err := parseTime(text, layouts, loose)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(t), nil
+	}
+}
+
+func parseTime(text string, layouts []string, loose bool) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	if loose && isDigits(text) {
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("无法解析时间戳 %q：%w", text, err)
+		}
+		if len(text) <= unixSecondsMaxDigits {
+			return time.Unix(n, 0), nil
+		}
+		return time.UnixMilli(n), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, nil
+		}
+	}
+	msg := fmt.Sprintf("无法解析时间 %q，支持的格式为 %s", text, strings.Join(layouts, "、"))
+	if loose {
+		msg += " 或 Unix 秒/毫秒时间戳"
+	}
+	return time.Time{}, errors.New(msg)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
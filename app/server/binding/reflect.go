@@ -34,3 +34,15 @@ func dereferPointer(rv reflect.Value) reflect.Type {
 	}
 	return rt
 }
+
+// 返回 v 类型的新建零值指针，如 T 或 *T 均返回 *T
+func newStructPointer(v any) any {
+	rt := reflect.TypeOf(v)
+	if rt == nil {
+		return nil
+	}
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return reflect.New(rt).Interface()
+}
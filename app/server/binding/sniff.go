@@ -0,0 +1,62 @@
+package binding
+
+import (
+	"bytes"
+	stdJson "encoding/json"
+	"net/url"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// 不足以确定请求体格式的 Content-Type
+func isAmbiguousContentType(ct string) bool {
+	switch ct {
+	case "", consts.MIMETextPlain, consts.MIMEApplicationOctetStream:
+		return true
+	default:
+		return false
+	}
+}
+
+// 嗅探请求体的格式，返回对应的 Content-Type，无法识别则返回空字符串。
+//
+// 若识别为表单，则将请求体解析到 req.PostArgs()，供表单字段的解码器读取。
+func sniffContentType(req *protocol.Request) string {
+	body := req.Body()
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+
+	if (trimmed[0] == '{' || trimmed[0] == '[') && stdJson.Valid(trimmed) {
+		return consts.MIMEApplicationJSON
+	}
+
+	if looksLikeForm(body) {
+		req.PostArgs().ParseBytes(body)
+		return consts.MIMEApplicationHTMLForm
+	}
+	return ""
+}
+
+// 判断 body 是否为 key=val&... 形式的网址编码表单：
+// 仅含可打印 ASCII 字符，各键值对均含非空的键和 '='，且可被正确解码。
+func looksLikeForm(body []byte) bool {
+	for _, c := range body {
+		if c <= ' ' || c >= 0x7f {
+			return false
+		}
+	}
+	for _, pair := range bytes.Split(body, []byte{'&'}) {
+		if len(pair) == 0 {
+			continue
+		}
+		i := bytes.IndexByte(pair, '=')
+		if i <= 0 {
+			return false
+		}
+	}
+	_, err := url.ParseQuery(string(body))
+	return err == nil
+}
@@ -6,3 +6,10 @@ type StructValidator interface {
 	Engine() any              // 返回底层验证器
 	ValidateTag() string      // 返回验证标签
 }
+
+// LocalizedValidator 表示可按语言渲染错误消息的结构体验证器。
+//
+// langs 通常为请求 Accept-Language 中按优先级排列的语言标签。
+type LocalizedValidator interface {
+	ValidateStructLang(obj any, langs ...string) error
+}
@@ -3,6 +3,7 @@ package binding
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -40,3 +41,193 @@ func TestValidator_ValidateTag(t *testing.T) {
 	assert.NotNil(t, err)
 	fmt.Println(user.Age)
 }
+
+func TestValidator_Enums(t *testing.T) {
+	type Nested struct {
+		Level string `enums:"low, high"`
+	}
+	type Req struct {
+		Type   EnumType  `query:"type" enums:"TWEET,RETWEET"`
+		Code   int       `query:"code" enums:"0,1,2"`
+		Tags   []string  `query:"tag" enums:"a,b"`
+		Kind   *EnumType `query:"kind" enums:"2"`
+		Nested Nested
+		In     int `query:"in" vd:"in($,1,3)"`
+	}
+
+	req := newMockRequest().SetRequestURI("http://foobar.com?type=2&code=1&tag=a&tag=b&kind=2&in=3")
+	var r Req
+	r.Nested.Level = "high"
+	err := DefaultBinder().BindAndValidate(req.Req, &r, nil)
+	assert.Nil(t, err)
+
+	r = Req{Type: 1, In: 1}
+	err = DefaultValidator().ValidateStruct(&r)
+	assert.Equal(t, "Type 的取值 1 无效，允许的取值：TWEET,RETWEET", err.Error())
+
+	r = Req{Code: 5, In: 1}
+	err = DefaultValidator().ValidateStruct(&r)
+	assert.Equal(t, "Code 的取值 5 无效，允许的取值：0,1,2", err.Error())
+
+	r = Req{Tags: []string{"a", "c"}, In: 1}
+	err = DefaultValidator().ValidateStruct(&r)
+	assert.Equal(t, "Tags[1] 的取值 c 无效，允许的取值：a,b", err.Error())
+
+	r = Req{Nested: Nested{Level: "mid"}, In: 1}
+	err = DefaultValidator().ValidateStruct(&r)
+	assert.Equal(t, "Nested.Level 的取值 mid 无效，允许的取值：low, high", err.Error())
+
+	r = Req{In: 2}
+	err = DefaultValidator().ValidateStruct(&r)
+	assert.NotNil(t, err)
+
+	cfg := NewValidateConfig()
+	cfg.SetValidatorErrorFactory(func(fieldSelector, msg string) error {
+		return fmt.Errorf("自定义：%s", fieldSelector)
+	})
+	err = NewValidator(cfg).ValidateStruct(&Req{Code: 5, In: 1})
+	assert.Equal(t, "自定义：Code", err.Error())
+}
+
+func TestValidator_EnumsZeroValue(t *testing.T) {
+	type Req struct {
+		Status   string `query:"status" enums:"on,off"`
+		Priority int    `query:"priority,required" enums:"1,2"`
+	}
+
+	// 未设置的可选字段跳过校验
+	err := DefaultValidator().ValidateStruct(&Req{Priority: 1})
+	assert.Nil(t, err)
+
+	// 声明 required 的字段零值同样校验
+	err = DefaultValidator().ValidateStruct(&Req{Status: "on"})
+	assert.Equal(t, "Priority 的取值 0 无效，允许的取值：1,2", err.Error())
+}
+
+func TestValidator_EnumsOnly(t *testing.T) {
+	type Req struct {
+		Type string `query:"type" enums:"a,b"`
+	}
+
+	// 仅带枚举标签的结构体同样在绑定后校验
+	req := newMockRequest().SetRequestURI("http://foobar.com?type=c")
+	var r Req
+	err := DefaultBinder().BindAndValidate(req.Req, &r, nil)
+	assert.Equal(t, "Type 的取值 c 无效，允许的取值：a,b", err.Error())
+
+	req = newMockRequest().SetRequestURI("http://foobar.com?type=b")
+	err = DefaultBinder().BindAndValidate(req.Req, &r, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", r.Type)
+}
+
+func TestValidator_Localized(t *testing.T) {
+	type Req struct {
+		Name string `query:"name" vd:"len($)>0; msg:'required'"`
+		Age  int    `query:"age" vd:"$<=130"`
+		Type string `query:"type" enums:"a,b"`
+	}
+
+	vc := NewValidateConfig()
+	vc.RegValidateMessage("zh", "Name", "required", "{field} 不能为空")
+	vc.RegValidateMessage("en", "Name", "required", "{field} is required")
+	vc.RegValidateMessage("zh", "Age", "", "年龄无效")
+	vc.RegValidateMessage("zh", "Type", "enums", "类型无效：{msg}")
+	vd := NewValidator(vc)
+	lv := vd.(LocalizedValidator)
+
+	assert.EqualError(t, lv.ValidateStructLang(&Req{}, "zh-cn"), "Name 不能为空")
+	assert.EqualError(t, lv.ValidateStructLang(&Req{}, "fr", "en-us", "zh"), "Name is required")
+	assert.EqualError(t, lv.ValidateStructLang(&Req{}, "fr"), "required")
+	assert.EqualError(t, vd.ValidateStruct(&Req{}), "required")
+	assert.EqualError(t, lv.ValidateStructLang(&Req{Name: "a", Age: 200}, "zh"), "年龄无效")
+	err := lv.ValidateStructLang(&Req{Name: "a", Type: "c"}, "zh")
+	assert.Contains(t, err.Error(), "类型无效：Type 的取值")
+
+	// 经由绑定器按 Accept-Language 渲染，并交由自定义错误工厂
+	vc.SetValidatorErrorFactory(func(field, msg string) error {
+		return fmt.Errorf("%s: %s", field, msg)
+	})
+	bindConfig := NewBindConfig()
+	bindConfig.Validator = NewValidator(vc)
+	req := newMockRequest().
+		SetRequestURI("http://foobar.com?age=1").
+		SetHeader("Accept-Language", "en;q=0.8, zh-CN")
+	err = NewBinder(bindConfig).BindAndValidate(req.Req, &Req{}, nil)
+	assert.EqualError(t, err, "Name: Name 不能为空")
+}
+
+func TestValidator_CrossField(t *testing.T) {
+	type Range struct {
+		Min int     `query:"min"`
+		Max float64 `query:"max" gtefield:"Min"`
+	}
+	type Req struct {
+		StartAt  time.Time  `query:"start_at"`
+		EndAt    *time.Time `query:"end_at" gtfield:"StartAt"`
+		Nick     string     `query:"nick"`
+		Name     string     `query:"name" gtfield:"Nick" ltefield:"Intro"`
+		Intro    string     `query:"intro"`
+		Page     uint       `query:"page"`
+		Offset   int        `query:"offset" ltfield:"Page"`
+		Quantity Range
+	}
+
+	// 未声明验证标签时，经由绑定器也会校验跨字段比较
+	req := newMockRequest().SetRequestURI("http://foobar.com?start_at=2024-05-01T08:00:00Z&end_at=2024-05-01T09:00:00Z" +
+		"&nick=a&name=abc&intro=abcdef&page=2&offset=-1&min=1&max=1")
+	var r Req
+	assert.Nil(t, DefaultBinder().BindAndValidate(req.Req, &r, nil))
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	valid := Req{StartAt: start, Name: "a", Intro: "ab", Page: 1}
+	r = valid
+	r.EndAt = &end
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&r), "EndAt 须大于 StartAt")
+	err := DefaultBinder().BindAndValidate(newMockRequest().SetRequestURI("http://foobar.com?start_at=2024-05-01T08:00:00Z&end_at=2024-05-01T07:00:00Z").Req, &Req{}, nil)
+	assert.EqualError(t, err, "EndAt 须大于 StartAt")
+
+	// 空指针跳过
+	r = valid
+	assert.Nil(t, DefaultValidator().ValidateStruct(&r))
+
+	// 字符串按字符数比较
+	r = valid
+	r.Nick, r.Name = "风雨", "ab"
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&r), "Name 的长度须大于 Nick 的长度")
+	r.Name = "abc"
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&r), "Name 的长度须小于等于 Intro 的长度")
+
+	// 数值跨类型比较
+	r = valid
+	r.Offset = 1
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&r), "Offset 须小于 Page")
+	r = valid
+	r.Quantity = Range{Min: 2, Max: 1.5}
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&r), "Quantity.Max 须大于等于 Quantity.Min")
+
+	// 自定义错误工厂与多语言消息
+	cfg := NewValidateConfig()
+	cfg.RegValidateMessage("zh", "EndAt", gtFieldTag, "结束时间须晚于开始时间")
+	cfg.SetValidatorErrorFactory(func(fieldSelector, msg string) error {
+		return fmt.Errorf("%s: %s", fieldSelector, msg)
+	})
+	r = valid
+	r.EndAt = &end
+	vd := NewValidator(cfg)
+	assert.EqualError(t, vd.ValidateStruct(&r), "EndAt: EndAt 须大于 StartAt")
+	assert.EqualError(t, vd.(LocalizedValidator).ValidateStructLang(&r, "zh-cn"), "EndAt: 结束时间须晚于开始时间")
+
+	// 引用不存在或类型不可比较的字段
+	type BadRef struct {
+		A int `gtfield:"B"`
+	}
+	assert.EqualError(t, DefaultValidator().ValidateStruct(&BadRef{}), `字段 A 的 gtfield 标签引用了不存在的字段 "B"`)
+	type BadType struct {
+		A int
+		B string `gtfield:"A"`
+	}
+	assert.NotNil(t, DefaultValidator().ValidateStruct(&BadType{}))
+	assert.NotNil(t, DefaultValidator().(*validator).Precompile(BadType{}))
+}
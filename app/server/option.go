@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/tls"
 	"net"
+	"os"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/favbox/wind/app/server/binding"
@@ -96,6 +98,15 @@ func WithRedirectFixedPath(b bool) config.Option {
 	}}
 }
 
+// WithCaseInsensitiveRouting 路由匹配忽略路径的 ASCII 大小写，如 /User 与 /user 等价，参数值保留原样。
+// 与 WithRedirectFixedPath 的大小写修正互斥：开启后路径直接匹配，不再触发修正重定向。
+// 默认值：关闭。
+func WithCaseInsensitiveRouting(b bool) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.CaseInsensitiveRouting = b
+	}}
+}
+
 // WithHandleMethodNotAllowed 请求方法不匹配但有同路径其他方法，返回 405 方法不允许而非 404 找不到。
 // 默认值：关闭。
 func WithHandleMethodNotAllowed(b bool) config.Option {
@@ -104,6 +115,24 @@ func WithHandleMethodNotAllowed(b bool) config.Option {
 	}}
 }
 
+// WithAutoHead 为 GET 路由自动响应 HEAD 请求：复用 GET 处理链并丢弃正文。
+// 显式注册的 HEAD 路由优先。
+// 默认值：关闭。
+func WithAutoHead(b bool) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.AutoHead = b
+	}}
+}
+
+// WithHandleOPTIONS 自动响应 OPTIONS 请求：返回 204 并以 Allow 标头列出该路径的可用方法。
+// 显式注册的 OPTIONS 路由优先。
+// 默认值：关闭。
+func WithHandleOPTIONS(b bool) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.HandleOPTIONS = b
+	}}
+}
+
 // WithRemoveExtraSlash 移除额外的空格再进行路由匹配。
 // 如：/user/:name，开启后 /user//mike 也可匹配上参数。
 // 默认值：不使用。
@@ -150,6 +179,36 @@ func WithMaxRequestBodySize(bs int) config.Option {
 	}}
 }
 
+// WithMaxMultipartFieldSize 限制多部分表单单个文本字段的最大字节数，超限时解析返回 errors.ErrMultipartFieldTooLarge。
+func WithMaxMultipartFieldSize(n int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.MaxMultipartFieldSize = n
+	}}
+}
+
+// WithMaxMultipartFileSize 限制多部分表单单个文件的最大字节数，超限时解析返回 errors.ErrMultipartFileTooLarge。
+func WithMaxMultipartFileSize(n int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.MaxMultipartFileSize = n
+	}}
+}
+
+// WithMaxMultipartTotalSize 限制多部分表单的最大总字节数，超限时解析返回 errors.ErrMultipartTotalTooLarge。
+//
+// 该限制独立于 WithMaxRequestBodySize，仅作用于多部分表单的解析。
+func WithMaxMultipartTotalSize(n int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.MaxMultipartTotalSize = n
+	}}
+}
+
+// WithMaxMultipartFiles 限制多部分表单的最大文件个数，超限时解析返回 errors.ErrMultipartTooManyFiles。
+func WithMaxMultipartFiles(n int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.MaxMultipartFiles = n
+	}}
+}
+
 // WithMaxKeepBodySize 限制回收时保留的请求体和响应体的最大字节数。
 //
 // 大于此大小的正文缓冲区将被放回缓冲池。
@@ -206,6 +265,19 @@ func WithExitWaitTime(t time.Duration) config.Option {
 	}}
 }
 
+// WithGracefulShutdown 设置 Spin 优雅退出的触发信号，默认为 SIGINT 和 SIGTERM。
+//
+// 收到其一即调用 Shutdown 优雅退出，等待时间由 WithExitWaitTime 配置，期间定时打印处理中的请求数；
+// 退出过程中再次收到信号则立即强制退出。
+func WithGracefulShutdown(signals ...os.Signal) config.Option {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	return config.Option{F: func(o *config.Options) {
+		o.GracefulShutdownSignals = signals
+	}}
+}
+
 // WithTLS 配置为 TLS 服务器。
 func WithTLS(cfg *tls.Config) config.Option {
 	return config.Option{F: func(o *config.Options) {
@@ -253,6 +325,30 @@ func WithReadBufferSize(size int) config.Option {
 	}}
 }
 
+// WithWriteBufferSize 设置写缓冲区字节数。
+// 大响应场景调大可减少系统调用，小响应高并发场景调小可节省内存。默认值：4KB。
+func WithWriteBufferSize(size int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.WriteBufferSize = size
+	}}
+}
+
+// WithSocketReadBufferSize 设置内核套接字接收缓冲（SO_RCVBUF）字节数。
+// 与 WithReadBufferSize 设置的用户态读缓冲相互独立。默认值：0，沿用系统设置。
+func WithSocketReadBufferSize(size int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.SocketReadBufferSize = size
+	}}
+}
+
+// WithSocketWriteBufferSize 设置内核套接字发送缓冲（SO_SNDBUF）字节数。
+// 与 WithWriteBufferSize 设置的用户态写缓冲相互独立。默认值：0，沿用系统设置。
+func WithSocketWriteBufferSize(size int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.SocketWriteBufferSize = size
+	}}
+}
+
 // WithALPN 设置是否开启 ALPN。默认值：false，关闭。
 func WithALPN(enable bool) config.Option {
 	return config.Option{F: func(o *config.Options) {
@@ -321,6 +417,54 @@ func WithOnConnect(fn func(ctx context.Context, conn network.Conn) context.Conte
 	}}
 }
 
+// WithSlowRequestThreshold 设置慢请求的耗时阈值，处理耗时超过该值时触发引擎的 OnSlowRequest 回调。
+func WithSlowRequestThreshold(threshold time.Duration) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.SlowRequestThreshold = threshold
+	}}
+}
+
+// WithDefaultResponseHeaders 设置每个响应统一注入的默认标头，处理器可设置同名标头覆盖。
+func WithDefaultResponseHeaders(headers map[string]string) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.DefaultResponseHeaders = headers
+	}}
+}
+
+// WithAutoChunkThreshold 设置响应缓冲体的自动分块阈值，缓冲体超过该值时切换为 chunked 流式发送。
+//
+// 切换后已发送的正文不可再修改，依赖完整正文的中间件（如压缩、ETag）将无法获取正文。
+func WithAutoChunkThreshold(threshold int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.AutoChunkThreshold = threshold
+	}}
+}
+
+// WithRawTrafficMaxSize 设置引擎 OnRawRequest、OnRawResponse 每次记录的最大字节数，超出部分截断。
+//
+// 默认值：0，即不限。
+func WithRawTrafficMaxSize(size int) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.RawTrafficMaxSize = size
+	}}
+}
+
+// WithRawTrafficSampleRate 设置原始字节的采样率，取值 (0, 1)，仅被采样的请求会触发 OnRawRequest、OnRawResponse。
+//
+// 默认值：0，即全部记录。
+func WithRawTrafficSampleRate(rate float64) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.RawTrafficSampleRate = rate
+	}}
+}
+
+// WithConnWrapper 添加连接的包装函数，多个包装按顺序叠加，先添加的在内层。
+func WithConnWrapper(wrappers ...network.ConnWrapper) config.Option {
+	return config.Option{F: func(o *config.Options) {
+		o.ConnWrappers = append(o.ConnWrappers, wrappers...)
+	}}
+}
+
 // WithDisableHeaderNamesNormalizing 设置是否禁用标头名称规范化。
 func WithDisableHeaderNamesNormalizing(disable bool) config.Option {
 	return config.Option{F: func(o *config.Options) {
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"os"
+	"syscall"
 	"testing"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/favbox/wind/common/config"
 	"github.com/favbox/wind/common/tracer/stats"
 	"github.com/favbox/wind/common/utils"
+	"github.com/favbox/wind/network"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,6 +27,9 @@ func TestOptions(t *testing.T) {
 		WithKeepAliveTimeout(time.Second),
 		WithRedirectTrailingSlash(false),
 		WithRedirectFixedPath(true),
+		WithCaseInsensitiveRouting(true),
+		WithRawTrafficMaxSize(4096),
+		WithRawTrafficSampleRate(0.1),
 		WithHandleMethodNotAllowed(true),
 		WithUseRawPath(true),
 		WithRemoveExtraSlash(true),
@@ -36,17 +42,28 @@ func TestOptions(t *testing.T) {
 		WithDisablePrintRoute(true),
 		WithNetwork("unix"),
 		WithExitWaitTime(time.Second),
+		WithGracefulShutdown(syscall.SIGHUP),
 		WithMaxKeepBodySize(500),
+		WithMaxMultipartFieldSize(1),
+		WithAutoHead(true),
+		WithHandleOPTIONS(true),
+		WithMaxMultipartFileSize(2),
+		WithMaxMultipartTotalSize(3),
+		WithMaxMultipartFiles(4),
 		WithGetOnly(true),
 		WithKeepAlive(false),
 		WithTLS(nil),
 		WithH2C(true),
 		WithReadBufferSize(100),
+		WithWriteBufferSize(200),
+		WithSocketReadBufferSize(300),
+		WithSocketWriteBufferSize(400),
 		WithALPN(true),
 		WithTraceLevel(stats.LevelDisabled),
 		WithRegistry(nil, info),
 		WithAutoReloadRender(true, 5*time.Second),
 		WithDisableHeaderNamesNormalizing(true),
+		WithConnWrapper(func(conn network.Conn) network.Conn { return conn }),
 	})
 
 	assert.Equal(t, opt.ReadTimeout, time.Second)
@@ -55,6 +72,9 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, opt.KeepAliveTimeout, time.Second)
 	assert.Equal(t, opt.RedirectTrailingSlash, false)
 	assert.Equal(t, opt.RedirectFixedPath, true)
+	assert.Equal(t, opt.CaseInsensitiveRouting, true)
+	assert.Equal(t, opt.RawTrafficMaxSize, 4096)
+	assert.Equal(t, opt.RawTrafficSampleRate, 0.1)
 	assert.Equal(t, opt.HandleMethodNotAllowed, true)
 	assert.Equal(t, opt.UseRawPath, true)
 	assert.Equal(t, opt.RemoveExtraSlash, true)
@@ -67,11 +87,21 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, opt.DisablePrintRoute, true)
 	assert.Equal(t, opt.Network, "unix")
 	assert.Equal(t, opt.ExitWaitTimeout, time.Second)
+	assert.Equal(t, []os.Signal{syscall.SIGHUP}, opt.GracefulShutdownSignals)
+	assert.Equal(t, 1, opt.MaxMultipartFieldSize)
+	assert.True(t, opt.AutoHead)
+	assert.True(t, opt.HandleOPTIONS)
+	assert.Equal(t, 2, opt.MaxMultipartFileSize)
+	assert.Equal(t, 3, opt.MaxMultipartTotalSize)
+	assert.Equal(t, 4, opt.MaxMultipartFiles)
 	assert.Equal(t, opt.MaxKeepBodySize, 500)
 	assert.Equal(t, opt.GetOnly, true)
 	assert.Equal(t, opt.DisableKeepalive, true)
 	assert.Equal(t, opt.H2C, true)
 	assert.Equal(t, opt.ReadBufferSize, 100)
+	assert.Equal(t, opt.WriteBufferSize, 200)
+	assert.Equal(t, opt.SocketReadBufferSize, 300)
+	assert.Equal(t, opt.SocketWriteBufferSize, 400)
 	assert.Equal(t, opt.ALPN, true)
 	assert.Equal(t, opt.TraceLevel, stats.LevelDisabled)
 	assert.Equal(t, opt.RegistryInfo, info)
@@ -79,6 +109,7 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, opt.AutoReloadRender, true)
 	assert.Equal(t, opt.AutoReloadInterval, 5*time.Second)
 	assert.True(t, opt.DisableHeaderNamesNormalizing)
+	assert.Len(t, opt.ConnWrappers, 1)
 }
 
 func TestDefaultOptions(t *testing.T) {
@@ -88,6 +119,7 @@ func TestDefaultOptions(t *testing.T) {
 	assert.Equal(t, opt.KeepAliveTimeout, time.Minute)
 	assert.Equal(t, opt.RedirectTrailingSlash, true)
 	assert.Equal(t, opt.RedirectFixedPath, false)
+	assert.Equal(t, opt.CaseInsensitiveRouting, false)
 	assert.Equal(t, opt.HandleMethodNotAllowed, false)
 	assert.Equal(t, opt.UseRawPath, false)
 	assert.Equal(t, opt.RemoveExtraSlash, false)
@@ -102,9 +134,11 @@ func TestDefaultOptions(t *testing.T) {
 	assert.Equal(t, opt.DisablePrintRoute, false)
 	assert.Equal(t, opt.Network, "tcp")
 	assert.Equal(t, opt.ExitWaitTimeout, time.Second*5)
+	assert.Nil(t, opt.GracefulShutdownSignals)
 	assert.Equal(t, opt.MaxKeepBodySize, 4*1024*1024)
 	assert.Equal(t, opt.H2C, false)
 	assert.Equal(t, opt.ReadBufferSize, 4096)
+	assert.Equal(t, opt.WriteBufferSize, 4096)
 	assert.Equal(t, opt.ALPN, false)
 	assert.Equal(t, opt.Registry, registry.NoopRegistry)
 	assert.Equal(t, opt.AutoReloadRender, false)
@@ -113,3 +147,8 @@ func TestDefaultOptions(t *testing.T) {
 	assert.Equal(t, opt.AutoReloadInterval, time.Duration(0))
 	assert.False(t, opt.DisableHeaderNamesNormalizing)
 }
+
+func TestWithGracefulShutdownDefaultSignals(t *testing.T) {
+	opt := config.NewOptions([]config.Option{WithGracefulShutdown()})
+	assert.Equal(t, []os.Signal{syscall.SIGINT, syscall.SIGTERM}, opt.GracefulShutdownSignals)
+}
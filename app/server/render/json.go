@@ -3,6 +3,8 @@ package render
 import (
 	"bytes"
 	"encoding/json"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	hjson "github.com/favbox/wind/common/json"
 	"github.com/favbox/wind/protocol"
@@ -17,7 +19,10 @@ var (
 type JSONMarshaler func(v any) ([]byte, error)
 
 func init() {
-	ResetJSONMarshal(hjson.Marshal)
+	// 默认跟随 common/json 的全局实现，以便 json.SetImplementation 对渲染同样生效
+	ResetJSONMarshal(func(v any) ([]byte, error) {
+		return hjson.Marshal(v)
+	})
 }
 
 // ResetStdJSONMarshal 重置 JSON 编码函数为标准库实现。
@@ -58,7 +63,7 @@ type PureJSON struct {
 func (r PureJSON) Render(resp *protocol.Response) error {
 	writeContentType(resp, jsonContentType)
 	buf := new(bytes.Buffer)
-	encoder := json.NewEncoder(buf)
+	encoder := hjson.NewEncoder(buf)
 	encoder.SetEscapeHTML(false)
 	err := encoder.Encode(r.Data)
 	if err != nil {
@@ -95,3 +100,129 @@ func (r IndentedJSON) Render(resp *protocol.Response) error {
 func (r IndentedJSON) WriteContentType(resp *protocol.Response) {
 	writeContentType(resp, jsonContentType)
 }
+
+// AsciiJSON 表示纯 ASCII 的 JSON 渲染器，非 ASCII 字符以 \uXXXX 形式转义，并转义 html 字符。
+type AsciiJSON struct {
+	Data any
+}
+
+func (r AsciiJSON) Render(resp *protocol.Response) error {
+	writeContentType(resp, jsonContentType)
+	jsonBytes, err := jsonMarshalFunc(r.Data)
+	if err != nil {
+		return err
+	}
+	resp.AppendBody(appendASCIIJSON(nil, jsonBytes))
+	return nil
+}
+
+func (r AsciiJSON) WriteContentType(resp *protocol.Response) {
+	writeContentType(resp, jsonContentType)
+}
+
+// 将 src 中的非 ASCII 字符及 <、>、& 以 \uXXXX 形式追加到 dst，超出 BMP 的字符转为代理对。
+//
+// 合法 JSON 中这些字符只会出现在字符串内，故可逐字节处理。
+func appendASCIIJSON(dst, src []byte) []byte {
+	const hex = "0123456789abcdef"
+	appendU := func(dst []byte, r rune) []byte {
+		return append(dst, '\\', 'u', hex[r>>12&0xf], hex[r>>8&0xf], hex[r>>4&0xf], hex[r&0xf])
+	}
+	for i := 0; i < len(src); {
+		c := src[i]
+		if c < utf8.RuneSelf {
+			if c == '<' || c == '>' || c == '&' {
+				dst = appendU(dst, rune(c))
+			} else {
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i:])
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			dst = appendU(appendU(dst, r1), r2)
+		} else {
+			dst = appendU(dst, r)
+		}
+		i += size
+	}
+	return dst
+}
+
+// DefaultSecureJSONPrefix 是 SecureJSON 的默认前缀。
+const DefaultSecureJSONPrefix = "while(1);"
+
+// SecureJSON 表示安全 JSON 渲染器，顶层为数组时在输出前写入 Prefix，以防 JSON 劫持。
+type SecureJSON struct {
+	Prefix string
+	Data   any
+}
+
+func (r SecureJSON) Render(resp *protocol.Response) error {
+	writeContentType(resp, jsonContentType)
+	jsonBytes, err := jsonMarshalFunc(r.Data)
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimLeft(jsonBytes, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		resp.AppendBodyString(r.Prefix)
+	}
+	resp.AppendBody(jsonBytes)
+	return nil
+}
+
+func (r SecureJSON) WriteContentType(resp *protocol.Response) {
+	writeContentType(resp, jsonContentType)
+}
+
+var jsonpContentType = "application/javascript; charset=utf-8"
+
+// JsonpJSON 表示 JSONP 渲染器，以 Callback(<json>); 的形式输出。
+//
+// Callback 为空或含有白名单外的字符时退化为普通 JSON 输出。
+type JsonpJSON struct {
+	Callback string
+	Data     any
+}
+
+func (r JsonpJSON) Render(resp *protocol.Response) error {
+	if !validCallback(r.Callback) {
+		return JSONRender{Data: r.Data}.Render(resp)
+	}
+	writeContentType(resp, jsonpContentType)
+	jsonBytes, err := jsonMarshalFunc(r.Data)
+	if err != nil {
+		return err
+	}
+	resp.AppendBodyString(r.Callback)
+	resp.AppendBodyString("(")
+	resp.AppendBody(jsonBytes)
+	resp.AppendBodyString(");")
+	return nil
+}
+
+func (r JsonpJSON) WriteContentType(resp *protocol.Response) {
+	if !validCallback(r.Callback) {
+		writeContentType(resp, jsonContentType)
+		return
+	}
+	writeContentType(resp, jsonpContentType)
+}
+
+// 校验回调名仅含字母、数字、_、$、. 及方括号，以防脚本注入。
+func validCallback(callback string) bool {
+	if callback == "" {
+		return false
+	}
+	for i := 0; i < len(callback); i++ {
+		c := callback[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '_', c == '$', c == '.', c == '[', c == ']':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,8 @@ package render
 import (
 	"testing"
 
+	"github.com/favbox/wind/protocol"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +36,40 @@ func TestDefaultJSONMarshal(t *testing.T) {
 	assert.Contains(t, string(jsonBytes), `"testA":"hello"`)
 	assert.Contains(t, string(jsonBytes), `"B":"world"`)
 }
+
+func TestRenderJsonpJSON(t *testing.T) {
+	resp := &protocol.Response{}
+	assert.Nil(t, JsonpJSON{Callback: "app.cb[0]", Data: map[string]int{"a": 1}}.Render(resp))
+	assert.Equal(t, jsonpContentType, string(resp.Header.ContentType()))
+	assert.Equal(t, `app.cb[0]({"a":1});`, string(resp.Body()))
+
+	// 非法回调名退化为普通 JSON
+	for _, cb := range []string{"", "alert(1)//", "a b", "x;y"} {
+		resp = &protocol.Response{}
+		assert.Nil(t, JsonpJSON{Callback: cb, Data: 1}.Render(resp))
+		assert.Equal(t, jsonContentType, string(resp.Header.ContentType()), cb)
+		assert.Equal(t, "1", string(resp.Body()), cb)
+	}
+}
+
+func TestRenderSecureJSON(t *testing.T) {
+	resp := &protocol.Response{}
+	assert.Nil(t, SecureJSON{Prefix: DefaultSecureJSONPrefix, Data: []int{1, 2}}.Render(resp))
+	assert.Equal(t, jsonContentType, string(resp.Header.ContentType()))
+	assert.Equal(t, "while(1);[1,2]", string(resp.Body()))
+
+	// 对象不加前缀
+	resp = &protocol.Response{}
+	assert.Nil(t, SecureJSON{Prefix: DefaultSecureJSONPrefix, Data: map[string]int{"a": 1}}.Render(resp))
+	assert.Equal(t, `{"a":1}`, string(resp.Body()))
+}
+
+func TestRenderAsciiJSON(t *testing.T) {
+	resp := &protocol.Response{}
+	assert.Nil(t, AsciiJSON{Data: map[string]string{"k": "风<b>&😀"}}.Render(resp))
+	assert.Equal(t, jsonContentType, string(resp.Header.ContentType()))
+	assert.Equal(t, `{"k":"\u98ce\u003cb\u003e\u0026\ud83d\ude00"}`, string(resp.Body()))
+
+	// 非法 UTF-8 转为替换字符
+	assert.Equal(t, `"\ufffd"`, string(appendASCIIJSON(nil, []byte{'"', 0xff, '"'})))
+}
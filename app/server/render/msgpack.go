@@ -0,0 +1,43 @@
+package render
+
+import (
+	"github.com/favbox/wind/common/msgpack"
+	"github.com/favbox/wind/protocol"
+)
+
+var (
+	msgPackContentType = "application/msgpack"
+	msgPackMarshalFunc MsgPackMarshaler
+)
+
+// MsgPackMarshaler 自定义 msgpack.Marshal。
+type MsgPackMarshaler func(v any) ([]byte, error)
+
+func init() {
+	ResetMsgPackMarshal(msgpack.Marshal)
+}
+
+// ResetMsgPackMarshal 重置 MessagePack 编码函数为给定的 fn，默认使用 common/msgpack 的轻量实现。
+func ResetMsgPackMarshal(fn MsgPackMarshaler) {
+	msgPackMarshalFunc = fn
+}
+
+// MsgPack 包含要渲染的 MessagePack 数据。
+type MsgPack struct {
+	Data any
+}
+
+func (r MsgPack) Render(resp *protocol.Response) error {
+	writeContentType(resp, msgPackContentType)
+	msgPackBytes, err := msgPackMarshalFunc(r.Data)
+	if err != nil {
+		return err
+	}
+
+	resp.AppendBody(msgPackBytes)
+	return nil
+}
+
+func (r MsgPack) WriteContentType(resp *protocol.Response) {
+	writeContentType(resp, msgPackContentType)
+}
@@ -15,6 +15,9 @@ var (
 	_ Render = Data{}
 	_ Render = String{}
 	_ Render = JSONRender{}
+	_ Render = JsonpJSON{}
+	_ Render = YAML{}
+	_ Render = MsgPack{}
 )
 
 // 设置响应的内容类型。
@@ -8,6 +8,7 @@ import (
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/protocol/consts"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 type xmlmap map[string]interface{}
@@ -147,3 +148,43 @@ func TestRenderIndentedJSON(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 }
+
+func TestRenderYAML(t *testing.T) {
+	type item struct {
+		Name string `yaml:"name"`
+	}
+	type config struct {
+		Title string `yaml:"title"`
+		Item  item   `yaml:"item"`
+	}
+	data := config{Title: "wind", Item: item{Name: "yaml"}}
+
+	// 未设置编码函数
+	ResetYAMLMarshal(nil)
+	resp := &protocol.Response{}
+	assert.NotNil(t, (YAML{data}).Render(resp))
+
+	ResetYAMLMarshal(yaml.Marshal)
+	defer ResetYAMLMarshal(nil)
+	resp = &protocol.Response{}
+	(YAML{data}).WriteContentType(resp)
+	assert.Equal(t, []byte("application/yaml; charset=utf-8"), resp.Header.Peek("Content-Type"))
+
+	err := (YAML{data}).Render(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, "title: wind\nitem:\n    name: yaml\n", string(resp.Body()))
+}
+
+func TestRenderMsgPack(t *testing.T) {
+	resp := &protocol.Response{}
+	data := map[string]any{"foo": "bar"}
+
+	(MsgPack{data}).WriteContentType(resp)
+	assert.Equal(t, []byte("application/msgpack"), resp.Header.Peek("Content-Type"))
+
+	err := (MsgPack{data}).Render(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x81, 0xa3, 'f', 'o', 'o', 0xa3, 'b', 'a', 'r'}, resp.Body())
+
+	assert.NotNil(t, (MsgPack{make(chan int)}).Render(&protocol.Response{}))
+}
@@ -15,12 +15,12 @@ type XML struct {
 
 func (r XML) Render(resp *protocol.Response) error {
 	writeContentType(resp, xmlContentType)
-	xmlBytess, err := xml.Marshal(r.Data)
+	xmlBytes, err := xml.Marshal(r.Data)
 	if err != nil {
 		return err
 	}
 
-	resp.AppendBody(xmlBytess)
+	resp.AppendBody(xmlBytes)
 	return nil
 }
 
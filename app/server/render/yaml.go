@@ -0,0 +1,47 @@
+package render
+
+import (
+	"errors"
+
+	"github.com/favbox/wind/protocol"
+)
+
+var (
+	yamlContentType = "application/yaml; charset=utf-8"
+	yamlMarshalFunc YAMLMarshaler
+)
+
+var errYAMLMarshalerNotSet = errors.New("未设置 YAML 编码函数，请先调用 render.ResetYAMLMarshal")
+
+// YAMLMarshaler 自定义 yaml.Marshal。
+type YAMLMarshaler func(v any) ([]byte, error)
+
+// ResetYAMLMarshal 重置 YAML 编码函数为给定的 fn，如 gopkg.in/yaml.v3 的 yaml.Marshal。
+//
+// 框架本身不依赖 YAML 库，渲染 YAML 前须先设置。
+func ResetYAMLMarshal(fn YAMLMarshaler) {
+	yamlMarshalFunc = fn
+}
+
+// YAML 包含要渲染的 YAML 数据。
+type YAML struct {
+	Data any
+}
+
+func (r YAML) Render(resp *protocol.Response) error {
+	writeContentType(resp, yamlContentType)
+	if yamlMarshalFunc == nil {
+		return errYAMLMarshalerNotSet
+	}
+	yamlBytes, err := yamlMarshalFunc(r.Data)
+	if err != nil {
+		return err
+	}
+
+	resp.AppendBody(yamlBytes)
+	return nil
+}
+
+func (r YAML) WriteContentType(resp *protocol.Response) {
+	writeContentType(resp, yamlContentType)
+}
@@ -49,6 +49,11 @@ func (w *Wind) Spin() {
 		errCh <- w.Run()
 	}()
 
+	if sigs := w.GetOptions().GracefulShutdownSignals; w.signalWaiter == nil && len(sigs) > 0 {
+		w.waitGracefulShutdown(errCh, sigs)
+		return
+	}
+
 	signalWaiter := defaultSignalWaiter
 	if w.signalWaiter != nil {
 		signalWaiter = w.signalWaiter
@@ -72,6 +77,58 @@ func (w *Wind) Spin() {
 	}
 }
 
+// 等待 sigs 中的信号并优雅退出，退出期间每秒打印处理中的请求数，再次收到信号则强制退出。
+func (w *Wind) waitGracefulShutdown(errCh chan error, sigs []os.Signal) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		wlog.SystemLogger().Infof("收到退出信号：%s", sig)
+	case err := <-errCh:
+		wlog.SystemLogger().Errorf("收到退出信号：错误=%v", err)
+		if err = w.Engine.Close(); err != nil {
+			wlog.SystemLogger().Errorf("退出错误：%v", err)
+		}
+		return
+	}
+
+	timeout := w.GetOptions().ExitWaitTimeout
+	wlog.SystemLogger().Infof("开始优雅退出，最多等待 %d 秒，再次发送信号可强制退出...", timeout/time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Shutdown(ctx)
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				wlog.SystemLogger().Errorf("退出错误：%v", err)
+				return
+			}
+			wlog.SystemLogger().Infof("优雅退出完成")
+			return
+		case <-ticker.C:
+			wlog.SystemLogger().Infof("正在优雅退出，处理中的请求数：%d", w.InFlightRequests())
+		case sig := <-signals:
+			wlog.SystemLogger().Infof("再次收到退出信号：%s，强制退出", sig)
+			cancel()
+			if err := w.Engine.Close(); err != nil {
+				wlog.SystemLogger().Errorf("退出错误：%v", err)
+			}
+			return
+		}
+	}
+}
+
 // SetCustomSignalWaiter 设置自定义的信号等待者。
 // 若默认的信号等待实现不符要求，则可以自定义。
 // Wind 在 f 返回错误后会立即退出，否则它将优雅退出。
@@ -0,0 +1,74 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package server
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGracefulShutdownSignal(t *testing.T) {
+	w := New(WithHostPorts("127.0.0.1:9241"), WithGracefulShutdown(syscall.SIGUSR2))
+	w.GET("/ping", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "pong")
+	})
+	var shutdown bool
+	w.OnShutdown = append(w.OnShutdown, func(ctx context.Context) {
+		shutdown = true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		w.Spin()
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Spin 未在收到信号后退出")
+	}
+	assert.True(t, shutdown)
+}
+
+func TestGracefulShutdownForce(t *testing.T) {
+	w := New(WithHostPorts("127.0.0.1:9242"), WithGracefulShutdown(syscall.SIGUSR2), WithExitWaitTime(10*time.Second))
+	block := make(chan struct{})
+	defer close(block)
+	w.OnShutdown = append(w.OnShutdown, func(ctx context.Context) {
+		select {
+		case <-block:
+		case <-ctx.Done():
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		w.Spin()
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("关闭钩子阻塞时不应退出")
+	default:
+	}
+
+	assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("二次信号未强制退出")
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/favbox/wind/protocol/consts"
+)
+
+// AddServerTiming 追加一项 Server-Timing 计时，格式如 `db;dur=12.3;desc="查询用户"`。
+//
+// 多次调用的计时项以逗号合并至同一个 Server-Timing 标头，desc 为空则省略。
+// 须在响应写出前调用，中间件可在各阶段分别调用。
+func (ctx *RequestContext) AddServerTiming(name string, dur time.Duration, desc string) {
+	var sb strings.Builder
+	if prev := ctx.Response.Header.Peek(consts.HeaderServerTiming); len(prev) > 0 {
+		sb.Write(prev)
+		sb.WriteString(", ")
+	}
+	sb.WriteString(name)
+	sb.WriteString(";dur=")
+	sb.WriteString(strconv.FormatFloat(float64(dur.Microseconds())/1000, 'f', -1, 64))
+	if desc != "" {
+		sb.WriteString(`;desc="`)
+		sb.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(desc))
+		sb.WriteByte('"')
+	}
+	ctx.Response.Header.Set(consts.HeaderServerTiming, sb.String())
+}
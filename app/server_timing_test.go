@@ -0,0 +1,20 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddServerTiming(t *testing.T) {
+	ctx := NewContext(0)
+	ctx.AddServerTiming("db", 12300*time.Microsecond, "查询用户")
+	assert.Equal(t, `db;dur=12.3;desc="查询用户"`, string(ctx.Response.Header.Peek(consts.HeaderServerTiming)))
+
+	ctx.AddServerTiming("cache", 2*time.Millisecond, "")
+	ctx.AddServerTiming("app", 500*time.Microsecond, `say "hi"`)
+	assert.Equal(t, `db;dur=12.3;desc="查询用户", cache;dur=2, app;dur=0.5;desc="say \"hi\""`,
+		string(ctx.Response.Header.Peek(consts.HeaderServerTiming)))
+}
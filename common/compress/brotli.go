@@ -0,0 +1,181 @@
+package compress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/favbox/wind/common/bytebufferpool"
+	"github.com/favbox/wind/common/stackless"
+	"github.com/favbox/wind/common/utils"
+	"github.com/favbox/wind/network"
+)
+
+// Brotli 支持的压缩级别。
+const (
+	CompressBrotliNoCompression   = 0
+	CompressBrotliBestSpeed       = brotli.BestSpeed
+	CompressBrotliBestCompression = brotli.BestCompression
+
+	// CompressBrotliDefaultCompression 为默认压缩级别，
+	// 选择 4 而非 brotli.DefaultCompression（6），以兼顾压缩率与即时压缩的 CPU 开销。
+	CompressBrotliDefaultCompression = 4
+)
+
+var brotliReaderPool sync.Pool
+
+var (
+	stacklessBrotliWriterPoolMap = newCompressWriterPoolMap()
+	realBrotliWriterPoolMap      = newCompressWriterPoolMap()
+)
+
+// AppendBrotliBytes 以默认级别压缩 src 并附加到 dst，然后返回。
+func AppendBrotliBytes(dst, src []byte) []byte {
+	return AppendBrotliBytesLevel(dst, src, CompressBrotliDefaultCompression)
+}
+
+// AppendBrotliBytesLevel 附加压缩后的 src 到 dst 并返回（使用指定的压缩级别）。
+//
+// 支持的压缩级别为：
+//
+//   - CompressBrotliNoCompression
+//   - CompressBrotliBestSpeed
+//   - CompressBrotliBestCompression
+//   - CompressBrotliDefaultCompression
+func AppendBrotliBytesLevel(dst, src []byte, level int) []byte {
+	w := &byteSliceWriter{dst}
+	_, _ = WriteBrotliLevel(w, src, level)
+	return w.b
+}
+
+// WriteBrotliLevel 压缩 p 并写入 w（使用指定压缩级别），返回写入 w 的压缩量。
+//
+// 支持的压缩级别同 AppendBrotliBytesLevel。
+func WriteBrotliLevel(w io.Writer, p []byte, level int) (int, error) {
+	switch w.(type) {
+	case *byteSliceWriter,
+		*bytes.Buffer,
+		*bytebufferpool.ByteBuffer:
+		// 这些写入器不会阻塞，故可使用无栈压缩
+		ctx := &compressCtx{
+			w:     w,
+			p:     p,
+			level: level,
+		}
+		stacklessWriteBrotli(ctx)
+		return len(p), nil
+	default:
+		zw := AcquireStacklessBrotliWriter(w, level)
+		n, err := zw.Write(p)
+		ReleaseStacklessBrotliWriter(zw, level)
+		return n, err
+	}
+}
+
+// AppendUnbrotliBytes 解压 src 到 dst 并返回。
+func AppendUnbrotliBytes(dst, src []byte) ([]byte, error) {
+	w := &byteSliceWriter{dst}
+	_, err := WriteUnbrotli(w, src)
+	return w.b, err
+}
+
+// WriteUnbrotli 解压 p 并写入 w，返回写入的解压字节数。
+func WriteUnbrotli(w io.Writer, p []byte) (int, error) {
+	zr := AcquireBrotliReader(&byteSliceReader{p})
+	zw := network.NewWriter(w)
+	n, err := utils.CopyZeroAlloc(zw, zr)
+	ReleaseBrotliReader(zr)
+	nn := int(n)
+	if int64(nn) != n {
+		return 0, fmt.Errorf("待解压数据过大: %d", n)
+	}
+	return nn, err
+}
+
+// AcquireStacklessBrotliWriter 获取 io.Writer 的无堆栈 Brotli 压缩写入器。
+//
+// 用完记得调用 ReleaseStacklessBrotliWriter 释放，以降低 GC，提高性能。
+func AcquireStacklessBrotliWriter(w io.Writer, level int) stackless.Writer {
+	nLevel := normalizeBrotliCompressLevel(level)
+	p := stacklessBrotliWriterPoolMap[nLevel]
+	v := p.Get()
+	if v == nil {
+		return stackless.NewWriter(w, func(w io.Writer) stackless.Writer {
+			return acquireRealBrotliWriter(w, level)
+		})
+	}
+	sw := v.(stackless.Writer)
+	sw.Reset(w)
+	return sw
+}
+
+// ReleaseStacklessBrotliWriter 释放无堆栈 Brotli 压缩写入器到指定级别池。
+func ReleaseStacklessBrotliWriter(sw stackless.Writer, level int) {
+	_ = sw.Close()
+	nLevel := normalizeBrotliCompressLevel(level)
+	p := stacklessBrotliWriterPoolMap[nLevel]
+	p.Put(sw)
+}
+
+// AcquireBrotliReader 获取 r 的 Brotli 读取器。
+//
+// 记得用完调用 ReleaseBrotliReader 释放并放回池中以减少内存开销。
+func AcquireBrotliReader(r io.Reader) *brotli.Reader {
+	v := brotliReaderPool.Get()
+	if v == nil {
+		return brotli.NewReader(r)
+	}
+	zr := v.(*brotli.Reader)
+	_ = zr.Reset(r)
+	return zr
+}
+
+// ReleaseBrotliReader 将不用的 zr 放回池中，以减少内存开销。
+func ReleaseBrotliReader(zr *brotli.Reader) {
+	brotliReaderPool.Put(zr)
+}
+
+// 标准化 Brotli 压缩级别为 [0..11]，以用作 *PoolMap 的索引。
+func normalizeBrotliCompressLevel(level int) int {
+	if level < CompressBrotliNoCompression || level > CompressBrotliBestCompression {
+		level = CompressBrotliDefaultCompression
+	}
+	return level
+}
+
+var stacklessWriteBrotli = stackless.NewFunc(nonblockingWriteBrotli)
+
+func nonblockingWriteBrotli(ctxv any) {
+	ctx := ctxv.(*compressCtx)
+	zw := acquireRealBrotliWriter(ctx.w, ctx.level)
+
+	_, err := zw.Write(ctx.p)
+	if err != nil {
+		panic(fmt.Sprintf("BUG: brotli.Writer.Write for len(p)=%d returned unexpected error: %s", len(ctx.p), err))
+	}
+
+	releaseRealBrotliWriter(zw, ctx.level)
+}
+
+func releaseRealBrotliWriter(zw *brotli.Writer, level int) {
+	_ = zw.Close()
+	// 解除对目标写入器的引用，避免池中的写入器持有已释放的缓冲区或连接
+	zw.Reset(nil)
+	nLevel := normalizeBrotliCompressLevel(level)
+	p := realBrotliWriterPoolMap[nLevel]
+	p.Put(zw)
+}
+
+func acquireRealBrotliWriter(w io.Writer, level int) *brotli.Writer {
+	nLevel := normalizeBrotliCompressLevel(level)
+	p := realBrotliWriterPoolMap[nLevel]
+	v := p.Get()
+	if v == nil {
+		return brotli.NewWriterLevel(w, nLevel)
+	}
+	zw := v.(*brotli.Writer)
+	zw.Reset(w)
+	return zw
+}
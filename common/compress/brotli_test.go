@@ -0,0 +1,51 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressAppendBrotliBytesLevel(t *testing.T) {
+	src := bytes.Repeat([]byte("hello, wind! "), 100)
+	for _, level := range []int{CompressBrotliNoCompression, CompressBrotliBestSpeed, CompressBrotliDefaultCompression, CompressBrotliBestCompression, -1, 100} {
+		dst := AppendBrotliBytesLevel([]byte("!!!"), src, level)
+		if string(dst[:3]) != "!!!" {
+			t.Fatalf("级别 %d 覆盖了 dst 的前缀：%q", level, dst[:3])
+		}
+		res, err := AppendUnbrotliBytes(nil, dst[3:])
+		if err != nil {
+			t.Fatalf("级别 %d 解压出错：%s", level, err)
+		}
+		if !bytes.Equal(res, src) {
+			t.Fatalf("级别 %d 解压结果不符：%q", level, res)
+		}
+	}
+}
+
+func TestCompressStacklessBrotliWriter(t *testing.T) {
+	src := bytes.Repeat([]byte("hello, wind! "), 100)
+	var w defaultByteWriter
+	zw := AcquireStacklessBrotliWriter(&w, CompressBrotliBestSpeed)
+	if _, err := zw.Write(src); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	ReleaseStacklessBrotliWriter(zw, CompressBrotliBestSpeed)
+
+	res, err := AppendUnbrotliBytes(nil, w.b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !bytes.Equal(res, src) {
+		t.Fatalf("不期待：%q", res)
+	}
+
+	// 非阻塞写入器之外的写入器走池化的无栈写入器
+	w.b = w.b[:0]
+	n, err := WriteBrotliLevel(&w, src, CompressBrotliDefaultCompression)
+	if err != nil || n != len(src) {
+		t.Fatalf("Unexpected result: %d, %v", n, err)
+	}
+	if res, _ = AppendUnbrotliBytes(nil, w.b); !bytes.Equal(res, src) {
+		t.Fatalf("不期待：%q", res)
+	}
+}
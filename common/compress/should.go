@@ -0,0 +1,76 @@
+package compress
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// MinCompressSize 是默认值得压缩的最小正文字节数，过小的正文压缩后收益甚微。
+const MinCompressSize = 1024
+
+// CompressibleContentTypes 是默认可压缩的 Content-Type 前缀白名单。
+//
+// 图片、音视频和压缩包等已压缩的内容不在其列。
+var CompressibleContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+	"application/ecmascript",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/ld+json",
+	"application/problem+json",
+	"application/x-www-form-urlencoded",
+	"application/wasm",
+	"image/svg+xml",
+	"font/ttf",
+	"font/otf",
+}
+
+// ShouldCompressFunc 是判断正文是否值得压缩的函数，size 为 -1 表示长度未知（如流式正文）。
+type ShouldCompressFunc func(contentType string, size int) bool
+
+var shouldCompress atomic.Value
+
+func init() {
+	shouldCompress.Store(ShouldCompressFunc(DefaultShouldCompress))
+}
+
+// ShouldCompress 判断给定 Content-Type 和大小的正文是否值得压缩。
+//
+// 默认使用 DefaultShouldCompress，可通过 SetShouldCompress 覆盖。
+func ShouldCompress(contentType string, size int) bool {
+	return shouldCompress.Load().(ShouldCompressFunc)(contentType, size)
+}
+
+// SetShouldCompress 覆盖 ShouldCompress 的判定函数，f 为 nil 则恢复默认。
+func SetShouldCompress(f ShouldCompressFunc) {
+	if f == nil {
+		f = DefaultShouldCompress
+	}
+	shouldCompress.Store(f)
+}
+
+// DefaultShouldCompress 是默认的判定函数：
+// 正文长度未知或不小于 MinCompressSize，且 Content-Type 匹配 CompressibleContentTypes 中的前缀。
+func DefaultShouldCompress(contentType string, size int) bool {
+	if size >= 0 && size < MinCompressSize {
+		return false
+	}
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if contentType == "" {
+		return false
+	}
+	for _, prefix := range CompressibleContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldCompress(t *testing.T) {
+	assert.True(t, ShouldCompress("text/html; charset=utf-8", 2048))
+	assert.True(t, ShouldCompress("Application/JSON", 4096))
+	assert.True(t, ShouldCompress("application/javascript", -1))
+	assert.False(t, ShouldCompress("application/json", 100))
+	assert.False(t, ShouldCompress("image/png", 1<<20))
+	assert.False(t, ShouldCompress("video/mp4", -1))
+	assert.False(t, ShouldCompress("", 4096))
+
+	SetShouldCompress(func(contentType string, size int) bool {
+		return contentType == "image/png"
+	})
+	assert.True(t, ShouldCompress("image/png", 10))
+	assert.False(t, ShouldCompress("text/html", 4096))
+
+	SetShouldCompress(nil)
+	assert.True(t, ShouldCompress("text/html", 4096))
+}
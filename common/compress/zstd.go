@@ -0,0 +1,194 @@
+package compress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/favbox/wind/common/bytebufferpool"
+	"github.com/favbox/wind/common/stackless"
+	"github.com/favbox/wind/common/utils"
+	"github.com/favbox/wind/network"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Zstd 支持的压缩级别。
+const (
+	CompressZstdSpeedFastest       = int(zstd.SpeedFastest)
+	CompressZstdSpeedDefault       = int(zstd.SpeedDefault)
+	CompressZstdSpeedBetter        = int(zstd.SpeedBetterCompression)
+	CompressZstdBestCompression    = int(zstd.SpeedBestCompression)
+	CompressZstdDefaultCompression = CompressZstdSpeedDefault
+)
+
+var zstdReaderPool sync.Pool
+
+var (
+	stacklessZstdWriterPoolMap = newCompressWriterPoolMap()
+	realZstdWriterPoolMap      = newCompressWriterPoolMap()
+)
+
+// AppendZstdBytes 以默认级别压缩 src 并附加到 dst，然后返回。
+func AppendZstdBytes(dst, src []byte) []byte {
+	return AppendZstdBytesLevel(dst, src, CompressZstdDefaultCompression)
+}
+
+// AppendZstdBytesLevel 附加压缩后的 src 到 dst 并返回（使用指定的压缩级别）。
+//
+// 支持的压缩级别为：
+//
+//   - CompressZstdSpeedFastest
+//   - CompressZstdSpeedDefault
+//   - CompressZstdSpeedBetter
+//   - CompressZstdBestCompression
+func AppendZstdBytesLevel(dst, src []byte, level int) []byte {
+	w := &byteSliceWriter{dst}
+	_, _ = WriteZstdLevel(w, src, level)
+	return w.b
+}
+
+// WriteZstdLevel 压缩 p 并写入 w（使用指定压缩级别），返回写入 w 的压缩量。
+//
+// 支持的压缩级别同 AppendZstdBytesLevel。
+func WriteZstdLevel(w io.Writer, p []byte, level int) (int, error) {
+	switch w.(type) {
+	case *byteSliceWriter,
+		*bytes.Buffer,
+		*bytebufferpool.ByteBuffer:
+		// 这些写入器不会阻塞，故可使用无栈压缩
+		ctx := &compressCtx{
+			w:     w,
+			p:     p,
+			level: level,
+		}
+		stacklessWriteZstd(ctx)
+		return len(p), nil
+	default:
+		zw := AcquireStacklessZstdWriter(w, level)
+		n, err := zw.Write(p)
+		ReleaseStacklessZstdWriter(zw, level)
+		return n, err
+	}
+}
+
+// AppendUnzstdBytes 解压 src 到 dst 并返回。
+func AppendUnzstdBytes(dst, src []byte) ([]byte, error) {
+	w := &byteSliceWriter{dst}
+	_, err := WriteUnzstd(w, src)
+	return w.b, err
+}
+
+// WriteUnzstd 解压 p 并写入 w，返回写入的解压字节数。
+func WriteUnzstd(w io.Writer, p []byte) (int, error) {
+	zr, err := AcquireZstdReader(&byteSliceReader{p})
+	if err != nil {
+		return 0, err
+	}
+	zw := network.NewWriter(w)
+	n, err := utils.CopyZeroAlloc(zw, zr)
+	ReleaseZstdReader(zr)
+	nn := int(n)
+	if int64(nn) != n {
+		return 0, fmt.Errorf("待解压数据过大: %d", n)
+	}
+	return nn, err
+}
+
+// AcquireStacklessZstdWriter 获取 io.Writer 的无堆栈 Zstd 压缩写入器。
+//
+// Flush 仅输出已缓冲的数据块，帧尾要等 Close 或 ReleaseStacklessZstdWriter 时写出。
+// 用完记得调用 ReleaseStacklessZstdWriter 释放，以降低 GC，提高性能。
+func AcquireStacklessZstdWriter(w io.Writer, level int) stackless.Writer {
+	nLevel := normalizeZstdCompressLevel(level)
+	p := stacklessZstdWriterPoolMap[nLevel]
+	v := p.Get()
+	if v == nil {
+		return stackless.NewWriter(w, func(w io.Writer) stackless.Writer {
+			return acquireRealZstdWriter(w, level)
+		})
+	}
+	sw := v.(stackless.Writer)
+	sw.Reset(w)
+	return sw
+}
+
+// ReleaseStacklessZstdWriter 写完帧尾后释放无堆栈 Zstd 压缩写入器到指定级别池。
+func ReleaseStacklessZstdWriter(sw stackless.Writer, level int) {
+	_ = sw.Close()
+	nLevel := normalizeZstdCompressLevel(level)
+	p := stacklessZstdWriterPoolMap[nLevel]
+	p.Put(sw)
+}
+
+// AcquireZstdReader 获取 r 的 Zstd 读取器，如果没有则新建一个。
+//
+// 记得用完调用 ReleaseZstdReader 释放并放回池中以减少内存开销。
+func AcquireZstdReader(r io.Reader) (*zstd.Decoder, error) {
+	v := zstdReaderPool.Get()
+	if v == nil {
+		return zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	}
+	zr := v.(*zstd.Decoder)
+	if err := zr.Reset(r); err != nil {
+		return nil, err
+	}
+	return zr, nil
+}
+
+// ReleaseZstdReader 将不用的 zr 放回池中，以减少内存开销。
+//
+// 注意不可调用 zr.Close，关闭后的解码器无法再复用。
+func ReleaseZstdReader(zr *zstd.Decoder) {
+	zstdReaderPool.Put(zr)
+}
+
+// 标准化 Zstd 压缩级别为 [1..4]，以用作 *PoolMap 的索引。
+func normalizeZstdCompressLevel(level int) int {
+	if level < CompressZstdSpeedFastest || level > CompressZstdBestCompression {
+		level = CompressZstdDefaultCompression
+	}
+	return level
+}
+
+var stacklessWriteZstd = stackless.NewFunc(nonblockingWriteZstd)
+
+func nonblockingWriteZstd(ctxv any) {
+	ctx := ctxv.(*compressCtx)
+	zw := acquireRealZstdWriter(ctx.w, ctx.level)
+
+	_, err := zw.Write(ctx.p)
+	if err != nil {
+		panic(fmt.Sprintf("BUG: zstd.Encoder.Write for len(p)=%d returned unexpected error: %s", len(ctx.p), err))
+	}
+
+	releaseRealZstdWriter(zw, ctx.level)
+}
+
+func releaseRealZstdWriter(zw *zstd.Encoder, level int) {
+	_ = zw.Close()
+	// 解除对目标写入器的引用，Reset 同时清除上次的错误与帧状态，避免池中复用时泄漏
+	zw.Reset(nil)
+	nLevel := normalizeZstdCompressLevel(level)
+	p := realZstdWriterPoolMap[nLevel]
+	p.Put(zw)
+}
+
+func acquireRealZstdWriter(w io.Writer, level int) *zstd.Encoder {
+	nLevel := normalizeZstdCompressLevel(level)
+	p := realZstdWriterPoolMap[nLevel]
+	v := p.Get()
+	if v == nil {
+		// 并发度设为 1，编码在调用方协程内同步完成，不启动后台协程，便于无栈写入与池化复用
+		zw, err := zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.EncoderLevel(nLevel)),
+			zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(fmt.Sprintf("BUG: 来自 zstd.NewWriter(%d) 的意外错误：%s", nLevel, err))
+		}
+		return zw
+	}
+	zw := v.(*zstd.Encoder)
+	zw.Reset(w)
+	return zw
+}
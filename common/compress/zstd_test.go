@@ -0,0 +1,51 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressAppendZstdBytesLevel(t *testing.T) {
+	src := bytes.Repeat([]byte("hello, wind! "), 100)
+	for _, level := range []int{CompressZstdSpeedFastest, CompressZstdSpeedDefault, CompressZstdSpeedBetter, CompressZstdBestCompression, 0, 100} {
+		dst := AppendZstdBytesLevel([]byte("!!!"), src, level)
+		if string(dst[:3]) != "!!!" {
+			t.Fatalf("级别 %d 覆盖了 dst 的前缀：%q", level, dst[:3])
+		}
+		res, err := AppendUnzstdBytes(nil, dst[3:])
+		if err != nil {
+			t.Fatalf("级别 %d 解压出错：%s", level, err)
+		}
+		if !bytes.Equal(res, src) {
+			t.Fatalf("级别 %d 解压结果不符：%q", level, res)
+		}
+	}
+}
+
+func TestCompressStacklessZstdWriter(t *testing.T) {
+	src := bytes.Repeat([]byte("hello, wind! "), 100)
+	var w defaultByteWriter
+	// 反复获取与释放，验证池中复用的写入器不残留上一帧的状态
+	for i := 0; i < 3; i++ {
+		w.b = w.b[:0]
+		zw := AcquireStacklessZstdWriter(&w, CompressZstdSpeedFastest)
+		if _, err := zw.Write(src[:len(src)/2]); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := zw.Flush(); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := zw.Write(src[len(src)/2:]); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		ReleaseStacklessZstdWriter(zw, CompressZstdSpeedFastest)
+
+		res, err := AppendUnzstdBytes(nil, w.b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !bytes.Equal(res, src) {
+			t.Fatalf("不期待：%q", res)
+		}
+	}
+}
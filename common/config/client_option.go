@@ -2,9 +2,11 @@ package config
 
 import (
 	"crypto/tls"
+	"net/http"
 	"time"
 
 	"github.com/favbox/wind/app/client/retry"
+	"github.com/favbox/wind/app/client/trace"
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/protocol/consts"
 )
@@ -27,6 +29,9 @@ type HostClientState interface {
 
 type HostClientStateFunc func(HostClientState)
 
+// ConnStateChangeFunc 是连接池状态变化的回调函数，addr 为主机客户端地址，state 为变化后的状态快照。
+type ConnStateChangeFunc func(addr string, state ConnPoolState)
+
 // ClientOption 是配置客户端选项的唯一结构体。
 type ClientOption struct {
 	F func(o *ClientOptions)
@@ -69,6 +74,12 @@ type ClientOptions struct {
 	// 是否流式响应正文。
 	ResponseBodyStream bool
 
+	// 是否自动请求压缩响应。
+	//
+	// 启用后，未设置 Accept-Encoding 的请求将自动添加 gzip, br，并透明解压 gzip 或 br 响应。
+	// 流式响应正文时不生效。
+	RequestCompressed bool
+
 	// 客户端名称。用于 User-Agent 请求标头。
 	Name string
 
@@ -80,6 +91,12 @@ type ClientOptions struct {
 	// 若未设置，则使用默认拨号器。
 	Dialer network.Dialer
 
+	// 经由的 unix 域套接字路径，如 /var/run/app.sock。
+	//
+	// 若设置，则所有请求均拨号该套接字而非网址中的主机，Host 标头仍取自网址。
+	// 默认不启用。
+	UnixSocket string
+
 	// 双重包好，若为真，则尝试同时连接 ipv4 和 ipv6 的主机地址。
 	//
 	// 该选项仅当使用默认 TCP 拨号器时可用，如 Dialer 未设置。
@@ -117,6 +134,15 @@ type ClientOptions struct {
 	// 默认不禁用。
 	DisablePathNormalizing bool
 
+	// 长度未知的流式请求体的分块阈值。
+	//
+	// 不超过该字节数的流缓冲后以 Content-Length 发送，超过则直接以 chunked 发送，不缓冲整个正文。
+	// 默认为 0，即流式请求体长度未知时总是以 chunked 发送。
+	ChunkedBodyThreshold int
+
+	// 请求各阶段的跟踪钩子
+	Trace *trace.ClientTrace
+
 	// 与重试相关的所有配置
 	RetryConfig *retry.Config
 
@@ -126,9 +152,30 @@ type ClientOptions struct {
 	// 观察间隔时长
 	ObservationInterval time.Duration
 
+	// 连接池状态变化的回调。
+	//
+	// 在连接建立、取出、放回、关闭及进入等待队列时触发，由后台协程按序异步调用，不阻塞请求；
+	// 回调处理过慢导致事件积压时丢弃新事件，后续事件仍会带来最新状态。
+	OnConnStateChange ConnStateChangeFunc
+
 	// 重配主机客户端的回调钩子。
 	// 若出错，则请求将被终止。
 	HostClientConfigHook func(hc any) error
+
+	// 自动管理 cookie 的容器。
+	//
+	// 若设置，则自动存储响应的 Set-Cookie，并在后续请求中附加匹配的 Cookie。
+	// 默认不启用。
+	CookieJar http.CookieJar
+
+	// 是否合并并发的相同请求。
+	//
+	// 方法、网址、标头及请求体均相同的并发请求只发送一次，各调用者获得响应的独立副本。
+	// 默认不启用。
+	Singleflight bool
+
+	// 不参与合并的请求标头，带有其中任一标头的请求总是单独发送。
+	SingleflightExcludeHeaders []string
 }
 
 func (o *ClientOptions) Apply(opts []ClientOption) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"net"
+	"os"
 	"time"
 
 	"github.com/favbox/wind/app/server/registry"
@@ -19,6 +20,7 @@ const (
 	defaultBasePath           = "/"
 	defaultMaxRequestBodySize = 4 * 1024 * 1024
 	defaultReadBufferSize     = 4 * 1024
+	defaultWriteBufferSize    = 4 * 1024
 )
 
 // Option 是用于配置 Options 唯一结构体。
@@ -46,9 +48,19 @@ type Options struct {
 	// 将 /FOO 和 /..//FOO 重定向到 /foo，默认不重定向。
 	RedirectFixedPath bool
 
+	// 路由匹配忽略路径的 ASCII 大小写（参数值保留原样），直接匹配而不重定向。默认关闭。
+	// 开启后 RedirectFixedPath 的大小写修正不再触发，二者无需同时开启。
+	CaseInsensitiveRouting bool
+
 	// 请求方法不匹配但有同路径其他方法，返回 405 方法不允许而非 404 找不到。
 	HandleMethodNotAllowed bool
 
+	// HEAD 请求未匹配到显式注册的 HEAD 路由时，复用同路径的 GET 处理链并丢弃正文。默认关闭。
+	AutoHead bool
+
+	// OPTIONS 请求未匹配到显式注册的 OPTIONS 路由时，自动返回 204 并以 Allow 标头列出该路径的可用方法。默认关闭。
+	HandleOPTIONS bool
+
 	// 移除额外的斜杠。
 	RemoveExtraSlash bool
 
@@ -64,6 +76,10 @@ type Options struct {
 
 	MaxRequestBodySize           int           // 正文的最大请求字节数，默认 4MB
 	MaxKeepBodySize              int           // 正文的最大保留字节数，默认 4MB
+	MaxMultipartFieldSize        int           // 多部分表单单个文本字段的最大字节数，默认 0 不限
+	MaxMultipartFileSize         int           // 多部分表单单个文件的最大字节数，默认 0 不限
+	MaxMultipartTotalSize        int           // 多部分表单的最大总字节数，默认 0 不限
+	MaxMultipartFiles            int           // 多部分表单的最大文件个数，默认 0 不限
 	GetOnly                      bool          // 是否仅支持 GET 请求，默认否
 	DisableKeepalive             bool          // 是否禁用长连接，默认否
 	DisablePreParseMultipartForm bool          // 是否不预先解析多部分表单，默认否
@@ -76,10 +92,14 @@ type Options struct {
 	Addr                         string        // 监听地址，默认 ":8888"
 	BasePath                     string        // 基本路径，默认 "/"
 	ExitWaitTimeout              time.Duration // 优雅退出的等待时间，默认 5s
+	GracefulShutdownSignals      []os.Signal   // 触发优雅退出的信号，非空时 Spin 收到其一即优雅退出、再次收到则强制退出，默认空
 	TLS                          *tls.Config
 	ALPN                         bool  // 是否打开 ALPN 应用层协议协商的开关，默认否
 	H2C                          bool  // 是否打开 HTTP/2 Cleartext （明文）协议开关，默认否
 	ReadBufferSize               int   // 初始的读缓冲大小，默认 4KB。通常无需设置。
+	WriteBufferSize              int   // 写缓冲节点的分配大小，默认 4KB。通常无需设置。
+	SocketReadBufferSize         int   // 内核套接字接收缓冲（SO_RCVBUF）大小，默认 0 沿用系统设置
+	SocketWriteBufferSize        int   // 内核套接字发送缓冲（SO_SNDBUF）大小，默认 0 沿用系统设置
 	Tracers                      []any // 链路跟踪控制器器，默认零长度切片
 	TraceLevel                   any   // 跟踪级别，默认 stats.LevelDetailed
 	ListenConfig                 *net.ListenConfig
@@ -101,6 +121,29 @@ type Options struct {
 	OnAccept  func(conn net.Conn) context.Context
 	OnConnect func(ctx context.Context, conn network.Conn) context.Context
 
+	// SlowRequestThreshold 是慢请求的耗时阈值，处理耗时超过该值时触发引擎的 OnSlowRequest 回调。
+	// 默认为 0，即不检测慢请求。
+	SlowRequestThreshold time.Duration
+
+	// DefaultResponseHeaders 是每个响应统一注入的默认标头，处理器可设置同名标头覆盖。
+	// 对 404、405 及请求解析错误等响应同样生效。
+	DefaultResponseHeaders map[string]string
+
+	// AutoChunkThreshold 是响应缓冲体的自动分块阈值，缓冲体超过该值时切换为 chunked 流式发送。
+	// 默认为 0，即始终缓冲后带 Content-Length 发送。
+	AutoChunkThreshold int
+
+	// RawTrafficMaxSize 是引擎 OnRawRequest、OnRawResponse 每次记录的最大字节数，超出部分截断。
+	// 默认为 0，即不限。
+	RawTrafficMaxSize int
+
+	// RawTrafficSampleRate 是原始字节的采样率，取值 (0, 1)。
+	// 默认为 0，即全部记录。
+	RawTrafficSampleRate float64
+
+	// ConnWrappers 是连接的包装函数，在连接交由协议服务器处理前按顺序叠加。
+	ConnWrappers []network.ConnWrapper
+
 	// 用于服务注册。
 	Registry registry.Registry
 
@@ -151,6 +194,7 @@ func NewOptions(opts []Option) *Options {
 		MaxKeepBodySize:               defaultMaxRequestBodySize,
 		ExitWaitTimeout:               defaultWaitExitTimeout,
 		ReadBufferSize:                defaultReadBufferSize,
+		WriteBufferSize:               defaultWriteBufferSize,
 		Tracers:                       []any{},
 		TraceLevel:                    new(any),
 		Registry:                      registry.NoopRegistry,
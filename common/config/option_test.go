@@ -32,6 +32,9 @@ func TestDefaultOptions(t *testing.T) {
 	assert.Equal(t, defaultWaitExitTimeout, options.ExitWaitTimeout)
 	assert.Nil(t, options.TLS)
 	assert.Equal(t, defaultReadBufferSize, options.ReadBufferSize)
+	assert.Equal(t, defaultWriteBufferSize, options.WriteBufferSize)
+	assert.Zero(t, options.SocketReadBufferSize)
+	assert.Zero(t, options.SocketWriteBufferSize)
 	assert.False(t, options.ALPN)
 	assert.False(t, options.H2C)
 	assert.Equal(t, []any{}, options.Tracers)
@@ -17,6 +17,7 @@ func TestRequestOptions(t *testing.T) {
 		WithDialTimeout(time.Second),
 		WithReadTimeout(time.Second),
 		WithWriteTimeout(time.Second),
+		WithDiscardBody(true),
 	})
 	assert.Equal(t, "b", opt.Tag("a"))
 	assert.Equal(t, "d", opt.Tag("c"))
@@ -25,6 +26,7 @@ func TestRequestOptions(t *testing.T) {
 	assert.Equal(t, time.Second, opt.ReadTimeout())
 	assert.Equal(t, time.Second, opt.WriteTimeout())
 	assert.True(t, opt.IsSD())
+	assert.True(t, opt.DiscardBody())
 }
 
 // TestRequestOptionsWithDefaultOpts 使用默认值测试请求选项。
@@ -53,9 +55,11 @@ func TestRequestOptions_CopyTo(t *testing.T) {
 	opt := NewRequestOptions([]RequestOption{
 		WithTag("a", "b"),
 		WithSD(false),
+		WithProxy("http://proxy.com:8080"),
 	})
 	var copyOpt RequestOptions
 	opt.CopyTo(&copyOpt)
 	assert.Equal(t, opt.Tags(), copyOpt.Tags())
 	assert.Equal(t, opt.IsSD(), copyOpt.IsSD())
+	assert.Equal(t, "http://proxy.com:8080", copyOpt.Proxy())
 }
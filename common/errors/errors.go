@@ -12,6 +12,8 @@ var (
 	ErrIdleTimeout        = errors.New("idle timeout")
 	ErrConnectionClosed   = errors.New("连接已关闭")
 	ErrNoMultipartForm    = errors.New("请求的内容类型没有多部分表单数据")
+	ErrMissingFile        = errors.New("http: 无此文件")
+	ErrMultipartParsed    = errors.New("多部分表单已被解析")
 	ErrNothingRead        = errors.New("未读取任何内容")
 	ErrNeedMore           = errors.New("需要更多数据")
 	ErrBodyTooLarge       = errors.New("正文大小超过给定限制")
@@ -21,6 +23,17 @@ var (
 	ErrShortConnection    = errors.New("短链接")
 	ErrNotSupportProtocol = errors.New("不支持的协议")
 	ErrBadPoolConn        = errors.New("连接在连接池中时被对端关闭")
+	ErrChecksumMissing    = errors.New("缺少请求体校验和")
+	ErrChecksumMismatch   = errors.New("请求体校验和不匹配")
+	ErrResponseCommitted  = errors.New("响应已开始写出")
+	ErrJSONTooDeep        = errors.New("JSON 嵌套层数超过限制")
+	ErrInvalidPagination  = errors.New("分页参数无效")
+	ErrNotAcceptable      = errors.New("无法提供请求可接受的内容类型")
+
+	ErrMultipartFieldTooLarge = errors.New("多部分表单文本字段大小超过给定限制")
+	ErrMultipartFileTooLarge  = errors.New("多部分表单文件大小超过给定限制")
+	ErrMultipartTotalTooLarge = errors.New("多部分表单总大小超过给定限制")
+	ErrMultipartTooManyFiles  = errors.New("多部分表单文件个数超过给定限制")
 )
 
 type ErrorType uint64
@@ -0,0 +1,105 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Decoder 是从 io.Reader 读取 JSON 的解码器。
+type Decoder interface {
+	Decode(v any) error
+	Buffered() io.Reader
+	DisallowUnknownFields()
+	More() bool
+	UseNumber()
+}
+
+// Encoder 是向 io.Writer 写入 JSON 的编码器。
+type Encoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+	SetIndent(prefix, indent string)
+}
+
+// Implementation 表示一套完整的 JSON 编解码实现。
+type Implementation struct {
+	Name          string
+	Marshal       func(v any) ([]byte, error)
+	Unmarshal     func(data []byte, v any) error
+	MarshalIndent func(v any, prefix, indent string) ([]byte, error)
+	NewDecoder    func(r io.Reader) Decoder
+	NewEncoder    func(w io.Writer) Encoder
+}
+
+// Std 是基于标准库 encoding/json 的实现，在所有平台可用。
+var Std = Implementation{
+	Name:          "encoding/json",
+	Marshal:       json.Marshal,
+	Unmarshal:     json.Unmarshal,
+	MarshalIndent: json.MarshalIndent,
+	NewDecoder:    func(r io.Reader) Decoder { return json.NewDecoder(r) },
+	NewEncoder:    func(w io.Writer) Encoder { return json.NewEncoder(w) },
+}
+
+var (
+	// Name 是当前生效的 JSON 包名。
+	Name string
+	// Marshal 用于 JSON 编码。
+	Marshal func(v any) ([]byte, error)
+	// Unmarshal 用于 JSON 解码。
+	Unmarshal func(data []byte, v any) error
+	// MarshalIndent 用于编码为带缩进格式的 JSON。
+	MarshalIndent func(v any, prefix, indent string) ([]byte, error)
+	// NewDecoder 用于创建读取 io.Reader 的 JSON 解码器。
+	NewDecoder func(r io.Reader) Decoder
+	// NewEncoder 用于创建写入 io.Writer 的 JSON 编码器。
+	NewEncoder func(w io.Writer) Encoder
+)
+
+func init() {
+	SetImplementation(defaultImplementation)
+}
+
+// SetImplementation 设置全局的 JSON 实现，绑定解码与响应渲染均随之切换。
+//
+// impl 中未设置的函数将回退至标准库实现。
+// 注意：非并发安全，应在服务启动前调用。
+func SetImplementation(impl Implementation) {
+	if impl.Name == "" {
+		impl.Name = Std.Name
+	}
+	if impl.Marshal == nil {
+		impl.Marshal = Std.Marshal
+	}
+	if impl.Unmarshal == nil {
+		impl.Unmarshal = Std.Unmarshal
+	}
+	if impl.MarshalIndent == nil {
+		impl.MarshalIndent = Std.MarshalIndent
+	}
+	if impl.NewDecoder == nil {
+		impl.NewDecoder = Std.NewDecoder
+	}
+	if impl.NewEncoder == nil {
+		impl.NewEncoder = Std.NewEncoder
+	}
+
+	Name = impl.Name
+	Marshal = impl.Marshal
+	Unmarshal = impl.Unmarshal
+	MarshalIndent = impl.MarshalIndent
+	NewDecoder = impl.NewDecoder
+	NewEncoder = impl.NewEncoder
+}
+
+// CurrentImplementation 返回当前生效的 JSON 实现。
+func CurrentImplementation() Implementation {
+	return Implementation{
+		Name:          Name,
+		Marshal:       Marshal,
+		Unmarshal:     Unmarshal,
+		MarshalIndent: MarshalIndent,
+		NewDecoder:    NewDecoder,
+		NewEncoder:    NewEncoder,
+	}
+}
@@ -0,0 +1,37 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetImplementation(t *testing.T) {
+	defer SetImplementation(defaultImplementation)
+
+	errMock := errors.New("mock")
+	SetImplementation(Implementation{
+		Name: "mock",
+		Unmarshal: func(data []byte, v any) error {
+			return errMock
+		},
+	})
+	assert.Equal(t, "mock", Name)
+	assert.Equal(t, errMock, Unmarshal([]byte(`{}`), &struct{}{}))
+
+	// 未设置的函数回退至标准库实现
+	b, err := Marshal(map[string]string{"a": "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"b"}`, string(b))
+	var buf bytes.Buffer
+	assert.Nil(t, NewEncoder(&buf).Encode(1))
+	assert.Equal(t, "1\n", buf.String())
+
+	SetImplementation(Std)
+	assert.Equal(t, Std.Name, CurrentImplementation().Name)
+	var v map[string]int
+	assert.Nil(t, NewDecoder(bytes.NewBufferString(`{"a":1}`)).Decode(&v))
+	assert.Equal(t, 1, v["a"])
+}
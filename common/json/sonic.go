@@ -2,21 +2,21 @@
 
 package json
 
-import "github.com/bytedance/sonic"
+import (
+	"io"
 
-// Name 是有效的JSON 包名。
-const Name = "sonic"
-
-var (
-	json = sonic.ConfigStd
-	// Marshal 用于 JSON 编码而导出的 sonic 实现。
-	Marshal = json.Marshal
-	// Unmarshal 用于 JSON 解码而导出的 sonic 实现。
-	Unmarshal = json.Unmarshal
-	// MarshalIndent 用于编码为带缩进格式的 JSON 而导出的 sonic 实现。
-	MarshalIndent = json.MarshalIndent
-	// NewDecoder 用于读取 io.Reader 而导出的 JSON 读取器。
-	NewDecoder = json.NewDecoder
-	// NewEncoder 用于写入 io.Writer 而导出的 JSON 编码器。
-	NewEncoder = json.NewEncoder
+	"github.com/bytedance/sonic"
 )
+
+// Sonic 是基于 sonic 的高性能实现，仅在 amd64 的 linux/windows/darwin 平台可用。
+var Sonic = Implementation{
+	Name:          "sonic",
+	Marshal:       sonic.ConfigStd.Marshal,
+	Unmarshal:     sonic.ConfigStd.Unmarshal,
+	MarshalIndent: sonic.ConfigStd.MarshalIndent,
+	NewDecoder:    func(r io.Reader) Decoder { return sonic.ConfigStd.NewDecoder(r) },
+	NewEncoder:    func(w io.Writer) Encoder { return sonic.ConfigStd.NewEncoder(w) },
+}
+
+// 当前平台默认使用 sonic 实现。
+var defaultImplementation = Sonic
@@ -0,0 +1,806 @@
+// Package msgpack 提供轻量的 MessagePack 编解码实现。
+//
+// Marshal 按 json 标签反射遍历值，字段名、omitempty、string 选项与 "-" 均与 encoding/json 一致，
+// []byte 编码为 bin，time.Time 编码为时间戳扩展（类型 -1）。
+// Unmarshal 先将 MessagePack 解析为通用值再经 JSON 解码到目标，bin 还原为 []byte，时间戳扩展还原为 time.Time，
+// 故二者可互为逆操作。对性能或类型保真有更高要求时，可在绑定配置与渲染中替换为第三方实现。
+package msgpack
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	stdJson "encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/favbox/wind/common/json"
+)
+
+// 嵌套的最大层数，防御深层嵌套的数据耗尽栈空间。
+const maxDepth = 10000
+
+var (
+	errShortData = errors.New("msgpack: 数据不完整")
+	errTooDeep   = errors.New("msgpack: 嵌套层数超过限制")
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	numberType        = reflect.TypeOf(stdJson.Number(""))
+	jsonMarshalerType = reflect.TypeOf((*stdJson.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Marshal 返回 v 的 MessagePack 编码。
+//
+// 实现了 json.Marshaler 的类型按其 JSON 输出编码，实现了 encoding.TextMarshaler 的类型编码为字符串。
+func Marshal(v any) ([]byte, error) {
+	return appendReflect(nil, reflect.ValueOf(v), 0)
+}
+
+// Unmarshal 解析 MessagePack 编码的 data 并将结果存入 v 指向的值。
+func Unmarshal(data []byte, v any) error {
+	d := &decoder{data: data}
+	generic, err := d.value(0)
+	if err != nil {
+		return err
+	}
+	if d.off != len(data) {
+		return fmt.Errorf("msgpack: 数据末尾有 %d 字节多余内容", len(data)-d.off)
+	}
+	b, err := stdJson.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// 将任意值编码为 MessagePack 并追加到 dst。
+func appendReflect(dst []byte, rv reflect.Value, depth int) ([]byte, error) {
+	if depth > maxDepth {
+		return nil, errTooDeep
+	}
+	if !rv.IsValid() {
+		return append(dst, 0xc0), nil
+	}
+	if rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return append(dst, 0xc0), nil
+		}
+		return appendReflect(dst, rv.Elem(), depth+1)
+	}
+
+	switch t := rv.Type(); {
+	case t == timeType:
+		return appendTime(dst, rv.Interface().(time.Time)), nil
+	case t == numberType:
+		return appendValue(dst, stdJson.Number(rv.String()))
+	case implements(rv, jsonMarshalerType):
+		b, err := methodValue(rv, jsonMarshalerType).(stdJson.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return appendJSON(dst, b)
+	case implements(rv, textMarshalerType):
+		b, err := methodValue(rv, textMarshalerType).(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return appendString(dst, string(b)), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(dst, 0xc3), nil
+		}
+		return append(dst, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(dst, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint(dst, rv.Uint()), nil
+	case reflect.Float32:
+		return appendFloat32(dst, float32(rv.Float())), nil
+	case reflect.Float64:
+		return appendFloat(dst, rv.Float()), nil
+	case reflect.String:
+		return appendString(dst, rv.String()), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return append(dst, 0xc0), nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return appendBin(dst, rv.Bytes()), nil
+		}
+		return appendArray(dst, rv, depth)
+	case reflect.Array:
+		return appendArray(dst, rv, depth)
+	case reflect.Map:
+		if rv.IsNil() {
+			return append(dst, 0xc0), nil
+		}
+		return appendMap(dst, rv, depth)
+	case reflect.Struct:
+		return appendStruct(dst, rv, depth)
+	}
+	return nil, fmt.Errorf("msgpack: 不支持的类型 %s", rv.Type())
+}
+
+// 汇报 rv 或其地址是否实现了接口 t。
+func implements(rv reflect.Value, t reflect.Type) bool {
+	return rv.Type().Implements(t) || rv.CanAddr() && reflect.PtrTo(rv.Type()).Implements(t)
+}
+
+// 返回实现了接口 t 的值，指针接收者的方法经由 rv 的地址调用。
+func methodValue(rv reflect.Value, t reflect.Type) any {
+	if rv.Type().Implements(t) {
+		return rv.Interface()
+	}
+	return rv.Addr().Interface()
+}
+
+// 将 JSON 文本转为 MessagePack 并追加到 dst。
+func appendJSON(dst, data []byte) ([]byte, error) {
+	dec := stdJson.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return appendValue(dst, generic)
+}
+
+func appendArray(dst []byte, rv reflect.Value, depth int) ([]byte, error) {
+	n := rv.Len()
+	dst = appendLen(dst, n, 0x90, 0xdc, 0xdd)
+	var err error
+	for i := 0; i < n; i++ {
+		if dst, err = appendReflect(dst, rv.Index(i), depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+func appendMap(dst []byte, rv reflect.Value, depth int) ([]byte, error) {
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	// 键按字典序编码，使输出稳定
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	dst = appendLen(dst, len(entries), 0x80, 0xde, 0xdf)
+	var err error
+	for _, e := range entries {
+		dst = appendString(dst, e.key)
+		if dst, err = appendReflect(dst, e.val, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// 按 encoding/json 的规则将映射的键转为字符串。
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("msgpack: 不支持的映射键类型 %s", k.Type())
+}
+
+func appendStruct(dst []byte, rv reflect.Value, depth int) ([]byte, error) {
+	fields := cachedFields(rv.Type())
+	used := make([]field, 0, len(fields))
+	vals := make([]reflect.Value, 0, len(fields))
+	for _, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		used = append(used, f)
+		vals = append(vals, fv)
+	}
+
+	dst = appendLen(dst, len(used), 0x80, 0xde, 0xdf)
+	var err error
+	for i, f := range used {
+		dst = appendString(dst, f.name)
+		if f.quoted {
+			dst, err = appendQuoted(dst, vals[i])
+		} else {
+			dst, err = appendReflect(dst, vals[i], depth+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// 按 json 标签的 string 选项将标量编码为字符串。
+func appendQuoted(dst []byte, rv reflect.Value) ([]byte, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return append(dst, 0xc0), nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return appendString(dst, strconv.FormatBool(rv.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendString(dst, strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendString(dst, strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return appendString(dst, strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())), nil
+	case reflect.String:
+		b, _ := stdJson.Marshal(rv.String())
+		return appendString(dst, string(b)), nil
+	}
+	return appendReflect(dst, rv, 0)
+}
+
+// 沿 index 取嵌入字段，途经空指针时返回 false。
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// 结构体中参与编码的字段。
+type field struct {
+	name      string
+	index     []int
+	tagged    bool // 名称来自 json 标签
+	omitEmpty bool
+	quoted    bool // json 标签的 string 选项
+}
+
+var fieldsCache sync.Map // reflect.Type -> []field
+
+func cachedFields(t reflect.Type) []field {
+	if v, ok := fieldsCache.Load(t); ok {
+		return v.([]field)
+	}
+	v, _ := fieldsCache.LoadOrStore(t, typeFields(t))
+	return v.([]field)
+}
+
+// 按 encoding/json 的规则收集字段：嵌入结构体的字段提升到外层，
+// 同名时层级浅者优先，同层时带标签者优先，仍无法区分则全部忽略。
+func typeFields(t reflect.Type) []field {
+	var all []field
+	collectFields(t, nil, map[reflect.Type]bool{}, &all)
+
+	byName := make(map[string][]field)
+	var names []string
+	for _, f := range all {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
+		if f, ok := dominantField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return lessIndex(fields[i].index, fields[j].index) })
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool, all *[]field) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous {
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(append([]int(nil), index...), i)
+
+		if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+			collectFields(ft, idx, visited, all)
+			continue
+		}
+		f := field{name: name, index: idx, tagged: name != ""}
+		if name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "string":
+				switch ft.Kind() {
+				case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+					reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+					f.quoted = true
+				}
+			}
+		}
+		*all = append(*all, f)
+	}
+}
+
+func dominantField(fields []field) (field, bool) {
+	minDepth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < minDepth {
+			minDepth = len(f.index)
+		}
+	}
+	var found []field
+	for _, f := range fields {
+		if len(f.index) == minDepth {
+			found = append(found, f)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+	var tagged []field
+	for _, f := range found {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return field{}, false
+}
+
+func lessIndex(a, b []int) bool {
+	for i := range a {
+		if i >= len(b) {
+			return false
+		}
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// 将 JSON 通用值编码为 MessagePack 并追加到 dst。
+func appendValue(dst []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, 0xc0), nil
+	case bool:
+		if v {
+			return append(dst, 0xc3), nil
+		}
+		return append(dst, 0xc2), nil
+	case stdJson.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return appendInt(dst, i), nil
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return appendUint(dst, u), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return appendFloat(dst, f), nil
+	case string:
+		return appendString(dst, v), nil
+	case []any:
+		dst = appendLen(dst, len(v), 0x90, 0xdc, 0xdd)
+		var err error
+		for _, e := range v {
+			if dst, err = appendValue(dst, e); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]any:
+		dst = appendLen(dst, len(v), 0x80, 0xde, 0xdf)
+		// 键按字典序编码，使输出稳定
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var err error
+		for _, k := range keys {
+			dst = appendString(dst, k)
+			if dst, err = appendValue(dst, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	default:
+		return nil, fmt.Errorf("msgpack: 不支持的类型 %T", v)
+	}
+}
+
+func appendInt(dst []byte, i int64) []byte {
+	if i >= 0 {
+		return appendUint(dst, uint64(i))
+	}
+	switch {
+	case i >= -32:
+		return append(dst, byte(i))
+	case i >= math.MinInt8:
+		return append(dst, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return append(dst, 0xd1, byte(i>>8), byte(i))
+	case i >= math.MinInt32:
+		return append(dst, 0xd2, byte(i>>24), byte(i>>16), byte(i>>8), byte(i))
+	default:
+		return appendUint64(append(dst, 0xd3), uint64(i))
+	}
+}
+
+func appendUint(dst []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(dst, byte(u))
+	case u <= math.MaxUint8:
+		return append(dst, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return append(dst, 0xcd, byte(u>>8), byte(u))
+	case u <= math.MaxUint32:
+		return append(dst, 0xce, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+	default:
+		return appendUint64(append(dst, 0xcf), u)
+	}
+}
+
+func appendUint64(dst []byte, u uint64) []byte {
+	return append(dst, byte(u>>56), byte(u>>48), byte(u>>40), byte(u>>32), byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendFloat32(dst []byte, f float32) []byte {
+	u := math.Float32bits(f)
+	return append(dst, 0xca, byte(u>>24), byte(u>>16), byte(u>>8), byte(u))
+}
+
+func appendFloat(dst []byte, f float64) []byte {
+	return appendUint64(append(dst, 0xcb), math.Float64bits(f))
+}
+
+func appendString(dst []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		dst = append(dst, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xd9, byte(n))
+	default:
+		dst = appendLen(dst, n, 0, 0xda, 0xdb)
+	}
+	return append(dst, s...)
+}
+
+func appendBin(dst, b []byte) []byte {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		dst = append(dst, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		dst = append(dst, 0xc5, byte(n>>8), byte(n))
+	default:
+		dst = append(dst, 0xc6, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(dst, b...)
+}
+
+// 按时间戳扩展（类型 -1）编码，依取值选用 32、64 或 96 位格式。
+func appendTime(dst []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0 && sec <= math.MaxUint32:
+		return append(dst, 0xd6, 0xff, byte(sec>>24), byte(sec>>16), byte(sec>>8), byte(sec))
+	case sec>>34 == 0:
+		return appendUint64(append(dst, 0xd7, 0xff), nsec<<34|uint64(sec))
+	default:
+		dst = append(dst, 0xc7, 12, 0xff, byte(nsec>>24), byte(nsec>>16), byte(nsec>>8), byte(nsec))
+		return appendUint64(dst, uint64(sec))
+	}
+}
+
+// 追加数组或映射的长度头，fix 为 fixarray/fixmap 的前缀，b16、b32 为 16 位和 32 位长度的类型码。
+func appendLen(dst []byte, n int, fix, b16, b32 byte) []byte {
+	switch {
+	case n <= 15 && fix != 0:
+		return append(dst, fix|byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, b16, byte(n>>8), byte(n))
+	default:
+		return append(dst, b32, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+// 将 MessagePack 解析为通用值的解码器。
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errTooDeep
+	}
+	c, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.mapValue(int(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return d.arrayValue(int(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.bytes(int(n))
+		if err != nil {
+			return nil, err
+		}
+		// bin 以 base64 字符串参与 JSON 解码，可还原到 []byte 字段
+		return base64.StdEncoding.EncodeToString(b), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(int(n))
+	case 0xca:
+		u, err := d.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(uint32(u))), nil
+	case 0xcb:
+		u, err := d.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(u), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		u, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// 按位宽符号扩展
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.arrayValue(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.mapValue(int(n), depth)
+	}
+	return nil, fmt.Errorf("msgpack: 无效的类型码 0x%x", c)
+}
+
+func (d *decoder) arrayValue(n, depth int) (any, error) {
+	// 每个元素至少占 1 字节，据此拒绝虚报的长度，避免超大分配
+	if n > len(d.data)-d.off {
+		return nil, errShortData
+	}
+	arr := make([]any, n)
+	for i := range arr {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return arr, nil
+}
+
+func (d *decoder) mapValue(n, depth int) (any, error) {
+	if n > (len(d.data)-d.off)/2 {
+		return nil, errShortData
+	}
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// 解析扩展类型，仅支持时间戳（类型 -1）。
+func (d *decoder) ext(n int) (any, error) {
+	typ, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) != -1 {
+		return nil, fmt.Errorf("msgpack: 不支持的扩展类型 %d", int8(typ))
+	}
+
+	var sec int64
+	var nsec uint32
+	switch n {
+	case 4:
+		sec = int64(be(b))
+	case 8:
+		u := be(b)
+		nsec, sec = uint32(u>>34), int64(u&(1<<34-1))
+	case 12:
+		nsec, sec = uint32(be(b[:4])), int64(be(b[4:]))
+	default:
+		return nil, fmt.Errorf("msgpack: 无效的时间戳长度 %d", n)
+	}
+	return time.Unix(sec, int64(nsec)).UTC().Format(time.RFC3339Nano), nil
+}
+
+func (d *decoder) str(n int) (any, error) {
+	b, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	if d.off >= len(d.data) {
+		return 0, errShortData
+	}
+	c := d.data[d.off]
+	d.off++
+	return c, nil
+}
+
+func (d *decoder) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.off {
+		return nil, errShortData
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+// 读取 size 字节的大端无符号整数。
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.bytes(size)
+	if err != nil {
+		return 0, err
+	}
+	return be(b), nil
+}
+
+// 解析大端无符号整数。
+func be(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
@@ -0,0 +1,164 @@
+package msgpack
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type user struct {
+	Name     string    `json:"name"`
+	Age      int       `json:"age"`
+	Score    float64   `json:"score"`
+	Balance  int64     `json:"balance"`
+	Big      uint64    `json:"big"`
+	Tags     []string  `json:"tags"`
+	Avatar   []byte    `json:"avatar"`
+	Address  *address  `json:"address"`
+	Extra    any       `json:"extra"`
+	Birthday time.Time `json:"birthday"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	u := user{
+		Name:     "wind",
+		Age:      18,
+		Score:    99.5,
+		Balance:  math.MinInt64,
+		Big:      math.MaxUint64,
+		Tags:     []string{"a", "b"},
+		Avatar:   []byte{0, 1, 2},
+		Address:  &address{City: "上海"},
+		Birthday: time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	b, err := Marshal(u)
+	assert.Nil(t, err)
+
+	var got user
+	assert.Nil(t, Unmarshal(b, &got))
+	assert.Equal(t, u, got)
+}
+
+func TestMarshalFormat(t *testing.T) {
+	tests := []struct {
+		v    any
+		want []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{1, []byte{0x01}},
+		{-1, []byte{0xff}},
+		{200, []byte{0xcc, 0xc8}},
+		{-200, []byte{0xd1, 0xff, 0x38}},
+		{70000, []byte{0xce, 0x00, 0x01, 0x11, 0x70}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"ab", []byte{0xa2, 'a', 'b'}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{map[int]bool{2: true}, []byte{0x81, 0xa1, '2', 0xc3}},
+		{float32(1.5), []byte{0xca, 0x3f, 0xc0, 0, 0}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{time.Unix(1, 1), []byte{0xd7, 0xff, 0, 0, 0, 0x04, 0, 0, 0, 0x01}},
+		{time.Unix(-1, 0), []byte{0xc7, 0x0c, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{[]string(nil), []byte{0xc0}},
+	}
+	for _, tt := range tests {
+		b, err := Marshal(tt.v)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.want, b, tt.v)
+	}
+}
+
+type base struct {
+	ID   int    `json:"id"`
+	Note string `json:"note"`
+}
+
+type level string
+
+func (l *level) MarshalText() ([]byte, error) {
+	return []byte("L-" + string(*l)), nil
+}
+
+func TestMarshalStructTags(t *testing.T) {
+	type item struct {
+		base
+		Note    string `json:"note"` // 浅层字段优先
+		Skip    string `json:"-"`
+		Empty   string `json:"empty,omitempty"`
+		Count   int    `json:"count,string"`
+		Level   level  `json:"level"`
+		private int
+	}
+	b, err := Marshal(&item{base: base{ID: 1, Note: "inner"}, Note: "outer", Skip: "x", Count: 3, Level: "a", private: 1})
+	assert.Nil(t, err)
+
+	var got map[string]any
+	assert.Nil(t, Unmarshal(b, &got))
+	assert.Equal(t, map[string]any{
+		"id":    float64(1),
+		"note":  "outer",
+		"count": "3",
+		"level": "L-a",
+	}, got)
+}
+
+func TestUnmarshalFormat(t *testing.T) {
+	type target struct {
+		I8   int8    `json:"i8"`
+		I16  int16   `json:"i16"`
+		U32  uint32  `json:"u32"`
+		F32  float32 `json:"f32"`
+		Str  string  `json:"str"`
+		Bin  []byte  `json:"bin"`
+		Time string  `json:"time"`
+	}
+	data := []byte{
+		0x87,
+		0xa2, 'i', '8', 0xd0, 0x80,
+		0xa3, 'i', '1', '6', 0xd1, 0x80, 0x00,
+		0xa3, 'u', '3', '2', 0xce, 0xff, 0xff, 0xff, 0xff,
+		0xa3, 'f', '3', '2', 0xca, 0x3f, 0xc0, 0x00, 0x00,
+		0xa3, 's', 't', 'r', 0xd9, 0x02, 'o', 'k',
+		0xa3, 'b', 'i', 'n', 0xc4, 0x02, 0x01, 0x02,
+		0xa4, 't', 'i', 'm', 'e', 0xd6, 0xff, 0x00, 0x00, 0x00, 0x01,
+	}
+	var got target
+	assert.Nil(t, Unmarshal(data, &got))
+	assert.Equal(t, target{
+		I8:   math.MinInt8,
+		I16:  math.MinInt16,
+		U32:  math.MaxUint32,
+		F32:  1.5,
+		Str:  "ok",
+		Bin:  []byte{1, 2},
+		Time: "1970-01-01T00:00:01Z",
+	}, got)
+}
+
+func TestUnmarshalError(t *testing.T) {
+	var v any
+	for _, data := range [][]byte{
+		nil,
+		{0xa3, 'a'},                    // 字符串不完整
+		{0xdd, 0xff, 0xff, 0xff, 0xff}, // 虚报的数组长度
+		{0xc1},                         // 无效类型码
+		{0xd4, 0x01, 0x00},             // 不支持的扩展类型
+		{0x01, 0x02},                   // 多余内容
+	} {
+		assert.NotNil(t, Unmarshal(data, &v), data)
+	}
+
+	deep := make([]byte, maxDepth+2)
+	for i := range deep {
+		deep[i] = 0x91
+	}
+	assert.Equal(t, errTooDeep, Unmarshal(deep, &v))
+}
@@ -0,0 +1,9 @@
+// Package jsonrpc 提供 JSON-RPC 2.0 的便捷处理层。
+//
+// 按方法名注册处理器后，Server 负责解析请求帧、路由到对应处理器并封装响应或错误帧，
+// 支持批量请求与通知（无 id 的请求不回写响应）。
+//
+// 传输层只需实现按消息收发的 MessageConn，如 WebSocket 连接的文本帧。
+//
+// https://www.jsonrpc.org/specification
+package jsonrpc
@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Version 是协议版本号。
+const Version = "2.0"
+
+// 规范约定的错误码。
+const (
+	CodeParseError     = -32700 // 无效的 JSON
+	CodeInvalidRequest = -32600 // 无效的请求对象
+	CodeMethodNotFound = -32601 // 方法不存在
+	CodeInvalidParams  = -32602 // 无效的方法参数
+	CodeInternalError  = -32603 // 内部错误
+)
+
+// Error 是 JSON-RPC 错误对象。
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// NewError 创建指定错误码和消息的错误对象。
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// WithData 设置错误的附加数据并返回该错误。
+func (e *Error) WithData(data any) *Error {
+	e.Data = data
+	return e
+}
+
+func (e *Error) Error() string {
+	return "jsonrpc: " + e.Message + " (code " + strconv.Itoa(e.Code) + ")"
+}
+
+// Request 是 JSON-RPC 请求对象。
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// IsNotification 判断请求是否为通知，即不含 id、无需响应。
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response 是 JSON-RPC 响应对象。
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+var null = json.RawMessage("null")
@@ -0,0 +1,189 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	wjson "github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/common/wlog"
+)
+
+// HandlerFunc 是方法处理器，params 为请求的原始参数。
+//
+// 返回 *Error 时原样回写给对端，其他错误按内部错误回写。
+type HandlerFunc func(ctx context.Context, params json.RawMessage) (result any, err error)
+
+// MessageConn 是按消息收发的连接，如 WebSocket 连接。
+//
+// ReadMessage 返回的切片在下次读取后仍须有效。
+type MessageConn interface {
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+}
+
+// Server 是 JSON-RPC 服务端，按方法名路由请求。
+type Server struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewServer 创建一个 JSON-RPC 服务端。
+func NewServer() *Server {
+	return &Server{handlers: make(map[string]HandlerFunc)}
+}
+
+// Register 注册方法处理器，重复注册会覆盖之前的处理器。
+func (s *Server) Register(method string, h HandlerFunc) {
+	if method == "" {
+		panic("jsonrpc: 方法名不能为空")
+	}
+	if h == nil {
+		panic("jsonrpc: 方法处理器不能为空")
+	}
+	s.mu.Lock()
+	s.handlers[method] = h
+	s.mu.Unlock()
+}
+
+// Serve 循环读取 conn 的消息并逐条并发处理，响应按处理完成的先后回写。
+//
+// 读取出错时等待处理中的请求完成后返回该错误。
+func (s *Server) Serve(ctx context.Context, conn MessageConn) error {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	defer wg.Wait()
+
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out := s.Handle(ctx, msg)
+			if out == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err := conn.WriteMessage(out); err != nil {
+				wlog.SystemLogger().CtxErrorf(ctx, "[JSON-RPC] 回写响应失败：%v", err)
+			}
+		}()
+	}
+}
+
+// Handle 处理一条请求消息（单个或批量），返回应回写的响应帧。
+//
+// 消息仅含通知时无需响应，返回 nil。批量请求中的各请求并发处理，响应顺序与请求一致。
+func (s *Server) Handle(ctx context.Context, msg []byte) []byte {
+	msg = bytes.TrimSpace(msg)
+	if !json.Valid(msg) {
+		return marshalResponse(errorResponse(null, NewError(CodeParseError, "无效的 JSON")))
+	}
+
+	if msg[0] != '[' {
+		resp := s.handleOne(ctx, msg)
+		if resp == nil {
+			return nil
+		}
+		return marshalResponse(resp)
+	}
+
+	var batch []json.RawMessage
+	if err := wjson.Unmarshal(msg, &batch); err != nil || len(batch) == 0 {
+		return marshalResponse(errorResponse(null, NewError(CodeInvalidRequest, "无效的批量请求")))
+	}
+	resps := make([]*Response, len(batch))
+	var wg sync.WaitGroup
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resps[i] = s.handleOne(ctx, batch[i])
+		}(i)
+	}
+	wg.Wait()
+
+	out := resps[:0]
+	for _, resp := range resps {
+		if resp != nil {
+			out = append(out, resp)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return marshalResponse(out)
+}
+
+// 处理单个请求，通知返回 nil。
+func (s *Server) handleOne(ctx context.Context, raw json.RawMessage) *Response {
+	var req Request
+	if err := wjson.Unmarshal(raw, &req); err != nil {
+		return errorResponse(null, NewError(CodeInvalidRequest, "无效的请求对象"))
+	}
+	id := req.ID
+	if len(id) == 0 {
+		id = null
+	}
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(id, NewError(CodeInvalidRequest, "无效的请求对象"))
+	}
+
+	s.mu.RLock()
+	h, ok := s.handlers[req.Method]
+	s.mu.RUnlock()
+
+	var resp *Response
+	if !ok {
+		resp = errorResponse(id, NewError(CodeMethodNotFound, "方法不存在").WithData(req.Method))
+	} else {
+		resp = call(ctx, h, id, req.Params)
+	}
+	if req.IsNotification() {
+		return nil
+	}
+	return resp
+}
+
+// 调用处理器并封装响应，处理器 panic 时按内部错误返回。
+func call(ctx context.Context, h HandlerFunc, id, params json.RawMessage) (resp *Response) {
+	defer func() {
+		if r := recover(); r != nil {
+			wlog.SystemLogger().CtxErrorf(ctx, "[JSON-RPC] 处理器 panic：%v", r)
+			resp = errorResponse(id, NewError(CodeInternalError, "内部错误"))
+		}
+	}()
+
+	result, err := h(ctx, params)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = NewError(CodeInternalError, err.Error())
+		}
+		return errorResponse(id, rpcErr)
+	}
+
+	data, err := wjson.Marshal(result)
+	if err != nil {
+		return errorResponse(id, NewError(CodeInternalError, fmt.Sprintf("序列化结果失败：%v", err)))
+	}
+	return &Response{JSONRPC: Version, Result: data, ID: id}
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	return &Response{JSONRPC: Version, Error: err, ID: id}
+}
+
+func marshalResponse(v any) []byte {
+	data, _ := wjson.Marshal(v)
+	return data
+}
@@ -0,0 +1,119 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer() *Server {
+	s := NewServer()
+	s.Register("sum", func(ctx context.Context, params json.RawMessage) (any, error) {
+		var nums []int
+		if err := json.Unmarshal(params, &nums); err != nil {
+			return nil, NewError(CodeInvalidParams, "参数须为整数数组")
+		}
+		total := 0
+		for _, n := range nums {
+			total += n
+		}
+		return total, nil
+	})
+	s.Register("fail", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+	s.Register("panic", func(ctx context.Context, params json.RawMessage) (any, error) {
+		panic("oops")
+	})
+	return s
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"result", `{"jsonrpc":"2.0","method":"sum","params":[1,2,3],"id":1}`, `{"jsonrpc":"2.0","result":6,"id":1}`},
+		{"string id", `{"jsonrpc":"2.0","method":"sum","params":[],"id":"a"}`, `{"jsonrpc":"2.0","result":0,"id":"a"}`},
+		{"invalid params", `{"jsonrpc":"2.0","method":"sum","params":{},"id":2}`, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"参数须为整数数组"},"id":2}`},
+		{"internal error", `{"jsonrpc":"2.0","method":"fail","id":3}`, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"boom"},"id":3}`},
+		{"panic", `{"jsonrpc":"2.0","method":"panic","id":4}`, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"内部错误"},"id":4}`},
+		{"method not found", `{"jsonrpc":"2.0","method":"none","id":5}`, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"方法不存在","data":"none"},"id":5}`},
+		{"parse error", `{"jsonrpc":"2.0","method"`, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"无效的 JSON"},"id":null}`},
+		{"invalid request", `{"jsonrpc":"1.0","method":"sum","id":6}`, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"无效的请求对象"},"id":6}`},
+		{"empty batch", `[]`, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"无效的批量请求"},"id":null}`},
+		{"notification", `{"jsonrpc":"2.0","method":"fail"}`, ``},
+		{"batch notifications", `[{"jsonrpc":"2.0","method":"sum","params":[1]},{"jsonrpc":"2.0","method":"none"}]`, ``},
+		{
+			"batch",
+			`[{"jsonrpc":"2.0","method":"sum","params":[1,2],"id":1},{"jsonrpc":"2.0","method":"sum","params":[1]},1,{"jsonrpc":"2.0","method":"none","id":2}]`,
+			`[{"jsonrpc":"2.0","result":3,"id":1},{"jsonrpc":"2.0","error":{"code":-32600,"message":"无效的请求对象"},"id":null},{"jsonrpc":"2.0","error":{"code":-32601,"message":"方法不存在","data":"none"},"id":2}]`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := s.Handle(ctx, []byte(c.in))
+			if c.out == "" {
+				assert.Nil(t, out)
+				return
+			}
+			assert.JSONEq(t, c.out, string(out))
+		})
+	}
+}
+
+func TestServer_Register(t *testing.T) {
+	s := NewServer()
+	assert.Panics(t, func() { s.Register("", func(context.Context, json.RawMessage) (any, error) { return nil, nil }) })
+	assert.Panics(t, func() { s.Register("m", nil) })
+}
+
+type mockConn struct {
+	in  chan []byte
+	mu  sync.Mutex
+	out []string
+}
+
+func (c *mockConn) ReadMessage() ([]byte, error) {
+	msg, ok := <-c.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (c *mockConn) WriteMessage(data []byte) error {
+	c.mu.Lock()
+	c.out = append(c.out, string(data))
+	c.mu.Unlock()
+	return nil
+}
+
+func TestServer_Serve(t *testing.T) {
+	s := newTestServer()
+	conn := &mockConn{in: make(chan []byte, 3)}
+	conn.in <- []byte(`{"jsonrpc":"2.0","method":"sum","params":[1],"id":1}`)
+	conn.in <- []byte(`{"jsonrpc":"2.0","method":"sum","params":[2]}`)
+	conn.in <- []byte(`{"jsonrpc":"2.0","method":"sum","params":[3],"id":3}`)
+	close(conn.in)
+
+	err := s.Serve(context.Background(), conn)
+	assert.Equal(t, io.EOF, err)
+
+	// 并发处理，响应顺序不固定
+	sort.Strings(conn.out)
+	assert.Equal(t, []string{
+		`{"jsonrpc":"2.0","result":1,"id":1}`,
+		`{"jsonrpc":"2.0","result":3,"id":3}`,
+	}, conn.out)
+}
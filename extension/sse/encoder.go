@@ -109,3 +109,40 @@ func writeData(w io.Writer, data []byte) (err error) {
 
 	return nil
 }
+
+// EncodeComment 将 text 编码为注释行写入 w，多行文本逐行加注释前缀。
+func EncodeComment(w io.Writer, text string) (err error) {
+	for {
+		line := text
+		i := strings.IndexAny(text, "\r\n")
+		if i >= 0 {
+			line = text[:i]
+		}
+		if len(line) > 0 {
+			_, err = w.Write([]byte(": "))
+			if err != nil {
+				return
+			}
+			_, err = io.WriteString(w, line)
+		} else {
+			_, err = w.Write([]byte(":"))
+		}
+		if err != nil {
+			return
+		}
+		_, err = w.Write([]byte("\n"))
+		if err != nil {
+			return
+		}
+		if i < 0 {
+			break
+		}
+		// \r\n 视为一个换行
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+		text = text[i+1:]
+	}
+	_, err = w.Write([]byte("\n"))
+	return
+}
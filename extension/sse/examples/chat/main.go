@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/favbox/wind/app"
@@ -24,6 +25,9 @@ type ChatServer struct {
 
 	// 每个用户一个的聊天消息通道
 	Receive map[string]chan ChatMessage
+
+	// 每个用户一个的已发送消息缓存，断线重连后据此补发
+	History map[string]*sse.RingBuffer
 }
 
 type ChatMessage struct {
@@ -59,6 +63,7 @@ func NewServer() (srv *ChatServer) {
 		BroadcastMessageC: make(chan ChatMessage),
 		DirectMessageC:    make(chan ChatMessage),
 		Receive:           make(map[string]chan ChatMessage),
+		History:           make(map[string]*sse.RingBuffer),
 	}
 
 	go srv.relay()
@@ -73,6 +78,7 @@ func (srv *ChatServer) CreateReceiveChannel() app.HandlerFunc {
 		if _, found := srv.Receive[username]; !found {
 			receive := make(chan ChatMessage, 100)
 			srv.Receive[username] = receive
+			srv.History[username] = sse.NewRingBuffer(100)
 		}
 		c.Next(ctx)
 	}
@@ -82,7 +88,15 @@ func (srv *ChatServer) ServerSentEvent(ctx context.Context, c *app.RequestContex
 	// 生产环境，应使用其他方式如 Authorization 获取用户的标识
 	username := c.Query("username")
 
-	stream := sse.NewStream(c)
+	stream := sse.NewStream(c, sse.WithReplayBuffer(srv.History[username]))
+	defer stream.Close()
+
+	// 客户端重连时会带上最后收到的事件编号，先补发其错过的消息
+	lastEventID := sse.GetLastEventID(c)
+	if err := stream.Replay(lastEventID); err != nil {
+		return
+	}
+
 	for msg := range srv.Receive[username] {
 		payload, err := json.Marshal(msg)
 		if err != nil {
@@ -92,6 +106,7 @@ func (srv *ChatServer) ServerSentEvent(ctx context.Context, c *app.RequestContex
 		wlog.CtxInfof(ctx, "收到消息：%+v", msg)
 		event := &sse.Event{
 			Event: msg.Type,
+			ID:    strconv.FormatInt(msg.Timestamp.UnixNano(), 10),
 			Data:  payload,
 		}
 		c.SetStatusCode(http.StatusOK)
@@ -0,0 +1,164 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/favbox/wind/protocol"
+)
+
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// Reader 从响应正文中逐个读取服务器发送的事件。
+//
+// 按 HTML 标准的事件流格式解析：支持 CRLF、LF 与 CR 混用的换行，忽略流首的 BOM 与注释行，
+// 多行 data 以 \n 拼接，含 NUL 的 id 被忽略。
+type Reader struct {
+	resp *protocol.Response
+	r    *bufio.Reader
+
+	line        []byte
+	started     bool // 是否已读过首行（BOM 仅出现在流首）
+	skipLF      bool // 上一行以 \r 结尾，若紧跟 \n 则一并视为换行
+	lastEventID string
+}
+
+// NewReader 创建读取 resp 正文事件的读取器。
+//
+// resp 为流式正文时边读边解析，否则解析已读取的完整正文。
+func NewReader(resp *protocol.Response) *Reader {
+	var body io.Reader
+	if resp.IsBodyStream() {
+		body = resp.BodyStream()
+	} else {
+		body = bytes.NewReader(resp.Body())
+	}
+	return &Reader{
+		resp: resp,
+		r:    bufio.NewReader(body),
+	}
+}
+
+// ReadEvent 读取下一个事件。
+//
+// 事件的 ID 为截至该事件最后收到的 id，未携带 id 的事件沿用之前的值。
+// 正文结束时返回 io.EOF，末尾未以空行结束的不完整事件将被丢弃。
+func (r *Reader) ReadEvent() (*Event, error) {
+	var (
+		event string
+		data  []byte
+		retry uint64
+	)
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// 空行：派发事件
+		if len(line) == 0 {
+			if data == nil {
+				event, retry = "", 0
+				continue
+			}
+			return &Event{
+				Event: event,
+				ID:    r.lastEventID,
+				Retry: retry,
+				Data:  data[:len(data)-1],
+			}, nil
+		}
+
+		// 注释行
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+		}
+
+		switch string(field) {
+		case "event":
+			event = string(value)
+		case "data":
+			data = append(data, value...)
+			data = append(data, '\n')
+		case "id":
+			if bytes.IndexByte(value, 0) < 0 {
+				r.lastEventID = string(value)
+			}
+		case "retry":
+			if n, ok := parseRetry(value); ok {
+				retry = n
+			}
+		}
+	}
+}
+
+// LastEventID 返回最后收到的事件 id，用于断线重连。
+func (r *Reader) LastEventID() string {
+	return r.lastEventID
+}
+
+// SetLastEventID 将最后收到的事件 id 回填到重连请求的 Last-Event-ID 请求头，尚未收到 id 时不设置。
+func (r *Reader) SetLastEventID(req *protocol.Request) {
+	if r.lastEventID != "" {
+		req.SetHeader(LastEventID, r.lastEventID)
+	}
+}
+
+// Close 关闭响应的正文流。
+func (r *Reader) Close() error {
+	return r.resp.CloseBodyStream()
+}
+
+// 读取一行，返回的切片在下次读取前有效。
+func (r *Reader) readLine() ([]byte, error) {
+	r.line = r.line[:0]
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			// 未以换行结束的末行不构成完整的行，一并丢弃
+			return nil, err
+		}
+		if r.skipLF {
+			r.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		if b == '\r' || b == '\n' {
+			r.skipLF = b == '\r'
+			break
+		}
+		r.line = append(r.line, b)
+	}
+
+	line := r.line
+	if !r.started {
+		r.started = true
+		line = bytes.TrimPrefix(line, bom)
+	}
+	return line, nil
+}
+
+// 解析 retry 字段，仅由 ASCII 数字组成时有效。
+func parseRetry(value []byte) (uint64, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseUint(string(value), 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,127 @@
+package sse
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStreamResponse(body string) *protocol.Response {
+	resp := &protocol.Response{}
+	resp.SetBodyStream(strings.NewReader(body), -1)
+	return resp
+}
+
+func readAll(t *testing.T, r *Reader) []*Event {
+	var events []*Event
+	for {
+		e, err := r.ReadEvent()
+		if err == io.EOF {
+			return events
+		}
+		assert.Nil(t, err)
+		events = append(events, e)
+	}
+}
+
+func TestReaderReadEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []*Event
+	}{
+		{
+			name: "单个事件",
+			body: "event: greet\nid: 1\nretry: 3000\ndata: hello\n\n",
+			want: []*Event{{Event: "greet", ID: "1", Retry: 3000, Data: []byte("hello")}},
+		},
+		{
+			name: "多行数据",
+			body: "data: a\ndata:b\ndata\ndata:  c\n\n",
+			want: []*Event{{Data: []byte("a\nb\n\n c")}},
+		},
+		{
+			name: "BOM 与换行混用",
+			body: "\xEF\xBB\xBFdata: a\r\ndata: b\rdata: c\n\r\ndata: d\r\r",
+			want: []*Event{{Data: []byte("a\nb\nc")}, {Data: []byte("d")}},
+		},
+		{
+			name: "仅流首 BOM 被忽略",
+			body: "data: a\n\n\xEF\xBB\xBFdata: b\n\n",
+			want: []*Event{{Data: []byte("a")}},
+		},
+		{
+			name: "注释与未知字段",
+			body: ": ping\n\nfoo: bar\ndata: x\n\n",
+			want: []*Event{{Data: []byte("x")}},
+		},
+		{
+			name: "id 沿用且忽略含 NUL 的 id",
+			body: "id: 1\ndata: a\n\ndata: b\n\nid: 2\x003\ndata: c\n\nid\ndata: d\n\n",
+			want: []*Event{
+				{ID: "1", Data: []byte("a")},
+				{ID: "1", Data: []byte("b")},
+				{ID: "1", Data: []byte("c")},
+				{ID: "", Data: []byte("d")},
+			},
+		},
+		{
+			name: "无数据不派发且重置事件类型",
+			body: "event: a\n\ndata: x\n\n",
+			want: []*Event{{Data: []byte("x")}},
+		},
+		{
+			name: "非法 retry 被忽略",
+			body: "retry: 1s\ndata: x\n\nretry: -1\ndata: y\n\n",
+			want: []*Event{{Data: []byte("x")}, {Data: []byte("y")}},
+		},
+		{
+			name: "丢弃不完整的末尾事件",
+			body: "data: a\n\ndata: b\n",
+			want: []*Event{{Data: []byte("a")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(newStreamResponse(tt.body))
+			assert.Equal(t, tt.want, readAll(t, r))
+			assert.Nil(t, r.Close())
+		})
+	}
+}
+
+func TestReaderEncodeRoundTrip(t *testing.T) {
+	events := []*Event{
+		{Event: "a", ID: "1", Retry: 10, Data: []byte("x\ny")},
+		{ID: "2", Data: []byte("z")},
+	}
+	var b bytes.Buffer
+	for _, e := range events {
+		assert.Nil(t, Encode(&b, e))
+	}
+	assert.Nil(t, EncodeComment(&b, "keep-alive"))
+
+	resp := &protocol.Response{}
+	resp.SetBody(b.Bytes())
+	assert.Equal(t, events, readAll(t, NewReader(resp)))
+}
+
+func TestReaderSetLastEventID(t *testing.T) {
+	r := NewReader(newStreamResponse("data: a\n\nid: 42\ndata: b\n\n"))
+
+	req := &protocol.Request{}
+	_, err := r.ReadEvent()
+	assert.Nil(t, err)
+	r.SetLastEventID(req)
+	assert.Equal(t, "", req.Header.Get(LastEventID))
+
+	_, err = r.ReadEvent()
+	assert.Nil(t, err)
+	assert.Equal(t, "42", r.LastEventID())
+	r.SetLastEventID(req)
+	assert.Equal(t, "42", req.Header.Get(LastEventID))
+}
@@ -0,0 +1,101 @@
+package sse
+
+import (
+	"strconv"
+	"sync"
+)
+
+// RingBuffer 是定长的事件缓存，写满后覆盖最早的事件，用于按 Last-Event-ID 回放断线期间错过的事件。
+//
+// 并发安全，可在多个协程中同时写入与回放。
+type RingBuffer struct {
+	mu     sync.RWMutex
+	events []*Event
+	head   int // 最早事件的下标
+	size   int
+}
+
+// NewRingBuffer 创建容量为 capacity 的事件缓存，capacity <= 0 表示不启用，不缓存任何事件。
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &RingBuffer{events: make([]*Event, capacity)}
+}
+
+// Add 缓存事件 e，缓存已满时覆盖最早的事件。
+//
+// 缓存持有 e 本身，加入后不应再修改。
+func (b *RingBuffer) Add(e *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) == 0 {
+		return
+	}
+	if b.size < len(b.events) {
+		b.events[(b.head+b.size)%len(b.events)] = e
+		b.size++
+		return
+	}
+	b.events[b.head] = e
+	b.head = (b.head + 1) % len(b.events)
+}
+
+// Len 返回已缓存的事件数。
+func (b *RingBuffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.size
+}
+
+// Since 按插入顺序返回 id 在 lastID 之后的事件，lastID 为空时返回 nil。
+//
+// 未设置 id 的事件沿用其前一事件的 id。两个 id 都是整数时按数值比较，返回更大者；
+// 否则按插入顺序，返回最后一个 id 等于 lastID 的事件之后的事件，若缓存中找不到 lastID，
+// 说明其已被覆盖或未知，则返回这些事件全部。
+func (b *RingBuffer) Since(lastID string) []*Event {
+	if lastID == "" {
+		return nil
+	}
+
+	b.mu.RLock()
+	events := make([]*Event, b.size)
+	for i := range events {
+		events[i] = b.events[(b.head+i)%len(b.events)]
+	}
+	b.mu.RUnlock()
+
+	ids := make([]string, len(events))
+	pos := -1
+	var id string
+	for i, e := range events {
+		if e.ID != "" {
+			id = e.ID
+		}
+		ids[i] = id
+		if id == lastID {
+			pos = i
+		}
+	}
+
+	last, lastNumeric := parseEventID(lastID)
+	var replay []*Event
+	for i, e := range events {
+		if n, ok := parseEventID(ids[i]); ok && lastNumeric {
+			if n > last {
+				replay = append(replay, e)
+			}
+			continue
+		}
+		if i > pos {
+			replay = append(replay, e)
+		}
+	}
+	return replay
+}
+
+// 解析整数形式的事件 id。
+func parseEventID(id string) (int64, bool) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,105 @@
+package sse
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func eventIDs(events []*Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID+"/"+string(e.Data))
+	}
+	return ids
+}
+
+func TestRingBufferSince(t *testing.T) {
+	b := NewRingBuffer(3)
+	for i := 1; i <= 5; i++ {
+		b.Add(&Event{ID: strconv.Itoa(i), Data: []byte("d" + strconv.Itoa(i))})
+	}
+	assert.Equal(t, 3, b.Len())
+
+	tests := []struct {
+		lastID string
+		want   []string
+	}{
+		{"", []string{}},
+		{"0", []string{"3/d3", "4/d4", "5/d5"}},
+		{"3", []string{"4/d4", "5/d5"}},
+		{"5", []string{}},
+		{"9", []string{}},
+		{"unknown", []string{"3/d3", "4/d4", "5/d5"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, eventIDs(b.Since(tt.lastID)), tt.lastID)
+	}
+}
+
+func TestRingBufferSinceInsertionOrder(t *testing.T) {
+	b := NewRingBuffer(10)
+	b.Add(&Event{ID: "b", Data: []byte("1")})
+	b.Add(&Event{ID: "a", Data: []byte("2")})
+	b.Add(&Event{Data: []byte("3")})
+	b.Add(&Event{ID: "c", Data: []byte("4")})
+
+	assert.Equal(t, []string{"a/2", "/3", "c/4"}, eventIDs(b.Since("b")))
+	// 未设置 id 的事件沿用前一事件的 id
+	assert.Equal(t, []string{"c/4"}, eventIDs(b.Since("a")))
+	assert.Equal(t, []string{}, eventIDs(b.Since("c")))
+}
+
+func TestRingBufferDisabled(t *testing.T) {
+	b := NewRingBuffer(0)
+	b.Add(&Event{ID: "1"})
+	assert.Equal(t, 0, b.Len())
+	assert.Nil(t, b.Since("0"))
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+	b := NewRingBuffer(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Add(&Event{ID: strconv.Itoa(j)})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				assert.True(t, len(b.Since("0")) <= 16)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 16, b.Len())
+}
+
+func TestStreamReplay(t *testing.T) {
+	b := NewRingBuffer(10)
+	w := &mockWriter{}
+	s := &Stream{w: w, buffer: b}
+	for i := 1; i <= 3; i++ {
+		assert.Nil(t, s.Publish(&Event{ID: strconv.Itoa(i), Data: []byte("x")}))
+	}
+	assert.Equal(t, 3, b.Len())
+
+	// 重连后补发 id 在 1 之后的事件
+	w = &mockWriter{}
+	s = &Stream{w: w, buffer: b}
+	assert.Nil(t, s.Replay("1"))
+	assert.Equal(t, "id:2\ndata:x\n\nid:3\ndata:x\n\n", w.String())
+	assert.Equal(t, 3, b.Len())
+
+	// 未设置缓存时不补发
+	w = &mockWriter{}
+	s = &Stream{w: w}
+	assert.Nil(t, s.Replay("1"))
+	assert.Equal(t, "", w.String())
+}
@@ -1,6 +1,11 @@
 package sse
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
 	"github.com/favbox/wind/app"
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/protocol/http1/resp"
@@ -13,6 +18,9 @@ const (
 	LastEventID  = "Last-Event-ID"
 )
 
+// ErrStreamClosed 表示事件流已关闭。
+var ErrStreamClosed = errors.New("sse: 事件流已关闭")
+
 type Event struct {
 	Event string
 	ID    string
@@ -27,26 +35,203 @@ func GetLastEventID(c *app.RequestContext) string {
 
 type Stream struct {
 	w network.ExtWriter
+
+	ctx      context.Context
+	finished <-chan struct{}
+
+	buffer *RingBuffer // 已发布事件的缓存，用于回放
+
+	mu        sync.Mutex // 串行化写入，心跳与事件可能并发发送
+	closed    bool
+	keepAlive chan struct{} // 关闭以停止当前心跳协程
+}
+
+// StreamOption 自定义流的选项。
+type StreamOption func(s *Stream)
+
+// WithReplayBuffer 设置事件缓存，发布的事件将写入 b，供重连后经 Stream.Replay 回放。
+//
+// b 通常按订阅者跨连接复用，为空则不缓存。
+func WithReplayBuffer(b *RingBuffer) StreamOption {
+	return func(s *Stream) {
+		s.buffer = b
+	}
 }
 
 // NewStream 为指定上下文发布事件创建一个新的流。
 // 底层本质是劫持响应编写器。
-func NewStream(c *app.RequestContext) *Stream {
+func NewStream(c *app.RequestContext, opts ...StreamOption) *Stream {
+	return NewStreamWithContext(context.Background(), c, opts...)
+}
+
+// NewStreamWithContext 同 NewStream，ctx 取消时自动停止心跳。
+func NewStreamWithContext(ctx context.Context, c *app.RequestContext, opts ...StreamOption) *Stream {
 	c.Response.Header.SetContentType(ContentType)
 	if c.Response.Header.Get(cacheControl) == "" {
 		c.Response.Header.Set(cacheControl, noCache)
 	}
 
 	writer := resp.NewChunkedBodyWriter(&c.Response, c.GetWriter())
-	c.Response.HijackWriter(writer)
-	return &Stream{writer}
+	s := &Stream{
+		w:        writer,
+		ctx:      ctx,
+		finished: c.Finished(),
+	}
+	c.Response.HijackWriter(&finalizeWriter{ExtWriter: writer, s: s})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// 被劫持的响应写入器，框架写出末尾分块时先关闭流，以免心跳等后续写入混入连接或复用的上下文。
+type finalizeWriter struct {
+	network.ExtWriter
+	s *Stream
 }
 
-// Publish 发布事件至客户端。
+func (w *finalizeWriter) Finalize() error {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+	w.s.stopKeepAlive()
+	w.s.closed = true
+	return w.ExtWriter.Finalize()
+}
+
+// Publish 发布事件至客户端，若设置了事件缓存则同时缓存该事件。
 func (s *Stream) Publish(event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	if s.buffer != nil {
+		s.buffer.Add(event)
+	}
 	err := Encode(s.w, event)
 	if err != nil {
 		return err
 	}
 	return s.w.Flush()
 }
+
+// Replay 按序补发缓存中 id 在 lastID 之后的事件，应在发布新事件前调用。
+//
+// id 的比较规则见 RingBuffer.Since。未设置事件缓存或 lastID 为空时不补发。
+func (s *Stream) Replay(lastID string) error {
+	if s.buffer == nil {
+		return nil
+	}
+	events := s.buffer.Since(lastID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := Encode(s.w, event); err != nil {
+			return err
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return s.w.Flush()
+}
+
+// Comment 发送注释行至客户端，客户端会忽略注释，常用于保持连接活跃。
+func (s *Stream) Comment(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkWritable(); err != nil {
+		return err
+	}
+
+	err := EncodeComment(s.w, text)
+	if err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// SetKeepAlive 在后台每隔 interval 发送一次心跳注释，以免空闲连接被中间代理超时断开。
+//
+// 重复调用会替换之前的心跳，interval <= 0 则停止心跳。
+// 心跳协程在 ctx 取消、请求结束、发送失败或调用 Close 时退出。
+// 处理器返回后框架写出末尾分块时流随之关闭，心跳不会再写入连接。
+func (s *Stream) SetKeepAlive(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopKeepAlive()
+	if s.closed || interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	s.keepAlive = stop
+	go s.runKeepAlive(interval, stop)
+}
+
+func (s *Stream) runKeepAlive(interval time.Duration, stop <-chan struct{}) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if err := s.Comment(""); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-s.finished:
+			return
+		}
+	}
+}
+
+// Close 停止心跳并关闭流，之后的发送均返回 ErrStreamClosed。
+//
+// Close 不关闭底层连接，可多次调用。
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopKeepAlive()
+	s.closed = true
+	return nil
+}
+
+// 停止当前心跳协程，调用方须持有 s.mu。
+func (s *Stream) stopKeepAlive() {
+	if s.keepAlive != nil {
+		close(s.keepAlive)
+		s.keepAlive = nil
+	}
+}
+
+// 流已关闭或请求已结束时返回 ErrStreamClosed，调用方须持有 s.mu。
+func (s *Stream) checkWritable() error {
+	if s.closed {
+		return ErrStreamClosed
+	}
+	select {
+	case <-s.finished:
+		s.closed = true
+		return ErrStreamClosed
+	default:
+		return nil
+	}
+}
@@ -1 +1,173 @@
 package sse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	flushes int
+	err     error
+}
+
+func (w *mockWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+func (w *mockWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushes++
+	return w.err
+}
+
+func (w *mockWriter) Finalize() error { return nil }
+
+func (w *mockWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func (w *mockWriter) setErr(err error) {
+	w.mu.Lock()
+	w.err = err
+	w.mu.Unlock()
+}
+
+func TestEncodeComment(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ":\n\n"},
+		{"ping", ": ping\n\n"},
+		{"a\nb", ": a\n: b\n\n"},
+		{"a\r\nb\rc", ": a\n: b\n: c\n\n"},
+		{"a\n\nb", ": a\n:\n: b\n\n"},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		assert.Nil(t, EncodeComment(&b, tt.text))
+		assert.Equal(t, tt.want, b.String())
+	}
+}
+
+func TestStreamComment(t *testing.T) {
+	w := &mockWriter{}
+	s := &Stream{w: w}
+	assert.Nil(t, s.Comment("hello"))
+	assert.Equal(t, ": hello\n\n", w.String())
+	assert.Equal(t, 1, w.flushes)
+
+	assert.Nil(t, s.Close())
+	assert.Equal(t, ErrStreamClosed, s.Comment("hello"))
+	assert.Equal(t, ErrStreamClosed, s.Publish(&Event{Data: []byte("x")}))
+}
+
+func TestStreamKeepAlive(t *testing.T) {
+	w := &mockWriter{}
+	s := &Stream{w: w}
+	s.SetKeepAlive(10 * time.Millisecond)
+	time.Sleep(55 * time.Millisecond)
+	assert.Nil(t, s.Close())
+
+	n := strings.Count(w.String(), ":\n\n")
+	assert.True(t, n >= 3 && n <= 6, "心跳次数 %d", n)
+
+	// 关闭后不再发送
+	got := w.String()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, got, w.String())
+}
+
+func TestStreamKeepAliveExit(t *testing.T) {
+	// ctx 取消
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &mockWriter{}
+	s := &Stream{w: w, ctx: ctx}
+	s.SetKeepAlive(10 * time.Millisecond)
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+	got := w.String()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, got, w.String())
+
+	// 请求结束
+	finished := make(chan struct{})
+	w = &mockWriter{}
+	s = &Stream{w: w, finished: finished}
+	s.SetKeepAlive(10 * time.Millisecond)
+	close(finished)
+	time.Sleep(30 * time.Millisecond)
+	got = w.String()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, got, w.String())
+
+	// 连接写入失败
+	w = &mockWriter{}
+	w.setErr(errors.New("broken pipe"))
+	s = &Stream{w: w}
+	s.SetKeepAlive(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	w.mu.Lock()
+	flushes := w.flushes
+	w.mu.Unlock()
+	assert.Equal(t, 0, flushes)
+
+	// interval <= 0 停止心跳
+	w = &mockWriter{}
+	s = &Stream{w: w}
+	s.SetKeepAlive(10 * time.Millisecond)
+	s.SetKeepAlive(0)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, "", w.String())
+}
+
+func TestStreamFinalize(t *testing.T) {
+	conn := mock.NewConn("")
+	c := app.NewContext(0)
+	c.SetConn(conn)
+	s := NewStream(c)
+	assert.Nil(t, s.Publish(&Event{Data: []byte("x")}))
+	s.SetKeepAlive(time.Millisecond)
+
+	// 处理器未调用 Close 即返回，框架写出末尾分块后流随之关闭
+	assert.Nil(t, c.Response.GetHijackWriter().Finalize())
+	assert.Nil(t, conn.Flush()) // 与服务端一致，末尾分块由框架随连接刷新
+	assert.Equal(t, ErrStreamClosed, s.Publish(&Event{Data: []byte("y")}))
+	assert.Equal(t, ErrStreamClosed, s.Comment(""))
+
+	recorder := conn.WriterRecorder()
+	n := recorder.WroteLen()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, recorder.WroteLen())
+	out, _ := recorder.Peek(n)
+	assert.True(t, strings.HasSuffix(string(out), "0\r\n\r\n"), "末尾分块后不应再有写入：%q", out)
+}
+
+func TestStreamFinished(t *testing.T) {
+	finished := make(chan struct{})
+	w := &mockWriter{}
+	s := &Stream{w: w, finished: finished}
+	close(finished)
+	assert.Equal(t, ErrStreamClosed, s.Comment(""))
+	assert.Equal(t, ErrStreamClosed, s.Publish(&Event{Data: []byte("x")}))
+	assert.Equal(t, "", w.String())
+}
@@ -79,9 +79,12 @@ var (
 	StrCookieSameSiteLax    = []byte("Lax")
 	StrCookieSameSiteStrict = []byte("Strict")
 	StrCookieSameSiteNone   = []byte("None")
+	StrCookiePartitioned    = []byte("Partitioned")
 
 	StrClose               = []byte("close")
 	StrGzip                = []byte("gzip")
+	StrBr                  = []byte("br")
+	StrZstd                = []byte("zstd")
 	StrDeflate             = []byte("deflate")
 	StrKeepAlive           = []byte("keep-alive") // 用于指明连接为保活的长连接
 	StrUpgrade             = []byte("Upgrade")
@@ -64,6 +64,19 @@ type Conn interface {
 	SetWriteTimeout(t time.Duration) error
 }
 
+// ConnWrapper 表示连接的包装函数，用于在连接上叠加限速、统计、审计等横切逻辑。
+//
+// 包装后的连接若需保留 TLS 能力（如 ALPN 协商），应同时实现 ConnTLSer。
+type ConnWrapper func(conn Conn) Conn
+
+// WrapConn 按顺序用 wrappers 包装 conn，先注册的包装在内层。
+func WrapConn(conn Conn, wrappers ...ConnWrapper) Conn {
+	for _, wrap := range wrappers {
+		conn = wrap(conn)
+	}
+	return conn
+}
+
 // ConnTLSer 表示安全读写的连接。
 type ConnTLSer interface {
 	Handshake() error
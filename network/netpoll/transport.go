@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cloudwego/netpoll"
@@ -27,6 +28,8 @@ type transport struct {
 	keepAliveTimeout time.Duration
 	readTimeout      time.Duration
 	writeTimeout     time.Duration
+	sockReadBuffer   int
+	sockWriteBuffer  int
 	listener         net.Listener
 	eventLoop        netpoll.EventLoop
 	listenConfig     *net.ListenConfig
@@ -51,7 +54,8 @@ func (t *transport) ListenAndServe(onReq network.OnData) (err error) {
 	opts := []netpoll.Option{
 		netpoll.WithIdleTimeout(t.keepAliveTimeout),
 		netpoll.WithOnPrepare(func(conn netpoll.Connection) context.Context {
-			// 设置准备期间的读写超时
+			// 设置准备期间的读写缓冲及超时
+			setSockBuffer(conn, t.sockReadBuffer, t.sockWriteBuffer)
 			_ = conn.SetReadTimeout(t.readTimeout)
 			if t.writeTimeout > 0 {
 				_ = conn.SetWriteTimeout(t.writeTimeout)
@@ -113,6 +117,20 @@ func (t *transport) Shutdown(ctx context.Context) error {
 	return t.eventLoop.Shutdown(ctx)
 }
 
+// 设置内核套接字的接收与发送缓冲，大小为 0 时沿用系统设置。
+func setSockBuffer(conn netpoll.Connection, readSize, writeSize int) {
+	c, ok := conn.(interface{ Fd() int })
+	if !ok {
+		return
+	}
+	if readSize > 0 {
+		_ = syscall.SetsockoptInt(c.Fd(), syscall.SOL_SOCKET, syscall.SO_RCVBUF, readSize)
+	}
+	if writeSize > 0 {
+		_ = syscall.SetsockoptInt(c.Fd(), syscall.SOL_SOCKET, syscall.SO_SNDBUF, writeSize)
+	}
+}
+
 // NewTransporter 创建 netpoll 网络传输器。
 func NewTransporter(options *config.Options) network.Transporter {
 	return &transport{
@@ -122,6 +140,8 @@ func NewTransporter(options *config.Options) network.Transporter {
 		keepAliveTimeout: options.KeepAliveTimeout,
 		readTimeout:      options.ReadTimeout,
 		writeTimeout:     options.WriteTimeout,
+		sockReadBuffer:   options.SocketReadBufferSize,
+		sockWriteBuffer:  options.SocketWriteBufferSize,
 		listener:         nil,
 		eventLoop:        nil,
 		listenConfig:     options.ListenConfig,
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudwego/netpoll"
 	"github.com/favbox/wind/common/config"
 	"github.com/favbox/wind/network"
 	"github.com/stretchr/testify/assert"
@@ -87,3 +88,30 @@ func TestTransport(t *testing.T) {
 		})
 	})
 }
+
+func TestSetSockBuffer(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	conn, err := netpoll.DialConnection("tcp", ln.Addr().String(), time.Second)
+	assert.Nil(t, err)
+	defer conn.Close()
+	fd := conn.(interface{ Fd() int }).Fd()
+
+	rcv, _ := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+	snd, _ := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+
+	// 大小为 0 时不改动内核缓冲
+	setSockBuffer(conn, 0, 0)
+	v, _ := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+	assert.Equal(t, rcv, v)
+	v, _ = syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+	assert.Equal(t, snd, v)
+
+	setSockBuffer(conn, 256*1024, 8*1024)
+	v, _ = syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+	assert.GreaterOrEqual(t, v, 256*1024)
+	v, _ = syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+	assert.GreaterOrEqual(t, v, 8*1024)
+}
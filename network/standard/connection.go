@@ -30,6 +30,7 @@ type Conn struct {
 	outputBuffer *linkBuffer
 	caches       [][]byte // 跨包时由 Next 分配，不用时要释放
 	maxSize      int      // 历史最大 malloc 大小
+	writeSize    int      // 写缓冲节点的最小分配大小
 
 	err error
 }
@@ -340,8 +341,8 @@ func (c *Conn) Malloc(n int) (buf []byte, err error) {
 	}
 
 	mallocSize := n
-	if n < defaultMallocSize {
-		mallocSize = defaultMallocSize
+	if n < c.writeSize {
+		mallocSize = c.writeSize
 	}
 	node := newBufferNode(mallocSize)
 	node.malloc = n
@@ -546,10 +547,13 @@ func min(a, b int) int {
 	return b
 }
 
-func newConn(c net.Conn, size int) network.Conn {
+func newConn(c net.Conn, readSize, writeSize int) network.Conn {
 	maxSize := defaultMallocSize
-	if size > maxSize {
-		maxSize = size
+	if readSize > maxSize {
+		maxSize = readSize
+	}
+	if writeSize <= 0 {
+		writeSize = defaultMallocSize
 	}
 
 	node := newBufferNode(maxSize)
@@ -572,13 +576,17 @@ func newConn(c net.Conn, size int) network.Conn {
 		inputBuffer:  inputBuffer,
 		outputBuffer: outputBuffer,
 		maxSize:      maxSize,
+		writeSize:    writeSize,
 	}
 }
 
-func newTLSConn(c net.Conn, size int) network.Conn {
+func newTLSConn(c net.Conn, readSize, writeSize int) network.Conn {
 	maxSize := defaultMallocSize
-	if size > maxSize {
-		maxSize = size
+	if readSize > maxSize {
+		maxSize = readSize
+	}
+	if writeSize <= 0 {
+		writeSize = defaultMallocSize
 	}
 
 	node := newBufferNode(maxSize)
@@ -602,6 +610,7 @@ func newTLSConn(c net.Conn, size int) network.Conn {
 			inputBuffer:  inputBuffer,
 			outputBuffer: outputBuffer,
 			maxSize:      maxSize,
+			writeSize:    writeSize,
 		},
 	}
 }
@@ -115,7 +115,7 @@ func mockLinkBufferNodeRelease(b *linkBufferNode) {
 
 func TestRead(t *testing.T) {
 	c := mockConn{}
-	conn := newConn(&c, 4096)
+	conn := newConn(&c, 4096, 4096)
 	// test read small data
 	b := make([]byte, 1)
 	conn.Read(b)
@@ -149,7 +149,7 @@ func TestReadFromHasBufferAvailable(t *testing.T) {
 	tailData := []byte("tail data")
 	data := strings.NewReader(rawData)
 	c := &mockConn{}
-	conn := newConn(c, 4096)
+	conn := newConn(c, 4096, 4096)
 
 	// WriteBinary will malloc a buffer if no buffer available.
 	_, err0 := conn.WriteBinary(preData)
@@ -175,7 +175,7 @@ func TestReadFromNoBufferAvailable(t *testing.T) {
 	tailData := []byte("tail data")
 	data := strings.NewReader(rawData)
 	c := &mockConn{}
-	conn := newConn(c, 4096)
+	conn := newConn(c, 4096, 4096)
 	reader, ok := conn.(io.ReaderFrom)
 	assert.True(t, ok)
 
@@ -194,7 +194,7 @@ func TestReadFromNoBufferAvailable(t *testing.T) {
 
 func TestPeekRelease(t *testing.T) {
 	c := mockConn{}
-	conn := newConn(&c, 4096)
+	conn := newConn(&c, 4096, 4096)
 	b, _ := conn.Peek(1)
 	if len(b) != 1 {
 		t.Errorf("unexpected len(b): %v, expected 1", len(b))
@@ -243,7 +243,7 @@ func TestPeekRelease(t *testing.T) {
 
 func TestReadBytes(t *testing.T) {
 	c := mockConn{}
-	conn := newConn(&c, 4096)
+	conn := newConn(&c, 4096, 4096)
 	b, _ := conn.Peek(1)
 	if len(b) != 1 {
 		t.Errorf("unexpected len(b): %v, expected 1", len(b))
@@ -276,7 +276,7 @@ func TestReadBytes(t *testing.T) {
 
 func TestWriteLogic(t *testing.T) {
 	c := mockConn{}
-	conn := newConn(&c, 4096)
+	conn := newConn(&c, 4096, 4096)
 	conn.Malloc(8190)
 	connection := conn.(*Conn)
 	// test left buffer
@@ -317,6 +317,22 @@ func TestWriteLogic(t *testing.T) {
 	}
 }
 
+func TestWriteBufferSize(t *testing.T) {
+	c := mockConn{}
+	conn := newConn(&c, 4096, 16*1024).(*Conn)
+	conn.Malloc(10)
+	if cap(conn.outputBuffer.write.buf) != 16*1024 {
+		t.Errorf("unexpected cap: %v, expected %v", cap(conn.outputBuffer.write.buf), 16*1024)
+	}
+
+	// 非法值回退到默认大小
+	conn = newConn(&c, 4096, 0).(*Conn)
+	conn.Malloc(10)
+	if cap(conn.outputBuffer.write.buf) != defaultMallocSize {
+		t.Errorf("unexpected cap: %v, expected %v", cap(conn.outputBuffer.write.buf), defaultMallocSize)
+	}
+}
+
 func TestInitializeConn(t *testing.T) {
 	c := mockConn{
 		localAddr: &mockAddr{
@@ -328,7 +344,7 @@ func TestInitializeConn(t *testing.T) {
 			address: "192.168.0.20:80",
 		},
 	}
-	conn := newConn(&c, 8192)
+	conn := newConn(&c, 8192, 8192)
 	// check the assignment
 	assert.Equal(t, errors.New("conn: write deadline not supported"), conn.SetDeadline(time.Time{}))
 	assert.Equal(t, errors.New("conn: read deadline not supported"), conn.SetReadDeadline(time.Time{}))
@@ -342,7 +358,7 @@ func TestInitializeConn(t *testing.T) {
 
 func TestInitializeTLSConn(t *testing.T) {
 	c := mockConn{}
-	tlsConn := newTLSConn(&c, 8192).(*TLSConn)
+	tlsConn := newTLSConn(&c, 8192, 8192).(*TLSConn)
 	assert.Equal(t, errors.New("conn: method not supported"), tlsConn.Handshake())
 	assert.Equal(t, tls.ConnectionState{}, tlsConn.ConnectionState())
 }
@@ -360,7 +376,7 @@ func TestConnSetFinalizer(t *testing.T) {
 	Mock((*linkBufferNode).Release).To(mockLinkBufferNodeRelease).Build()
 
 	atomic.StoreUint32(&releaseCount, 0)
-	_ = newConn(&mockConn{}, 4096)
+	_ = newConn(&mockConn{}, 4096, 4096)
 
 	runtime.GC()
 	time.Sleep(time.Millisecond * 100)
@@ -372,7 +388,7 @@ func TestFillReturnErrAndN(t *testing.T) {
 	c := &mockConn{
 		readReturnErr: true,
 	}
-	conn := newConn(c, 4099)
+	conn := newConn(c, 4099, 4099)
 	b, err := conn.Peek(4099)
 	assert.Nil(t, err)
 	assert.Equal(t, len(b), 4099)
@@ -0,0 +1,40 @@
+package standard
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 仅统计写调用次数的连接。
+type countWriteConn struct {
+	mockConn
+	writes int
+}
+
+func (c *countWriteConn) Write(b []byte) (int, error) {
+	c.writes++
+	return len(b), nil
+}
+
+// BenchmarkConnWriteBufferSize 对比不同写缓冲大小下输出 64KB 响应的开销。
+// 缓冲越大节点越少，需要的写调用越少；缓冲越小则单连接常驻内存越省。
+func BenchmarkConnWriteBufferSize(b *testing.B) {
+	chunk := make([]byte, 512)
+	for _, size := range []int{block1k, block4k, 16 * block1k, 64 * block1k} {
+		b.Run(fmt.Sprintf("%dKB", size/block1k), func(b *testing.B) {
+			c := &countWriteConn{}
+			conn := newConn(c, block4k, size)
+			b.SetBytes(64 * block1k)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < 64*block1k/len(chunk); j++ {
+					buf, _ := conn.Malloc(len(chunk))
+					copy(buf, chunk)
+				}
+				_ = conn.Flush()
+			}
+			b.ReportMetric(float64(c.writes)/float64(b.N), "writes/op")
+		})
+	}
+}
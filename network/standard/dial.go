@@ -14,10 +14,10 @@ func (d *dialer) DialConnection(network, address string, timeout time.Duration,
 	c, err := net.DialTimeout(network, address, timeout)
 	if tlsConfig != nil {
 		cTLS := tls.Client(c, tlsConfig)
-		conn = newTLSConn(cTLS, defaultMallocSize)
+		conn = newTLSConn(cTLS, defaultMallocSize, defaultMallocSize)
 		return
 	}
-	conn = newConn(c, defaultMallocSize)
+	conn = newConn(c, defaultMallocSize, defaultMallocSize)
 	return
 }
 
@@ -32,7 +32,7 @@ func (d *dialer) AddTLS(conn network.Conn, tlsConfig *tls.Config) (network.Conn,
 	if err != nil {
 		return nil, err
 	}
-	conn = newTLSConn(cTLS, defaultMallocSize)
+	conn = newTLSConn(cTLS, defaultMallocSize, defaultMallocSize)
 	return conn, nil
 }
 
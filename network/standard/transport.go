@@ -21,6 +21,9 @@ type transport struct {
 	//
 	// 若未设置则使用默认缓冲大小。
 	readBufferSize   int
+	writeBufferSize  int
+	sockReadBuffer   int // 内核套接字接收缓冲大小，0 表示沿用系统设置
+	sockWriteBuffer  int // 内核套接字发送缓冲大小，0 表示沿用系统设置
 	network          string
 	addr             string
 	keepAliveTimeout time.Duration
@@ -80,15 +83,16 @@ func (t *transport) serve() (err error) {
 			return err
 		}
 
+		setSockBuffer(conn, t.sockReadBuffer, t.sockWriteBuffer)
 		if t.OnAccept != nil {
 			ctx = t.OnAccept(conn)
 		}
 
 		var c network.Conn
 		if t.tls != nil {
-			c = newTLSConn(tls.Server(conn, t.tls), t.readBufferSize)
+			c = newTLSConn(tls.Server(conn, t.tls), t.readBufferSize, t.writeBufferSize)
 		} else {
-			c = newConn(conn, t.readBufferSize)
+			c = newConn(conn, t.readBufferSize, t.writeBufferSize)
 		}
 
 		if t.OnConnect != nil {
@@ -98,10 +102,30 @@ func (t *transport) serve() (err error) {
 	}
 }
 
+// 设置内核套接字的接收与发送缓冲，大小为 0 时沿用系统设置。
+func setSockBuffer(conn net.Conn, readSize, writeSize int) {
+	c, ok := conn.(interface {
+		SetReadBuffer(bytes int) error
+		SetWriteBuffer(bytes int) error
+	})
+	if !ok {
+		return
+	}
+	if readSize > 0 {
+		_ = c.SetReadBuffer(readSize)
+	}
+	if writeSize > 0 {
+		_ = c.SetWriteBuffer(writeSize)
+	}
+}
+
 // NewTransporter 创建标准库网络传输器。
 func NewTransporter(options *config.Options) network.Transporter {
 	return &transport{
 		readBufferSize:   options.ReadBufferSize,
+		writeBufferSize:  options.WriteBufferSize,
+		sockReadBuffer:   options.SocketReadBufferSize,
+		sockWriteBuffer:  options.SocketWriteBufferSize,
 		network:          options.Network,
 		addr:             options.Addr,
 		keepAliveTimeout: options.KeepAliveTimeout,
@@ -263,6 +263,16 @@ func peekAllArgBytesToDst(dst [][]byte, h []argsKV, k []byte) [][]byte {
 	return dst
 }
 
+// 统计键为 k 的参数个数。
+func countArgBytes(h []argsKV, k []byte) (n int) {
+	for i := range h {
+		if bytes.Equal(h[i].key, k) {
+			n++
+		}
+	}
+	return
+}
+
 // 释放切片中的最后一个参数
 func releaseArg(args []argsKV) []argsKV {
 	return args[:len(args)-1]
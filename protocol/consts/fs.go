@@ -8,9 +8,13 @@ const (
 
 	// FSCompressedFileSuffix 是 FS 另存压缩文件时追加到原始文件名的后缀。
 	// 详见 app.FS。
-	FSCompressedFileSuffix    = ".wind.gz"
-	FSMinCompressRatio        = 0.8
-	FsMaxCompressibleFileSize = 8 * 1024 * 1024 // 最大可压缩文件字节数
+	FSCompressedFileSuffix = ".wind.gz"
+	// FSBrotliCompressedFileSuffix 是 FS 另存 Brotli 压缩文件时追加到原始文件名的后缀。
+	FSBrotliCompressedFileSuffix = ".wind.br"
+	// FSZstdCompressedFileSuffix 是 FS 另存 Zstd 压缩文件时追加到原始文件名的后缀。
+	FSZstdCompressedFileSuffix = ".wind.zst"
+	FSMinCompressRatio         = 0.8
+	FsMaxCompressibleFileSize  = 8 * 1024 * 1024 // 最大可压缩文件字节数
 
 	// FSHandlerCacheDuration FS 打开的不活跃文件处理器的默认缓存时长。
 	FSHandlerCacheDuration = 10 * time.Second
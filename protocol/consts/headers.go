@@ -7,10 +7,19 @@ const (
 	HeaderLastModified    = "Last-Modified"
 
 	HeaderLocation = "Location" // 重定向
+	HeaderLink     = "Link"     // 关联资源，如预加载
 
 	HeaderVary = "Vary"
 )
 
+// 缓存类
+const (
+	HeaderCacheControl = "Cache-Control"
+	HeaderETag         = "ETag"
+	HeaderIfMatch      = "If-Match"
+	HeaderIfNoneMatch  = "If-None-Match"
+)
+
 // 传输编码类
 const (
 	HeaderTE               = "TE"
@@ -53,27 +62,35 @@ const (
 
 // 响应上下文类
 const (
-	HeaderAllow       = "Allow"
-	HeaderServer      = "Server"
-	HeaderServerLower = "server"
+	HeaderAllow        = "Allow"
+	HeaderRetryAfter   = "Retry-After"
+	HeaderServer       = "Server"
+	HeaderServerLower  = "server"
+	HeaderServerTiming = "Server-Timing"
 )
 
 // 请求上下文
 const (
-	HeaderFrom          = "From"
-	HeaderHost          = "Host"
-	HeaderReferer       = "Referer"
-	HeaderRefererPolicy = "Referer-Policy"
-	HeaderUserAgent     = "User-Agent"
+	HeaderFrom                = "From"
+	HeaderHost                = "Host"
+	HeaderReferer             = "Referer"
+	HeaderRefererPolicy       = "Referer-Policy"
+	HeaderUserAgent           = "User-Agent"
+	HeaderXHTTPMethodOverride = "X-HTTP-Method-Override"
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
 )
 
 // 正文信息
 const (
-	HeaderContentEncoding = "Content-Encoding"
-	HeaderContentLanguage = "Content-Language"
-	HeaderContentLength   = "Content-Length"
-	HeaderContentLocation = "Content-Location"
-	HeaderContentType     = "Content-Type"
+	HeaderContentDisposition = "Content-Disposition"
+	HeaderContentEncoding    = "Content-Encoding"
+	HeaderContentLanguage    = "Content-Language"
+	HeaderContentLength      = "Content-Length"
+	HeaderContentLocation    = "Content-Location"
+	HeaderContentType        = "Content-Type"
+	HeaderContentMD5         = "Content-MD5"
+	HeaderChecksumSHA256     = "X-Checksum-SHA256"
 )
 
 // 内容协商类
@@ -90,6 +107,7 @@ const (
 	HTTP11 = "HTTP/1.1"
 	HTTP10 = "HTTP/1.0"
 	HTTP20 = "HTTP/2.0"
+	HTTP30 = "HTTP/3.0"
 )
 
 // 文本类 MIME
@@ -104,6 +122,8 @@ const (
 	MIMETextCss               = "text/css"
 	MIMETextJavascript        = "text/javascript"
 	MIMETextEventStream       = "text/event-stream"
+	MIMETextXML               = "text/xml"
+	MIMETextYAML              = "text/yaml"
 	MIMEMultipartPOSTForm     = "multipart/form-data"
 )
 
@@ -132,6 +152,10 @@ const (
 	MIMEApplicationOpenXMLWord  = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
 	MIMEApplicationOpenXMLExcel = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	MIMEApplicationOpenXMLPPT   = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	MIMEApplicationYAML         = "application/yaml"
+	MIMEApplicationXYAML        = "application/x-yaml"
+	MIMEApplicationMsgPack      = "application/msgpack"
+	MIMEApplicationXMsgPack     = "application/x-msgpack"
 	MIMEPROTOBUF                = "application/x-protobuf"
 )
 
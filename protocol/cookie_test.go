@@ -286,6 +286,41 @@ func TestCookieHttpOnly(t *testing.T) {
 	}
 }
 
+func TestCookiePartitioned(t *testing.T) {
+	t.Parallel()
+
+	var c Cookie
+	c.SetKey("foo")
+	c.SetValue("bar")
+	c.SetPath("/")
+	c.SetSameSite(CookieSameSiteNoneMode)
+	c.SetPartitioned(true)
+	assert.True(t, c.Secure())
+	assert.Equal(t, "foo=bar; path=/; secure; SameSite=None; Partitioned", c.String())
+
+	// 经由响应头往返
+	var h ResponseHeader
+	h.SetCookie(&c)
+	parsed := AcquireCookie()
+	defer ReleaseCookie(parsed)
+	parsed.SetKey("foo")
+	assert.True(t, h.Cookie(parsed))
+	assert.True(t, parsed.Partitioned())
+	assert.True(t, parsed.Secure())
+	assert.Equal(t, CookieSameSiteNoneMode, parsed.SameSite())
+	assert.Equal(t, c.String(), parsed.String())
+
+	assert.Nil(t, c.Parse("foo=bar; Secure; partitioned"))
+	assert.True(t, c.Partitioned())
+	assert.Nil(t, c.Parse("foo=bar; secure"))
+	assert.False(t, c.Partitioned())
+	assert.NotContains(t, c.String(), "Partitioned")
+
+	c.SetPartitioned(true)
+	c.Reset()
+	assert.False(t, c.Partitioned())
+}
+
 func TestCookieParse(t *testing.T) {
 	t.Parallel()
 
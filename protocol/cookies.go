@@ -76,9 +76,10 @@ type Cookie struct {
 	domain []byte
 	path   []byte
 
-	httpOnly bool
-	secure   bool
-	sameSite CookieSameSite
+	httpOnly    bool
+	secure      bool
+	sameSite    CookieSameSite
+	partitioned bool // 是否为分区 Cookie（CHIPS）
 }
 
 // AppendBytes 附加到 dst 并返回。
@@ -123,6 +124,10 @@ func (c *Cookie) AppendBytes(dst []byte) []byte {
 	case CookieSameSiteNoneMode:
 		dst = appendCookiePart(dst, bytestr.StrCookieSameSite, bytestr.StrCookieSameSiteNone)
 	}
+	if c.partitioned {
+		dst = append(dst, ';', ' ')
+		dst = append(dst, bytestr.StrCookiePartitioned...)
+	}
 	return dst
 }
 
@@ -256,6 +261,22 @@ func (c *Cookie) SetSameSite(mode CookieSameSite) {
 	}
 }
 
+// Partitioned 返回 Cookie 是否为分区 Cookie。
+func (c *Cookie) Partitioned() bool {
+	return c.partitioned
+}
+
+// SetPartitioned 设置 Cookie 的 Partitioned 标识，使浏览器按顶级站点分区存储第三方 Cookie。
+//
+// 设为 true 也会将 Secure 设为 true，浏览器仅接受安全的分区 Cookie。
+// 详见 https://developer.mozilla.org/en-US/docs/Web/Privacy/Privacy_sandbox/Partitioned_cookies
+func (c *Cookie) SetPartitioned(partitioned bool) {
+	c.partitioned = partitioned
+	if partitioned {
+		c.SetSecure(true)
+	}
+}
+
 // 返回 Cookie 的字符串表达形式。
 //
 // 注：没有 maxAge 到期秒数，则取 expire 到期时间。
@@ -349,6 +370,10 @@ func (c *Cookie) ParseBytes(src []byte) error {
 				} else if utils.CaseInsensitiveCompare(bytestr.StrCookieSameSite, kv.value) {
 					c.sameSite = CookieSameSiteDefaultMode
 				}
+			case 'p': // partitioned
+				if utils.CaseInsensitiveCompare(bytestr.StrCookiePartitioned, kv.value) {
+					c.partitioned = true
+				}
 			}
 		} // 其他为空或不匹配
 	}
@@ -366,6 +391,7 @@ func (c *Cookie) Reset() {
 	c.httpOnly = false
 	c.secure = false
 	c.sameSite = CookieSameSiteDisabled
+	c.partitioned = false
 }
 
 type cookieScanner struct {
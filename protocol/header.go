@@ -3,6 +3,9 @@ package protocol
 import (
 	"bytes"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -213,6 +216,13 @@ func (h *RequestHeader) CopyTo(dst *RequestHeader) {
 	dst.protocol = h.protocol
 }
 
+// Clone 返回请求头的深拷贝，可安全地跨协程传递。
+func (h *RequestHeader) Clone() *RequestHeader {
+	dst := &RequestHeader{}
+	h.CopyTo(dst)
+	return dst
+}
+
 // DelAllCookies 删除请求头中的所有 cookies。
 func (h *RequestHeader) DelAllCookies() {
 	h.collectCookies()
@@ -322,6 +332,49 @@ func (h *RequestHeader) HasAcceptEncodingBytes(acceptEncoding []byte) bool {
 	return ae[n-1] == ' '
 }
 
+// AcceptLanguages 返回 Accept-Language 标头中的语言标签，按 q 值从高到低排列。
+//
+// 语言标签统一转为小写，q=0 及通配符 * 会被忽略。
+func (h *RequestHeader) AcceptLanguages() []string {
+	al := h.Peek(consts.HeaderAcceptLanguage)
+	if len(al) == 0 {
+		return nil
+	}
+
+	type langQ struct {
+		lang string
+		q    float64
+	}
+	var langs []langQ
+	for _, part := range strings.Split(string(al), ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.EqualFold(strings.TrimSpace(k), "q") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || f < 0 || f > 1 {
+				continue
+			}
+			q = f
+		}
+		if q > 0 {
+			langs = append(langs, langQ{lang, q})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+
+	result := make([]string, len(langs))
+	for i, l := range langs {
+		result[i] = l.lang
+	}
+	return result
+}
+
 // Header 返回请求头的字节切片形式。
 func (h *RequestHeader) Header() []byte {
 	h.bufKV.value = h.AppendBytes(h.bufKV.value[:0])
@@ -535,6 +588,30 @@ func (h *RequestHeader) peekAll(key []byte) [][]byte {
 	return h.mulHeader
 }
 
+// CountHeader 返回 key 的标头出现次数，不分配新切片。
+func (h *RequestHeader) CountHeader(key string) int {
+	k := getHeaderKeyBytes(&h.bufKV, key, h.disableNormalizing)
+	switch string(k) {
+	case consts.HeaderHost:
+		return boolToInt(len(h.Host()) > 0)
+	case consts.HeaderContentType:
+		return boolToInt(len(h.ContentType()) > 0)
+	case consts.HeaderUserAgent:
+		return boolToInt(len(h.UserAgent()) > 0)
+	case consts.HeaderConnection:
+		if h.ConnectionClose() {
+			return 1
+		}
+	case consts.HeaderContentLength:
+		return boolToInt(len(h.contentLengthBytes) > 0)
+	case consts.HeaderCookie:
+		if h.cookiesCollected {
+			return boolToInt(len(h.cookies) > 0)
+		}
+	}
+	return countArgBytes(h.h, k)
+}
+
 // PeekArgBytes 返回指定 key （不考虑规范化）对应的标头值字节切片。
 func (h *RequestHeader) PeekArgBytes(key []byte) []byte {
 	return peekArgBytes(h.h, key)
@@ -847,6 +924,19 @@ func (h *RequestHeader) UserAgent() []byte {
 	return h.userAgent
 }
 
+// VisitAllTrailer 对每个已声明的 Trailer 键名称应用函数 f。
+//
+// f 在返回后不得保留对键的引用，以防数据竞赛。
+// 如果需要保留键内容，请在返回之前复制它们。
+func (h *RequestHeader) VisitAllTrailer(f func(key []byte)) {
+	if h.trailer == nil {
+		return
+	}
+	h.trailer.VisitAll(func(key, _ []byte) {
+		f(key)
+	})
+}
+
 // VisitAll 对每个标头应用函数 f。
 //
 // f 在返回后不得保留对键或值的引用，以防数据竞赛。
@@ -1075,6 +1165,13 @@ func (h *ResponseHeader) CopyTo(dst *ResponseHeader) {
 	h.Trailer().CopyTo(dst.Trailer())
 }
 
+// Clone 返回响应头的深拷贝，可安全地跨协程传递。
+func (h *ResponseHeader) Clone() *ResponseHeader {
+	dst := &ResponseHeader{}
+	h.CopyTo(dst)
+	return dst
+}
+
 // Del 删除指定 key 的响应头。
 func (h *ResponseHeader) Del(key string) {
 	k := getHeaderKeyBytes(&h.bufKV, key, h.disableNormalizing)
@@ -1361,6 +1458,30 @@ func (h *ResponseHeader) peekAll(key []byte) [][]byte {
 	return h.mulHeader
 }
 
+// CountHeader 返回 key 的标头出现次数，不分配新切片。
+//
+// Set-Cookie 按 Cookie 个数计数，Content-Type 仅在显式设置时计数。
+func (h *ResponseHeader) CountHeader(key string) int {
+	k := getHeaderKeyBytes(&h.bufKV, key, h.disableNormalizing)
+	switch string(k) {
+	case consts.HeaderContentType:
+		return boolToInt(len(h.contentType) > 0)
+	case consts.HeaderContentEncoding:
+		return boolToInt(len(h.ContentEncoding()) > 0)
+	case consts.HeaderServer:
+		return boolToInt(len(h.Server()) > 0)
+	case consts.HeaderConnection:
+		if h.ConnectionClose() {
+			return 1
+		}
+	case consts.HeaderContentLength:
+		return boolToInt(len(h.contentLengthBytes) > 0)
+	case consts.HeaderSetCookie:
+		return len(h.cookies)
+	}
+	return countArgBytes(h.h, k)
+}
+
 // PeekArgBytes 获取响应头中指定 key 的值。
 func (h *ResponseHeader) PeekArgBytes(key []byte) []byte {
 	return peekArgBytes(h.h, key)
@@ -1631,6 +1752,19 @@ func (h *ResponseHeader) Trailer() *Trailer {
 	return h.trailer
 }
 
+// VisitAllTrailer 对每个已声明的 Trailer 键名称应用函数 f。
+//
+// f 在返回后不得保留对键的引用，以防数据竞赛。
+// 如果需要保留键内容，请在返回之前复制它们。
+func (h *ResponseHeader) VisitAllTrailer(f func(key []byte)) {
+	if h.trailer == nil {
+		return
+	}
+	h.trailer.VisitAll(func(key, _ []byte) {
+		f(key)
+	})
+}
+
 // VisitAll 对每个标头应用函数 f。
 //
 // f 在返回后不得保留对键或值的引用，以防数据竞赛。
@@ -1732,3 +1866,10 @@ func appendHeaderLine(dst, key, value []byte) []byte {
 	dst = append(dst, value...)
 	return append(dst, bytestr.StrCRLF...)
 }
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -626,6 +626,55 @@ func expectResponseHeaderAll(t *testing.T, h *ResponseHeader, key string, expect
 	assert.Equal(t, h.PeekAll(key), expectedValue)
 }
 
+func TestRequestHeader_CountHeader(t *testing.T) {
+	t.Parallel()
+
+	h := &RequestHeader{}
+	assert.Equal(t, 0, h.CountHeader(consts.HeaderHost))
+	assert.Equal(t, 0, h.CountHeader(consts.HeaderContentLength))
+	h.Add(consts.HeaderHost, "aaabbb")
+	h.Add("content-type", "aaa")
+	h.Add("Content-Length", "1123")
+	h.Add("Cookie", "foo=bar")
+	h.Add("aaa", "aaa")
+	h.Add("aaa", "bbb")
+	h.Add("aaa", "ccc")
+
+	assert.Equal(t, 1, h.CountHeader(consts.HeaderHost))
+	assert.Equal(t, 1, h.CountHeader("Content-Type"))
+	assert.Equal(t, 1, h.CountHeader("content-length"))
+	assert.Equal(t, 1, h.CountHeader("Cookie"))
+	assert.Equal(t, 3, h.CountHeader("aaa"))
+	assert.Equal(t, 0, h.CountHeader("bbb"))
+
+	h.SetConnectionClose(true)
+	assert.Equal(t, 1, h.CountHeader(consts.HeaderConnection))
+}
+
+func TestResponseHeader_CountHeader(t *testing.T) {
+	t.Parallel()
+
+	h := &ResponseHeader{}
+	// 未设置时不计入默认的内容类型
+	assert.Equal(t, 0, h.CountHeader(consts.HeaderContentType))
+
+	h.Add(consts.HeaderContentEncoding, "gzip")
+	h.Add(consts.HeaderSetCookie, "a=1")
+	h.Add(consts.HeaderSetCookie, "b=2")
+	h.Add("Vary", "Accept")
+	h.Add("vary", "Origin")
+
+	assert.Equal(t, 2, h.CountHeader(consts.HeaderSetCookie))
+	assert.Equal(t, 2, h.CountHeader("set-cookie"))
+	assert.Equal(t, 1, h.CountHeader(consts.HeaderContentEncoding))
+	assert.Equal(t, 0, h.CountHeader(consts.HeaderServer))
+	assert.Equal(t, 2, h.CountHeader("Vary"))
+	assert.Equal(t, len(h.PeekAll("Vary")), h.CountHeader("Vary"))
+
+	h.SetContentType("aaa/bbb")
+	assert.Equal(t, 1, h.CountHeader(consts.HeaderContentType))
+}
+
 func TestRequestHeaderCopyTo(t *testing.T) {
 	t.Parallel()
 
@@ -684,6 +733,57 @@ func TestResponseHeaderCopyTo(t *testing.T) {
 	assert.Equal(t, hCopy.GetHeaderLength(), 100)
 }
 
+func TestRequestHeaderClone(t *testing.T) {
+	t.Parallel()
+
+	h := &RequestHeader{}
+	h.DisableNormalizing()
+	h.SetMethod(consts.MethodPost)
+	h.Set("x-foo", "bar")
+	h.Set(consts.HeaderCookie, "a=1")
+	h.Cookie("a") // 触发 cookie 收集
+	h.rawHeaders = []byte("x-foo: bar\r\n")
+
+	c := h.Clone()
+	assert.Equal(t, h.disableNormalizing, c.disableNormalizing)
+	assert.Equal(t, h.cookiesCollected, c.cookiesCollected)
+	assert.Equal(t, h.rawHeaders, c.rawHeaders)
+	assert.Equal(t, "bar", c.Get("x-foo"))
+	assert.Equal(t, "1", string(c.Cookie("a")))
+
+	// 修改克隆不影响源
+	c.Set("x-foo", "baz")
+	c.SetCookie("a", "2")
+	c.SetMethod(consts.MethodGet)
+	c.rawHeaders[0] = 'X'
+	assert.Equal(t, "bar", h.Get("x-foo"))
+	assert.Equal(t, "1", string(h.Cookie("a")))
+	assert.Equal(t, consts.MethodPost, string(h.Method()))
+	assert.Equal(t, "x-foo: bar\r\n", string(h.rawHeaders))
+}
+
+func TestResponseHeaderClone(t *testing.T) {
+	t.Parallel()
+
+	h := &ResponseHeader{}
+	h.SetStatusCode(consts.StatusCreated)
+	h.SetNoDefaultDate(true)
+	h.Set("X-Foo", "bar")
+	h.Add(consts.HeaderSetCookie, "a=1")
+
+	c := h.Clone()
+	assert.Equal(t, consts.StatusCreated, c.StatusCode())
+	assert.True(t, c.noDefaultDate)
+	assert.Equal(t, "bar", c.Get("X-Foo"))
+
+	c.Set("X-Foo", "baz")
+	c.SetStatusCode(consts.StatusOK)
+	c.DelAllCookies()
+	assert.Equal(t, "bar", h.Get("X-Foo"))
+	assert.Equal(t, consts.StatusCreated, h.StatusCode())
+	assert.Equal(t, "a=1", h.Get(consts.HeaderSetCookie))
+}
+
 func TestResponseHeaderDateEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -695,3 +795,11 @@ func TestResponseHeaderDateEmpty(t *testing.T) {
 		t.Fatalf("ResponseDateNoDefaultNotEmpty fail, response: \n%+v\noutcome: \n%q\n", h, headers) //nolint:govet
 	}
 }
+
+func TestRequestHeaderAcceptLanguages(t *testing.T) {
+	var h RequestHeader
+	assert.Nil(t, h.AcceptLanguages())
+
+	h.Set(consts.HeaderAcceptLanguage, "en-US;q=0.8, zh-CN, fr;q=0, *;q=0.5, de;q=0.9, ja;q=abc")
+	assert.Equal(t, []string{"zh-cn", "de", "en-us"}, h.AcceptLanguages())
+}
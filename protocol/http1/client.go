@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/favbox/wind/app/client/retry"
+	"github.com/favbox/wind/app/client/trace"
+	"github.com/favbox/wind/common/compress"
 	"github.com/favbox/wind/common/config"
 	errs "github.com/favbox/wind/common/errors"
 	"github.com/favbox/wind/common/timer"
@@ -34,6 +36,9 @@ import (
 var (
 	clientConnPool sync.Pool
 
+	// 自动请求压缩时的 Accept-Encoding 取值
+	strGzipBr = []byte("gzip, br")
+
 	errTimeout          = errs.New(errs.ErrTimeout, errs.ErrorTypePublic, "host client")
 	errConnectionClosed = errs.NewPublic("服务器在返回首个响应字节之前关闭了连接。请确保服务器在关闭连接之前返回 'Connection: close' 响应头")
 )
@@ -121,6 +126,15 @@ type ClientOptions struct {
 	// 是否流式处理响应正文
 	ResponseBodyStream bool
 
+	// 是否自动请求 gzip 或 br 压缩响应并透明解压，流式处理响应正文时不生效
+	RequestCompressed bool
+
+	// 长度未知的流式请求体的分块阈值，0 表示不预读，总是以 chunked 发送
+	ChunkedBodyThreshold int
+
+	// 请求各阶段的跟踪钩子
+	Trace *trace.ClientTrace
+
 	// 与重试相关的所有配置
 	RetryConfig *retry.Config
 
@@ -131,6 +145,9 @@ type ClientOptions struct {
 
 	// 观察间隔时长
 	ObservationInterval time.Duration
+
+	// 连接池状态变化的回调，由后台协程按序异步调用
+	OnConnStateChange config.ConnStateChangeFunc
 }
 
 // HostClient 在 Addr 列举的主机之间平衡 http 请求。并发不安全，拷贝不安全。
@@ -147,6 +164,7 @@ type HostClient struct {
 	//	- foobar.com:80
 	//	- foobar.com:443
 	//	- foobar.com:8080
+	//	- unix:/var/run/app.sock（unix 域套接字）
 	Addr     string
 	IsTLS    bool
 	ProxyURI *protocol.URI
@@ -170,6 +188,9 @@ type HostClient struct {
 
 	connsCleanerRun bool
 
+	connStateCh   chan config.ConnPoolState
+	connStateOnce sync.Once
+
 	closed chan struct{}
 }
 
@@ -324,6 +345,155 @@ func (c *HostClient) Do(ctx context.Context, req *protocol.Request, resp *protoc
 	return err
 }
 
+// DoBatch 在同一连接上以 HTTP/1 管道化方式依次写出 reqs，再按序读取响应至 resps。
+//
+// 返回的错误切片与 reqs 一一对应，resps[i] 为空时忽略对应响应。
+// 写入失败时所有请求均返回该错误；某个响应读取失败或要求关闭连接时，
+// 连接将被关闭，其后的请求返回 ErrConnectionClosed。
+// 批量请求不重试、不支持流式响应正文，仅建议用于幂等请求。
+func (c *HostClient) DoBatch(ctx context.Context, reqs []*protocol.Request, resps []*protocol.Response) []error {
+	if len(reqs) != len(resps) {
+		panic("BUG: reqs 和 resps 的长度必须一致")
+	}
+	errList := make([]error, len(reqs))
+	if len(reqs) == 0 {
+		return errList
+	}
+	select {
+	case <-ctx.Done():
+		for i, req := range reqs {
+			req.CloseBodyStream()
+			errList[i] = ctx.Err()
+		}
+		return errList
+	default:
+	}
+
+	atomic.AddInt32(&c.pendingRequests, int32(len(reqs)))
+	c.doBatch(reqs, resps, errList)
+	atomic.AddInt32(&c.pendingRequests, -int32(len(reqs)))
+	return errList
+}
+
+func (c *HostClient) doBatch(reqs []*protocol.Request, resps []*protocol.Response, errList []error) {
+	setAll := func(from int, err error) {
+		for i := from; i < len(errList); i++ {
+			errList[i] = err
+		}
+	}
+
+	atomic.StoreUint32(&c.lastUseTime, uint32(time.Now().Unix()-startTimeUnix))
+
+	for i, req := range reqs {
+		req.Options().StartRequest()
+		if c.DisablePathNormalizing {
+			req.URI().DisablePathNormalizing = true
+		}
+		if err := reqI.BufferBodyStream(req, c.ChunkedBodyThreshold); err != nil {
+			setAll(i, err)
+			return
+		}
+	}
+
+	rc := c.preHandleConfig(reqs[0].Options())
+	dialTimeout := rc.dialTimeout
+	if reqTimeout := reqs[0].Options().RequestTimeout(); (reqTimeout > 0 && reqTimeout < dialTimeout) || dialTimeout == 0 {
+		dialTimeout = reqTimeout
+	}
+	if c.Trace != nil && c.Trace.GetConn != nil {
+		c.Trace.GetConn(time.Now(), c.Addr)
+	}
+	cc, inPool, err := c.acquireConn(dialTimeout)
+	if err != nil {
+		setAll(0, err)
+		return
+	}
+	if c.Trace != nil && c.Trace.GotConn != nil {
+		c.Trace.GotConn(time.Now(), inPool)
+	}
+	conn := cc.c
+
+	// 依次写入全部请求后统一刷新
+	if err = conn.SetWriteTimeout(rc.writeTimeout); err != nil {
+		c.closeConn(cc)
+		setAll(0, err)
+		return
+	}
+	zw := c.acquireWriter(conn)
+	for _, req := range reqs {
+		if len(req.Header.UserAgent()) == 0 {
+			req.Header.SetUserAgentBytes(c.getClientName())
+		}
+		if c.ProxyURI != nil && bytes.Equal(req.Scheme(), bytestr.StrHTTP) {
+			proxy.SetProxyAuthHeader(&req.Header, c.ProxyURI)
+			err = reqI.ProxyWrite(req, zw)
+		} else {
+			err = reqI.Write(req, zw)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = zw.Flush()
+	}
+	if c.Trace != nil && c.Trace.WroteRequest != nil {
+		c.Trace.WroteRequest(time.Now(), err)
+	}
+	if err != nil {
+		c.closeConn(cc)
+		if errNorm, ok := conn.(network.ErrorNormalization); ok {
+			err = errNorm.ToWindError(err)
+		}
+		setAll(0, err)
+		return
+	}
+
+	// 按序读取响应，任一失败则放弃剩余响应
+	zr := c.acquireReader(conn)
+	defer zr.Release()
+	for i, req := range reqs {
+		resp := resps[i]
+		if resp == nil {
+			resp = protocol.AcquireResponse()
+			defer protocol.ReleaseResponse(resp)
+		}
+		customSkipBody := resp.SkipBody
+		resp.Reset()
+		resp.SkipBody = customSkipBody || req.Header.IsHead() || req.Header.IsConnect()
+		if c.DisableHeaderNamesNormalizing {
+			resp.Header.DisableNormalizing()
+		}
+		resp.ParseNetAddr(conn)
+
+		shouldClose, timeout := updateReqTimeout(req.Options().RequestTimeout(), c.preHandleConfig(req.Options()).readTimeout, req.Options().StartTime())
+		if shouldClose {
+			err = errTimeout
+		} else if err = conn.SetReadTimeout(timeout); err == nil {
+			if req.Options().DiscardBody() {
+				err = respI.ReadHeaderAndDiscardBody(resp, zr)
+			} else {
+				err = respI.ReadHeaderAndLimitBody(resp, zr, c.MaxResponseBodySize)
+			}
+		}
+		if err != nil {
+			c.closeConn(cc)
+			if err == io.EOF {
+				err = errConnectionClosed
+			}
+			errList[i] = err
+			setAll(i+1, errs.ErrConnectionClosed)
+			return
+		}
+		if req.ConnectionClose() || resp.ConnectionClose() {
+			c.closeConn(cc)
+			setAll(i+1, errs.ErrConnectionClosed)
+			return
+		}
+	}
+	c.releaseConn(cc)
+}
+
 // DoDeadline 执行给定的 http 请求并等待响应直至到达截止时间。
 //
 // Request 至少包含非空的完整网址（包括方案和主机）或非空的主机头+请求网址。
@@ -480,6 +650,7 @@ func (c *HostClient) WantConnectionCount() (count int) {
 }
 
 func (c *HostClient) decConnsCount() {
+	defer c.connStateChanged()
 	if c.MaxConnWaitTimeout <= 0 {
 		c.connsLock.Lock()
 		c.connsCount--
@@ -506,6 +677,7 @@ func (c *HostClient) decConnsCount() {
 }
 
 func (c *HostClient) releaseConn(cc *clientConn) {
+	defer c.connStateChanged()
 	cc.lastUseTime = time.Now()
 	if c.MaxConnWaitTimeout <= 0 {
 		c.connsLock.Lock()
@@ -534,7 +706,7 @@ func (c *HostClient) releaseConn(cc *clientConn) {
 }
 
 func (c *HostClient) dialConnFor(w *wantConn) {
-	conn, err := c.dialHostHard(c.DialTimeout)
+	conn, err := c.dialHostHard(c.DialTimeout, c.ProxyURI)
 	if err != nil {
 		w.tryDeliver(nil, err)
 		c.decConnsCount()
@@ -542,6 +714,7 @@ func (c *HostClient) dialConnFor(w *wantConn) {
 	}
 
 	cc := acquireClientConn(conn)
+	c.connStateChanged()
 	delivered := w.tryDeliver(cc, nil)
 	if !delivered {
 		// 未送达，返回闲置连接
@@ -549,7 +722,7 @@ func (c *HostClient) dialConnFor(w *wantConn) {
 	}
 }
 
-func (c *HostClient) dialHostHard(dialTimeout time.Duration) (conn network.Conn, err error) {
+func (c *HostClient) dialHostHard(dialTimeout time.Duration, proxyURI *protocol.URI) (conn network.Conn, err error) {
 	// 在放弃之前尝试拨打所有可用的主机
 
 	c.addrsLock.Lock()
@@ -564,8 +737,8 @@ func (c *HostClient) dialHostHard(dialTimeout time.Duration) (conn network.Conn,
 	deadline := time.Now().Add(dialTimeout)
 	for n > 0 {
 		addr := c.nextAddr()
-		tlsConfig := c.cachedTLSConfig(addr)
-		conn, err = dialAddr(addr, c.Dialer, c.DialDualStack, tlsConfig, dialTimeout, c.ProxyURI, c.IsTLS)
+		tlsConfig := c.cachedTLSConfigFor(addr, proxyURI)
+		conn, err = dialAddr(addr, c.Dialer, c.DialDualStack, tlsConfig, dialTimeout, proxyURI, c.IsTLS, c.Trace)
 		if err == nil {
 			return conn, nil
 		}
@@ -577,7 +750,7 @@ func (c *HostClient) dialHostHard(dialTimeout time.Duration) (conn network.Conn,
 	return nil, err
 }
 
-func dialAddr(addr string, dial network.Dialer, dialDualStack bool, tlsConfig *tls.Config, timeout time.Duration, proxyURI *protocol.URI, isTLS bool) (network.Conn, error) {
+func dialAddr(addr string, dial network.Dialer, dialDualStack bool, tlsConfig *tls.Config, timeout time.Duration, proxyURI *protocol.URI, isTLS bool, t *trace.ClientTrace) (network.Conn, error) {
 	var conn network.Conn
 	var err error
 	if dial == nil {
@@ -587,11 +760,14 @@ func dialAddr(addr string, dial network.Dialer, dialDualStack bool, tlsConfig *t
 	dialFunc := dial.DialConnection
 
 	// 地址已有端口号，此处无需操作
+	dialNetwork, address := addrNetwork(addr)
 	if proxyURI != nil {
 		// 先用 tcp 连接，代理将向其添加 TLS
-		conn, err = dialFunc("tcp", string(proxyURI.Host()), timeout, nil)
+		conn, err = traceDial(dialFunc, "tcp", string(proxyURI.Host()), timeout, nil, t)
+	} else if dialNetwork == "tcp" && t != nil && (t.DNSStart != nil || t.DNSDone != nil) {
+		conn, err = traceDialResolved(dialFunc, address, timeout, tlsConfig, t)
 	} else {
-		conn, err = dialFunc("tcp", addr, timeout, tlsConfig)
+		conn, err = traceDial(dialFunc, dialNetwork, address, timeout, tlsConfig, t)
 	}
 
 	if err != nil {
@@ -610,9 +786,101 @@ func dialAddr(addr string, dial network.Dialer, dialDualStack bool, tlsConfig *t
 		return nil, err
 	}
 
+	if t != nil && (t.TLSHandshakeStart != nil || t.TLSHandshakeDone != nil) {
+		if err = traceHandshake(conn, t); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
 	return conn, nil
 }
 
+type dialFunc func(network, address string, timeout time.Duration, tlsConfig *tls.Config) (network.Conn, error)
+
+// UnixAddrPrefix 是 unix 域套接字主机地址的前缀，如 unix:/var/run/app.sock。
+const UnixAddrPrefix = "unix:"
+
+// 返回主机地址的网络类型及拨号地址，带 UnixAddrPrefix 前缀的地址使用 unix 网络。
+func addrNetwork(addr string) (dialNetwork, address string) {
+	if strings.HasPrefix(addr, UnixAddrPrefix) {
+		return "unix", addr[len(UnixAddrPrefix):]
+	}
+	return "tcp", addr
+}
+
+// 拨号并触发 ConnectStart 与 ConnectDone 钩子
+func traceDial(dial dialFunc, dialNetwork, addr string, timeout time.Duration, tlsConfig *tls.Config, t *trace.ClientTrace) (network.Conn, error) {
+	if t != nil && t.ConnectStart != nil {
+		t.ConnectStart(time.Now(), dialNetwork, addr)
+	}
+	conn, err := dial(dialNetwork, addr, timeout, tlsConfig)
+	if t != nil && t.ConnectDone != nil {
+		t.ConnectDone(time.Now(), dialNetwork, addr, err)
+	}
+	return conn, err
+}
+
+// 自行解析域名并依次拨号解析出的地址，以触发 DNSStart 与 DNSDone 钩子
+func traceDialResolved(dial dialFunc, addr string, timeout time.Duration, tlsConfig *tls.Config, t *trace.ClientTrace) (network.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return traceDial(dial, "tcp", addr, timeout, tlsConfig, t)
+	}
+
+	if t.DNSStart != nil {
+		t.DNSStart(time.Now(), host)
+	}
+	deadline := time.Now().Add(timeout)
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if t.DNSDone != nil {
+		t.DNSDone(time.Now(), ips, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var conn network.Conn
+	for _, ip := range ips {
+		remaining := timeout
+		if timeout > 0 {
+			if remaining = time.Until(deadline); remaining <= 0 {
+				return nil, errTimeout
+			}
+		}
+		conn, err = traceDial(dial, "tcp", net.JoinHostPort(ip.String(), port), remaining, tlsConfig, t)
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// 立即完成 TLS 握手并触发 TLSHandshakeStart 与 TLSHandshakeDone 钩子
+func traceHandshake(conn network.Conn, t *trace.ClientTrace) error {
+	tlsConn, ok := conn.(interface {
+		Handshake() error
+		ConnectionState() tls.ConnectionState
+	})
+	if !ok {
+		return nil
+	}
+	if t.TLSHandshakeStart != nil {
+		t.TLSHandshakeStart(time.Now())
+	}
+	err := tlsConn.Handshake()
+	if t.TLSHandshakeDone != nil {
+		t.TLSHandshakeDone(time.Now(), tlsConn.ConnectionState(), err)
+	}
+	return err
+}
+
 func (c *HostClient) nextAddr() string {
 	c.addrsLock.Lock()
 	if c.addrs == nil {
@@ -628,9 +896,14 @@ func (c *HostClient) nextAddr() string {
 }
 
 func (c *HostClient) cachedTLSConfig(addr string) *tls.Config {
+	return c.cachedTLSConfigFor(addr, c.ProxyURI)
+}
+
+// 返回经 proxyURI 代理拨号 addr 时所用的 TLS 配置。
+func (c *HostClient) cachedTLSConfigFor(addr string, proxyURI *protocol.URI) *tls.Config {
 	var cfgAddr string
-	if c.ProxyURI != nil && bytes.Equal(c.ProxyURI.Scheme(), bytestr.StrHTTPS) {
-		cfgAddr = bytesconv.B2s(c.ProxyURI.Host())
+	if proxyURI != nil && bytes.Equal(proxyURI.Scheme(), bytestr.StrHTTPS) {
+		cfgAddr = bytesconv.B2s(proxyURI.Host())
 	}
 
 	if c.IsTLS && cfgAddr == "" {
@@ -692,6 +965,11 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 	if c.DisablePathNormalizing {
 		req.URI().DisablePathNormalizing = true
 	}
+
+	// 按阈值决定长度未知的流式请求体以 Content-Length 还是 chunked 发送
+	if err := reqI.BufferBodyStream(req, c.ChunkedBodyThreshold); err != nil {
+		return false, err
+	}
 	reqTimeout := req.Options().RequestTimeout()
 	begin := req.Options().StartTime()
 
@@ -699,18 +977,38 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 	if (reqTimeout > 0 && reqTimeout < dialTimeout) || dialTimeout == 0 {
 		dialTimeout = reqTimeout
 	}
-	cc, inPool, err := c.acquireConn(dialTimeout)
+	if c.Trace != nil && c.Trace.GetConn != nil {
+		c.Trace.GetConn(time.Now(), c.Addr)
+	}
+
+	// 请求级代理的连接不能与连接池中的连接混用，故新建短连接，用完即关
+	proxyURI := c.ProxyURI
+	reqProxyURI := c.requestProxyURI(req)
+	var (
+		cc     *clientConn
+		inPool bool
+		err    error
+	)
+	if reqProxyURI != nil {
+		proxyURI = reqProxyURI
+		cc, err = c.dialProxyConn(dialTimeout, reqProxyURI)
+	} else {
+		cc, inPool, err = c.acquireConn(dialTimeout)
+	}
 	// 若获取连接出错，立即返回错误
 	if err != nil {
 		return false, err
 	}
+	if c.Trace != nil && c.Trace.GotConn != nil {
+		c.Trace.GotConn(time.Now(), inPool)
+	}
 	conn := cc.c
 
 	// 设置代理网址和鉴权标头
 	usingProxy := false
-	if c.ProxyURI != nil && bytes.Equal(req.Scheme(), bytestr.StrHTTP) {
+	if proxyURI != nil && bytes.Equal(req.Scheme(), bytestr.StrHTTP) {
 		usingProxy = true
-		proxy.SetProxyAuthHeader(&req.Header, c.ProxyURI)
+		proxy.SetProxyAuthHeader(&req.Header, proxyURI)
 	}
 
 	resp.ParseNetAddr(conn)
@@ -742,6 +1040,14 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 		req.Header.SetUserAgentBytes(c.getClientName())
 	}
 
+	// 用户未指定编码时自动请求压缩，范围请求除外
+	requestedCompressed := false
+	if c.RequestCompressed && !c.ResponseBodyStream && !req.Header.IsHead() &&
+		len(req.Header.Peek(consts.HeaderAcceptEncoding)) == 0 && len(req.Header.Peek(consts.HeaderRange)) == 0 {
+		req.Header.SetCanonical(bytestr.StrAcceptEncoding, strGzipBr)
+		requestedCompressed = true
+	}
+
 	// 将请求写入连接
 	zw := c.acquireWriter(conn)
 	if !usingProxy {
@@ -752,10 +1058,16 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 	if resetConnection {
 		req.Header.ResetConnectionClose()
 	}
+	if requestedCompressed {
+		req.Header.Del(consts.HeaderAcceptEncoding)
+	}
 
 	if err == nil {
 		err = zw.Flush()
 	}
+	if c.Trace != nil && c.Trace.WroteRequest != nil {
+		c.Trace.WroteRequest(time.Now(), err)
+	}
 	// 错误发生于写入请求时，关闭连接，重试其他连接（若启用重试）
 	if err != nil {
 		defer c.closeConn(cc)
@@ -832,15 +1144,20 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 		}
 		return false, err
 	}
+	if c.Trace != nil && c.Trace.GotFirstResponseByte != nil {
+		c.Trace.GotFirstResponseByte(time.Now())
+	}
 
 	// 此处初始化时用于在 ReadBodyStream 的闭包中传递，
 	// 且该值降载读取响应头后被指派
 	//
 	// 这是为了解决 Response 响应和 BodyStream 正文流相互依赖的问题。
-	shouldCloseConn := false
+	shouldCloseConn := reqProxyURI != nil
 
 	// 真正读取响应标头和正文
-	if !c.ResponseBodyStream {
+	if req.Options().DiscardBody() {
+		err = respI.ReadHeaderAndDiscardBody(resp, zr)
+	} else if !c.ResponseBodyStream {
 		err = respI.ReadHeaderAndLimitBody(resp, zr, c.MaxResponseBodySize)
 	} else {
 		err = respI.ReadBodyStream(resp, zr, c.MaxResponseBodySize, func(shouldClose bool) error {
@@ -863,7 +1180,12 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 
 	zr.Release()
 
-	shouldCloseConn = resetConnection || req.ConnectionClose() || resp.ConnectionClose()
+	// 透明解压自动请求的 gzip 或 br 响应
+	if requestedCompressed && len(resp.BodyBytes()) > 0 {
+		err = decompressResponseBody(resp)
+	}
+
+	shouldCloseConn = reqProxyURI != nil || resetConnection || req.ConnectionClose() || resp.ConnectionClose()
 
 	// 在流模式下，如果线上无内容依然可以立即关闭或释放连接。
 	if c.ResponseBodyStream && resp.BodyStream() != protocol.NoResponseBody {
@@ -879,6 +1201,27 @@ func (c *HostClient) doNonNilReqResp(req *protocol.Request, resp *protocol.Respo
 	return false, err
 }
 
+// 按 Content-Encoding 解压 gzip 或 br 响应体，其他编码保持原样。
+func decompressResponseBody(resp *protocol.Response) error {
+	var body []byte
+	var err error
+	switch encoding := resp.Header.ContentEncoding(); {
+	case bytes.Equal(encoding, bytestr.StrGzip):
+		body, err = resp.BodyGunzip()
+	case bytes.Equal(encoding, bytestr.StrBr):
+		body, err = compress.AppendUnbrotliBytes(nil, resp.BodyBytes())
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.SetBody(body)
+	resp.Header.DelBytes(bytestr.StrContentEncoding)
+	resp.Header.SetContentLength(len(body))
+	return nil
+}
+
 func updateReqTimeout(reqTimeout, compareTimeout time.Duration, before time.Time) (shouldCloseConn bool, timeout time.Duration) {
 	if reqTimeout <= 0 {
 		return false, compareTimeout
@@ -953,6 +1296,7 @@ func (c *HostClient) acquireConn(dialTimeout time.Duration) (cc *clientConn, inP
 	c.connsLock.Unlock()
 
 	if cc != nil {
+		c.connStateChanged()
 		return cc, true, nil
 	}
 	if !createConn {
@@ -980,6 +1324,7 @@ func (c *HostClient) acquireConn(dialTimeout time.Duration) (cc *clientConn, inP
 		// 需要在等待时建立连接，则使用 HostClient 的拨号超时，
 		// 而不是请求选项中的拨号超时。
 		c.queueForIdle(w)
+		c.connStateChanged()
 
 		select {
 		case <-w.ready:
@@ -993,16 +1338,91 @@ func (c *HostClient) acquireConn(dialTimeout time.Duration) (cc *clientConn, inP
 		go c.connsCleaner()
 	}
 
-	conn, err := c.dialHostHard(dialTimeout)
+	conn, err := c.dialHostHard(dialTimeout, c.ProxyURI)
 	if err != nil {
 		c.decConnsCount()
 		return nil, false, err
 	}
 	cc = acquireClientConn(conn)
+	c.connStateChanged()
 
 	return cc, false, nil
 }
 
+// 经请求级代理 proxyURI 新建一条不进入连接池的连接。
+//
+// 连接仍计入 connsCount 并受 MaxConns 限制，以便用完后统一经 closeConn 关闭。
+// 连接数已达上限时返回 errs.ErrNoFreeConns。
+func (c *HostClient) dialProxyConn(dialTimeout time.Duration, proxyURI *protocol.URI) (*clientConn, error) {
+	maxConns := c.MaxConns
+	if maxConns <= 0 {
+		maxConns = consts.DefaultMaxConnsPerHost
+	}
+	c.connsLock.Lock()
+	if c.connsCount >= maxConns {
+		c.connsLock.Unlock()
+		return nil, errs.ErrNoFreeConns
+	}
+	c.connsCount++
+	c.connsLock.Unlock()
+
+	conn, err := c.dialHostHard(dialTimeout, proxyURI)
+	if err != nil {
+		c.decConnsCount()
+		return nil, err
+	}
+	c.connStateChanged()
+	return acquireClientConn(conn), nil
+}
+
+// 连接池状态回调的事件队列长度。
+const connStateQueueSize = 64
+
+// 将当前连接池状态快照加入回调队列，不可在持有 connsLock 时调用。
+//
+// 回调由单个后台协程按序调用，队列已满时丢弃最旧的快照，既不阻塞请求，也保证最新状态总能送达。
+func (c *HostClient) connStateChanged() {
+	if c.OnConnStateChange == nil {
+		return
+	}
+	c.connStateOnce.Do(func() {
+		c.connStateCh = make(chan config.ConnPoolState, connStateQueueSize)
+		go c.notifyConnState()
+	})
+	state := c.ConnPoolState()
+	for {
+		select {
+		case c.connStateCh <- state:
+			return
+		default:
+		}
+		select {
+		case <-c.connStateCh:
+		default:
+		}
+	}
+}
+
+func (c *HostClient) notifyConnState() {
+	for {
+		select {
+		case <-c.closed:
+			return
+		case state := <-c.connStateCh:
+			c.OnConnStateChange(state.Addr, state)
+		}
+	}
+}
+
+// 返回请求级代理网址，与客户端代理相同或未设置时返回 nil。
+func (c *HostClient) requestProxyURI(req *protocol.Request) *protocol.URI {
+	p := req.Options().Proxy()
+	if p == "" || (c.ProxyURI != nil && p == c.ProxyURI.String()) {
+		return nil
+	}
+	return protocol.ParseURI(p)
+}
+
 func (c *HostClient) connsCleaner() {
 	var (
 		scratch             []*clientConn
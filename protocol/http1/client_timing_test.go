@@ -0,0 +1,131 @@
+package http1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/common/config"
+	"github.com/favbox/wind/network/standard"
+	"github.com/favbox/wind/protocol"
+)
+
+const batchSize = 16
+
+// 启动按序应答 body 的 HTTP/1.1 服务端，返回其地址
+func startPipelineServer(b *testing.B, body string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatalf("unexpected error: %s", err)
+	}
+	b.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				br := bufio.NewReader(conn)
+				bw := bufio.NewWriter(conn)
+				for {
+					req, err := http.ReadRequest(br)
+					if err != nil {
+						return
+					}
+					req.Body.Close()
+					fmt.Fprintf(bw, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+					// 管道中仍有请求时延后刷新
+					if br.Buffered() == 0 {
+						if bw.Flush() != nil {
+							return
+						}
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func newBenchmarkHostClient(b *testing.B) *HostClient {
+	return newBenchmarkBodyHostClient(b, "ok")
+}
+
+func newBenchmarkBodyHostClient(b *testing.B, body string) *HostClient {
+	return &HostClient{
+		ClientOptions: &ClientOptions{
+			Dialer:   standard.NewDialer(),
+			MaxConns: 1,
+		},
+		Addr: startPipelineServer(b, body),
+	}
+}
+
+func newBenchmarkBatch(addr string) ([]*protocol.Request, []*protocol.Response) {
+	reqs := make([]*protocol.Request, batchSize)
+	resps := make([]*protocol.Response, batchSize)
+	for i := range reqs {
+		reqs[i] = protocol.AcquireRequest()
+		reqs[i].SetRequestURI("http://" + addr + "/")
+		resps[i] = protocol.AcquireResponse()
+	}
+	return reqs, resps
+}
+
+func BenchmarkHostClient_Do(b *testing.B) {
+	c := newBenchmarkHostClient(b)
+	reqs, resps := newBenchmarkBatch(c.Addr)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range reqs {
+			if err := c.Do(context.Background(), reqs[j], resps[j]); err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+		}
+	}
+}
+
+func BenchmarkHostClient_DoBatch(b *testing.B) {
+	c := newBenchmarkHostClient(b)
+	reqs, resps := newBenchmarkBatch(c.Addr)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, err := range c.DoBatch(context.Background(), reqs, resps) {
+			if err != nil {
+				b.Fatalf("unexpected error: %s", err)
+			}
+		}
+	}
+}
+
+func benchmarkHostClientBody(b *testing.B, discard bool) {
+	c := newBenchmarkBodyHostClient(b, strings.Repeat("a", 64*1024))
+	req := protocol.AcquireRequest()
+	req.SetRequestURI("http://" + c.Addr + "/")
+	req.SetOptions(config.WithDiscardBody(discard))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 每次使用新的响应以体现正文存储的分配
+		var resp protocol.Response
+		if err := c.Do(context.Background(), req, &resp); err != nil {
+			b.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+func BenchmarkHostClient_ReadBody(b *testing.B) {
+	benchmarkHostClientBody(b, false)
+}
+
+func BenchmarkHostClient_DiscardBody(b *testing.B) {
+	benchmarkHostClientBody(b, true)
+}
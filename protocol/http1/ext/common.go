@@ -108,6 +108,31 @@ func ReadBody(r network.Reader, contentLength, maxBodySize int, dst []byte) ([]b
 	return readBodyIdentity(r, maxBodySize, dst)
 }
 
+// DiscardBody 从网络读取器读完正文并丢弃，不分配存储。
+//
+// 分块正文的标头挂车也会一并跳过，以便连接可被复用。
+func DiscardBody(r network.Reader, contentLength int) error {
+	// >= 0 固定大小跳过
+	if contentLength >= 0 {
+		return discardFixedSize(r, contentLength)
+	}
+
+	// -1 分块跳过
+	if contentLength == -1 {
+		return discardBodyChunked(r)
+	}
+
+	// 按自身长度读到连接关闭
+	for {
+		if _, err := r.Peek(1); err != nil {
+			return nil
+		}
+		if err := r.Skip(r.Len()); err != nil {
+			return err
+		}
+	}
+}
+
 // ReadTrailer 从网络读取器 r 中读取标头挂车 到 t。
 func ReadTrailer(t *protocol.Trailer, r network.Reader) error {
 	n := 1
@@ -382,6 +407,55 @@ func readBodyChunked(r network.Reader, maxBodySize int, dst []byte) ([]byte, err
 	}
 }
 
+func discardBodyChunked(r network.Reader) error {
+	strCRLFLen := len(bytestr.StrCRLF)
+	for {
+		chunkSize, err := utils.ParseChunkSize(r)
+		if err != nil {
+			return err
+		}
+		// 若是块尾，跳过 trailer 及其后的 CRLF
+		if chunkSize == 0 {
+			return SkipTrailer(r)
+		}
+		if err = discardFixedSize(r, chunkSize); err != nil {
+			return err
+		}
+		buf, err := r.Peek(strCRLFLen)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(buf, bytestr.StrCRLF) {
+			return errBrokenChunk
+		}
+		r.Skip(strCRLFLen)
+	}
+}
+
+// 分段跳过 n 个字节，每次至多跳过已缓冲的数据，避免读取器为大正文扩容。
+func discardFixedSize(r network.Reader, n int) error {
+	for n > 0 {
+		nn := r.Len()
+		if nn == 0 {
+			if _, err := r.Peek(1); err != nil {
+				if err == io.EOF {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			}
+			nn = r.Len()
+		}
+		if nn > n {
+			nn = n
+		}
+		if err := r.Skip(nn); err != nil {
+			return err
+		}
+		n -= nn
+	}
+	return nil
+}
+
 func appendBodyFixedSize(r network.Reader, dst []byte, n int) ([]byte, error) {
 	if n == 0 {
 		return dst, nil
@@ -143,6 +143,33 @@ func TestBrokenBodyChunked(t *testing.T) {
 	assert.True(t, bytes.Contains(log.Bytes(), []byte("写入分块响应体时遇到错误，这可能会导致响应体的内容不完整。")))
 }
 
+func TestDiscardBody(t *testing.T) {
+	// 固定大小
+	zr := mock.NewZeroCopyReader("foobarnext")
+	assert.Nil(t, DiscardBody(zr, 6))
+	rest, _ := zr.Peek(zr.Len())
+	assert.Equal(t, "next", string(rest))
+
+	// 分块并跳过挂车
+	zr = mock.NewZeroCopyReader("6\r\nfoobar\r\n3\r\nbaz\r\n0\r\nFoo: bar\r\n\r\nnext")
+	assert.Nil(t, DiscardBody(zr, -1))
+	rest, _ = zr.Peek(zr.Len())
+	assert.Equal(t, "next", string(rest))
+
+	// 分块数据损坏
+	zr = mock.NewZeroCopyReader("3\r\nfoobar\r\n0\r\n\r\n")
+	assert.Equal(t, errBrokenChunk, DiscardBody(zr, -1))
+
+	// 正文不完整
+	zr = mock.NewZeroCopyReader("foo")
+	assert.Equal(t, io.ErrUnexpectedEOF, DiscardBody(zr, 6))
+
+	// 读到连接关闭
+	zr = mock.NewZeroCopyReader("foobar")
+	assert.Nil(t, DiscardBody(zr, -2))
+	assert.Equal(t, 0, zr.Len())
+}
+
 func TestBodyFixedSize(t *testing.T) {
 	body := mock.CreateFixedBody(10)
 	b := bytes.NewBuffer(body)
@@ -0,0 +1,128 @@
+package http1
+
+import (
+	"io"
+	"math/rand"
+
+	"github.com/favbox/wind/network"
+)
+
+// RawTrafficFunc 接收一次请求或响应的原始字节，切片归回调所有。
+type RawTrafficFunc func(b []byte)
+
+// 是否记录本次请求的原始字节。
+func (s Server) sampleRawTraffic() bool {
+	if s.OnRawRequest == nil && s.OnRawResponse == nil {
+		return false
+	}
+	rate := s.RawTrafficSampleRate
+	return rate <= 0 || rate >= 1 || rand.Float64() < rate
+}
+
+// 追加 b 到 dst，总长度不超过 max（max <= 0 表示不限）。
+func appendRawLimited(dst, b []byte, max int) []byte {
+	if max > 0 && len(dst)+len(b) > max {
+		b = b[:max-len(dst)]
+	}
+	return append(dst, b...)
+}
+
+// rawReader 记录从连接中读取（消费）的原始字节。
+type rawReader struct {
+	network.Reader
+	buf []byte
+	max int
+}
+
+func (r *rawReader) Skip(n int) error {
+	if b, err := r.Reader.Peek(n); err == nil {
+		r.record(b)
+	}
+	return r.Reader.Skip(n)
+}
+
+func (r *rawReader) ReadByte() (byte, error) {
+	c, err := r.Reader.ReadByte()
+	if err == nil {
+		r.record([]byte{c})
+	}
+	return c, err
+}
+
+func (r *rawReader) ReadBinary(n int) ([]byte, error) {
+	b, err := r.Reader.ReadBinary(n)
+	r.record(b)
+	return b, err
+}
+
+// Read 供多部分表单解析等需要 io.Reader 的场景使用。
+func (r *rawReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.(io.Reader).Read(p)
+	r.record(p[:n])
+	return n, err
+}
+
+func (r *rawReader) record(b []byte) {
+	if r.max <= 0 || len(r.buf) < r.max {
+		r.buf = appendRawLimited(r.buf, b, r.max)
+	}
+}
+
+// 取出已记录的字节。
+func (r *rawReader) take() []byte {
+	b := r.buf
+	r.buf = nil
+	return b
+}
+
+// rawWriter 记录写入连接的原始字节。
+//
+// Malloc 分配的缓冲区在刷新前才会填充完毕，故在 Flush 时统一复制。
+type rawWriter struct {
+	network.Writer
+	enabled bool
+	pending [][]byte
+	buf     []byte
+	max     int
+}
+
+func (w *rawWriter) Malloc(n int) ([]byte, error) {
+	b, err := w.Writer.Malloc(n)
+	if err == nil && w.enabled {
+		w.pending = append(w.pending, b)
+	}
+	return b, err
+}
+
+func (w *rawWriter) WriteBinary(b []byte) (int, error) {
+	n, err := w.Writer.WriteBinary(b)
+	if w.enabled {
+		w.pending = append(w.pending, b[:n])
+	}
+	return n, err
+}
+
+func (w *rawWriter) Flush() error {
+	for _, b := range w.pending {
+		if w.max <= 0 || len(w.buf) < w.max {
+			w.buf = appendRawLimited(w.buf, b, w.max)
+		}
+	}
+	w.pending = w.pending[:0]
+	return w.Writer.Flush()
+}
+
+// 开始记录新的请求对应的响应字节。
+func (w *rawWriter) start(enabled bool) {
+	w.enabled = enabled
+	w.pending = w.pending[:0]
+	w.buf = nil
+}
+
+// 取出已记录的字节。
+func (w *rawWriter) take() []byte {
+	b := w.buf
+	w.buf = nil
+	w.enabled = false
+	return b
+}
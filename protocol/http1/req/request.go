@@ -367,6 +367,54 @@ func write(req *protocol.Request, w network.Writer, usingProxy bool) error {
 	return nil
 }
 
+// BufferBodyStream 按阈值预读长度未知的请求体流。
+//
+// 若流在 threshold 字节内读完，则转为普通正文，以 Content-Length 发送；
+// 否则将已读部分与剩余的流拼接，以 chunked 发送，不缓冲整个正文。
+// threshold <= 0、非流式正文或长度已知时不做处理。
+func BufferBodyStream(req *protocol.Request, threshold int) error {
+	if threshold <= 0 || !req.IsBodyStream() || req.Header.ContentLength() >= 0 {
+		return nil
+	}
+	stream := req.BodyStream()
+	if ext.LimitedReaderSize(stream) >= 0 {
+		return nil
+	}
+
+	buf := make([]byte, threshold+1)
+	n, err := io.ReadFull(stream, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		req.SetBody(buf[:n])
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var closer io.Closer
+	if c, ok := stream.(io.Closer); ok {
+		closer = c
+	}
+	req.ConstructBodyStream(req.BodyBuffer(), &prefixedStream{
+		Reader: io.MultiReader(bytes.NewReader(buf[:n]), stream),
+		closer: closer,
+	})
+	return nil
+}
+
+// prefixedStream 是拼接了预读数据的正文流，关闭时关闭原始流。
+type prefixedStream struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (s *prefixedStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
 func writeBodyStream(req *protocol.Request, w network.Writer) error {
 	var err error
 
@@ -1,10 +1,12 @@
 package req
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
 	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/protocol"
 	"github.com/favbox/wind/protocol/consts"
 )
@@ -95,3 +97,73 @@ func TestRequestReadNoBodyStreaming(t *testing.T) {
 		t.Fatalf("unexpected Content-Length")
 	}
 }
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestBufferBodyStream(t *testing.T) {
+	t.Parallel()
+
+	write := func(r *protocol.Request) string {
+		var buf bytes.Buffer
+		zw := network.NewWriter(&buf)
+		if err := Write(r, zw); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		zw.Flush()
+		return buf.String()
+	}
+
+	// 未超过阈值：以 Content-Length 发送
+	var r protocol.Request
+	r.SetRequestURI("http://foobar.com/upload")
+	r.SetMethod(consts.MethodPost)
+	small := &closeRecorder{Reader: strings.NewReader("hello")}
+	r.SetBodyStream(small, -1)
+	if err := BufferBodyStream(&r, 8); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if r.IsBodyStream() {
+		t.Fatalf("expecting buffered body")
+	}
+	if !small.closed {
+		t.Fatalf("expecting body stream closed")
+	}
+	if s := write(&r); !strings.Contains(s, "Content-Length: 5\r\n") || !strings.HasSuffix(s, "\r\n\r\nhello") {
+		t.Fatalf("unexpected request %q", s)
+	}
+
+	// 超过阈值：以 chunked 发送，正文完整
+	r.Reset()
+	r.SetRequestURI("http://foobar.com/upload")
+	r.SetMethod(consts.MethodPost)
+	large := &closeRecorder{Reader: strings.NewReader("hello world")}
+	r.SetBodyStream(large, -1)
+	if err := BufferBodyStream(&r, 4); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !r.IsBodyStream() {
+		t.Fatalf("expecting body stream")
+	}
+	s := write(&r)
+	if !strings.Contains(s, "Transfer-Encoding: chunked\r\n") || !strings.HasSuffix(s, "\r\n\r\n5\r\nhello\r\n6\r\n world\r\n0\r\n\r\n") {
+		t.Fatalf("unexpected request %q", s)
+	}
+	if !large.closed {
+		t.Fatalf("expecting body stream closed")
+	}
+
+	// 长度已知：不处理
+	r.Reset()
+	r.SetBodyStream(strings.NewReader("hello"), 5)
+	if err := BufferBodyStream(&r, 8); err != nil || !r.IsBodyStream() {
+		t.Fatalf("unexpected buffering, err: %v", err)
+	}
+}
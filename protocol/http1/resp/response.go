@@ -57,8 +57,9 @@ func ReadBodyStream(resp *protocol.Response, r network.Reader, maxBodySize int,
 		return err
 	}
 
-	if resp.Header.StatusCode() == consts.StatusContinue {
-		// 读取下一个响应，根据 http://www.w3.org/Protocols/rfc2616/rfc2616-sec8.html
+	for isInterimStatus(resp.Header.StatusCode()) {
+		// 跳过 100 Continue、103 Early Hints 等中间响应，读取下一个响应，
+		// 根据 http://www.w3.org/Protocols/rfc2616/rfc2616-sec8.html
 		if err = ReadHeader(&resp.Header, r); err != nil {
 			return err
 		}
@@ -93,6 +94,11 @@ func ReadBodyStream(resp *protocol.Response, r network.Reader, maxBodySize int,
 	return nil
 }
 
+// 报告是否为需跳过的 1xx 中间响应，101 Switching Protocols 为最终响应。
+func isInterimStatus(code int) bool {
+	return code >= consts.StatusContinue && code < consts.StatusOK && code != consts.StatusSwitchingProtocols
+}
+
 // Read 读取 r 到请求 req（包括正文）。
 //
 // 若 r 已关闭则返回 io.EOF。
@@ -111,8 +117,9 @@ func ReadHeaderAndLimitBody(resp *protocol.Response, zr network.Reader, maxBodyS
 	if err != nil {
 		return err
 	}
-	if resp.Header.StatusCode() == consts.StatusContinue {
-		// 读取下一个响应，根据 http://www.w3.org/Protocols/rfc2616/rfc2616-sec8.html
+	for isInterimStatus(resp.Header.StatusCode()) {
+		// 跳过 100 Continue、103 Early Hints 等中间响应，读取下一个响应，
+		// 根据 http://www.w3.org/Protocols/rfc2616/rfc2616-sec8.html
 		if err = ReadHeader(&resp.Header, zr); err != nil {
 			return err
 		}
@@ -137,6 +144,27 @@ func ReadHeaderAndLimitBody(resp *protocol.Response, zr network.Reader, maxBodyS
 	return nil
 }
 
+// ReadHeaderAndDiscardBody 读取 r 的响应头到 resp，然后读完正文并丢弃。
+//
+// 响应头（含 Content-Length）保持原样，正文为空。
+func ReadHeaderAndDiscardBody(resp *protocol.Response, zr network.Reader) error {
+	resp.ResetBody()
+	err := ReadHeader(&resp.Header, zr)
+	if err != nil {
+		return err
+	}
+	for isInterimStatus(resp.Header.StatusCode()) {
+		if err = ReadHeader(&resp.Header, zr); err != nil {
+			return err
+		}
+	}
+
+	if resp.MustSkipBody() {
+		return nil
+	}
+	return ext.DiscardBody(zr, resp.Header.ContentLength())
+}
+
 // Write 写响应到网路写入器。
 //
 // Write 出于性能原因不会刷新响应到网络写入器。
@@ -353,6 +353,10 @@ func TestResponseReadSuccess(t *testing.T) {
 	// chunked response with empty body
 	testResponseReadSuccess(t, resp, "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nTrailer: Foo5\r\nTransfer-Encoding: chunked\r\n\r\n0\r\nFoo5: bar5\r\n\r\n",
 		consts.StatusOK, 0, "text/html", "", map[string]string{"Foo5": "bar5"}, consts.HTTP11)
+
+	// 跳过 100 Continue 与 103 Early Hints 中间响应
+	testResponseReadSuccess(t, resp, "HTTP/1.1 100 Continue\r\n\r\nHTTP/1.1 103 Early Hints\r\nLink: </a.css>; rel=preload\r\n\r\nHTTP/1.1 200 OK\r\nContent-Length: 2\r\nContent-Type: foo/bar\r\n\r\nok",
+		consts.StatusOK, 2, "foo/bar", "ok", nil, consts.HTTP11)
 }
 
 func TestResponseBodyStreamWithTrailer(t *testing.T) {
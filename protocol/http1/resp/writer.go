@@ -87,3 +87,53 @@ func NewChunkedBodyWriter(r *protocol.Response, w network.Writer) network.ExtWri
 	runtime.SetFinalizer(extWriter, (*chunkedBodyWriter).release)
 	return extWriter
 }
+
+// 自动分块正文写入器，累积超过阈值后作为一个块写出并刷新。
+type autoChunkedBodyWriter struct {
+	network.ExtWriter
+	buf       []byte
+	threshold int
+}
+
+// 累积写入 p，超过阈值时分块发送。
+func (a *autoChunkedBodyWriter) Write(p []byte) (n int, err error) {
+	a.buf = append(a.buf, p...)
+	if len(a.buf) > a.threshold {
+		if err = a.Flush(); err != nil {
+			return
+		}
+	}
+	return len(p), nil
+}
+
+// Flush 写出累积的数据并刷新至对端。
+func (a *autoChunkedBodyWriter) Flush() error {
+	if len(a.buf) > 0 {
+		if _, err := a.ExtWriter.Write(a.buf); err != nil {
+			return err
+		}
+		a.buf = a.buf[:0]
+	}
+	return a.ExtWriter.Flush()
+}
+
+// Finalize 写出剩余数据及结束块。
+func (a *autoChunkedBodyWriter) Finalize() error {
+	if len(a.buf) > 0 {
+		if _, err := a.ExtWriter.Write(a.buf); err != nil {
+			return err
+		}
+		a.buf = nil
+	}
+	return a.ExtWriter.Finalize()
+}
+
+// NewAutoChunkedBodyWriter 创建一个自动分块响应体写入器。
+//
+// 写入的数据累积超过 threshold 字节后才作为一个块发送并刷新，以免小块写入频繁触发系统调用。
+func NewAutoChunkedBodyWriter(r *protocol.Response, w network.Writer, threshold int) network.ExtWriter {
+	return &autoChunkedBodyWriter{
+		ExtWriter: NewChunkedBodyWriter(r, w),
+		threshold: threshold,
+	}
+}
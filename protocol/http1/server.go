@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/favbox/wind/app"
@@ -60,9 +61,16 @@ type Option struct {
 	TLS                           *tls.Config       // 安全链接配置
 	EnableTrace                   bool              // 是否启用链路追踪
 	HTMLRender                    render.HTMLRender // HTML 渲染器
+	DefaultResponseHeaders        map[string]string // 每个响应统一注入的默认标头
+	AutoChunkThreshold            int               // 响应缓冲体超过该值时自动切换为分块发送
 
 	ContinueHandler  func(header *protocol.RequestHeader) bool // 继续读取处理器
 	HijackConnHandle func(c network.Conn, h app.HijackHandler) // 劫持连接处理器
+
+	OnRawRequest         RawTrafficFunc // 请求读取完毕后接收其原始字节，流式读取的正文不含在内
+	OnRawResponse        RawTrafficFunc // 响应刷新后接收其原始字节，劫持连接及自动分块写出的数据不含在内
+	RawTrafficMaxSize    int            // 每次记录的最大字节数，<= 0 表示不限
+	RawTrafficSampleRate float64        // 原始字节的采样率，(0, 1) 之外表示全部记录
 }
 
 // Server 表示 HTTP/1.1 服务器。实现 protocol.Server 协议接口。
@@ -140,6 +148,16 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 		serverName = s.ServerName
 	}
 
+	// 原始字节记录，未设置钩子时不包装读写器
+	var (
+		rawR *rawReader
+		rawW *rawWriter
+	)
+	if s.OnRawResponse != nil {
+		rawW = &rawWriter{Writer: ctx.GetWriter(), max: s.RawTrafficMaxSize}
+		zw = rawW
+	}
+
 	connRequestNum := uint64(0)
 
 	for {
@@ -166,6 +184,16 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 			ctx.GetConn().SetReadTimeout(s.ReadTimeout)
 		}
 
+		rawR = nil
+		sampled := s.sampleRawTraffic()
+		if sampled && s.OnRawRequest != nil {
+			rawR = &rawReader{Reader: zr, max: s.RawTrafficMaxSize}
+			zr = rawR
+		}
+		if rawW != nil {
+			rawW.start(sampled)
+		}
+
 		// 跟踪器记录请求开始和结束信息。
 		if s.EnableTrace {
 			cc = traceCtl.DoStart(c, ctx)
@@ -178,6 +206,9 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 		ctx.Response.Header.SetNoDefaultDate(s.NoDefaultDate)
 		ctx.Response.Header.SetNoDefaultContentType(s.NoDefaultContentType)
 
+		// 先于处理器注入默认标头，处理器可覆盖
+		setDefaultResponseHeaders(&ctx.Response.Header, s.DefaultResponseHeaders)
+
 		if s.DisableHeaderNamesNormalizing {
 			ctx.Request.Header.DisableNormalizing()
 			ctx.Response.Header.DisableNormalizing()
@@ -223,10 +254,13 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 			}
 
 			if err == io.EOF {
+				s.emitRawRequest(rawR)
 				return errUnexpectedEOF
 			}
 
-			writeErrorResponse(zw, ctx, serverName, err)
+			s.emitRawRequest(rawR)
+			writeErrorResponse(zw, ctx, serverName, s.DefaultResponseHeaders, err)
+			s.emitRawResponse(rawW)
 			return
 		}
 
@@ -241,7 +275,10 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 			}
 
 			if continueReadingRequest {
-				zw = ctx.GetWriter()
+				// 已包装为 rawW 时保留，以便记录 100 Continue 响应
+				if zw == nil {
+					zw = ctx.GetWriter()
+				}
 				// 发送 'HTTP/1.1 100 Continue' 响应。
 				_, err = zw.WriteBinary(bytestr.StrResponseContinue)
 				if err != nil {
@@ -262,11 +299,14 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 					err = req.ContinueReadBody(&ctx.Request, zr, s.MaxRequestBodySize, !s.DisablePreParseMultipartForm)
 				}
 				if err != nil {
-					writeErrorResponse(zw, ctx, serverName, err)
+					s.emitRawRequest(rawR)
+					writeErrorResponse(zw, ctx, serverName, s.DefaultResponseHeaders, err)
+					s.emitRawResponse(rawW)
 					return
 				}
 			}
 		}
+		s.emitRawRequest(rawR)
 
 		connectionClose = s.DisableKeepalive || ctx.Request.Header.ConnectionClose()
 		isHTTP11 = ctx.Request.Header.IsHTTP11()
@@ -275,6 +315,13 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 		if serverName != nil {
 			ctx.Response.Header.SetServerBytes(serverName)
 		}
+
+		// 分块编码仅限 HTTP/1.1，HEAD 请求无正文
+		if s.AutoChunkThreshold > 0 && isHTTP11 && !ctx.Request.Header.IsHead() {
+			ctx.Response.SetAutoChunk(s.AutoChunkThreshold, func() network.ExtWriter {
+				return resp.NewAutoChunkedBodyWriter(&ctx.Response, ctx.GetWriter(), s.AutoChunkThreshold)
+			})
+		}
 		if s.EnableTrace {
 			internalStats.Record(ctx.GetTraceInfo(), stats.ServerHandleStart, err)
 			eventsToTrigger.push(func(ti traceinfo.TraceInfo, err error) {
@@ -324,6 +371,7 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 			})
 		}
 		if err = writeResponse(ctx, zw); err != nil {
+			ctx.NotifyResponseError(normalizeWriteErr(conn, err))
 			return
 		}
 
@@ -343,8 +391,10 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 		}
 		// 刷新响应。
 		if err = zw.Flush(); err != nil {
+			ctx.NotifyResponseError(normalizeWriteErr(conn, err))
 			return
 		}
+		s.emitRawResponse(rawW)
 		if s.EnableTrace {
 			// 写入完成
 			if last := eventsToTrigger.pop(); last != nil {
@@ -394,6 +444,26 @@ func (s Server) Serve(c context.Context, conn network.Conn) (err error) {
 	}
 }
 
+// 将已读取的请求原始字节交给 OnRawRequest。
+func (s Server) emitRawRequest(r *rawReader) {
+	if r == nil {
+		return
+	}
+	if b := r.take(); len(b) > 0 {
+		s.OnRawRequest(b)
+	}
+}
+
+// 将已刷新的响应原始字节交给 OnRawResponse。
+func (s Server) emitRawResponse(w *rawWriter) {
+	if w == nil || !w.enabled {
+		return
+	}
+	if b := w.take(); len(b) > 0 {
+		s.OnRawResponse(b)
+	}
+}
+
 func defaultErrorHandler(ctx *app.RequestContext, err error) {
 	if netErr, ok := err.(*net.OpError); ok && netErr.Timeout() {
 		ctx.AbortWithMsg("请求超时", consts.StatusRequestTimeout)
@@ -404,7 +474,7 @@ func defaultErrorHandler(ctx *app.RequestContext, err error) {
 	}
 }
 
-func writeErrorResponse(zw network.Writer, ctx *app.RequestContext, serverName []byte, err error) network.Writer {
+func writeErrorResponse(zw network.Writer, ctx *app.RequestContext, serverName []byte, defaultHeaders map[string]string, err error) network.Writer {
 	errorHandler := defaultErrorHandler
 
 	errorHandler(ctx, err)
@@ -412,6 +482,7 @@ func writeErrorResponse(zw network.Writer, ctx *app.RequestContext, serverName [
 	if serverName != nil {
 		ctx.Response.Header.SetServerBytes(serverName)
 	}
+	setDefaultResponseHeaders(&ctx.Response.Header, defaultHeaders)
 	ctx.SetConnectionClose()
 	if zw == nil {
 		zw = ctx.GetWriter()
@@ -421,6 +492,12 @@ func writeErrorResponse(zw network.Writer, ctx *app.RequestContext, serverName [
 	return zw
 }
 
+func setDefaultResponseHeaders(h *protocol.ResponseHeader, headers map[string]string) {
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+}
+
 func writeResponse(ctx *app.RequestContext, w network.Writer) error {
 	// 若连接已被劫持，则跳过默认响应的写入逻辑由其自己处理
 	if ctx.Response.GetHijackWriter() != nil {
@@ -431,6 +508,17 @@ func writeResponse(ctx *app.RequestContext, w network.Writer) error {
 	return err
 }
 
+// 将客户端断开导致的写出错误统一为 errs.ErrConnectionClosed。
+func normalizeWriteErr(conn network.Conn, err error) error {
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return errs.ErrConnectionClosed
+	}
+	if en, ok := conn.(network.ErrorNormalization); ok {
+		return en.ToWindError(err)
+	}
+	return err
+}
+
 type eventStack []func(ti traceinfo.TraceInfo, err error)
 
 func (e *eventStack) isEmpty() bool {
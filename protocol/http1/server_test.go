@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -350,6 +351,163 @@ func TestHijackHandler(t *testing.T) {
 	assert.Equal(t, hijackReadTimeout, defaultConn.GetReadTimeout())
 }
 
+func TestDefaultResponseHeaders(t *testing.T) {
+	server := &Server{}
+	server.DefaultResponseHeaders = map[string]string{"X-Served-By": "wind", "X-Version": "1.0"}
+	reqCtx := &app.RequestContext{}
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return reqCtx
+		}},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			ctx.Response.Header.Set("X-Version", "2.0")
+			ctx.SetStatusCode(consts.StatusNotFound)
+		},
+	}
+	defaultConn := mock.NewConn("GET / HTTP/1.1\nHost: foobar.com\n\n")
+	err := server.Serve(context.TODO(), defaultConn)
+	assert.True(t, errors.Is(err, errs.ErrShortConnection))
+	response := protocol.AcquireResponse()
+	resp.Read(response, defaultConn.WriterRecorder())
+	assert.Equal(t, consts.StatusNotFound, response.StatusCode())
+	assert.Equal(t, "wind", response.Header.Get("X-Served-By"))
+	assert.Equal(t, "2.0", response.Header.Get("X-Version"))
+
+	// 解析出错的响应同样注入
+	errConn := mock.NewConn("GET / HTTP/1.1\nHost: foobar.com\nContent-Length: abc\n\n")
+	_ = server.Serve(context.TODO(), errConn)
+	response.Reset()
+	resp.Read(response, errConn.WriterRecorder())
+	assert.Equal(t, consts.StatusBadRequest, response.StatusCode())
+	assert.Equal(t, "wind", response.Header.Get("X-Served-By"))
+}
+
+func TestRawTraffic(t *testing.T) {
+	var rawReq, rawResp []byte
+	server := &Server{}
+	server.OnRawRequest = func(b []byte) { rawReq = b }
+	server.OnRawResponse = func(b []byte) { rawResp = b }
+	reqCtx := &app.RequestContext{}
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return reqCtx
+		}},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			ctx.SetBodyString("pong")
+		},
+	}
+	rawRequest := "POST /ping HTTP/1.1\r\nHost: foobar.com\r\nContent-Length: 4\r\n\r\nping"
+	conn := mock.NewConn(rawRequest)
+	err := server.Serve(context.TODO(), conn)
+	assert.True(t, errors.Is(err, errs.ErrShortConnection))
+	assert.Equal(t, rawRequest, string(rawReq))
+	written, _ := conn.WriterRecorder().ReadBinary(conn.WriterRecorder().WroteLen())
+	assert.Equal(t, string(written), string(rawResp))
+	assert.True(t, strings.HasSuffix(string(rawResp), "\r\n\r\npong"))
+
+	// 限长
+	server.RawTrafficMaxSize = 10
+	rawReq, rawResp = nil, nil
+	_ = server.Serve(context.TODO(), mock.NewConn(rawRequest))
+	assert.Equal(t, rawRequest[:10], string(rawReq))
+	assert.Equal(t, "HTTP/1.1 2", string(rawResp))
+}
+
+func TestRawTrafficExpect100Continue(t *testing.T) {
+	var rawReq, rawResp []byte
+	server := &Server{}
+	server.OnRawRequest = func(b []byte) { rawReq = b }
+	server.OnRawResponse = func(b []byte) { rawResp = b }
+	reqCtx := &app.RequestContext{}
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return reqCtx
+		}},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			ctx.Response.SetBody(ctx.Request.Body())
+		},
+	}
+	rawRequest := "POST /ping HTTP/1.1\r\nHost: foobar.com\r\nExpect: 100-continue\r\nContent-Length: 4\r\n\r\nping"
+	conn := mock.NewConn(rawRequest)
+	err := server.Serve(context.TODO(), conn)
+	assert.True(t, errors.Is(err, errs.ErrShortConnection))
+	assert.Equal(t, rawRequest, string(rawReq))
+	written, _ := conn.WriterRecorder().ReadBinary(conn.WriterRecorder().WroteLen())
+	assert.Equal(t, string(written), string(rawResp))
+	assert.True(t, strings.HasPrefix(string(rawResp), "HTTP/1.1 100 Continue\r\n\r\nHTTP/1.1 200 OK\r\n"))
+	assert.True(t, strings.HasSuffix(string(rawResp), "\r\n\r\nping"))
+}
+
+func TestEarlyHints(t *testing.T) {
+	server := &Server{}
+	reqCtx := &app.RequestContext{}
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return reqCtx
+		}},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			assert.Nil(t, ctx.WriteEarlyHints([]string{"</app.css>; rel=preload; as=style"}))
+			ctx.SetBodyString("ok")
+		},
+	}
+	defaultConn := mock.NewConn("GET / HTTP/1.1\nHost: foobar.com\n\n")
+	err := server.Serve(context.TODO(), defaultConn)
+	assert.True(t, errors.Is(err, errs.ErrShortConnection))
+
+	recorder := defaultConn.WriterRecorder()
+	interim, _ := recorder.Peek(recorder.WroteLen())
+	assert.True(t, strings.HasPrefix(string(interim),
+		"HTTP/1.1 103 Early Hints\r\nLink: </app.css>; rel=preload; as=style\r\n\r\nHTTP/1.1 200 OK\r\n"))
+	response := protocol.AcquireResponse()
+	resp.Read(response, recorder)
+	assert.Equal(t, consts.StatusOK, response.StatusCode())
+	assert.Equal(t, "ok", string(response.Body()))
+}
+
+func TestAutoChunk(t *testing.T) {
+	server := &Server{}
+	server.AutoChunkThreshold = 8
+	reqCtx := &app.RequestContext{}
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return reqCtx
+		}},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			ctx.WriteString("hello")
+			assert.Nil(t, ctx.Response.GetHijackWriter())
+			if string(ctx.Path()) == "/big" {
+				ctx.WriteString("hello")
+				assert.Equal(t, ctx.Request.Header.IsHTTP11(), ctx.Response.GetHijackWriter() != nil)
+				ctx.WriteString("world")
+			}
+		},
+	}
+
+	conn := mock.NewConn("GET /big HTTP/1.1\nHost: foobar.com\n\n")
+	assert.True(t, errors.Is(server.Serve(context.TODO(), conn), errs.ErrShortConnection))
+	recorder := conn.WriterRecorder()
+	raw, _ := recorder.Peek(recorder.WroteLen())
+	assert.Contains(t, string(raw), "Transfer-Encoding: chunked")
+	response := protocol.AcquireResponse()
+	assert.Nil(t, resp.Read(response, recorder))
+	assert.Equal(t, "hellohelloworld", string(response.Body()))
+
+	// 未超过阈值仍带 Content-Length 发送
+	conn = mock.NewConn("GET /small HTTP/1.1\nHost: foobar.com\n\n")
+	assert.True(t, errors.Is(server.Serve(context.TODO(), conn), errs.ErrShortConnection))
+	response.Reset()
+	assert.Nil(t, resp.Read(response, conn.WriterRecorder()))
+	assert.Equal(t, 5, response.Header.ContentLength())
+	assert.Equal(t, "hello", string(response.Body()))
+
+	// HTTP/1.0 不分块
+	conn = mock.NewConn("GET /big HTTP/1.0\nHost: foobar.com\n\n")
+	assert.True(t, errors.Is(server.Serve(context.TODO(), conn), errs.ErrShortConnection))
+	response.Reset()
+	assert.Nil(t, resp.Read(response, conn.WriterRecorder()))
+	assert.Equal(t, 15, response.Header.ContentLength())
+}
+
 func TestKeepAlive(t *testing.T) {
 	server := NewServer()
 	reqCtx := &app.RequestContext{}
@@ -441,3 +599,43 @@ func TestShouldRecordInTraceError(t *testing.T) {
 	assert.True(t, shouldRecordInTraceError(errTimeout))
 	assert.True(t, shouldRecordInTraceError(errors.New("foo error")))
 }
+
+type mockBrokenPipeWriter struct {
+	network.Conn
+}
+
+func (w *mockBrokenPipeWriter) Flush() error {
+	return syscall.EPIPE
+}
+
+func TestOnResponseError(t *testing.T) {
+	server := &Server{}
+	server.eventStackPool = pool
+	var (
+		called int
+		gotErr error
+	)
+	server.Core = &mockCore{
+		ctxPool: &sync.Pool{New: func() any {
+			return app.NewContext(0)
+		}},
+		controller: &internalStats.Controller{},
+		mockHandler: func(c context.Context, ctx *app.RequestContext) {
+			ctx.OnResponseError(func(err error) {
+				called++
+				gotErr = err
+			})
+		},
+	}
+	err := server.Serve(context.TODO(), &mockBrokenPipeWriter{
+		mock.NewConn("GET /aaa HTTP/1.1\nHost: foobar.com\n\n"),
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, called)
+	assert.True(t, errors.Is(gotErr, errs.ErrConnectionClosed))
+
+	called = 0
+	err = server.Serve(context.TODO(), mock.NewConn("GET /aaa HTTP/1.1\nHost: foobar.com\n\n"))
+	assert.True(t, errors.Is(err, errs.ErrShortConnection))
+	assert.Equal(t, 0, called)
+}
@@ -1 +1,93 @@
 package config
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// ClientConfig 是 HTTP/2 客户端的配置。
+type ClientConfig struct {
+	// TLS 配置，ALPN 会自动协商 h2。为空时使用默认配置。
+	TLSConfig *tls.Config
+
+	// 建立连接的超时时长，零表示不限制。
+	DialTimeout time.Duration
+
+	// 连接上无帧可读超过该时长后发送 PING 探活，零表示不探活。
+	ReadIdleTimeout time.Duration
+
+	// 发送 PING 后等待响应的超时时长，超时则关闭连接。零表示 15 秒。
+	PingTimeout time.Duration
+
+	// 是否严格遵守服务端通告的 SETTINGS_MAX_CONCURRENT_STREAMS。
+	// 为真时超出并发上限的请求排队等待；否则另建连接承载。
+	StrictMaxConcurrentStreams bool
+
+	// 响应体的最大字节数，零表示不限制。
+	MaxResponseBodySize int
+
+	// 是否以流的方式读取响应体。
+	ResponseBodyStream bool
+}
+
+// ClientOption 用于设置 HTTP/2 ClientConfig 的唯一结构体。
+type ClientOption struct {
+	F func(o *ClientConfig)
+}
+
+// Apply 应用给定的客户端配置项。
+func (o *ClientConfig) Apply(opts []ClientOption) {
+	for _, opt := range opts {
+		opt.F(o)
+	}
+}
+
+// NewClientConfig 创建 HTTP/2 客户端配置。
+func NewClientConfig(opts ...ClientOption) *ClientConfig {
+	cfg := &ClientConfig{}
+	cfg.Apply(opts)
+	return cfg
+}
+
+// WithClientTLSConfig 设置客户端的 TLS 配置。
+func WithClientTLSConfig(cfg *tls.Config) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.TLSConfig = cfg
+	}}
+}
+
+// WithDialTimeout 设置建立连接的超时时长。
+func WithDialTimeout(t time.Duration) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.DialTimeout = t
+	}}
+}
+
+// WithReadIdleTimeout 设置连接空闲探活的间隔与 PING 超时。
+func WithReadIdleTimeout(idle, ping time.Duration) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.ReadIdleTimeout = idle
+		o.PingTimeout = ping
+	}}
+}
+
+// WithStrictMaxConcurrentStreams 设置是否严格遵守服务端通告的最大并发流数。
+func WithStrictMaxConcurrentStreams(b bool) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.StrictMaxConcurrentStreams = b
+	}}
+}
+
+// WithMaxResponseBodySize 设置响应体的最大字节数。
+func WithMaxResponseBodySize(n int) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.MaxResponseBodySize = n
+	}}
+}
+
+// WithResponseBodyStream 设置是否以流的方式读取响应体。
+func WithResponseBodyStream(b bool) ClientOption {
+	return ClientOption{F: func(o *ClientConfig) {
+		o.ResponseBodyStream = b
+	}}
+}
@@ -21,6 +21,13 @@ type Config struct {
 	// 若为零，则根据 HTTP/2 规范的建议默认为 100个。
 	MaxConcurrentStreams uint32
 
+	// 单个连接在其生命周期内可打开的流总数，达到后服务器发送 GOAWAY 优雅关闭连接，
+	// 客户端需在新连接上继续请求，用于防止单连接被长期滥用。零表示不限制。
+	MaxStreamsPerConnection uint32
+
+	// 通过 SETTINGS_MAX_HEADER_LIST_SIZE 通告的标头列表最大字节数。若为零，则使用默认值。
+	MaxHeaderListSize uint32
+
 	// 指定服务器将读取的最大帧大小。有效值的范围是[16K,16M]。若为零，则使用默认值。
 	MaxReadFrameSize uint32
 
@@ -72,6 +79,20 @@ func WithMaxConcurrentStreams(n uint32) Option {
 	}}
 }
 
+// WithMaxStreamsPerConnection 指定单个连接在其生命周期内可打开的流总数，零表示不限制。
+func WithMaxStreamsPerConnection(n uint32) Option {
+	return Option{F: func(o *Config) {
+		o.MaxStreamsPerConnection = n
+	}}
+}
+
+// WithMaxHeaderListSize 指定通告给客户端的标头列表最大字节数。
+func WithMaxHeaderListSize(n uint32) Option {
+	return Option{F: func(o *Config) {
+		o.MaxHeaderListSize = n
+	}}
+}
+
 // WithMaxReadFrameSize 指定服务器将读取的最大帧大小。
 func WithMaxReadFrameSize(n uint32) Option {
 	return Option{F: func(o *Config) {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -12,6 +13,8 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, time.Duration(0), conf.ReadTimeout)
 	assert.Equal(t, false, conf.DisableKeepalive)
 	assert.Equal(t, uint32(0), conf.MaxConcurrentStreams)
+	assert.Equal(t, uint32(0), conf.MaxStreamsPerConnection)
+	assert.Equal(t, uint32(0), conf.MaxHeaderListSize)
 	assert.Equal(t, uint32(0), conf.MaxReadFrameSize)
 	assert.Equal(t, false, conf.PermitProhibitedCipherSuites)
 	assert.Equal(t, 10*time.Second, conf.IdleTimeout)
@@ -27,6 +30,8 @@ func TestOptions(t *testing.T) {
 		WithIdleTimeout(4*time.Second),
 		WithMaxUploadBufferPerConnection(5),
 		WithMaxUploadBufferPerStream(6),
+		WithMaxStreamsPerConnection(7),
+		WithMaxHeaderListSize(8),
 	)
 	assert.Equal(t, time.Second, conf.ReadTimeout)
 	assert.Equal(t, true, conf.DisableKeepalive)
@@ -36,4 +41,25 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, 4*time.Second, conf.IdleTimeout) // has default value
 	assert.Equal(t, int32(5), conf.MaxUploadBufferPerConnection)
 	assert.Equal(t, int32(6), conf.MaxUploadBufferPerStream)
+	assert.Equal(t, uint32(7), conf.MaxStreamsPerConnection)
+	assert.Equal(t, uint32(8), conf.MaxHeaderListSize)
+}
+
+func TestClientConfig(t *testing.T) {
+	tlsCfg := &tls.Config{}
+	cfg := NewClientConfig(
+		WithClientTLSConfig(tlsCfg),
+		WithDialTimeout(time.Second),
+		WithReadIdleTimeout(2*time.Second, 3*time.Second),
+		WithStrictMaxConcurrentStreams(true),
+		WithMaxResponseBodySize(1024),
+		WithResponseBodyStream(true),
+	)
+	assert.Equal(t, tlsCfg, cfg.TLSConfig)
+	assert.Equal(t, time.Second, cfg.DialTimeout)
+	assert.Equal(t, 2*time.Second, cfg.ReadIdleTimeout)
+	assert.Equal(t, 3*time.Second, cfg.PingTimeout)
+	assert.True(t, cfg.StrictMaxConcurrentStreams)
+	assert.Equal(t, 1024, cfg.MaxResponseBodySize)
+	assert.True(t, cfg.ResponseBodyStream)
 }
@@ -0,0 +1,272 @@
+// Package h2client 提供基于 TLS ALPN 协商的 HTTP/2 客户端（h2），非 TLS 时以先验知识直连（h2c）。
+//
+// 单个连接上多路复用并发请求，流控、GOAWAY 与 PING 探活由 golang.org/x/net/http2 处理，
+// 请求与响应复用 protocol.Request 与 protocol.Response。
+//
+// 用法：
+//
+//	c, _ := client.NewClient()
+//	c.SetClientFactory(h2client.NewClientFactory())
+package h2client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/internal/bytesconv"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/client"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/protocol/http2/config"
+	"github.com/favbox/wind/protocol/suite"
+	"golang.org/x/net/http2"
+)
+
+var (
+	_ client.HostClient   = (*HostClient)(nil)
+	_ suite.ClientFactory = (*clientFactory)(nil)
+
+	errNoH2 = errs.NewPublic("服务器未通过 ALPN 协商 h2")
+)
+
+// HostClient 是单个上游主机的 HTTP/2 客户端。
+//
+// 与 http1 的连接池不同，一个连接承载多个并发请求；
+// 仅当连接达到服务端的并发流上限（未开启 StrictMaxConcurrentStreams）或收到 GOAWAY 时才另建连接。
+type HostClient struct {
+	cfg *config.ClientConfig
+
+	once      sync.Once
+	transport *http2.Transport
+	addr      string
+	isTLS     bool
+
+	connCount int32
+}
+
+// NewHostClient 创建 HTTP/2 主机客户端，cfg 为 nil 时使用默认配置。
+func NewHostClient(cfg *config.ClientConfig) *HostClient {
+	if cfg == nil {
+		cfg = config.NewClientConfig()
+	}
+	return &HostClient{cfg: cfg, isTLS: true}
+}
+
+// SetDynamicConfig 设置上游地址及是否启用 TLS。
+func (c *HostClient) SetDynamicConfig(dc *client.DynamicConfig) {
+	c.addr = dc.Addr
+	c.isTLS = dc.IsTLS
+}
+
+// Do 发送请求并等待响应。
+func (c *HostClient) Do(ctx context.Context, req *protocol.Request, resp *protocol.Response) error {
+	c.once.Do(c.init)
+
+	if timeout := req.Options().RequestTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	hreq, err := c.newHTTPRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	hresp, err := c.transport.RoundTrip(hreq)
+	if err != nil {
+		return err
+	}
+	return c.readResponse(hresp, resp)
+}
+
+// CloseIdleConnections 关闭没有进行中的流的连接。
+func (c *HostClient) CloseIdleConnections() {
+	if c.transport != nil {
+		c.transport.CloseIdleConnections()
+	}
+}
+
+// ShouldRemove 汇报是否已无连接，可被移除。
+func (c *HostClient) ShouldRemove() bool {
+	return c.ConnectionCount() == 0
+}
+
+// ConnectionCount 返回当前打开的连接数。
+func (c *HostClient) ConnectionCount() int {
+	return int(atomic.LoadInt32(&c.connCount))
+}
+
+func (c *HostClient) init() {
+	tlsConfig := c.cfg.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	c.transport = &http2.Transport{
+		TLSClientConfig:            tlsConfig,
+		DialTLSContext:             c.dial,
+		AllowHTTP:                  !c.isTLS,
+		StrictMaxConcurrentStreams: c.cfg.StrictMaxConcurrentStreams,
+		ReadIdleTimeout:            c.cfg.ReadIdleTimeout,
+		PingTimeout:                c.cfg.PingTimeout,
+	}
+}
+
+// 拨号至 DynamicConfig 中的地址，TLS 连接须协商出 h2。
+func (c *HostClient) dial(ctx context.Context, network, addr string, cfg *tls.Config) (net.Conn, error) {
+	if c.addr != "" {
+		addr = c.addr
+	}
+	d := &net.Dialer{Timeout: c.cfg.DialTimeout}
+
+	var conn net.Conn
+	var err error
+	if c.isTLS {
+		conn, err = (&tls.Dialer{NetDialer: d, Config: cfg}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if conn.(*tls.Conn).ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+			conn.Close()
+			return nil, errNoH2
+		}
+	} else {
+		conn, err = d.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	atomic.AddInt32(&c.connCount, 1)
+	return &countedConn{Conn: conn, count: &c.connCount}, nil
+}
+
+func (c *HostClient) newHTTPRequest(ctx context.Context, req *protocol.Request) (*http.Request, error) {
+	u, err := url.Parse(string(req.URI().FullURI()))
+	if err != nil {
+		return nil, fmt.Errorf("解析请求网址出错：%w", err)
+	}
+	u.Scheme = "https"
+	if !c.isTLS {
+		u.Scheme = "http"
+	}
+
+	var body io.Reader = http.NoBody
+	contentLength := int64(0)
+	if req.IsBodyStream() {
+		body = req.BodyStream()
+		contentLength = int64(req.Header.ContentLength())
+		if contentLength < 0 {
+			contentLength = -1
+		}
+	} else if b := req.Body(); len(b) > 0 {
+		body = bytes.NewReader(b)
+		contentLength = int64(len(b))
+	}
+
+	method := string(req.Method())
+	if method == "" {
+		method = consts.MethodGet
+	}
+	hreq, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	hreq.ContentLength = contentLength
+	hreq.Host = string(req.Host())
+
+	req.Header.VisitAll(func(key, value []byte) {
+		k := bytesconv.B2s(key)
+		switch {
+		case strings.EqualFold(k, consts.HeaderHost),
+			strings.EqualFold(k, consts.HeaderContentLength),
+			strings.EqualFold(k, consts.HeaderConnection),
+			strings.EqualFold(k, consts.HeaderTransferEncoding),
+			strings.EqualFold(k, consts.HeaderTrailer):
+			// 逐跳标头不适用于 HTTP/2
+			return
+		}
+		hreq.Header.Add(k, string(value))
+	})
+	return hreq, nil
+}
+
+func (c *HostClient) readResponse(hresp *http.Response, resp *protocol.Response) error {
+	resp.Reset()
+	resp.Header.SetProtocol(hresp.Proto)
+	resp.SetStatusCode(hresp.StatusCode)
+	for k, vv := range hresp.Header {
+		if strings.EqualFold(k, consts.HeaderContentLength) {
+			continue
+		}
+		for _, v := range vv {
+			resp.Header.Add(k, v)
+		}
+	}
+
+	if c.cfg.ResponseBodyStream {
+		resp.SetBodyStream(hresp.Body, int(hresp.ContentLength))
+		return nil
+	}
+
+	defer hresp.Body.Close()
+	var r io.Reader = hresp.Body
+	if limit := c.cfg.MaxResponseBodySize; limit > 0 {
+		if hresp.ContentLength > int64(limit) {
+			return errs.ErrBodyTooLarge
+		}
+		r = io.LimitReader(r, int64(limit)+1)
+	}
+	buf := resp.BodyBuffer()
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+	if limit := c.cfg.MaxResponseBodySize; limit > 0 && buf.Len() > limit {
+		return errs.ErrBodyTooLarge
+	}
+	resp.Header.SetContentLength(buf.Len())
+
+	// 正文读完后 Trailer 才可用
+	for k, vv := range hresp.Trailer {
+		for _, v := range vv {
+			_ = resp.Header.Trailer().Add(k, v)
+		}
+	}
+	return nil
+}
+
+// 关闭时递减连接计数。
+type countedConn struct {
+	net.Conn
+	count  *int32
+	closed int32
+}
+
+func (c *countedConn) Close() error {
+	if atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		atomic.AddInt32(c.count, -1)
+	}
+	return c.Conn.Close()
+}
+
+type clientFactory struct {
+	cfg *config.ClientConfig
+}
+
+func (f *clientFactory) NewHostClient() (client.HostClient, error) {
+	return NewHostClient(f.cfg), nil
+}
+
+// NewClientFactory 创建 HTTP/2 客户端工厂，可通过 client.Client.SetClientFactory 启用。
+func NewClientFactory(opts ...config.ClientOption) suite.ClientFactory {
+	return &clientFactory{cfg: config.NewClientConfig(opts...)}
+}
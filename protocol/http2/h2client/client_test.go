@@ -0,0 +1,144 @@
+package h2client
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	errs "github.com/favbox/wind/common/errors"
+	"github.com/favbox/wind/protocol"
+	"github.com/favbox/wind/protocol/client"
+	"github.com/favbox/wind/protocol/consts"
+	"github.com/favbox/wind/protocol/http2/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func newH2Server(t *testing.T, h http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewUnstartedServer(h)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(srv *httptest.Server, opts ...config.ClientOption) *HostClient {
+	opts = append([]config.ClientOption{config.WithClientTLSConfig(&tls.Config{InsecureSkipVerify: true})}, opts...)
+	c := NewHostClient(config.NewClientConfig(opts...))
+	c.SetDynamicConfig(&client.DynamicConfig{Addr: srv.Listener.Addr().String(), IsTLS: true})
+	return c
+}
+
+func TestHostClientDo(t *testing.T) {
+	srv := newH2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Proto", r.Proto)
+		w.Header().Set("X-Echo", r.Header.Get("X-Echo"))
+		w.Header().Set("Trailer", "X-Sum")
+		w.WriteHeader(consts.StatusCreated)
+		_, _ = w.Write([]byte(r.Method + ":" + r.URL.RequestURI() + ":" + string(body)))
+		w.Header().Set("X-Sum", "ok")
+	})
+	c := newTestClient(srv)
+
+	req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+	defer protocol.ReleaseRequest(req)
+	defer protocol.ReleaseResponse(resp)
+	req.SetRequestURI("https://example.com/echo?a=1")
+	req.SetMethod(consts.MethodPost)
+	req.Header.Set("X-Echo", "wind")
+	req.SetBodyString("hello")
+
+	assert.Nil(t, c.Do(context.Background(), req, resp))
+	assert.Equal(t, consts.StatusCreated, resp.StatusCode())
+	assert.Equal(t, "HTTP/2.0", string(resp.Header.Peek("X-Proto")))
+	assert.Equal(t, "wind", string(resp.Header.Peek("X-Echo")))
+	assert.Equal(t, "POST:/echo?a=1:hello", string(resp.Body()))
+	assert.Equal(t, "ok", string(resp.Header.Trailer().Peek("X-Sum")))
+}
+
+func TestHostClientMultiplex(t *testing.T) {
+	srv := newH2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("i")))
+	})
+	c := newTestClient(srv)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i string) {
+			defer wg.Done()
+			req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+			defer protocol.ReleaseRequest(req)
+			defer protocol.ReleaseResponse(resp)
+			req.SetRequestURI("https://example.com/?i=" + i)
+			assert.Nil(t, c.Do(context.Background(), req, resp))
+			assert.Equal(t, i, string(resp.Body()))
+		}(strings.Repeat("x", i))
+	}
+	wg.Wait()
+
+	// 并发请求复用同一连接
+	assert.Equal(t, 1, c.ConnectionCount())
+	assert.False(t, c.ShouldRemove())
+}
+
+func TestHostClientBodyLimitAndStream(t *testing.T) {
+	srv := newH2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	})
+
+	req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+	defer protocol.ReleaseRequest(req)
+	defer protocol.ReleaseResponse(resp)
+	req.SetRequestURI("https://example.com/")
+
+	c := newTestClient(srv, config.WithMaxResponseBodySize(5))
+	assert.ErrorIs(t, c.Do(context.Background(), req, resp), errs.ErrBodyTooLarge)
+
+	c = newTestClient(srv, config.WithResponseBodyStream(true))
+	assert.Nil(t, c.Do(context.Background(), req, resp))
+	b, err := io.ReadAll(resp.BodyStream())
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", string(b))
+	assert.Nil(t, resp.CloseBodyStream())
+}
+
+func TestHostClientRequireH2(t *testing.T) {
+	// 仅支持 HTTP/1.1 的服务器
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	c := newTestClient(srv, config.WithClientTLSConfig(&tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2", "http/1.1"},
+	}))
+
+	req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+	defer protocol.ReleaseRequest(req)
+	defer protocol.ReleaseResponse(resp)
+	req.SetRequestURI("https://example.com/")
+	assert.ErrorIs(t, c.Do(context.Background(), req, resp), errNoH2)
+	assert.Equal(t, 0, c.ConnectionCount())
+}
+
+func TestHostClientH2C(t *testing.T) {
+	srv := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}), &http2.Server{}))
+	defer srv.Close()
+
+	c := NewHostClient(nil)
+	c.SetDynamicConfig(&client.DynamicConfig{Addr: srv.Listener.Addr().String()})
+	req, resp := protocol.AcquireRequest(), protocol.AcquireResponse()
+	defer protocol.ReleaseRequest(req)
+	defer protocol.ReleaseResponse(resp)
+	req.SetRequestURI("http://example.com/")
+	assert.Nil(t, c.Do(context.Background(), req, resp))
+	assert.Equal(t, "HTTP/2.0", string(resp.Body()))
+}
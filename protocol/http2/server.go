@@ -306,6 +306,7 @@ type serverConn struct {
 	advMaxStreams               uint32 // our SETTINGS_MAX_CONCURRENT_STREAMS advertised the client
 	curClientStreams            uint32 // number of open streams initiated by the client
 	curPushedStreams            uint32 // number of open streams initiated by server push
+	totalClientStreams          uint32 // number of streams ever opened by the client
 	maxClientStreamID           uint32 // max ever seen from client (odd), or 0 if there have been no client requests
 	maxPushPromiseID            uint32 // ID of the last push promise (even), or 0 if there have been no pushes
 	streams                     map[uint32]*stream
@@ -343,6 +344,9 @@ func (e *BaseEngine) ReleaseReqCtx(ctx *app.RequestContext) {
 }
 
 func (sc *serverConn) maxHeaderListSize() uint32 {
+	if v := sc.srv.MaxHeaderListSize; v > 0 {
+		return v
+	}
 	n := http.DefaultMaxHeaderBytes
 	// http2's count is in a slightly different unit and includes 32 bytes per pair.
 	// So, take the net/http.Server value and pad it up a bit, assuming 10 headers.
@@ -1576,6 +1580,12 @@ func (sc *serverConn) processHeaders(f *MetaHeadersFrame) error {
 		return streamError(id, ErrCodeRefusedStream)
 	}
 
+	// 单连接流总数达到上限后，处理完当前流即发送 GOAWAY，后续流将被忽略。
+	sc.totalClientStreams++
+	if max := sc.srv.MaxStreamsPerConnection; max > 0 && sc.totalClientStreams >= max {
+		sc.startGracefulShutdownInternal()
+	}
+
 	initialState := stateOpen
 	if f.StreamEnded() {
 		initialState = stateHalfClosedRemote
@@ -2220,6 +2220,67 @@ func TestServer_Rejects_Too_Many_Streams(t *testing.T) {
 	}
 }
 
+func TestServer_MaxStreamsPerConnection(t *testing.T) {
+	var handled int32
+	st := newHertzServerTester(t, func(c context.Context, ctx *app.RequestContext) {
+		atomic.AddInt32(&handled, 1)
+	}, config.WithMaxStreamsPerConnection(2), config.WithMaxHeaderListSize(4096))
+	defer st.Close()
+
+	st.greetAndCheckSettings(func(s Setting) error {
+		if s.ID == SettingMaxHeaderListSize && s.Val != 4096 {
+			t.Errorf("MAX_HEADER_LIST_SIZE = %d; want 4096", s.Val)
+		}
+		return nil
+	})
+	sendReq := func(id uint32) {
+		st.writeHeaders(HeadersFrameParam{
+			StreamID:      id,
+			BlockFragment: st.encodeHeader(),
+			EndStream:     true,
+			EndHeaders:    true,
+		})
+	}
+	sendReq(1)
+	if hf := st.wantHeaders(); hf.StreamID != 1 {
+		t.Fatalf("got HEADERS for stream %d; want 1", hf.StreamID)
+	}
+
+	// 第 2 个流达到上限：服务器发送 GOAWAY 并仍处理该流，之后的流被忽略
+	sendReq(3)
+	sendReq(5)
+	_ = st.cc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var goAway *GoAwayFrame
+	var gotStream3 bool
+	for {
+		f, err := st.readFrame()
+		if err != nil {
+			break
+		}
+		switch f := f.(type) {
+		case *GoAwayFrame:
+			goAway = f
+		case *HeadersFrame:
+			if f.StreamID != 3 {
+				t.Errorf("got HEADERS for stream %d; want only stream 3", f.StreamID)
+			}
+			gotStream3 = true
+		}
+	}
+	if goAway == nil {
+		t.Fatal("expected GOAWAY frame")
+	}
+	if goAway.ErrCode != ErrCodeNo || goAway.LastStreamID != 3 {
+		t.Errorf("GOAWAY = %v, last stream %d; want NO_ERROR, last stream 3", goAway.ErrCode, goAway.LastStreamID)
+	}
+	if !gotStream3 {
+		t.Error("expected response for stream 3")
+	}
+	if got := atomic.LoadInt32(&handled); got != 2 {
+		t.Errorf("handled %d requests; want 2", got)
+	}
+}
+
 // So many response headers that the server needs to use CONTINUATION frames:
 func TestServer_Response_ManyHeaders_With_Continuation(t *testing.T) {
 	testServerResponse(t, func(c context.Context, ctx *app.RequestContext) error {
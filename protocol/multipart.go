@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/favbox/wind/common/bytebufferpool"
+	errs "github.com/favbox/wind/common/errors"
 	"github.com/favbox/wind/common/utils"
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/protocol/consts"
@@ -74,6 +76,14 @@ func WriteMultipartForm(w io.Writer, f *multipart.Form, boundary string) error {
 	return nil
 }
 
+// MultipartLimits 表示解析多部分表单时的大小限制，零值表示不限。
+type MultipartLimits struct {
+	MaxFieldSize int // 单个文本字段的最大字节数
+	MaxFileSize  int // 单个文件的最大字节数
+	MaxTotalSize int // 表单的最大总字节数
+	MaxFiles     int // 文件的最大个数
+}
+
 // ReadMultipartForm 从 r 中读取表单信息。
 func ReadMultipartForm(r io.Reader, boundary string, size, maxInMemoryFileSize int) (*multipart.Form, error) {
 	// 不用关心此处的内存分派，因为与多部分表单发送的数据（通常几MB）相比，以下内存分配很小。
@@ -81,18 +91,168 @@ func ReadMultipartForm(r io.Reader, boundary string, size, maxInMemoryFileSize i
 	if size <= 0 {
 		return nil, fmt.Errorf("表单大小必须大于0。给定 %d", size)
 	}
-	lr := io.LimitReader(r, int64(size))
-	mr := multipart.NewReader(lr, boundary)
+	return readMultipartForm(r, boundary, size, maxInMemoryFileSize, MultipartLimits{})
+}
+
+// 按 limits 读取表单，size 大于 0 时最多读取 size 字节。
+//
+// 各项限制在读取数据流的过程中即时检查，超限时立即中止解析，
+// 并返回包装了 errors.ErrMultipartFieldTooLarge、ErrMultipartFileTooLarge、
+// ErrMultipartTotalTooLarge 或 ErrMultipartTooManyFiles 的错误。
+func readMultipartForm(r io.Reader, boundary string, size, maxInMemoryFileSize int, limits MultipartLimits) (*multipart.Form, error) {
+	if limits.MaxTotalSize > 0 && size > limits.MaxTotalSize {
+		return nil, errs.ErrMultipartTotalTooLarge
+	}
+	if size > 0 {
+		r = io.LimitReader(r, int64(size))
+	}
+	if limits.MaxTotalSize > 0 {
+		r = &multipartTotalReader{r: r, n: int64(limits.MaxTotalSize)}
+	}
+
+	var lr *multipartLimitReader
+	if limits.MaxFieldSize > 0 || limits.MaxFileSize > 0 || limits.MaxFiles > 0 {
+		lr = newMultipartLimitReader(r, boundary, limits)
+		r, boundary = lr, lr.boundary
+	}
+
+	mr := multipart.NewReader(r, boundary)
 	f, err := mr.ReadForm(int64(maxInMemoryFileSize))
+	if lr != nil {
+		if limitErr := lr.close(); isMultipartLimitError(limitErr) {
+			if f != nil {
+				_ = f.RemoveAll()
+			}
+			err = limitErr
+		}
+	}
 	if err != nil {
+		if errors.Is(err, errs.ErrMultipartTotalTooLarge) {
+			return nil, errs.ErrMultipartTotalTooLarge
+		}
+		if isMultipartLimitError(err) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("无法读取多部分表单数据体: %s", err)
 	}
 	return f, nil
 }
 
+func isMultipartLimitError(err error) bool {
+	return errors.Is(err, errs.ErrMultipartFieldTooLarge) ||
+		errors.Is(err, errs.ErrMultipartFileTooLarge) ||
+		errors.Is(err, errs.ErrMultipartTotalTooLarge) ||
+		errors.Is(err, errs.ErrMultipartTooManyFiles)
+}
+
+// 逐个读取原始表单的各部分并检查单项限制，再经管道转交给 multipart.Reader.ReadForm。
+//
+// 超限的部分只会读取到限制值加一字节，随后管道以限制错误关闭，ReadForm 随之中止，
+// 不会将整个超限部分缓存到内存或临时文件。
+type multipartLimitReader struct {
+	pr       *io.PipeReader
+	boundary string
+	done     chan error
+}
+
+func newMultipartLimitReader(r io.Reader, boundary string, limits MultipartLimits) *multipartLimitReader {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	lr := &multipartLimitReader{
+		pr:       pr,
+		boundary: mw.Boundary(),
+		done:     make(chan error, 1),
+	}
+	go func() {
+		err := copyMultipartParts(mw, multipart.NewReader(r, boundary), limits)
+		_ = pw.CloseWithError(err)
+		lr.done <- err
+	}()
+	return lr
+}
+
+func (r *multipartLimitReader) Read(p []byte) (int, error) {
+	return r.pr.Read(p)
+}
+
+// 关闭管道并等待转交协程退出，返回其遇到的错误。
+func (r *multipartLimitReader) close() error {
+	_ = r.pr.CloseWithError(io.ErrClosedPipe)
+	return <-r.done
+}
+
+// 将 mr 中的各部分原样写入 mw，超出 limits 时返回相应的限制错误。
+func copyMultipartParts(mw *multipart.Writer, mr *multipart.Reader, limits MultipartLimits) error {
+	files := 0
+	for {
+		p, err := mr.NextRawPart()
+		if err == io.EOF {
+			return mw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		// 与 ReadForm 一致，忽略没有字段名的部分
+		limit, name := 0, p.FormName()
+		if name != "" {
+			if p.FileName() == "" {
+				limit = limits.MaxFieldSize
+			} else {
+				files++
+				if limits.MaxFiles > 0 && files > limits.MaxFiles {
+					return fmt.Errorf("%w: 最多 %d 个", errs.ErrMultipartTooManyFiles, limits.MaxFiles)
+				}
+				limit = limits.MaxFileSize
+			}
+		}
+
+		w, err := mw.CreatePart(p.Header)
+		if err != nil {
+			return err
+		}
+		var src io.Reader = p
+		if limit > 0 {
+			src = io.LimitReader(p, int64(limit)+1)
+		}
+		n, err := io.Copy(w, src)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && n > int64(limit) {
+			if p.FileName() == "" {
+				return fmt.Errorf("%w: 字段 %q", errs.ErrMultipartFieldTooLarge, name)
+			}
+			return fmt.Errorf("%w: 字段 %q 文件 %q", errs.ErrMultipartFileTooLarge, name, p.FileName())
+		}
+	}
+}
+
+// 读取超过 n 字节时返回 errs.ErrMultipartTotalTooLarge。
+type multipartTotalReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *multipartTotalReader) Read(p []byte) (int, error) {
+	if r.n < 0 {
+		return 0, errs.ErrMultipartTotalTooLarge
+	}
+	n, err := r.r.Read(p)
+	r.n -= int64(n)
+	if r.n < 0 {
+		// 丢弃超限部分，避免表单在读到错误前就已完整解析
+		return n + int(r.n), errs.ErrMultipartTotalTooLarge
+	}
+	return n, err
+}
+
 // ParseMultipartForm 从 r 中读取表单信息。
 func ParseMultipartForm(r io.Reader, request *Request, size, maxInMemoryFileSize int) error {
-	m, err := ReadMultipartForm(r, request.multipartFormBoundary, size, maxInMemoryFileSize)
+	if size <= 0 {
+		return fmt.Errorf("表单大小必须大于0。给定 %d", size)
+	}
+	m, err := readMultipartForm(r, request.multipartFormBoundary, size, maxInMemoryFileSize, request.multipartLimits)
 	if err != nil {
 		return err
 	}
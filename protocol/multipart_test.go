@@ -2,11 +2,14 @@ package protocol
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
 	"strings"
 	"testing"
 
+	errs "github.com/favbox/wind/common/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,6 +91,86 @@ value
 	assert.NotNil(t, err)
 }
 
+func TestParseMultipartFormLimits(t *testing.T) {
+	t.Parallel()
+	s := strings.Replace(`--foo
+Content-Disposition: form-data; name="name"
+
+wind
+--foo
+Content-Disposition: form-data; name="avatar"; filename="a.png"
+Content-Type: image/png
+
+0123456789
+--foo--
+`, "\n", "\r\n", -1)
+
+	parse := func(limits MultipartLimits) error {
+		req := Request{}
+		req.SetMultipartFormBoundary("foo")
+		req.SetMultipartLimits(limits)
+		return ParseMultipartForm(strings.NewReader(s), &req, len(s), 1024)
+	}
+
+	assert.Nil(t, parse(MultipartLimits{MaxFieldSize: 4, MaxFileSize: 10, MaxTotalSize: len(s)}))
+	assert.ErrorIs(t, parse(MultipartLimits{MaxFieldSize: 3}), errs.ErrMultipartFieldTooLarge)
+	assert.ErrorIs(t, parse(MultipartLimits{MaxFileSize: 9}), errs.ErrMultipartFileTooLarge)
+	assert.ErrorIs(t, parse(MultipartLimits{MaxTotalSize: len(s) - 1}), errs.ErrMultipartTotalTooLarge)
+
+	// 流式请求体同样受限
+	req := Request{}
+	req.Header.SetContentTypeBytes([]byte("multipart/form-data; boundary=foo"))
+	req.SetBodyStream(strings.NewReader(s), -1)
+	req.SetMultipartLimits(MultipartLimits{MaxTotalSize: 64})
+	_, err := req.MultipartForm()
+	assert.ErrorIs(t, err, errs.ErrMultipartTotalTooLarge)
+}
+
+func TestParseMultipartFormLimitsStreaming(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for i := 0; i < 2; i++ {
+		w, err := mw.CreateFormFile("file", fmt.Sprintf("%d.bin", i))
+		assert.Nil(t, err)
+		_, err = w.Write(bytes.Repeat([]byte("a"), 1<<20))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, mw.Close())
+	body := buf.Bytes()
+
+	parse := func(limits MultipartLimits) (*countingReader, error) {
+		r := &countingReader{r: bytes.NewReader(body)}
+		req := Request{}
+		req.SetMultipartFormBoundary(mw.Boundary())
+		req.SetMultipartLimits(limits)
+		return r, ParseMultipartForm(r, &req, len(body), 1024)
+	}
+
+	// 超限文件在读取过程中即被拒绝，无需读完整个请求体
+	r, err := parse(MultipartLimits{MaxFileSize: 10})
+	assert.ErrorIs(t, err, errs.ErrMultipartFileTooLarge)
+	assert.Less(t, r.n, len(body)/2)
+
+	r, err = parse(MultipartLimits{MaxFiles: 1})
+	assert.ErrorIs(t, err, errs.ErrMultipartTooManyFiles)
+	assert.Less(t, r.n, len(body))
+
+	_, err = parse(MultipartLimits{MaxFiles: 2, MaxFileSize: 1 << 20})
+	assert.Nil(t, err)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
+
 func TestWriteMultipartFormFile(t *testing.T) {
 	t.Parallel()
 	bodyBuffer := &bytes.Buffer{}
@@ -24,7 +24,7 @@ import (
 )
 
 var (
-	errMissingFile = errors.NewPublic("http: 无此文件")
+	errMissingFile = errors.New(errors.ErrMissingFile, errors.ErrorTypePublic, nil)
 
 	// 请求体缓冲池，减少 GC
 	requestBodyPool bytebufferpool.Pool
@@ -69,6 +69,7 @@ type Request struct {
 	multipartFormBoundary string
 	multipartFiles        []*File
 	multipartFields       []*MultipartField
+	multipartLimits       MultipartLimits
 
 	// URI 是否已解析
 	parsedURI bool
@@ -389,7 +390,7 @@ func (req *Request) MultipartForm() (*multipart.Form, error) {
 		} else if len(ce) > 0 {
 			return nil, fmt.Errorf("不支持的内容编码：%q", ce)
 		}
-		f, err = ReadMultipartForm(bytes.NewReader(body), req.multipartFormBoundary, len(body), len(body))
+		f, err = readMultipartForm(bytes.NewReader(body), req.multipartFormBoundary, len(body), len(body), req.multipartLimits)
 	} else {
 		bodyStream := req.bodyStream
 		if req.Header.contentLength > 0 {
@@ -404,9 +405,7 @@ func (req *Request) MultipartForm() (*multipart.Form, error) {
 			return nil, fmt.Errorf("不支持的内容编码：%q", ce)
 		}
 
-		mr := multipart.NewReader(bodyStream, req.multipartFormBoundary)
-
-		f, err = mr.ReadForm(8 * 1024)
+		f, err = readMultipartForm(bodyStream, req.multipartFormBoundary, 0, 8*1024, req.multipartLimits)
 	}
 
 	if err != nil {
@@ -416,6 +415,42 @@ func (req *Request) MultipartForm() (*multipart.Form, error) {
 	return f, nil
 }
 
+// MultipartReader 返回按部分流式读取请求表单的读取器，不缓冲整个请求体。
+//
+// 若请求的内容类型不是 'multipart/form-data' 则返回 errors.ErrNoMultipartForm；
+// 若表单已由 MultipartForm 解析则返回 errors.ErrMultipartParsed。
+func (req *Request) MultipartReader() (*multipart.Reader, error) {
+	if req.multipartForm != nil {
+		return nil, errors.ErrMultipartParsed
+	}
+	req.multipartFormBoundary = string(req.Header.MultipartFormBoundary())
+	if len(req.multipartFormBoundary) == 0 {
+		return nil, errors.ErrNoMultipartForm
+	}
+
+	var r io.Reader
+	if req.IsBodyStream() {
+		r = req.bodyStream
+		if req.Header.contentLength > 0 {
+			r = io.LimitReader(r, int64(req.Header.contentLength))
+		}
+	} else {
+		r = bytes.NewReader(req.BodyBytes())
+	}
+
+	ce := req.Header.peek(bytestr.StrContentEncoding)
+	if bytes.Equal(ce, bytestr.StrGzip) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("无法解压缩请求体：%w", err)
+		}
+		r = zr
+	} else if len(ce) > 0 {
+		return nil, fmt.Errorf("不支持的内容编码：%q", ce)
+	}
+	return multipart.NewReader(r, req.multipartFormBoundary), nil
+}
+
 func (req *Request) MultipartFormBoundary() string {
 	return req.multipartFormBoundary
 }
@@ -704,6 +739,11 @@ func (req *Request) SetMaxKeepBodySize(n int) {
 	req.maxKeepBodySize = n
 }
 
+// SetMultipartLimits 设置解析多部分表单时的大小限制。
+func (req *Request) SetMultipartLimits(limits MultipartLimits) {
+	req.multipartLimits = limits
+}
+
 // SetMethod 设置请求的方法。
 func (req *Request) SetMethod(method string) {
 	req.Header.SetMethod(method)
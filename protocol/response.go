@@ -63,6 +63,10 @@ type Response struct {
 
 	// 若设置劫持写入器，wind 将跳过默认的响应头/体的写入过程。
 	hijackWriter network.ExtWriter
+
+	// 缓冲体超过 autoChunkThreshold 时，由 newAutoChunkWriter 创建劫持写入器转为分块发送。
+	autoChunkThreshold int
+	newAutoChunkWriter func() network.ExtWriter
 }
 
 type responseBodyWriter struct {
@@ -84,6 +88,7 @@ func (resp *Response) AppendBody(p []byte) {
 		return
 	}
 	_, _ = resp.BodyBuffer().Write(p)
+	resp.tryAutoChunk()
 }
 
 // AppendBodyString 追加 s 至响应的主体字节缓冲区。
@@ -94,6 +99,43 @@ func (resp *Response) AppendBodyString(s string) {
 		return
 	}
 	_, _ = resp.BodyBuffer().WriteString(s)
+	resp.tryAutoChunk()
+}
+
+// SetAutoChunk 设置自动分块：追加写入使缓冲体超过 threshold 时，
+// 以 newWriter 创建的写入器作为劫持写入器，发送已缓冲部分，后续写入也经由其发送。
+//
+// 通常由服务器设置，threshold <= 0 时禁用。
+func (resp *Response) SetAutoChunk(threshold int, newWriter func() network.ExtWriter) {
+	resp.autoChunkThreshold = threshold
+	resp.newAutoChunkWriter = newWriter
+}
+
+func (resp *Response) tryAutoChunk() {
+	if resp.autoChunkThreshold <= 0 || resp.newAutoChunkWriter == nil || resp.body.Len() <= resp.autoChunkThreshold {
+		return
+	}
+	w := resp.newAutoChunkWriter()
+	resp.newAutoChunkWriter = nil
+	resp.hijackWriter = w
+	_, _ = w.Write(resp.body.B)
+	resp.body.Reset()
+}
+
+// PreallocBody 预分配响应主体缓冲区，使其容量至少为 size 字节。
+//
+// 仅作为减少扩容的优化提示，不改变主体内容；已有内容会保留，流式主体或劫持写入时忽略。
+func (resp *Response) PreallocBody(size int) {
+	if size <= 0 || resp.bodyStream != nil || resp.hijackWriter != nil {
+		return
+	}
+	buf := resp.BodyBuffer()
+	if cap(buf.B) >= size {
+		return
+	}
+	b := make([]byte, len(buf.B), size)
+	copy(b, buf.B)
+	buf.B = b
 }
 
 // Body 返回响应的主体。
@@ -292,6 +334,8 @@ func (resp *Response) Reset() {
 	resp.laddr = nil
 	resp.ImmediateHeaderFlush = false
 	resp.hijackWriter = nil
+	resp.autoChunkThreshold = 0
+	resp.newAutoChunkWriter = nil
 }
 
 // ResetBody 只重置响应的主体。
@@ -253,3 +253,26 @@ func TestResponse_HijackWriter(t *testing.T) {
 	_ = resp.GetHijackWriter().Finalize()
 	assert.True(t, isFinal)
 }
+
+func TestResponsePreallocBody(t *testing.T) {
+	resp := AcquireResponse()
+	defer ReleaseResponse(resp)
+
+	resp.SetBodyString("hello")
+	resp.PreallocBody(1024)
+	assert.True(t, cap(resp.BodyBuffer().B) >= 1024)
+	assert.Equal(t, "hello", string(resp.Body()))
+
+	resp.AppendBodyString(" world")
+	assert.Equal(t, "hello world", string(resp.Body()))
+
+	// 容量已足够时不重新分配
+	b := resp.BodyBuffer().B
+	resp.PreallocBody(16)
+	assert.Equal(t, &b[:1][0], &resp.BodyBuffer().B[:1][0])
+
+	// 流式主体忽略
+	resp.SetBodyStream(bytes.NewReader([]byte("abc")), 3)
+	resp.PreallocBody(4096)
+	assert.True(t, resp.IsBodyStream())
+}
@@ -65,6 +65,11 @@ func (t *Trailer) GetTrailers() []argsKV {
 	return t.h
 }
 
+// Count 返回已声明的 Trailer 键数量。
+func (t *Trailer) Count() int {
+	return len(t.h)
+}
+
 // Empty 判断 Trailer 标头切片是否为空。
 func (t *Trailer) Empty() bool {
 	return len(t.h) == 0
@@ -123,6 +123,33 @@ func TestTrailerVisitAll(t *testing.T) {
 		})
 }
 
+func TestTrailerCount(t *testing.T) {
+	var tr Trailer
+	assert.Equal(t, 0, tr.Count())
+	assert.Nil(t, tr.SetTrailers([]byte("foo, bar")))
+	assert.Equal(t, 2, tr.Count())
+	tr.Del("foo")
+	assert.Equal(t, 1, tr.Count())
+}
+
+func TestHeaderVisitAllTrailer(t *testing.T) {
+	var keys []string
+	collect := func(k []byte) { keys = append(keys, string(k)) }
+
+	var resp ResponseHeader
+	resp.VisitAllTrailer(collect)
+	assert.Nil(t, keys)
+	assert.Nil(t, resp.Trailer().SetTrailers([]byte("foo, bar")))
+	resp.VisitAllTrailer(collect)
+	assert.Equal(t, []string{"Foo", "Bar"}, keys)
+
+	keys = nil
+	var req RequestHeader
+	assert.Nil(t, req.Trailer().SetTrailers([]byte("baz")))
+	req.VisitAllTrailer(collect)
+	assert.Equal(t, []string{"Baz"}, keys)
+}
+
 func TestIsBadTrailer(t *testing.T) {
 	assert.True(t, IsBadTrailer(bytestr.StrAuthorization))
 	assert.True(t, IsBadTrailer(bytestr.StrContentEncoding))
@@ -0,0 +1,173 @@
+package route
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/favbox/wind/common/json"
+	"github.com/favbox/wind/common/utils"
+)
+
+// 路由树的导出格式。
+const (
+	DumpFormatText = "text" // 树形文本
+	DumpFormatJSON = "json" // JSON
+	DumpFormatDOT  = "dot"  // Graphviz DOT 图
+)
+
+// RouteTree 表示一个请求方法的路由树。
+type RouteTree struct {
+	Method string     `json:"method"`
+	Root   *RouteNode `json:"root"`
+}
+
+// RouteNode 表示路由树的一个节点。
+type RouteNode struct {
+	Kind     string       `json:"kind"`               // 节点类型：static、param 或 any
+	Prefix   string       `json:"prefix"`             // 节点前缀
+	Path     string       `json:"path,omitempty"`     // 路由的完整路径，仅含处理器的节点有值
+	Params   []string     `json:"params,omitempty"`   // 路由的参数名称
+	Handlers []string     `json:"handlers,omitempty"` // 处理链中各处理器的名称
+	Children []*RouteNode `json:"children,omitempty"` // 子节点，依次为静态、命名参数和通配参数节点
+}
+
+// RouteTrees 返回所有请求方法的路由树快照。
+func (engine *Engine) RouteTrees() []RouteTree {
+	trees := make([]RouteTree, 0, len(engine.trees))
+	for _, tree := range engine.trees {
+		trees = append(trees, RouteTree{Method: tree.method, Root: dumpNode(tree.root)})
+	}
+	return trees
+}
+
+// DumpRoutes 以给定格式将路由树写入 w。
+//
+// 支持的格式有 DumpFormatText、DumpFormatJSON 和 DumpFormatDOT，为空则默认为 DumpFormatText。
+func (engine *Engine) DumpRoutes(w io.Writer, format string) error {
+	trees := engine.RouteTrees()
+	switch format {
+	case "", DumpFormatText:
+		return dumpText(w, trees)
+	case DumpFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(trees)
+	case DumpFormatDOT:
+		return dumpDOT(w, trees)
+	default:
+		return fmt.Errorf("不支持的路由树导出格式：%q", format)
+	}
+}
+
+func dumpNode(n *node) *RouteNode {
+	if n == nil {
+		return nil
+	}
+	rn := &RouteNode{
+		Kind:   n.kind.String(),
+		Prefix: n.prefix,
+	}
+	if len(n.handlers) > 0 {
+		rn.Path = n.ppath
+		rn.Params = append([]string(nil), n.pnames...)
+		rn.Handlers = make([]string, len(n.handlers))
+		for i, h := range n.handlers {
+			rn.Handlers[i] = utils.NameOfFunction(h)
+		}
+	}
+	for _, child := range n.children {
+		rn.Children = append(rn.Children, dumpNode(child))
+	}
+	if n.paramChild != nil {
+		rn.Children = append(rn.Children, dumpNode(n.paramChild))
+	}
+	if n.anyChild != nil {
+		rn.Children = append(rn.Children, dumpNode(n.anyChild))
+	}
+	return rn
+}
+
+func (k kind) String() string {
+	switch k {
+	case pkind:
+		return "param"
+	case akind:
+		return "any"
+	default:
+		return "static"
+	}
+}
+
+// 树形文本，如：
+//
+//	GET
+//	└── /
+//	    ├── ping  [static] /ping --> main.ping (1 个处理器)
+//	    └── users/
+//	        └── :  [param] /users/:id (id) --> main.getUser (2 个处理器)
+func dumpText(w io.Writer, trees []RouteTree) error {
+	var sb strings.Builder
+	for _, tree := range trees {
+		sb.WriteString(tree.Method)
+		sb.WriteByte('\n')
+		writeTextNode(&sb, tree.Root, "", true)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeTextNode(sb *strings.Builder, n *RouteNode, indent string, last bool) {
+	if n == nil {
+		return
+	}
+	branch, next := "├── ", "│   "
+	if last {
+		branch, next = "└── ", "    "
+	}
+	sb.WriteString(indent + branch + n.Prefix)
+	if n.Path != "" {
+		fmt.Fprintf(sb, "  [%s] %s", n.Kind, n.Path)
+		if len(n.Params) > 0 {
+			fmt.Fprintf(sb, " (%s)", strings.Join(n.Params, ", "))
+		}
+		fmt.Fprintf(sb, " --> %s (%d 个处理器)", n.Handlers[len(n.Handlers)-1], len(n.Handlers))
+	}
+	sb.WriteByte('\n')
+	for i, child := range n.Children {
+		writeTextNode(sb, child, indent+next, i == len(n.Children)-1)
+	}
+}
+
+func dumpDOT(w io.Writer, trees []RouteTree) error {
+	var sb strings.Builder
+	sb.WriteString("digraph routes {\n")
+	sb.WriteString("  node [shape=box];\n")
+	id := 0
+	for _, tree := range trees {
+		methodID := fmt.Sprintf("n%d", id)
+		id++
+		fmt.Fprintf(&sb, "  %s [label=%q, shape=ellipse];\n", methodID, tree.Method)
+		writeDOTNode(&sb, tree.Root, methodID, &id)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeDOTNode(sb *strings.Builder, n *RouteNode, parentID string, id *int) {
+	if n == nil {
+		return
+	}
+	nodeID := fmt.Sprintf("n%d", *id)
+	*id++
+	label := n.Prefix
+	if n.Path != "" {
+		label += "\\n" + n.Path + "\\n" + strings.Join(n.Handlers, " → ")
+	}
+	fmt.Fprintf(sb, "  %s [label=\"%s\"];\n", nodeID, strings.ReplaceAll(label, `"`, `\"`))
+	fmt.Fprintf(sb, "  %s -> %s [label=%q];\n", parentID, nodeID, n.Kind)
+	for _, child := range n.Children {
+		writeDOTNode(sb, child, nodeID, id)
+	}
+}
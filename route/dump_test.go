@@ -0,0 +1,75 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDumpTestEngine() *Engine {
+	e := NewEngine(config.NewOptions(nil))
+	mw := func(c context.Context, ctx *app.RequestContext) {}
+	h := func(c context.Context, ctx *app.RequestContext) {}
+	e.Use(mw)
+	e.GET("/ping", h)
+	e.GET("/users/:id", h)
+	e.GET("/static/*filepath", h)
+	e.POST("/users", h)
+	return e
+}
+
+func TestEngine_DumpRoutesText(t *testing.T) {
+	e := newDumpTestEngine()
+	var buf bytes.Buffer
+	assert.Nil(t, e.DumpRoutes(&buf, DumpFormatText))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "GET\n"))
+	assert.Contains(t, out, "[static] /ping")
+	assert.Contains(t, out, "[param] /users/:id (id)")
+	assert.Contains(t, out, "[any] /static/*filepath (filepath)")
+	assert.Contains(t, out, "POST\n")
+	assert.Contains(t, out, "(2 个处理器)")
+}
+
+func TestEngine_DumpRoutesJSON(t *testing.T) {
+	e := newDumpTestEngine()
+	var buf bytes.Buffer
+	assert.Nil(t, e.DumpRoutes(&buf, DumpFormatJSON))
+
+	var trees []RouteTree
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &trees))
+	assert.Equal(t, 2, len(trees))
+	assert.Equal(t, "GET", trees[0].Method)
+
+	var paths []string
+	var walk func(n *RouteNode)
+	walk = func(n *RouteNode) {
+		if n.Path != "" {
+			paths = append(paths, n.Kind+" "+n.Path)
+			assert.Equal(t, 2, len(n.Handlers))
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(trees[0].Root)
+	assert.ElementsMatch(t, []string{"static /ping", "param /users/:id", "any /static/*filepath"}, paths)
+}
+
+func TestEngine_DumpRoutesDOT(t *testing.T) {
+	e := newDumpTestEngine()
+	var buf bytes.Buffer
+	assert.Nil(t, e.DumpRoutes(&buf, DumpFormatDOT))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "digraph routes {"))
+	assert.Contains(t, out, `[label="GET", shape=ellipse]`)
+	assert.Contains(t, out, `[label="param"]`)
+
+	assert.NotNil(t, e.DumpRoutes(&buf, "yaml"))
+}
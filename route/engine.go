@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"net/url"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/favbox/wind/app"
 	"github.com/favbox/wind/app/server/binding"
@@ -36,6 +39,8 @@ import (
 	"github.com/favbox/wind/protocol/http1"
 	"github.com/favbox/wind/protocol/http1/factory"
 	"github.com/favbox/wind/protocol/suite"
+	rConsts "github.com/favbox/wind/route/consts"
+	"github.com/favbox/wind/route/param"
 )
 
 const unknownTransporterName = "unknown"
@@ -74,6 +79,15 @@ type CtxCallback func(ctx context.Context)
 // CtxErrCallback 引擎关闭时，同时触发的钩子函数
 type CtxErrCallback func(ctx context.Context) error
 
+// SlowRequestCallback 请求处理耗时超过慢请求阈值时触发的钩子函数。
+type SlowRequestCallback func(c context.Context, ctx *app.RequestContext, latency time.Duration)
+
+// RawTrafficCallback 接收请求或响应原始字节的钩子函数，切片归钩子所有。
+type RawTrafficCallback func(b []byte)
+
+// PreRoutingFunc 路由匹配前执行的预处理钩子，返回 false 则中止请求（响应应已由钩子写入）。
+type PreRoutingFunc func(c context.Context, ctx *app.RequestContext) bool
+
 // Deprecated: 仅用于获取全局默认传输器 - 可能并非引擎真正使用的。
 // 使用 *Engine.GetTransporterName 获取真实使用的传输器。
 func GetTransporterName() (tName string) {
@@ -111,6 +125,7 @@ func NewEngine(opts *config.Options) *Engine {
 		protocolStreamServers: make(map[string]protocol.StreamServer),
 		enableTrace:           true,
 		options:               opts,
+		secureJSONPrefix:      render.DefaultSecureJSONPrefix,
 	}
 	engine.initBinderAndValidator(opts)
 	if opts.TransporterNewer != nil {
@@ -133,6 +148,7 @@ func NewEngine(opts *config.Options) *Engine {
 
 	// 初始化协议组
 	engine.protocolSuite = suite.New()
+	engine.rebuildOptionsHandlers()
 
 	return engine
 }
@@ -157,6 +173,7 @@ type Engine struct {
 
 	allNoMethod app.HandlersChain // 框架级方法不允许处理器
 	allNoRoute  app.HandlersChain // 框架级路由找不到处理器
+	allOptions  app.HandlersChain // 框架级 OPTIONS 自动响应处理器
 	noRoute     app.HandlersChain // 用户级路由找不到处理器
 	noMethod    app.HandlersChain // 用户级方法不允许处理器
 
@@ -209,11 +226,44 @@ type Engine struct {
 	// OnShutdown 是引擎关闭时，并行触发的一组钩子函数。
 	OnShutdown []CtxCallback
 
+	// OnSlowRequest 是请求处理耗时超过 config.Options.SlowRequestThreshold 时触发的钩子函数。
+	// 可通过 ctx 获取请求方法、路径和客户端 IP 等信息。
+	OnSlowRequest SlowRequestCallback
+
+	// OnRawRequest 在 HTTP/1.1 请求读取完毕后接收其完整的原始字节，用于审计留痕。
+	// 流式读取的请求体不含在内。需在引擎启动前设置，未设置时无额外开销。
+	OnRawRequest RawTrafficCallback
+
+	// OnRawResponse 在 HTTP/1.1 响应发送后接收其完整的原始字节，用于审计留痕。
+	// 劫持连接及自动分块写出的数据不含在内。需在引擎启动前设置，未设置时无额外开销。
+	//
+	// 开启后被采样的请求会额外复制一次收发的字节，
+	// 可通过 config.Options 的 RawTrafficMaxSize 和 RawTrafficSampleRate 限长和采样。
+	OnRawResponse RawTrafficCallback
+
+	// 正在处理中的请求数。
+	inFlight int64
+
 	clientIPFunc  app.ClientIP      // 自定义获取客户端 IP 的函数。
 	formValueFunc app.FormValueFunc // 自定义获取表单值的函数。
 
+	secureJSONPrefix string        // SecureJSON 的数组前缀。
+	envelope         *app.Envelope // ctx.OK、ctx.Fail 的统一响应格式。
+
 	binder    binding.Binder          // 自定义请求参数绑定器。
 	validator binding.StructValidator // 自定义请求参数验证器。
+
+	preRouting []PreRoutingFunc // 路由匹配前的预处理钩子。
+
+	namedRoutes map[string]Route // 按名称索引的命名路由。
+
+	prefixMiddlewares []prefixMiddleware // 按路由前缀应用的中间件。
+}
+
+// 按路由前缀应用的中间件
+type prefixMiddleware struct {
+	prefix   string
+	handlers app.HandlersChain
 }
 
 // NewContext 创建一个无请求/无响应信息的纯粹上下文。
@@ -257,7 +307,7 @@ func (engine *Engine) listenAndServe() error {
 func (engine *Engine) onData(ctx context.Context, conn any) (err error) {
 	switch conn := conn.(type) {
 	case network.Conn:
-		err = engine.Serve(ctx, conn)
+		err = engine.Serve(ctx, network.WrapConn(conn, engine.options.ConnWrappers...))
 	case network.StreamConn:
 		err = engine.ServeStream(ctx, conn)
 	}
@@ -467,6 +517,7 @@ func (engine *Engine) initBinderAndValidator(opt *config.Options) {
 	// 初始化绑定器。由于存在 "BindAndValidate" 接口，此处需注入 Validator。
 	defaultBindConfig := binding.NewBindConfig()
 	defaultBindConfig.Validator = engine.validator
+	defaultBindConfig.MaxRawBodySize = opt.MaxRequestBodySize
 	engine.binder = binding.NewBinder(defaultBindConfig)
 	if opt.BindConfig != nil {
 		bConf, ok := opt.BindConfig.(*binding.BindConfig)
@@ -476,10 +527,62 @@ func (engine *Engine) initBinderAndValidator(opt *config.Options) {
 		if bConf.Validator == nil {
 			bConf.Validator = engine.validator
 		}
+		if bConf.MaxRawBodySize <= 0 {
+			bConf.MaxRawBodySize = opt.MaxRequestBodySize
+		}
 		engine.binder = binding.NewBinder(bConf)
 	}
 }
 
+// 创建注入给定验证器的默认绑定器，引擎使用自定义绑定器时返回 nil。
+func (engine *Engine) newGroupBinder(validator binding.StructValidator) binding.Binder {
+	if engine.options.CustomBinder != nil {
+		return nil
+	}
+	bConf := binding.NewBindConfig()
+	if engine.options.BindConfig != nil {
+		c := *engine.options.BindConfig.(*binding.BindConfig)
+		bConf = &c
+	}
+	bConf.Validator = validator
+	if bConf.MaxRawBodySize <= 0 {
+		bConf.MaxRawBodySize = engine.options.MaxRequestBodySize
+	}
+	return binding.NewBinder(bConf)
+}
+
+// 记录路由的分组级绑定器和验证器。
+func (engine *Engine) setRouteBinding(method, path string, rb routeBinding) {
+	methodRouter := engine.trees.get(method)
+	if methodRouter == nil {
+		return
+	}
+	if methodRouter.bindings == nil {
+		methodRouter.bindings = make(map[string]routeBinding)
+	}
+	methodRouter.bindings[path] = rb
+}
+
+// PrecompileBinding 预编译请求参数结构体的绑定解码器和验证表达式。
+//
+// 通常在注册路由时调用，传入处理器所用的请求结构体（或其指针），
+// 以免首次请求时才进行反射和表达式解析。绑定器或验证器未实现 binding.Precompiler 时跳过。
+func (engine *Engine) PrecompileBinding(objs ...any) error {
+	for _, obj := range objs {
+		if p, ok := engine.binder.(binding.Precompiler); ok {
+			if err := p.Precompile(obj); err != nil {
+				return err
+			}
+		}
+		if p, ok := engine.validator.(binding.Precompiler); ok {
+			if err := p.Precompile(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // ↓ ↓ ↓ ↓ ↓ suite.Core 接口的具体实现  ↓ ↓ ↓ ↓ ↓
 
 // IsRunning 报告引擎是否正在运行。
@@ -494,12 +597,25 @@ func (engine *Engine) GetCtxPool() *sync.Pool {
 
 // ServeHTTP 提供请求服务。在服务过程中，会自动调用用户扩展的 app.HandlerFunc。
 func (engine *Engine) ServeHTTP(c context.Context, ctx *app.RequestContext) {
+	atomic.AddInt64(&engine.inFlight, 1)
+	defer atomic.AddInt64(&engine.inFlight, -1)
+	if engine.OnSlowRequest != nil && engine.options.SlowRequestThreshold > 0 {
+		defer engine.checkSlowRequest(c, ctx, time.Now())
+	}
+
 	ctx.SetBinder(engine.binder)
 	ctx.SetValidator(engine.validator)
+	defer ctx.ApplyResponseWrappers()
 	if engine.PanicHandler != nil {
 		defer engine.recover(ctx)
 	}
 
+	for _, hook := range engine.preRouting {
+		if !hook(c, ctx) {
+			return
+		}
+	}
+
 	rPath := string(ctx.Request.URI().Path())
 
 	// 对齐 https://datatracker.ietf.org/doc/html/rfc2616#section-5.2
@@ -538,6 +654,10 @@ func (engine *Engine) ServeHTTP(c context.Context, ctx *app.RequestContext) {
 		if value.handlers != nil {
 			ctx.SetHandlers(value.handlers)
 			ctx.SetFullPath(value.fullPath)
+			if rb, ok := t[i].bindings[value.fullPath]; ok {
+				ctx.SetBinder(rb.binder)
+				ctx.SetValidator(rb.validator)
+			}
 			ctx.Next(c)
 			return
 		}
@@ -553,6 +673,36 @@ func (engine *Engine) ServeHTTP(c context.Context, ctx *app.RequestContext) {
 		break
 	}
 
+	// 若启用了 HandleOPTIONS，则未匹配的 OPTIONS 请求经全局中间件后自动响应可用方法，
+	// CORS 等中间件可据此装饰预检响应或自行中止
+	if httpMethod == consts.MethodOptions && engine.options.HandleOPTIONS {
+		if allowed := engine.allowedMethods(rPath, paramsPointer, unescape); len(allowed) > 0 {
+			ctx.Params = ctx.Params[0:0]
+			ctx.Response.Header.Set(consts.HeaderAllow, strings.Join(allowed, ", "))
+			ctx.SetHandlers(engine.allOptions)
+			ctx.Next(c)
+			return
+		}
+	}
+
+	// 若启用了 AutoHead，则未匹配的 HEAD 请求复用 GET 处理链
+	if httpMethod == consts.MethodHead && engine.options.AutoHead {
+		if tree := t.get(consts.MethodGet); tree != nil {
+			ctx.Params = ctx.Params[0:0]
+			if value := tree.find(rPath, paramsPointer, unescape); value.handlers != nil {
+				ctx.Response.SkipBody = true
+				ctx.SetHandlers(value.handlers)
+				ctx.SetFullPath(value.fullPath)
+				if rb, ok := tree.bindings[value.fullPath]; ok {
+					ctx.SetBinder(rb.binder)
+					ctx.SetValidator(rb.validator)
+				}
+				ctx.Next(c)
+				return
+			}
+		}
+	}
+
 	// 若方法不允许，则尝试替代方法的处理链
 	if engine.options.HandleMethodNotAllowed {
 		for _, tree := range engine.trees {
@@ -574,6 +724,54 @@ func (engine *Engine) ServeHTTP(c context.Context, ctx *app.RequestContext) {
 	serveError(c, ctx, consts.StatusNotFound, default404Body)
 }
 
+// AllowedMethods 返回路径 path 上已注册的所有方法，按字母排序。
+//
+// 非空时包含 OPTIONS；启用 AutoHead 且注册了 GET 时包含 HEAD。
+func (engine *Engine) AllowedMethods(path string) []string {
+	params := make(param.Params, 0, engine.maxParams)
+	return engine.allowedMethods(path, &params, false)
+}
+
+func (engine *Engine) allowedMethods(path string, paramsPointer *param.Params, unescape bool) []string {
+	var allowed []string
+	hasGet, hasHead, hasOptions := false, false, false
+	for _, tree := range engine.trees {
+		if value := tree.find(path, paramsPointer, unescape); value.handlers != nil {
+			allowed = append(allowed, tree.method)
+			switch tree.method {
+			case consts.MethodGet:
+				hasGet = true
+			case consts.MethodHead:
+				hasHead = true
+			case consts.MethodOptions:
+				hasOptions = true
+			}
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	if engine.options.AutoHead && hasGet && !hasHead {
+		allowed = append(allowed, consts.MethodHead)
+	}
+	if !hasOptions {
+		allowed = append(allowed, consts.MethodOptions)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// InFlightRequests 返回正在处理中的请求数。
+func (engine *Engine) InFlightRequests() int64 {
+	return atomic.LoadInt64(&engine.inFlight)
+}
+
+func (engine *Engine) checkSlowRequest(c context.Context, ctx *app.RequestContext, start time.Time) {
+	if latency := time.Since(start); latency > engine.options.SlowRequestThreshold {
+		engine.OnSlowRequest(c, ctx, latency)
+	}
+}
+
 // GetTracer 获取链路跟踪控制器。
 func (engine *Engine) GetTracer() tracer.Controller {
 	return engine.tracerCtl
@@ -590,9 +788,84 @@ func (engine *Engine) Use(middleware ...app.HandlerFunc) Router {
 	engine.RouterGroup.Use(middleware...)
 	engine.rebuild404Handlers()
 	engine.rebuild405Handlers()
+	engine.rebuildOptionsHandlers()
 	return engine
 }
 
+// UseForPrefix 为路径匹配 prefix 的所有路由（含已注册和后续注册的）追加中间件。
+//
+// prefix 按路径段匹配，如 /api/v1 匹配 /api/v1 和 /api/v1/users，但不匹配 /api/v10。
+// 前缀中间件在全局和路由组中间件之后、路由最终处理器之前执行，多次调用按调用顺序执行。
+func (engine *Engine) UseForPrefix(prefix string, middleware ...app.HandlerFunc) {
+	if len(middleware) == 0 {
+		return
+	}
+	pm := prefixMiddleware{prefix: prefix, handlers: middleware}
+	engine.prefixMiddlewares = append(engine.prefixMiddlewares, pm)
+	for _, tree := range engine.trees {
+		injectPrefixMiddleware(tree.root, pm)
+	}
+}
+
+// 为已注册的路由注入前缀中间件
+func injectPrefixMiddleware(n *node, pm prefixMiddleware) {
+	if len(n.handlers) > 0 && pm.match(n.ppath) {
+		n.handlers = pm.inject(n.handlers)
+	}
+	for _, child := range n.children {
+		injectPrefixMiddleware(child, pm)
+	}
+	if n.paramChild != nil {
+		injectPrefixMiddleware(n.paramChild, pm)
+	}
+	if n.anyChild != nil {
+		injectPrefixMiddleware(n.anyChild, pm)
+	}
+}
+
+func (pm prefixMiddleware) match(path string) bool {
+	prefix := strings.TrimSuffix(pm.prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// 将中间件插入到最终处理器之前
+func (pm prefixMiddleware) inject(handlers app.HandlersChain) app.HandlersChain {
+	finalSize := len(handlers) + len(pm.handlers)
+	if finalSize >= int(rConsts.AbortIndex) {
+		panic("处理函数过多")
+	}
+	last := len(handlers) - 1
+	merged := make(app.HandlersChain, 0, finalSize)
+	merged = append(merged, handlers[:last]...)
+	merged = append(merged, pm.handlers...)
+	return append(merged, handlers[last])
+}
+
+// PreRouting 添加路由匹配前的预处理钩子。
+//
+// 钩子按添加顺序在路由查找之前执行，可改写请求方法、路径、Host 等以影响路由匹配。
+// 在 ServeHTTP 中的执行顺序为：
+//  1. 设置引擎级绑定器与验证器，并注册 PanicHandler（钩子中的 panic 同样会被捕获）；
+//  2. 依次执行钩子，任一钩子返回 false 即中止，后续钩子、路由查找与全局中间件均不再执行；
+//  3. 校验 Host 与路径格式（缺少 Host 的 HTTP/1.1 请求、非 '/' 开头的路径返回 400），
+//     因此钩子可在校验前补全或规范化它们；
+//  4. 按 UseRawPath、RemoveExtraSlash 等选项取路径并查找路由。
+func (engine *Engine) PreRouting(hooks ...PreRoutingFunc) {
+	for _, hook := range hooks {
+		if hook == nil {
+			panic("预处理钩子不能为空")
+		}
+	}
+	engine.preRouting = append(engine.preRouting, hooks...)
+}
+
+// AddConnWrapper 添加连接的包装函数，新连接交由协议服务器处理前按添加顺序叠加。
+//
+// 需在引擎运行前调用。
+func (engine *Engine) AddConnWrapper(wrappers ...network.ConnWrapper) {
+	engine.options.ConnWrappers = append(engine.options.ConnWrappers, wrappers...)
+}
+
 // GetOptions 返回路由器和协议服务器的配置项。
 func (engine *Engine) GetOptions() *config.Options {
 	return engine.options
@@ -647,14 +920,170 @@ func (engine *Engine) PrintRoute(method string) {
 	printNode(root.root, 0)
 }
 
-// Routes 返回已注册的路由切片，及关键信息，如： HTTP 方法、路径和处理器名称。
+// Routes 返回已注册的路由切片，及关键信息，如： HTTP 方法、路径、处理器名称和路由名称。
 func (engine *Engine) Routes() (routes Routes) {
 	for _, tree := range engine.trees {
 		routes = iterate(tree.method, routes, tree.root)
 	}
+	if len(engine.namedRoutes) > 0 {
+		names := make(map[string]string, len(engine.namedRoutes))
+		for name, r := range engine.namedRoutes {
+			names[r.Method+" "+r.Path] = name
+		}
+		for i := range routes {
+			routes[i].Name = names[routes[i].Method+" "+routes[i].Path]
+		}
+	}
 	return routes
 }
 
+// SetRouteName 为已注册的路由设置名称，供 URL 反向生成路径。
+//
+// 路由未注册或名称已被其他路由占用时 panic。
+func (engine *Engine) SetRouteName(method, path, name string) {
+	engine.checkRouteName(method, path, name)
+	found := false
+	for _, r := range engine.Routes() {
+		if r.Method == method && r.Path == path {
+			found = true
+			break
+		}
+	}
+	if !found {
+		panic(fmt.Sprintf("路由 %s %s 未注册，无法命名", method, path))
+	}
+	if engine.namedRoutes == nil {
+		engine.namedRoutes = make(map[string]Route)
+	}
+	engine.namedRoutes[name] = Route{Method: method, Path: path, Name: name}
+}
+
+// 校验路由名称，名称为空或已被其他路由占用时 panic。
+func (engine *Engine) checkRouteName(method, path, name string) {
+	if name == "" {
+		panic("路由名称不能为空")
+	}
+	if r, ok := engine.namedRoutes[name]; ok && (r.Method != method || r.Path != path) {
+		panic(fmt.Sprintf("路由名称 '%s' 已被 %s %s 使用", name, r.Method, r.Path))
+	}
+}
+
+// URL 按命名路由的模式回填参数生成路径。
+//
+// params 的键为路由中的参数名（不含 : 或 *），参数值会按路径规则转义，
+// 通配参数中的 / 保留原样。参数缺失、多余或名称未注册时返回错误。
+func (engine *Engine) URL(name string, params map[string]string) (string, error) {
+	r, ok := engine.namedRoutes[name]
+	if !ok {
+		return "", fmt.Errorf("路由名称 '%s' 未注册", name)
+	}
+
+	var (
+		sb   strings.Builder
+		used int
+		path = r.Path
+	)
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c != paramLabel && c != anyLabel {
+			sb.WriteByte(c)
+			continue
+		}
+		end := i + 1
+		for end < len(path) && path[end] != '/' {
+			end++
+		}
+		pname := path[i+1 : end]
+		value, ok := params[pname]
+		if !ok || (c == paramLabel && value == "") {
+			return "", fmt.Errorf("路由 '%s' 缺少参数 '%s'", name, pname)
+		}
+		used++
+		if c == paramLabel {
+			sb.WriteString(url.PathEscape(value))
+		} else {
+			segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, seg := range segments {
+				if j > 0 {
+					sb.WriteByte('/')
+				}
+				sb.WriteString(url.PathEscape(seg))
+			}
+		}
+		i = end - 1
+	}
+
+	if used != len(params) {
+		var extra []string
+		for k := range params {
+			if !routeHasParam(path, k) {
+				extra = append(extra, k)
+			}
+		}
+		sort.Strings(extra)
+		return "", fmt.Errorf("路由 '%s' 存在多余参数 %s", name, strings.Join(extra, ", "))
+	}
+	return sb.String(), nil
+}
+
+// 判断路由模式中是否包含给定名称的参数。
+func routeHasParam(path, name string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if len(seg) > 1 && (seg[0] == paramLabel || seg[0] == anyLabel) && seg[1:] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConflicts 静态扫描已注册的路由，返回所有潜在冲突的描述。
+//
+// 检查同一方法下：仅大小写不同、仅尾部斜杠不同，以及同一位置参数名不一致的路由，
+// 便于在启动前发现路由拼写错误。
+func (engine *Engine) CheckConflicts() []string {
+	var conflicts []string
+	routes := engine.Routes()
+	for i := 0; i < len(routes); i++ {
+		for j := i + 1; j < len(routes); j++ {
+			a, b := routes[i], routes[j]
+			if a.Method != b.Method || a.Path == b.Path {
+				continue
+			}
+			if reason := routeConflict(a.Path, b.Path); reason != "" {
+				conflicts = append(conflicts, fmt.Sprintf("%s %s 与 %s %s：%s", a.Method, a.Path, b.Method, b.Path, reason))
+			}
+		}
+	}
+	return conflicts
+}
+
+// 返回两条路由路径的冲突原因，无冲突返回空字符串。
+func routeConflict(a, b string) string {
+	if strings.EqualFold(a, b) {
+		return "仅大小写不同"
+	}
+	if strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/") {
+		return "仅尾部斜杠不同"
+	}
+
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, y := as[i], bs[i]
+		xParam := len(x) > 0 && (x[0] == paramLabel || x[0] == anyLabel)
+		yParam := len(y) > 0 && (y[0] == paramLabel || y[0] == anyLabel)
+		switch {
+		case xParam && yParam:
+			if x[0] == y[0] && x[1:] != y[1:] {
+				return fmt.Sprintf("同一位置的参数名不一致（%s 与 %s）", x[1:], y[1:])
+			}
+		case xParam || yParam || x != y:
+			// 路径自此分叉
+			return ""
+		}
+	}
+	return ""
+}
+
 // Delims 设置 HTML 模板的左右分隔符并返回引擎。
 func (engine *Engine) Delims(left, right string) *Engine {
 	engine.delims = render.Delims{
@@ -732,6 +1161,16 @@ func (engine *Engine) SetFormValueFunc(f app.FormValueFunc) {
 	engine.formValueFunc = f
 }
 
+// SetSecureJSONPrefix 设置 SecureJSON 的数组前缀，默认为 "while(1);"。
+func (engine *Engine) SetSecureJSONPrefix(prefix string) {
+	engine.secureJSONPrefix = prefix
+}
+
+// SetEnvelope 设置 ctx.OK、ctx.Fail 等方法的统一响应格式，nil 表示使用默认格式。
+func (engine *Engine) SetEnvelope(e *app.Envelope) {
+	engine.envelope = e
+}
+
 // HijackConnHandle 处理给定的劫持连接。
 func (engine *Engine) HijackConnHandle(c network.Conn, h app.HijackHandler) {
 	engine.hijackConnHandle(c, h)
@@ -752,12 +1191,18 @@ func (engine *Engine) addRoute(method, path string, handlers app.HandlersChain)
 	methodRouter := engine.trees.get(method)
 	if methodRouter == nil {
 		methodRouter = &router{
-			method:        method,
-			root:          &node{},
-			hasTsrHandler: make(map[string]bool),
+			method:          method,
+			root:            &node{},
+			hasTsrHandler:   make(map[string]bool),
+			caseInsensitive: engine.options.CaseInsensitiveRouting,
 		}
 		engine.trees = append(engine.trees, methodRouter)
 	}
+	for _, pm := range engine.prefixMiddlewares {
+		if pm.match(path) {
+			handlers = pm.inject(handlers)
+		}
+	}
 	methodRouter.addRoute(path, handlers)
 
 	// 更新 maxParams
@@ -776,8 +1221,17 @@ func (engine *Engine) allocateContext() *app.RequestContext {
 	ctx := engine.NewContext()
 	ctx.Request.SetMaxKeepBodySize(engine.options.MaxKeepBodySize)
 	ctx.Response.SetMaxKeepBodySize(engine.options.MaxKeepBodySize)
+	ctx.Request.SetMultipartLimits(protocol.MultipartLimits{
+		MaxFieldSize: engine.options.MaxMultipartFieldSize,
+		MaxFileSize:  engine.options.MaxMultipartFileSize,
+		MaxTotalSize: engine.options.MaxMultipartTotalSize,
+		MaxFiles:     engine.options.MaxMultipartFiles,
+	})
 	ctx.SetClientIPFunc(engine.clientIPFunc)
 	ctx.SetFormValueFunc(engine.formValueFunc)
+	ctx.SetSecureJSONPrefix(engine.secureJSONPrefix)
+	ctx.SetEnvelope(engine.envelope)
+	ctx.SetAllowedMethodsFunc(engine.AllowedMethods)
 	return ctx
 }
 
@@ -861,6 +1315,16 @@ func (engine *Engine) rebuild405Handlers() {
 	engine.allNoMethod = engine.combineHandlers(engine.noMethod)
 }
 
+// 重建 OPTIONS 自动响应处理器。
+func (engine *Engine) rebuildOptionsHandlers() {
+	engine.allOptions = engine.combineHandlers(app.HandlersChain{serveOptions})
+}
+
+// 以 204 响应自动处理的 OPTIONS 请求，Allow 标头已在分发前设置。
+func serveOptions(c context.Context, ctx *app.RequestContext) {
+	ctx.SetStatusCode(consts.StatusNoContent)
+}
+
 // 执行引擎退出的回调钩子。
 func (engine *Engine) executeOnShutdownHooks(ctx context.Context, ch chan struct{}) {
 	wg := sync.WaitGroup{}
@@ -894,6 +1358,16 @@ func newHttp1OptionFromEngine(engine *Engine) *http1.Option {
 		DisableHeaderNamesNormalizing: engine.options.DisableHeaderNamesNormalizing,
 		NoDefaultDate:                 engine.options.NoDefaultDate,
 		NoDefaultContentType:          engine.options.NoDefaultContentType,
+		DefaultResponseHeaders:        engine.options.DefaultResponseHeaders,
+		AutoChunkThreshold:            engine.options.AutoChunkThreshold,
+		RawTrafficMaxSize:             engine.options.RawTrafficMaxSize,
+		RawTrafficSampleRate:          engine.options.RawTrafficSampleRate,
+	}
+	if engine.OnRawRequest != nil {
+		opt.OnRawRequest = http1.RawTrafficFunc(engine.OnRawRequest)
+	}
+	if engine.OnRawResponse != nil {
+		opt.OnRawResponse = http1.RawTrafficFunc(engine.OnRawResponse)
 	}
 	// 标准库的空闲超时必不能为零，若为 0 则置为 -1。
 	// 由于网络库的触发方式不同，具体原因请参阅该值的实际使用情况。
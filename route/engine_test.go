@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/favbox/wind/common/config"
 	errs "github.com/favbox/wind/common/errors"
 	"github.com/favbox/wind/common/mock"
+	"github.com/favbox/wind/common/utils"
 	"github.com/favbox/wind/network"
 	"github.com/favbox/wind/network/standard"
 	"github.com/favbox/wind/protocol"
@@ -217,6 +219,149 @@ func TestEngine_Unescape(t *testing.T) {
 	}
 }
 
+func TestEngine_SlowRequest(t *testing.T) {
+	e := NewEngine(config.NewOptions([]config.Option{{F: func(o *config.Options) {
+		o.SlowRequestThreshold = 20 * time.Millisecond
+	}}}))
+	var slowPaths []string
+	e.OnSlowRequest = func(c context.Context, ctx *app.RequestContext, latency time.Duration) {
+		assert.True(t, latency > 20*time.Millisecond)
+		slowPaths = append(slowPaths, string(ctx.Path()))
+	}
+	e.GET("/fast", func(c context.Context, ctx *app.RequestContext) {
+		assert.Equal(t, int64(1), e.InFlightRequests())
+	})
+	e.GET("/slow", func(c context.Context, ctx *app.RequestContext) {
+		time.Sleep(30 * time.Millisecond)
+	})
+
+	performRequest(e, consts.MethodGet, "/fast")
+	performRequest(e, consts.MethodGet, "/slow")
+	assert.Equal(t, []string{"/slow"}, slowPaths)
+	assert.Equal(t, int64(0), e.InFlightRequests())
+}
+
+func TestEngine_WrapResponse(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	e.Use(func(c context.Context, ctx *app.RequestContext) {
+		ctx.WrapResponse(func(status int, contentType string, body []byte) (int, string, []byte) {
+			return consts.StatusOK, contentType, []byte(fmt.Sprintf(`{"code":%d,"data":%s}`, status, body))
+		})
+		ctx.Next(c)
+	})
+	e.GET("/user", func(c context.Context, ctx *app.RequestContext) {
+		ctx.JSON(consts.StatusAccepted, utils.H{"name": "wind"})
+	})
+
+	w := performRequest(e, consts.MethodGet, "/user")
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, `{"code":202,"data":{"name":"wind"}}`, w.Body.String())
+}
+
+func TestEngine_UseForPrefix(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	var trace []string
+	mark := func(name string) app.HandlerFunc {
+		return func(c context.Context, ctx *app.RequestContext) {
+			trace = append(trace, name)
+		}
+	}
+	e.Use(mark("global"))
+	v1 := e.Group("/api/v1", mark("group"))
+	v1.GET("/users", mark("users"))
+	e.GET("/api/v10", mark("v10"))
+
+	e.UseForPrefix("/api/v1", mark("prefix1"))
+	v1.GET("/orders", mark("orders"))
+	e.UseForPrefix("/api/v1/", mark("prefix2"))
+
+	performRequest(e, consts.MethodGet, "/api/v1/users")
+	assert.Equal(t, []string{"global", "group", "prefix1", "prefix2", "users"}, trace)
+
+	trace = nil
+	performRequest(e, consts.MethodGet, "/api/v1/orders")
+	assert.Equal(t, []string{"global", "group", "prefix1", "prefix2", "orders"}, trace)
+
+	trace = nil
+	performRequest(e, consts.MethodGet, "/api/v10")
+	assert.Equal(t, []string{"global", "v10"}, trace)
+}
+
+func TestEngine_MatchedRoute(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	e.options.HandleMethodNotAllowed = true
+	var route string
+	e.Use(func(c context.Context, ctx *app.RequestContext) {
+		ctx.Next(c)
+		route = ctx.MatchedRoute()
+	})
+	e.GET("/user/:id", func(c context.Context, ctx *app.RequestContext) {})
+
+	performRequest(e, consts.MethodGet, "/user/123")
+	assert.Equal(t, "/user/:id", route)
+	performRequest(e, consts.MethodGet, "/nope")
+	assert.Equal(t, app.UnmatchedRoute, route)
+	performRequest(e, consts.MethodPost, "/user/123")
+	assert.Equal(t, app.UnmatchedRoute, route)
+}
+
+type rejectValidator struct{}
+
+func (rejectValidator) ValidateStruct(any) error { return errors.New("rejected") }
+func (rejectValidator) Engine() any              { return nil }
+func (rejectValidator) ValidateTag() string      { return "vd" }
+
+type rejectBinder struct {
+	binding.Binder
+}
+
+func (rejectBinder) Bind(*protocol.Request, any, param.Params) error {
+	return errors.New("reject binder")
+}
+
+func TestEngine_GroupBinder(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	type req struct {
+		A string `query:"a" vd:"len($)>0"`
+	}
+	var bindErr, validateErr error
+	handler := func(c context.Context, ctx *app.RequestContext) {
+		var r req
+		bindErr = ctx.BindAndValidate(&r)
+		validateErr = ctx.Validate(&r)
+	}
+	e.GET("/loose", handler)
+	strict := e.Group("/strict").SetValidator(rejectValidator{})
+	strict.GET("/a", handler)
+	strict.Group("/sub").GET("/b", func(c context.Context, ctx *app.RequestContext) {
+		bindErr = ctx.Bind(&req{})
+	})
+	custom := e.Group("/custom").SetBinder(rejectBinder{binding.DefaultBinder()})
+	custom.GET("/c", func(c context.Context, ctx *app.RequestContext) {
+		bindErr = ctx.Bind(&req{})
+	})
+
+	performRequest(e, consts.MethodGet, "/loose?a=1")
+	assert.Nil(t, bindErr)
+	assert.Nil(t, validateErr)
+
+	performRequest(e, consts.MethodGet, "/strict/a?a=1")
+	assert.EqualError(t, bindErr, "rejected")
+	assert.EqualError(t, validateErr, "rejected")
+
+	// 子分组继承验证器，Bind 不做验证
+	performRequest(e, consts.MethodGet, "/strict/sub/b?a=1")
+	assert.Nil(t, bindErr)
+
+	performRequest(e, consts.MethodGet, "/custom/c?a=1")
+	assert.EqualError(t, bindErr, "reject binder")
+
+	// 引擎级配置不受影响
+	performRequest(e, consts.MethodGet, "/loose?a=1")
+	assert.Nil(t, bindErr)
+	assert.Nil(t, validateErr)
+}
+
 func TestEngine_UnescapeRaw(t *testing.T) {
 	e := NewEngine(config.NewOptions(nil))
 	e.options.UseRawPath = true
@@ -556,6 +701,21 @@ func TestInitBinderAndValidator(t *testing.T) {
 	NewEngine(opt)
 }
 
+func TestPrecompileBinding(t *testing.T) {
+	type Req struct {
+		ID int `query:"id" vd:"$>0"`
+	}
+	engine := NewEngine(config.NewOptions(nil))
+	assert.Nil(t, engine.PrecompileBinding(Req{}, &Req{}))
+	assert.NotNil(t, engine.PrecompileBinding("not a struct"))
+
+	// 自定义绑定器和验证器未实现 binding.Precompiler 时跳过
+	opt := config.NewOptions(nil)
+	opt.CustomBinder = &mockBinder{}
+	opt.CustomValidator = &mockValidator{}
+	assert.Nil(t, NewEngine(opt).PrecompileBinding(Req{}))
+}
+
 var errTestDeregsitry = fmt.Errorf("test deregsitry error")
 
 type mockDeregsitryErr struct{}
@@ -708,6 +868,36 @@ func TestOndata(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+type countingConn struct {
+	network.Conn
+	tag string
+}
+
+func TestOndata_ConnWrapper(t *testing.T) {
+	engine := NewEngine(config.NewOptions(nil))
+	engine.protocolServers[suite.HTTP1] = &mockProtocolServer{}
+
+	var order []string
+	wrapper := func(tag string) network.ConnWrapper {
+		return func(conn network.Conn) network.Conn {
+			order = append(order, tag)
+			return &countingConn{Conn: conn, tag: tag}
+		}
+	}
+	engine.AddConnWrapper(wrapper("a"), wrapper("b"))
+
+	conn := mock.NewConn("GET /foo HTTP/1.1\r\nHost: google.com\r\n\r\n")
+	err := engine.onData(context.Background(), conn)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, order)
+
+	wrapped := network.WrapConn(conn, engine.options.ConnWrappers...)
+	outer, ok := wrapped.(*countingConn)
+	assert.True(t, ok)
+	assert.Equal(t, "b", outer.tag)
+	assert.Equal(t, "a", outer.Conn.(*countingConn).tag)
+}
+
 func TestAcquireHijackConn(t *testing.T) {
 	engine := &Engine{
 		NoHijackConnPool: false,
@@ -728,3 +918,276 @@ func TestAcquireHijackConn(t *testing.T) {
 	assert.Equal(t, engine, hijackConn.e)
 	assert.Equal(t, conn, hijackConn.Conn)
 }
+
+func TestEngine_PreRouting(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	var order []string
+	e.Use(func(c context.Context, ctx *app.RequestContext) {
+		order = append(order, "middleware")
+		ctx.Next(c)
+	})
+	e.GET("/v2/users", func(c context.Context, ctx *app.RequestContext) {
+		order = append(order, "handler")
+		ctx.String(consts.StatusOK, string(ctx.Host()))
+	})
+	e.PreRouting(
+		func(c context.Context, ctx *app.RequestContext) bool {
+			order = append(order, "hook1")
+			// 补全缺失的 Host，早于 Host 校验
+			if len(ctx.Request.Host()) == 0 {
+				ctx.Request.SetHost("example.com")
+			}
+			return true
+		},
+		func(c context.Context, ctx *app.RequestContext) bool {
+			order = append(order, "hook2")
+			// 改写路径以影响路由匹配
+			if string(ctx.Request.URI().Path()) == "/api/users" {
+				ctx.Request.URI().SetPath("/v2/users")
+			}
+			if string(ctx.Request.URI().Path()) == "/blocked" {
+				ctx.AbortWithMsg("blocked", consts.StatusForbidden)
+				return false
+			}
+			return true
+		},
+	)
+
+	w := performRequest(e, consts.MethodGet, "/api/users")
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "example.com", w.Body.String())
+	assert.Equal(t, []string{"hook1", "hook2", "middleware", "handler"}, order)
+
+	order = nil
+	w = performRequest(e, consts.MethodGet, "/blocked")
+	assert.Equal(t, consts.StatusForbidden, w.Code)
+	assert.Equal(t, "blocked", w.Body.String())
+	assert.Equal(t, []string{"hook1", "hook2"}, order)
+
+	assert.Panics(t, func() { e.PreRouting(nil) })
+}
+
+func TestEngine_SetSecureJSONPrefix(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	e.SetSecureJSONPrefix(")]}',\n")
+	e.GET("/list", func(c context.Context, ctx *app.RequestContext) {
+		ctx.SecureJSON(consts.StatusOK, []int{1, 2})
+	})
+	w := performRequest(e, consts.MethodGet, "/list")
+	assert.Equal(t, ")]}',\n[1,2]", w.Body.String())
+}
+
+func TestEngine_SetEnvelope(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	e.SetEnvelope(&app.Envelope{OKCode: 200})
+	e.GET("/ok", func(c context.Context, ctx *app.RequestContext) {
+		ctx.OK("pong")
+	})
+	w := performRequest(e, consts.MethodGet, "/ok")
+	assert.Equal(t, `{"code":200,"data":"pong","msg":"ok"}`, w.Body.String())
+}
+
+func TestEngine_NamedRoutes(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	h := func(c context.Context, ctx *app.RequestContext) {}
+	v1 := e.Group("/v1")
+	v1.GETNamed("user", "/users/:id", h)
+	v1.GETNamed("user.file", "/users/:id/files/*filepath", h)
+	e.POST("/orders", h)
+	e.SetRouteName(consts.MethodPost, "/orders", "order.create")
+
+	u, err := e.URL("user", map[string]string{"id": "42"})
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/users/42", u)
+
+	u, err = e.URL("user.file", map[string]string{"id": "a b", "filepath": "/docs/读我.txt"})
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/users/a%20b/files/docs/%E8%AF%BB%E6%88%91.txt", u)
+
+	u, err = e.URL("order.create", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "/orders", u)
+
+	_, err = e.URL("user.file", map[string]string{"id": "1"})
+	assert.ErrorContains(t, err, "缺少参数 'filepath'")
+	_, err = e.URL("user", map[string]string{"id": "1", "x": "1", "page": "2"})
+	assert.ErrorContains(t, err, "多余参数 page, x")
+	_, err = e.URL("none", nil)
+	assert.NotNil(t, err)
+
+	for _, r := range e.Routes() {
+		if r.Path == "/v1/users/:id" {
+			assert.Equal(t, "user", r.Name)
+		}
+	}
+
+	assert.Panics(t, func() { e.SetRouteName(consts.MethodGet, "/orders", "x") })
+	assert.Panics(t, func() { v1.GETNamed("user", "/profile", h) })
+	assert.Panics(t, func() { v1.GETNamed("", "/profile", h) })
+	// 名称校验失败时路由不会注册
+	for _, r := range e.Routes() {
+		assert.NotEqual(t, "/v1/profile", r.Path)
+	}
+}
+
+func TestEngine_CaseInsensitiveRouting(t *testing.T) {
+	opts := config.NewOptions(nil)
+	opts.CaseInsensitiveRouting = true
+	e := NewEngine(opts)
+	e.GET("/User/:name/Profile", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, ctx.Param("name")+" "+ctx.FullPath())
+	})
+	e.GET("/static/*filepath", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, ctx.Param("filepath"))
+	})
+
+	for _, p := range []string{"/user/Mike/profile", "/USER/Mike/PROFILE", "/User/Mike/Profile"} {
+		w := performRequest(e, consts.MethodGet, p)
+		assert.Equal(t, consts.StatusOK, w.Code, p)
+		assert.Equal(t, "Mike /User/:name/Profile", w.Body.String(), p)
+	}
+	w := performRequest(e, consts.MethodGet, "/STATIC/Css/App.css")
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "Css/App.css", w.Body.String())
+
+	// 默认区分大小写
+	e = NewEngine(config.NewOptions(nil))
+	e.GET("/user/:name", func(c context.Context, ctx *app.RequestContext) {})
+	w = performRequest(e, consts.MethodGet, "/USER/Mike")
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+}
+
+func TestEngine_AutoHead(t *testing.T) {
+	opts := config.NewOptions(nil)
+	opts.AutoHead = true
+	e := NewEngine(opts)
+	e.GET("/users/:id", func(c context.Context, ctx *app.RequestContext) {
+		ctx.Header("X-User", ctx.Param("id"))
+		ctx.String(consts.StatusOK, "user")
+	})
+	e.GET("/explicit", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "get")
+	})
+	e.HEAD("/explicit", func(c context.Context, ctx *app.RequestContext) {
+		ctx.Header("X-Head", "1")
+		ctx.Status(consts.StatusAccepted)
+	})
+
+	w := performRequest(e, consts.MethodHead, "/users/1")
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-User"))
+
+	// 显式注册的 HEAD 路由优先
+	w = performRequest(e, consts.MethodHead, "/explicit")
+	assert.Equal(t, consts.StatusAccepted, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Head"))
+
+	w = performRequest(e, consts.MethodHead, "/none")
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+
+	// 默认关闭
+	e = NewEngine(config.NewOptions(nil))
+	e.GET("/users/:id", func(c context.Context, ctx *app.RequestContext) {})
+	w = performRequest(e, consts.MethodHead, "/users/1")
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+}
+
+func TestEngine_HandleOPTIONS(t *testing.T) {
+	opts := config.NewOptions(nil)
+	opts.HandleOPTIONS = true
+	opts.AutoHead = true
+	e := NewEngine(opts)
+	h := func(c context.Context, ctx *app.RequestContext) {}
+	e.GET("/users/:id", h)
+	e.PUT("/users/:id", h)
+	e.DELETE("/users/:id", h)
+	e.POST("/users", h)
+	e.OPTIONS("/explicit", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, "explicit")
+	})
+	e.GET("/methods", func(c context.Context, ctx *app.RequestContext) {
+		ctx.String(consts.StatusOK, strings.Join(ctx.GetAllowedMethods("/users/1"), ","))
+	})
+
+	// 204 无法经由 httptest.ResponseRecorder 写出，直接检查上下文
+	ctx := e.NewContext()
+	ctx.Request.SetMethod(consts.MethodOptions)
+	ctx.Request.SetRequestURI("/users/1")
+	e.ServeHTTP(context.Background(), ctx)
+	assert.Equal(t, consts.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS, PUT", string(ctx.Response.Header.Peek(consts.HeaderAllow)))
+
+	// 显式注册的 OPTIONS 路由优先
+	w := performRequest(e, consts.MethodOptions, "/explicit")
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, "explicit", w.Body.String())
+
+	// 尾斜杠重定向不受影响
+	w = performRequest(e, consts.MethodGet, "/methods/")
+	assert.Equal(t, consts.StatusMovedPermanently, w.Code)
+
+	w = performRequest(e, consts.MethodOptions, "/none")
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+
+	w = performRequest(e, consts.MethodGet, "/methods")
+	assert.Equal(t, "DELETE,GET,HEAD,OPTIONS,PUT", w.Body.String())
+
+	// 预检请求经过全局中间件，可由 CORS 等中间件装饰
+	e.Use(func(c context.Context, ctx *app.RequestContext) {
+		if string(ctx.Method()) == consts.MethodOptions {
+			ctx.Response.Header.Set("Access-Control-Allow-Methods", strings.Join(ctx.GetAllowedMethods(string(ctx.Path())), ", "))
+		}
+		ctx.Next(c)
+	})
+	ctx = e.allocateContext()
+	ctx.Request.SetMethod(consts.MethodOptions)
+	ctx.Request.SetRequestURI("/users/1")
+	e.ServeHTTP(context.Background(), ctx)
+	assert.Equal(t, consts.StatusNoContent, ctx.Response.StatusCode())
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS, PUT", string(ctx.Response.Header.Peek("Access-Control-Allow-Methods")))
+
+	// 中间件中止时不再覆盖其响应
+	e.Use(func(c context.Context, ctx *app.RequestContext) {
+		ctx.AbortWithStatus(consts.StatusForbidden)
+	})
+	ctx = e.allocateContext()
+	ctx.Request.SetMethod(consts.MethodOptions)
+	ctx.Request.SetRequestURI("/users/1")
+	e.ServeHTTP(context.Background(), ctx)
+	assert.Equal(t, consts.StatusForbidden, ctx.Response.StatusCode())
+
+	assert.Equal(t, []string{"OPTIONS", "POST"}, e.AllowedMethods("/users"))
+	assert.Nil(t, e.AllowedMethods("/none"))
+
+	// 默认关闭
+	e = NewEngine(config.NewOptions(nil))
+	e.GET("/users/:id", h)
+	w = performRequest(e, consts.MethodOptions, "/users/1")
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+}
+
+func TestEngine_CheckConflicts(t *testing.T) {
+	e := NewEngine(config.NewOptions(nil))
+	h := func(c context.Context, ctx *app.RequestContext) {}
+	e.GET("/users/:id", h)
+	e.GET("/users/:uid/posts", h)
+	e.GET("/Orders", h)
+	e.GET("/orders", h)
+	e.GET("/items", h)
+	e.GET("/items/", h)
+	e.POST("/users/:name", h)
+	e.GET("/files/:name", h)
+	e.GET("/static/*filepath", h)
+
+	assert.ElementsMatch(t, []string{
+		"GET /users/:id 与 GET /users/:uid/posts：同一位置的参数名不一致（id 与 uid）",
+		"GET /Orders 与 GET /orders：仅大小写不同",
+		"GET /items 与 GET /items/：仅尾部斜杠不同",
+	}, e.CheckConflicts())
+
+	e = NewEngine(config.NewOptions(nil))
+	e.GET("/users/:id", h)
+	e.GET("/users/:id/posts", h)
+	e.GET("/users/new", h)
+	assert.Empty(t, e.CheckConflicts())
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/app/server/binding"
 	"github.com/favbox/wind/protocol/consts"
 	rConsts "github.com/favbox/wind/route/consts"
 )
@@ -19,6 +20,7 @@ type Route struct {
 	Path        string          // 请求路径
 	Handler     string          // 处理器名称
 	HandlerFunc app.HandlerFunc // 处理器函数
+	Name        string          // 路由名称，未命名为空
 }
 
 // Routes 定义了一组路由信息。
@@ -53,6 +55,10 @@ type RouterGroup struct {
 	basePath string
 	engine   *Engine
 	root     bool
+
+	binder          binding.Binder          // 分组级绑定器，为空则沿用引擎的
+	validator       binding.StructValidator // 分组级验证器，为空则沿用引擎的
+	validatorBinder binding.Binder          // 仅设置验证器时，注入该验证器的默认绑定器
 }
 
 var _ Routers = (*RouterGroup)(nil)
@@ -65,12 +71,33 @@ func (group *RouterGroup) BasePath() string {
 // Group 创建分组路由。可添加有相同前缀和中间件的路由（如使用同一鉴权中间件的 /admin 路由）。
 func (group *RouterGroup) Group(relativePath string, handlers ...app.HandlerFunc) *RouterGroup {
 	return &RouterGroup{
-		Handlers: group.combineHandlers(handlers),
-		basePath: group.calculateAbsolutePath(relativePath),
-		engine:   group.engine,
+		Handlers:        group.combineHandlers(handlers),
+		basePath:        group.calculateAbsolutePath(relativePath),
+		engine:          group.engine,
+		binder:          group.binder,
+		validator:       group.validator,
+		validatorBinder: group.validatorBinder,
 	}
 }
 
+// SetBinder 设置该分组的请求参数绑定器，组内路由的 ctx.Bind 等方法将使用它。
+//
+// 仅影响此后注册的路由及创建的子分组。
+func (group *RouterGroup) SetBinder(binder binding.Binder) *RouterGroup {
+	group.binder = binder
+	return group
+}
+
+// SetValidator 设置该分组的请求参数验证器，组内路由的 ctx.Validate 将使用它。
+//
+// 若未通过 SetBinder 指定绑定器，且引擎未使用自定义绑定器，
+// 则组内 ctx.BindAndValidate 也使用该验证器。仅影响此后注册的路由及创建的子分组。
+func (group *RouterGroup) SetValidator(validator binding.StructValidator) *RouterGroup {
+	group.validator = validator
+	group.validatorBinder = group.engine.newGroupBinder(validator)
+	return group
+}
+
 // Use 添加中间件到该分组路由。
 func (group *RouterGroup) Use(middleware ...app.HandlerFunc) Router {
 	group.Handlers = append(group.Handlers, middleware...)
@@ -99,6 +126,26 @@ func (group *RouterGroup) Any(path string, handlers ...app.HandlerFunc) Router {
 	return group.asObject()
 }
 
+// HandleNamed 注册一条带名称的路由，名称可用于 engine.URL 反向生成路径。
+func (group *RouterGroup) HandleNamed(name, httpMethod, relativePath string, handlers ...app.HandlerFunc) Router {
+	// 先校验名称，避免名称冲突时路由已注册
+	absolutePath := group.calculateAbsolutePath(relativePath)
+	group.engine.checkRouteName(httpMethod, absolutePath, name)
+	group.Handle(httpMethod, relativePath, handlers...)
+	group.engine.SetRouteName(httpMethod, absolutePath, name)
+	return group.asObject()
+}
+
+// GETNamed 注册一条带名称的 GET 路由，是 HandleNamed(name, "GET", relativePath, handlers) 的快捷方式。
+func (group *RouterGroup) GETNamed(name, relativePath string, handlers ...app.HandlerFunc) Router {
+	return group.HandleNamed(name, consts.MethodGet, relativePath, handlers...)
+}
+
+// POSTNamed 注册一条带名称的 POST 路由，是 HandleNamed(name, "POST", relativePath, handlers) 的快捷方式。
+func (group *RouterGroup) POSTNamed(name, relativePath string, handlers ...app.HandlerFunc) Router {
+	return group.HandleNamed(name, consts.MethodPost, relativePath, handlers...)
+}
+
 // GET 注册一条 GET 路由，是 Handle("GET", relativePath, handlers) 的快捷方式。
 func (group *RouterGroup) GET(relativePath string, handlers ...app.HandlerFunc) Router {
 	return group.handle(consts.MethodGet, relativePath, handlers)
@@ -177,9 +224,27 @@ func (group *RouterGroup) handle(httpMethod, relativePath string, handlers app.H
 	absolutePath := group.calculateAbsolutePath(relativePath)
 	handlers = group.combineHandlers(handlers)
 	group.engine.addRoute(httpMethod, absolutePath, handlers)
+	if group.binder != nil || group.validator != nil {
+		group.engine.setRouteBinding(httpMethod, absolutePath, group.routeBinding())
+	}
 	return group.asObject()
 }
 
+// 返回分组实际生效的绑定器和验证器，未设置的部分沿用引擎配置。
+func (group *RouterGroup) routeBinding() routeBinding {
+	rb := routeBinding{binder: group.binder, validator: group.validator}
+	if rb.binder == nil {
+		rb.binder = group.validatorBinder
+	}
+	if rb.binder == nil {
+		rb.binder = group.engine.binder
+	}
+	if rb.validator == nil {
+		rb.validator = group.engine.validator
+	}
+	return rb
+}
+
 func (group *RouterGroup) calculateAbsolutePath(relativePath string) string {
 	return joinPaths(group.basePath, relativePath)
 }
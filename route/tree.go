@@ -8,6 +8,7 @@ import (
 	"unicode"
 
 	"github.com/favbox/wind/app"
+	"github.com/favbox/wind/app/server/binding"
 	"github.com/favbox/wind/internal/bytesconv"
 	"github.com/favbox/wind/internal/bytestr"
 	"github.com/favbox/wind/route/param"
@@ -43,6 +44,15 @@ type (
 		method        string
 		root          *node
 		hasTsrHandler map[string]bool
+		bindings      map[string]routeBinding // 按路由模板记录的分组级绑定器/验证器
+		// 是否忽略 ASCII 大小写匹配。开启后树中的静态部分以小写存储。
+		caseInsensitive bool
+	}
+
+	// 分组级的绑定器和验证器
+	routeBinding struct {
+		binder    binding.Binder
+		validator binding.StructValidator
 	}
 
 	// MethodTrees 是路由器的方法树切片
@@ -217,6 +227,9 @@ func (r *router) addRoute(path string, h app.HandlersChain) {
 		pnames []string // 参数名称
 		ppath  = path   // 路由定义的原始路径
 	)
+	if r.caseInsensitive {
+		path = lowerStaticPath(path)
+	}
 
 	if h == nil {
 		panic(fmt.Sprintf("添加的路由必须有对应的处理器: %v", path))
@@ -299,14 +312,14 @@ func (r *router) find(path string, paramsPointer *param.Params, unescape bool) (
 	// 搜索顺序：静态路由 > 命名参数路由 > 通配参数路由
 	for {
 		if cn.kind == skind {
-			if len(search) >= len(cn.prefix) && cn.prefix == search[:len(cn.prefix)] {
+			if len(search) >= len(cn.prefix) && r.prefixEqual(cn.prefix, search[:len(cn.prefix)]) {
 				// Continue search
 				search = search[len(cn.prefix):]
 				searchIndex = searchIndex + len(cn.prefix)
 			} else {
 				// not equal
 				if (len(cn.prefix) == len(search)+1) &&
-					(cn.prefix[len(search)]) == '/' && cn.prefix[:len(search)] == search && (cn.handlers != nil || cn.anyChild != nil) {
+					(cn.prefix[len(search)]) == '/' && r.prefixEqual(cn.prefix[:len(search)], search) && (cn.handlers != nil || cn.anyChild != nil) {
 					res.tsr = true
 				}
 				// No matching prefix, let's backtrack to the first possible alternative node of the decision path
@@ -332,7 +345,11 @@ func (r *router) find(path string, paramsPointer *param.Params, unescape bool) (
 			if search == "/" && cn.handlers != nil {
 				res.tsr = true
 			}
-			if child := cn.findChild(search[0]); child != nil {
+			label := search[0]
+			if r.caseInsensitive {
+				label = toLowerASCII(label)
+			}
+			if child := cn.findChild(label); child != nil {
 				cn = child
 				continue
 			}
@@ -521,7 +538,10 @@ func (r *router) insert(path string, h app.HandlersChain, t kind, ppath string,
 		} else {
 			// 节点已存在
 			if currentNode.handlers != nil && h != nil {
-				panic("该路径的处理器不可重复注册 '" + ppath + "'")
+				if currentNode.ppath == ppath {
+					panic(fmt.Sprintf("路由冲突：方法 %s 的路径 '%s' 不可重复注册", r.method, ppath))
+				}
+				panic(fmt.Sprintf("路由冲突：方法 %s 的路径 '%s' 与已注册的 '%s' 冲突", r.method, ppath, currentNode.ppath))
 			}
 
 			if h != nil {
@@ -593,3 +613,40 @@ func checkPathValid(path string) {
 		}
 	}
 }
+
+// 比较树中的前缀与请求路径片段，忽略大小写时 prefix 已为小写。
+func (r *router) prefixEqual(prefix, s string) bool {
+	if !r.caseInsensitive {
+		return prefix == s
+	}
+	for i := 0; i < len(s); i++ {
+		if toLowerASCII(s[i]) != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 将路由路径中的静态部分转为 ASCII 小写，保留参数名。
+func lowerStaticPath(path string) string {
+	b := []byte(path)
+	inParam := false
+	for i, c := range b {
+		switch {
+		case c == paramLabel || c == anyLabel:
+			inParam = true
+		case c == '/':
+			inParam = false
+		case !inParam:
+			b[i] = toLowerASCII(c)
+		}
+	}
+	return string(b)
+}
+
+func toLowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -317,6 +317,33 @@ func TestTreeDuplicatePath(t *testing.T) {
 	})
 }
 
+func TestTreeConflictPanicMessage(t *testing.T) {
+	tree := &router{method: "GET", root: &node{}, hasTsrHandler: make(map[string]bool)}
+	tree.addRoute("/users/:id", fakeHandler("/users/:id"))
+	tree.addRoute("/files/*path", fakeHandler("/files/*path"))
+
+	recv := catchPanic(func() {
+		tree.addRoute("/users/:id", fakeHandler("/users/:id"))
+	})
+	if recv != "路由冲突：方法 GET 的路径 '/users/:id' 不可重复注册" {
+		t.Fatalf("unexpected panic: %v", recv)
+	}
+
+	recv = catchPanic(func() {
+		tree.addRoute("/users/:name", fakeHandler("/users/:name"))
+	})
+	if recv != "路由冲突：方法 GET 的路径 '/users/:name' 与已注册的 '/users/:id' 冲突" {
+		t.Fatalf("unexpected panic: %v", recv)
+	}
+
+	recv = catchPanic(func() {
+		tree.addRoute("/files/*name", fakeHandler("/files/*name"))
+	})
+	if recv != "路由冲突：方法 GET 的路径 '/files/*name' 与已注册的 '/files/*path' 冲突" {
+		t.Fatalf("unexpected panic: %v", recv)
+	}
+}
+
 func TestEmptyWildcardName(t *testing.T) {
 	tree := &router{method: "GET", root: &node{}, hasTsrHandler: make(map[string]bool)}
 